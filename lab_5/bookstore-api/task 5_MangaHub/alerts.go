@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Low Stock Alerts ----------
+
+const (
+	AlertLowStock   = "low_stock"
+	AlertOutOfStock = "out_of_stock"
+)
+
+// lowStockThreshold is shared by the alert job, the stock.low webhook and
+// the low_stock statistic.
+var lowStockThreshold = envInt("LOW_STOCK_THRESHOLD", 10)
+
+type Alert struct {
+	ID         int     `json:"id"`
+	Type       string  `json:"type"`
+	BookID     int     `json:"book_id"`
+	Title      string  `json:"title"`
+	Stock      int     `json:"stock"`
+	Threshold  int     `json:"threshold"`
+	CreatedAt  string  `json:"created_at"`
+	ResolvedAt *string `json:"resolved_at"`
+}
+
+// startAlertScheduler scans inventory every ALERT_INTERVAL_SECONDS (default
+// 60, 0 disables the job).
+func startAlertScheduler() {
+	interval := time.Duration(envInt("ALERT_INTERVAL_SECONDS", 60)) * time.Second
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := scanLowStock(); err != nil {
+				log.Printf("alerts: scan failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// scanLowStock opens one alert per book below the threshold and resolves open
+// alerts for books that were restocked or trashed. A book keeps a single open
+// alert until it recovers, so repeated scans do not re-notify.
+func scanLowStock() error {
+	if _, err := db.Exec(`UPDATE alerts SET resolved_at = CURRENT_TIMESTAMP
+		WHERE resolved_at IS NULL AND book_id NOT IN (
+			SELECT id FROM books WHERE stock < ? AND deleted_at IS NULL)`, lowStockThreshold); err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`SELECT b.id, b.title, b.stock FROM books b
+		WHERE b.stock < ? AND b.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM alerts a WHERE a.book_id = b.id AND a.resolved_at IS NULL)
+		ORDER BY b.id`, lowStockThreshold)
+	if err != nil {
+		return err
+	}
+	var raised []Alert
+	for rows.Next() {
+		a := Alert{Type: AlertLowStock, Threshold: lowStockThreshold}
+		if err := rows.Scan(&a.BookID, &a.Title, &a.Stock); err != nil {
+			rows.Close()
+			return err
+		}
+		if a.Stock <= 0 {
+			a.Type = AlertOutOfStock
+		}
+		raised = append(raised, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range raised {
+		res, err := db.Exec("INSERT INTO alerts (type, book_id, title, stock, threshold) VALUES (?, ?, ?, ?, ?)",
+			a.Type, a.BookID, a.Title, a.Stock, a.Threshold)
+		if err != nil {
+			return err
+		}
+		id, _ := res.LastInsertId()
+		a.ID = int(id)
+		publishEvent(EventAlertRaised, a)
+	}
+	if len(raised) > 0 {
+		if err := emailAlerts(raised); err != nil {
+			log.Printf("alerts: email failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// emailAlerts sends one summary mail to ALERT_EMAIL_TO.
+func emailAlerts(alerts []Alert) error {
+	to := os.Getenv("ALERT_EMAIL_TO")
+	if to == "" {
+		return nil
+	}
+	var body strings.Builder
+	for _, a := range alerts {
+		fmt.Fprintf(&body, "- #%d %s: %d left (threshold %d)\r\n", a.BookID, a.Title, a.Stock, a.Threshold)
+	}
+	return sendMail(strings.Split(to, ","), fmt.Sprintf("[Bookstore] %d book(s) low on stock", len(alerts)), body.String())
+}
+
+// sendMail delivers a plain-text mail through ALERT_SMTP_ADDR. It is a no-op
+// when no SMTP server is configured. ALERT_SMTP_USER/ALERT_SMTP_PASSWORD
+// enable PLAIN auth and ALERT_EMAIL_FROM sets the sender.
+func sendMail(to []string, subject, body string) error {
+	addr := os.Getenv("ALERT_SMTP_ADDR")
+	if addr == "" || len(to) == 0 {
+		return nil
+	}
+	from := os.Getenv("ALERT_EMAIL_FROM")
+	if from == "" {
+		from = "bookstore@localhost"
+	}
+	var auth smtp.Auth
+	if user := os.Getenv("ALERT_SMTP_USER"); user != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		auth = smtp.PlainAuth("", user, os.Getenv("ALERT_SMTP_PASSWORD"), host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", from, strings.Join(to, ", "), subject, body)
+	return smtp.SendMail(addr, auth, from, to, []byte(msg))
+}
+
+// getAlerts serves GET /alerts?status=open|resolved|all (default open).
+func getAlerts(c *gin.Context) {
+	query := `SELECT id, type, book_id, COALESCE(title, ''), stock, threshold, created_at, resolved_at FROM alerts
+		WHERE book_id IN (SELECT id FROM books WHERE store_id = ?)`
+	switch c.DefaultQuery("status", "open") {
+	case "open":
+		query += " AND resolved_at IS NULL"
+	case "resolved":
+		query += " AND resolved_at IS NOT NULL"
+	case "all":
+	default:
+		respondError(c, http.StatusBadRequest, "status must be open, resolved or all")
+		return
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+
+	limit := parseIntQuery(c, "limit", 100)
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+	rows, err := db.Query(query, storeID(c), limit)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	alerts := []Alert{}
+	for rows.Next() {
+		var a Alert
+		if err := rows.Scan(&a.ID, &a.Type, &a.BookID, &a.Title, &a.Stock, &a.Threshold, &a.CreatedAt, &a.ResolvedAt); err != nil {
+			internalError(c, err)
+			return
+		}
+		alerts = append(alerts, a)
+	}
+	c.JSON(http.StatusOK, gin.H{"count": len(alerts), "alerts": alerts})
+}
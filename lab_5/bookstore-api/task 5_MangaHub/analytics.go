@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Sales Analytics ----------
+
+type TopBook struct {
+	BookID  int     `json:"book_id"`
+	Title   string  `json:"title"`
+	Units   int     `json:"units"`
+	Revenue float64 `json:"revenue"`
+}
+
+type SalesBucket struct {
+	Period   string    `json:"period"`
+	Revenue  float64   `json:"revenue"`
+	Units    int       `json:"units"`
+	Sales    int       `json:"sales"`
+	TopBooks []TopBook `json:"top_books,omitempty"`
+}
+
+type SalesReport struct {
+	GroupBy      string        `json:"group_by"`
+	From         string        `json:"from,omitempty"`
+	To           string        `json:"to,omitempty"`
+	TotalRevenue float64       `json:"total_revenue"`
+	TotalUnits   int           `json:"total_units"`
+	Buckets      []SalesBucket `json:"buckets"`
+}
+
+type BookSalesReport struct {
+	BookID int    `json:"book_id"`
+	Title  string `json:"title"`
+	SalesReport
+}
+
+// salesLedger is the sales table with returns folded in as negative rows
+// dated when the refund happened, so revenue and units are net of refunds.
+// sale is 1 for sales and 0 for returns, for counting transactions.
+const salesLedger = `(SELECT book_id, quantity, total, sold_at, 1 AS sale FROM sales
+	UNION ALL
+	SELECT book_id, -quantity, -refund, returned_at, 0 FROM sale_returns)`
+
+// periodExpr formats a timestamp column into a day (2006-01-02), week
+// (2006-W01) or month (2006-01) label in the store's SQL dialect.
+func periodExpr(dialect Dialect, column, groupBy string) (string, error) {
+	formats := map[Dialect]map[string]string{
+		DialectSQLite:   {"day": "strftime('%Y-%m-%d', %s)", "week": "strftime('%Y-W%W', %s)", "month": "strftime('%Y-%m', %s)"},
+		DialectMySQL:    {"day": "DATE_FORMAT(%s, '%Y-%m-%d')", "week": "DATE_FORMAT(%s, '%x-W%v')", "month": "DATE_FORMAT(%s, '%Y-%m')"},
+		DialectPostgres: {"day": "to_char(%s, 'YYYY-MM-DD')", "week": "to_char(%s, 'IYYY-\"W\"IW')", "month": "to_char(%s, 'YYYY-MM')"},
+	}
+	format, ok := formats[dialect][groupBy]
+	if !ok {
+		return "", errors.New("group_by must be day, week or month")
+	}
+	// The strftime/DATE_FORMAT patterns contain % themselves, so only the
+	// column placeholder is substituted.
+	return strings.Replace(format, "%s", column, 1), nil
+}
+
+// salesRange turns ?from=&to= (YYYY-MM-DD, inclusive) into a WHERE fragment,
+// limited to sales of the request's store.
+func salesRange(c *gin.Context) (string, []any, error) {
+	where, args := " AND s.book_id IN (SELECT id FROM books WHERE store_id = ?)", []any{storeID(c)}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return "", nil, errors.New("from must be YYYY-MM-DD")
+		}
+		where += " AND s.sold_at >= ?"
+		args = append(args, t.Format("2006-01-02 15:04:05"))
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return "", nil, errors.New("to must be YYYY-MM-DD")
+		}
+		where += " AND s.sold_at < ?"
+		args = append(args, t.AddDate(0, 0, 1).Format("2006-01-02 15:04:05"))
+	}
+	return where, args, nil
+}
+
+// salesBuckets aggregates the sales ledger matching the extra WHERE fragment
+// per period.
+func salesBuckets(ctx context.Context, groupBy, where string, args []any) ([]SalesBucket, error) {
+	period, err := periodExpr(db.Dialect, "s.sold_at", groupBy)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`SELECT %s AS period, SUM(s.total), SUM(s.quantity), SUM(s.sale)
+		FROM %s s WHERE 1=1%s GROUP BY period ORDER BY period`, period, salesLedger, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	buckets := []SalesBucket{}
+	for rows.Next() {
+		var b SalesBucket
+		if err := rows.Scan(&b.Period, &b.Revenue, &b.Units, &b.Sales); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+func newSalesReport(c *gin.Context, groupBy string, buckets []SalesBucket) SalesReport {
+	report := SalesReport{GroupBy: groupBy, From: c.Query("from"), To: c.Query("to"), Buckets: buckets}
+	for _, b := range buckets {
+		report.TotalRevenue += b.Revenue
+		report.TotalUnits += b.Units
+	}
+	return report
+}
+
+// getSalesAnalytics serves GET /analytics/sales?group_by=day|week|month
+// with optional from/to dates and top (best sellers per bucket, default 3).
+func getSalesAnalytics(c *gin.Context) {
+	groupBy := c.DefaultQuery("group_by", "day")
+	period, err := periodExpr(db.Dialect, "s.sold_at", groupBy)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "group_by", Message: err.Error()})
+		return
+	}
+	where, args, err := salesRange(c)
+	if err != nil {
+		validationError(c, ErrorDetail{Message: err.Error()})
+		return
+	}
+	buckets, err := salesBuckets(c.Request.Context(), groupBy, where, args)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	top := parseIntQuery(c, "top", 3)
+	if top > 0 && len(buckets) > 0 {
+		rows, err := db.QueryContext(c.Request.Context(), fmt.Sprintf(`SELECT %s AS period, s.book_id, COALESCE(b.title, ''), SUM(s.quantity) AS units, SUM(s.total)
+			FROM %s s LEFT JOIN books b ON b.id = s.book_id
+			WHERE 1=1%s GROUP BY period, s.book_id, b.title ORDER BY period, units DESC, s.book_id`, period, salesLedger, where), args...)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		defer rows.Close()
+		index := map[string]int{}
+		for i, b := range buckets {
+			index[b.Period] = i
+		}
+		for rows.Next() {
+			var p string
+			var tb TopBook
+			if err := rows.Scan(&p, &tb.BookID, &tb.Title, &tb.Units, &tb.Revenue); err != nil {
+				internalError(c, err)
+				return
+			}
+			if i, ok := index[p]; ok && len(buckets[i].TopBooks) < top {
+				buckets[i].TopBooks = append(buckets[i].TopBooks, tb)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, newSalesReport(c, groupBy, buckets))
+}
+
+// getBookSalesAnalytics serves GET /analytics/books/:id, the sales curve of
+// one title (trashed books included, since their history still counts).
+func getBookSalesAnalytics(c *gin.Context) {
+	id := c.Param("id")
+	var report BookSalesReport
+	err := db.QueryRow("SELECT id, title FROM books WHERE id = ? AND store_id = ?", id, storeID(c)).Scan(&report.BookID, &report.Title)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", "day")
+	if _, err := periodExpr(db.Dialect, "", groupBy); err != nil {
+		validationError(c, ErrorDetail{Field: "group_by", Message: err.Error()})
+		return
+	}
+	where, args, err := salesRange(c)
+	if err != nil {
+		validationError(c, ErrorDetail{Message: err.Error()})
+		return
+	}
+	buckets, err := salesBuckets(c.Request.Context(), groupBy, " AND s.book_id = ?"+where, append([]any{report.BookID}, args...))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	report.SalesReport = newSalesReport(c, groupBy, buckets)
+	c.JSON(http.StatusOK, report)
+}
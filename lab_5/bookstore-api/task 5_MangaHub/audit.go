@@ -0,0 +1,171 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Audit Log ----------
+
+type AuditEntry struct {
+	ID        int               `json:"id"`
+	Action    string            `json:"action"`
+	Entity    string            `json:"entity"`
+	EntityID  int               `json:"entity_id"`
+	Actor     string            `json:"actor"`
+	Before    json.RawMessage   `json:"before,omitempty"`
+	After     json.RawMessage   `json:"after,omitempty"`
+	Changes   map[string][2]any `json:"changes,omitempty"`
+	CreatedAt string            `json:"created_at"`
+}
+
+// auditActor identifies the caller. Clients may name themselves with
+// X-Actor; otherwise the client IP is recorded.
+func auditActor(c *gin.Context) string {
+	if actor := c.GetHeader("X-Actor"); actor != "" {
+		return actor
+	}
+	return c.ClientIP()
+}
+
+// recordAudit appends one entry to audit_log. before/after may be nil for
+// creates and purges. Failures are logged but never fail the request.
+func recordAudit(c *gin.Context, action, entity string, entityID int, before, after any) {
+	if db == nil {
+		return // in-memory repositories, nowhere to write
+	}
+	_, err := db.Exec(`INSERT INTO audit_log (action, entity, entity_id, actor, before_json, after_json)
+		VALUES (?, ?, ?, ?, ?, ?)`, action, entity, entityID, auditActor(c), auditJSON(before), auditJSON(after))
+	if err != nil {
+		log.Printf("audit: %s %s %d: %v", action, entity, entityID, err)
+	}
+}
+
+func auditJSON(v any) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return sql.NullString{}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// rowQuerier is satisfied by both *Store and *Tx, so snapshots can be taken
+// inside the transaction that changes the row.
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// snapshotBook loads a book row (trashed or not) for before/after images.
+func snapshotBook(id any) *Book {
+	return snapshotBookFrom(db, id)
+}
+
+func snapshotBookFrom(q rowQuerier, id any) *Book {
+	var b Book
+	err := q.QueryRow(`SELECT id, title, COALESCE(author_id, 0), isbn, price, stock, COALESCE(published_year, 0), COALESCE(description, ''), version
+		FROM books WHERE id = ?`, id).
+		Scan(&b.ID, &b.Title, &b.AuthorID, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
+// snapshotAuthor loads an author row (trashed or not) for before/after images.
+func snapshotAuthor(id any) *Author {
+	var a Author
+	err := db.QueryRow(`SELECT id, name, COALESCE(bio, ''), COALESCE(birth_year, 0), COALESCE(country, ''), created_at
+		FROM authors WHERE id = ?`, id).
+		Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt)
+	if err != nil {
+		return nil
+	}
+	return &a
+}
+
+// diffJSON returns field -> [before, after] for every top-level field that changed.
+func diffJSON(before, after json.RawMessage) map[string][2]any {
+	var b, a map[string]any
+	json.Unmarshal(before, &b)
+	json.Unmarshal(after, &a)
+	changes := map[string][2]any{}
+	for k, v := range a {
+		if !reflect.DeepEqual(b[k], v) {
+			changes[k] = [2]any{b[k], v}
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			changes[k] = [2]any{v, nil}
+		}
+	}
+	return changes
+}
+
+const auditSelect = `SELECT id, action, entity, COALESCE(entity_id, 0), COALESCE(actor, ''),
+	COALESCE(before_json, ''), COALESCE(after_json, ''), created_at
+	FROM audit_log`
+
+// getAuditLog serves GET /audit?entity=book&id=5&limit=50.
+func getAuditLog(c *gin.Context) {
+	query := auditSelect + " WHERE 1=1"
+	args := []any{}
+	if entity := c.Query("entity"); entity != "" {
+		query += " AND entity = ?"
+		args = append(args, entity)
+	}
+	if id := c.Query("id"); id != "" {
+		query += " AND entity_id = ?"
+		args = append(args, id)
+	}
+	if action := c.Query("action"); action != "" {
+		query += " AND action = ?"
+		args = append(args, action)
+	}
+	limit := parseIntQuery(c, "limit", 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	entries := []AuditEntry{}
+	for rows.Next() {
+		entries = append(entries, scanAuditEntry(rows))
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "count": len(entries)})
+}
+
+// scanAuditEntry reads one auditSelect row and diffs its before/after images.
+func scanAuditEntry(row interface{ Scan(...any) error }) AuditEntry {
+	var e AuditEntry
+	var before, after string
+	row.Scan(&e.ID, &e.Action, &e.Entity, &e.EntityID, &e.Actor, &before, &after, &e.CreatedAt)
+	if before != "" {
+		e.Before = json.RawMessage(before)
+	}
+	if after != "" {
+		e.After = json.RawMessage(after)
+	}
+	if e.Before != nil && e.After != nil {
+		e.Changes = diffJSON(e.Before, e.After)
+	}
+	return e
+}
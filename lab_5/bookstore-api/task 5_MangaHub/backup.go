@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mattn/go-sqlite3"
+)
+
+// ---------- Backup & Restore ----------
+
+// POST /admin/backup streams a consistent copy of the SQLite catalog made
+// with VACUUM INTO, without stopping the server. POST /admin/restore takes
+// such a file back: it is checked first, then copied over the live database
+// with SQLite's online backup API, so open connections see the restored data
+// at once. Both answer 501 on MySQL and Postgres.
+
+var restoreMaxBodyBytes = int64(envInt("RESTORE_MAX_BODY_BYTES", 100<<20))
+
+func requireSQLite(c *gin.Context) bool {
+	if db.Dialect != DialectSQLite {
+		respondError(c, http.StatusNotImplemented, "Backup and restore are only available on SQLite")
+		return false
+	}
+	return true
+}
+
+func backupDatabase(c *gin.Context) {
+	if !requireSQLite(c) {
+		return
+	}
+	dir, err := os.MkdirTemp("", "bookstore-backup-")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "backup.db")
+	if _, err := db.ExecContext(c.Request.Context(), "VACUUM INTO ?", path); err != nil {
+		internalError(c, err)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	filename := fmt.Sprintf("bookstore_%s.db", time.Now().UTC().Format("20060102-150405"))
+	recordAudit(c, "backup", "database", 0, nil, gin.H{"file": filename, "bytes": info.Size()})
+	c.DataFromReader(http.StatusOK, info.Size(), "application/vnd.sqlite3", f, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, filename),
+	})
+}
+
+// restoreDatabase serves POST /admin/restore with the backup in multipart
+// field "file". The upload must pass integrity_check, hold a books table and
+// be at a schema version no newer than this build; older backups are
+// migrated forward after the swap.
+func restoreDatabase(c *gin.Context) {
+	if !requireSQLite(c) {
+		return
+	}
+	fh, err := c.FormFile("file")
+	if tooLargeError(c, err) {
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "multipart field 'file' is required")
+		return
+	}
+	dir, err := os.MkdirTemp("", "bookstore-restore-")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "restore.db")
+	if err := c.SaveUploadedFile(fh, path); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	src, err := sql.Open(string(DialectSQLite), "file:"+path+"?mode=ro")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer src.Close()
+	version, err := checkBackup(c.Request.Context(), src)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "file", Message: err.Error()})
+		return
+	}
+
+	if err := copyDatabase(c.Request.Context(), src); err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := migrateUp(); err != nil {
+		internalError(c, err)
+		return
+	}
+	resetStatistics()
+	readCache.invalidate("/")
+	var books int
+	db.QueryRow("SELECT COUNT(*) FROM books WHERE deleted_at IS NULL").Scan(&books)
+	recordAudit(c, "restore", "database", 0, nil, gin.H{"file": fh.Filename, "backup_version": version})
+	c.JSON(http.StatusOK, gin.H{"message": "Database restored", "backup_version": version, "books": books})
+}
+
+// checkBackup validates an uploaded database and returns its schema version.
+func checkBackup(ctx context.Context, src *sql.DB) (int, error) {
+	var result string
+	if err := src.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return 0, errors.New("not a SQLite database")
+	}
+	if result != "ok" {
+		return 0, fmt.Errorf("integrity check failed: %s", result)
+	}
+	var books int
+	if err := src.QueryRowContext(ctx, "SELECT COUNT(*) FROM books").Scan(&books); err != nil {
+		return 0, errors.New("not a bookstore backup (no books table)")
+	}
+	// Databases created before migrations existed have no schema_migrations
+	// and count as version 0.
+	var version int
+	src.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if latest := migrations[len(migrations)-1].Version; version > latest {
+		return 0, fmt.Errorf("backup is at schema version %d, newer than this server (%d)", version, latest)
+	}
+	return version, nil
+}
+
+// copyDatabase overwrites the live database with src, page by page.
+func copyDatabase(ctx context.Context, src *sql.DB) error {
+	dstConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+	return dstConn.Raw(func(dst any) error {
+		return srcConn.Raw(func(s any) error {
+			backup, err := dst.(*sqlite3.SQLiteConn).Backup("main", s.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Response Cache ----------
+
+type cacheEntry struct {
+	body        []byte
+	contentType string
+	expires     time.Time
+}
+
+// responseCache keeps rendered GET responses keyed by request URI and store. Entries
+// expire after ttl and are dropped eagerly by invalidate on writes.
+type responseCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+var readCache = newResponseCache(time.Duration(envInt("CACHE_TTL_SECONDS", 30)) * time.Second)
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func (rc *responseCache) get(key string) (cacheEntry, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	e, ok := rc.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+func (rc *responseCache) set(key string, e cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e.expires = time.Now().Add(rc.ttl)
+	rc.entries[key] = e
+}
+
+// invalidate drops every entry whose path starts with one of the prefixes.
+func (rc *responseCache) invalidate(prefixes ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key := range rc.entries {
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				delete(rc.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// cacheMiddleware serves cached copies of successful GET responses and
+// records fresh ones. X-Cache reports HIT or MISS.
+func (rc *responseCache) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || rc.ttl <= 0 {
+			c.Next()
+			return
+		}
+		key := c.Request.URL.RequestURI() + "|store=" + strconv.Itoa(storeID(c))
+		if e, ok := rc.get(key); ok {
+			c.Header("X-Cache", "HIT")
+			c.Data(http.StatusOK, e.contentType, e.body)
+			c.Abort()
+			return
+		}
+
+		original := c.Writer
+		buf := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buf
+		c.Header("X-Cache", "MISS")
+		c.Next()
+		c.Writer = original
+
+		if buf.Status() == http.StatusOK {
+			rc.set(key, cacheEntry{body: bytes.Clone(buf.body.Bytes()), contentType: original.Header().Get("Content-Type")})
+		}
+		original.Write(buf.body.Bytes())
+	}
+}
+
+// invalidateOnWrite clears cached reads after any successful mutating request.
+// Book and author writes affect /books, /authors, /stats and /tags, so the
+// whole cache is dropped rather than tracking finer dependencies.
+func (rc *responseCache) invalidateOnWrite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return
+		}
+		if status := c.Writer.Status(); status >= 200 && status < 300 {
+			rc.invalidate("/books", "/authors", "/stats", "/tags")
+		}
+	}
+}
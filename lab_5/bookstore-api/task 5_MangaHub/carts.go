@@ -0,0 +1,423 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Carts & Orders ----------
+
+// A cart collects books without touching stock. Checkout is the second
+// phase: in one transaction it claims the cart, takes every line out of
+// stock with the same conditional UPDATE as POST /books/:id/sell, and
+// writes an order with one sale per line. If any line cannot be filled the
+// whole checkout is rolled back and the cart stays open.
+
+const (
+	CartStatusOpen       = "open"
+	CartStatusCheckedOut = "checked_out"
+)
+
+type Cart struct {
+	ID         int        `json:"id"`
+	CustomerID *int       `json:"customer_id"`
+	Status     string     `json:"status"`
+	OrderID    *int       `json:"order_id"`
+	Items      []CartItem `json:"items"`
+	ItemCount  int        `json:"item_count"`
+	Total      float64    `json:"total"`
+	CreatedAt  string     `json:"created_at"`
+	UpdatedAt  string     `json:"updated_at"`
+}
+
+// CartItem is a cart line priced at the book's current price. Available
+// tells whether checkout would fill it right now.
+type CartItem struct {
+	BookID    int     `json:"book_id" binding:"required,gt=0"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity" binding:"required,gt=0,max=1000"`
+	Price     float64 `json:"price"`
+	LineTotal float64 `json:"line_total"`
+	Stock     int     `json:"stock"`
+	Available bool    `json:"available"`
+	AddedAt   string  `json:"added_at"`
+
+	live bool
+}
+
+type CartRequest struct {
+	CustomerID int `json:"customer_id" binding:"omitempty,gt=0"`
+}
+
+type CartQuantityRequest struct {
+	Quantity int `json:"quantity" binding:"gte=0,max=1000"`
+}
+
+type Order struct {
+	ID         int         `json:"id"`
+	CartID     int         `json:"cart_id"`
+	CustomerID *int        `json:"customer_id"`
+	Total      float64     `json:"total"`
+	Items      []OrderLine `json:"items"`
+	CreatedAt  string      `json:"created_at"`
+}
+
+type OrderLine struct {
+	SaleID    int     `json:"sale_id"`
+	BookID    int     `json:"book_id"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
+
+// rowsQuerier is satisfied by both *Store and *Tx.
+type rowsQuerier interface {
+	rowQuerier
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func loadCart(q rowsQuerier, id, store int) (Cart, error) {
+	cart := Cart{Items: []CartItem{}}
+	err := q.QueryRow("SELECT id, customer_id, status, order_id, created_at, updated_at FROM carts WHERE id = ? AND store_id = ?", id, store).
+		Scan(&cart.ID, &cart.CustomerID, &cart.Status, &cart.OrderID, &cart.CreatedAt, &cart.UpdatedAt)
+	if err != nil {
+		return cart, err
+	}
+	rows, err := q.Query(`SELECT ci.book_id, b.title, ci.quantity, b.price, b.stock, b.deleted_at IS NULL, ci.added_at
+		FROM cart_items ci JOIN books b ON b.id = ci.book_id
+		WHERE ci.cart_id = ? ORDER BY ci.added_at, ci.book_id`, id)
+	if err != nil {
+		return cart, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var it CartItem
+		if err := rows.Scan(&it.BookID, &it.Title, &it.Quantity, &it.Price, &it.Stock, &it.live, &it.AddedAt); err != nil {
+			return cart, err
+		}
+		it.LineTotal = it.Price * float64(it.Quantity)
+		it.Available = it.live && it.Stock >= it.Quantity
+		cart.Items = append(cart.Items, it)
+		cart.ItemCount += it.Quantity
+		cart.Total += it.LineTotal
+	}
+	return cart, rows.Err()
+}
+
+// openCart resolves :id to an open cart of the current store, answering
+// 404 or 409 itself when there is none.
+func openCart(c *gin.Context) (Cart, bool) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Cart not found")
+		return Cart{}, false
+	}
+	cart, err := loadCart(db, id, storeID(c))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Cart not found")
+		return cart, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return cart, false
+	}
+	if cart.Status != CartStatusOpen {
+		respondError(c, http.StatusConflict, fmt.Sprintf("Cart was already checked out as order %d", *cart.OrderID))
+		return cart, false
+	}
+	return cart, true
+}
+
+func respondCart(c *gin.Context, status, id int) {
+	cart, err := loadCart(db, id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(status, cart)
+}
+
+// createCart serves POST /carts; the body, and customer_id in it, are
+// optional so anonymous visitors can shop too.
+func createCart(c *gin.Context) {
+	var req CartRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		bindError(c, err)
+		return
+	}
+	var customerID *int
+	if req.CustomerID > 0 {
+		if !customerExists(req.CustomerID) {
+			validationError(c, ErrorDetail{Field: "customer_id", Message: fmt.Sprintf("customer %d not found", req.CustomerID)})
+			return
+		}
+		customerID = &req.CustomerID
+	}
+	res, err := db.Exec("INSERT INTO carts (store_id, customer_id, status) VALUES (?, ?, ?)", storeID(c), customerID, CartStatusOpen)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondCart(c, http.StatusCreated, int(id))
+}
+
+func getCart(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Cart not found")
+		return
+	}
+	cart, err := loadCart(db, id, storeID(c))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Cart not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// addCartItem serves POST /carts/:id/items. Adding a book already in the
+// cart raises its quantity. Stock is not reserved or checked until checkout.
+func addCartItem(c *gin.Context) {
+	cart, ok := openCart(c)
+	if !ok {
+		return
+	}
+	var req CartItem
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if _, err := bookRepo.Get(c.Request.Context(), req.BookID); errors.Is(err, ErrNotFound) {
+		validationError(c, ErrorDetail{Field: "book_id", Message: fmt.Sprintf("book %d not found", req.BookID)})
+		return
+	} else if err != nil {
+		internalError(c, err)
+		return
+	}
+	status := http.StatusOK
+	res, err := db.Exec("UPDATE cart_items SET quantity = quantity + ? WHERE cart_id = ? AND book_id = ?", req.Quantity, cart.ID, req.BookID)
+	if err == nil {
+		if ra, _ := res.RowsAffected(); ra == 0 {
+			status = http.StatusCreated
+			_, err = db.Exec("INSERT INTO cart_items (cart_id, book_id, quantity) VALUES (?, ?, ?)", cart.ID, req.BookID, req.Quantity)
+		}
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	touchCart(cart.ID)
+	respondCart(c, status, cart.ID)
+}
+
+// updateCartItem serves PUT /carts/:id/items/:book_id; quantity 0 removes
+// the line.
+func updateCartItem(c *gin.Context) {
+	cart, ok := openCart(c)
+	if !ok {
+		return
+	}
+	var req CartQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	var res sql.Result
+	var err error
+	if req.Quantity == 0 {
+		res, err = db.Exec("DELETE FROM cart_items WHERE cart_id = ? AND book_id = ?", cart.ID, c.Param("book_id"))
+	} else {
+		res, err = db.Exec("UPDATE cart_items SET quantity = ? WHERE cart_id = ? AND book_id = ?", req.Quantity, cart.ID, c.Param("book_id"))
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book is not in this cart")
+		return
+	}
+	touchCart(cart.ID)
+	respondCart(c, http.StatusOK, cart.ID)
+}
+
+func removeCartItem(c *gin.Context) {
+	cart, ok := openCart(c)
+	if !ok {
+		return
+	}
+	res, err := db.Exec("DELETE FROM cart_items WHERE cart_id = ? AND book_id = ?", cart.ID, c.Param("book_id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book is not in this cart")
+		return
+	}
+	touchCart(cart.ID)
+	respondCart(c, http.StatusOK, cart.ID)
+}
+
+func touchCart(id int) {
+	db.Exec("UPDATE carts SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id)
+}
+
+// checkoutCart serves POST /carts/:id/checkout and answers 201 with the
+// order. Every line that cannot be filled is listed in the error details.
+func checkoutCart(c *gin.Context) {
+	cart, ok := openCart(c)
+	if !ok {
+		return
+	}
+	tx, err := db.BeginTx(c.Request.Context(), nil)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Claiming the cart first makes a concurrent second checkout find it
+	// closed instead of selling the same lines twice.
+	res, err := tx.Exec("UPDATE carts SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?",
+		CartStatusCheckedOut, cart.ID, CartStatusOpen)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusConflict, "Cart was already checked out")
+		return
+	}
+	cart, err = loadCart(tx, cart.ID, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if len(cart.Items) == 0 {
+		validationError(c, ErrorDetail{Field: "items", Message: "cart is empty"})
+		return
+	}
+
+	var shortages []ErrorDetail
+	for i, it := range cart.Items {
+		field := fmt.Sprintf("items[%d].quantity", i)
+		if !it.live {
+			shortages = append(shortages, ErrorDetail{Field: field, Message: fmt.Sprintf("book %d is no longer in the catalog", it.BookID)})
+			continue
+		}
+		res, err := tx.Exec("UPDATE books SET stock = stock - ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL AND stock >= ?",
+			it.Quantity, it.BookID, it.Quantity)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		if ra, _ := res.RowsAffected(); ra == 0 {
+			shortages = append(shortages, ErrorDetail{Field: field, Message: fmt.Sprintf("book %d: only %d available", it.BookID, it.Stock)})
+		}
+	}
+	if len(shortages) > 0 {
+		respondErrorCode(c, http.StatusBadRequest, CodeInsufficientStock, "Insufficient stock", shortages...)
+		return
+	}
+
+	orderRes, err := tx.Exec("INSERT INTO orders (store_id, customer_id, total) VALUES (?, ?, ?)", storeID(c), cart.CustomerID, cart.Total)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	orderID, _ := orderRes.LastInsertId()
+	order := Order{ID: int(orderID), CartID: cart.ID, CustomerID: cart.CustomerID, Total: cart.Total}
+	var before, after []*Book
+	for _, it := range cart.Items {
+		sale, err := tx.Exec("INSERT INTO sales (book_id, quantity, unit_price, total, order_id) VALUES (?, ?, ?, ?, ?)",
+			it.BookID, it.Quantity, it.Price, it.LineTotal, orderID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		saleID, _ := sale.LastInsertId()
+		if err := recordMovement(tx, c, it.BookID, -it.Quantity, MovementSell); err != nil {
+			internalError(c, err)
+			return
+		}
+		order.Items = append(order.Items, OrderLine{
+			SaleID: int(saleID), BookID: it.BookID, Title: it.Title,
+			Quantity: it.Quantity, UnitPrice: it.Price, Total: it.LineTotal,
+		})
+		b := snapshotBookFrom(tx, it.BookID)
+		prev := *b
+		prev.Stock += it.Quantity
+		before, after = append(before, &prev), append(after, b)
+	}
+	if _, err := tx.Exec("UPDATE carts SET order_id = ? WHERE id = ?", orderID, cart.ID); err != nil {
+		internalError(c, err)
+		return
+	}
+	tx.QueryRow("SELECT created_at FROM orders WHERE id = ?", orderID).Scan(&order.CreatedAt)
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	for i, b := range after {
+		countBook(storeID(c), before[i], b)
+		recordAudit(c, "sell", "book", b.ID, before[i], b)
+		publishEvent(EventBookSold, gin.H{"book_id": b.ID, "title": b.Title, "quantity": order.Items[i].Quantity, "stock": b.Stock, "order_id": order.ID})
+		if b.Stock < lowStockThreshold {
+			publishEvent(EventStockLow, gin.H{"book_id": b.ID, "title": b.Title, "stock": b.Stock, "threshold": lowStockThreshold})
+		}
+	}
+	recordAudit(c, "checkout", "cart", cart.ID, nil, &order)
+	c.JSON(http.StatusCreated, order)
+}
+
+// getOrder serves GET /orders/:id.
+func getOrder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	var order Order
+	err := sql.ErrNoRows
+	if ok {
+		err = db.QueryRow(`SELECT o.id, COALESCE(ca.id, 0), o.customer_id, o.total, o.created_at
+			FROM orders o LEFT JOIN carts ca ON ca.order_id = o.id
+			WHERE o.id = ? AND o.store_id = ?`, id, storeID(c)).
+			Scan(&order.ID, &order.CartID, &order.CustomerID, &order.Total, &order.CreatedAt)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Order not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	rows, err := db.Query(`SELECT s.id, s.book_id, COALESCE(b.title, ''), s.quantity, s.unit_price, s.total
+		FROM sales s LEFT JOIN books b ON b.id = s.book_id
+		WHERE s.order_id = ? ORDER BY s.id`, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	order.Items = []OrderLine{}
+	for rows.Next() {
+		var l OrderLine
+		if err := rows.Scan(&l.SaleID, &l.BookID, &l.Title, &l.Quantity, &l.UnitPrice, &l.Total); err != nil {
+			internalError(c, err)
+			return
+		}
+		order.Items = append(order.Items, l)
+	}
+	c.JSON(http.StatusOK, order)
+}
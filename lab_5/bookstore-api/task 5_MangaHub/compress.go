@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Response Compression ----------
+
+// Responses are gzipped when the client accepts it, the content type is
+// text-like and the body reaches GZIP_MIN_BYTES (default 1024). The first
+// bytes are held back until that size is reached, so small replies go out
+// untouched and streamed exports are compressed as they are written.
+// GZIP_LEVEL picks the level (1-9, default 6); 0 turns compression off.
+// Brotli would need a third-party encoder and is not offered.
+
+var (
+	gzipMinBytes = envInt("GZIP_MIN_BYTES", 1024)
+	gzipLevel    = envInt("GZIP_LEVEL", gzip.DefaultCompression)
+)
+
+var compressibleTypes = []string{"application/json", "application/problem+json", "text/"}
+
+type gzipWriter struct {
+	gin.ResponseWriter
+	pending bytes.Buffer
+	gz      *gzip.Writer
+	decided bool
+	level   int
+	minSize int
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.pending.Write(data)
+		if w.pending.Len() < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// decide starts compressing if the response qualifies, then sends what was
+// held back.
+func (w *gzipWriter) decide() error {
+	w.decided = true
+	h := w.Header()
+	if w.pending.Len() > 0 && w.pending.Len() >= w.minSize && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		// An invalid GZIP_LEVEL leaves the response uncompressed.
+		if gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level); err == nil {
+			h.Set("Content-Encoding", "gzip")
+			h.Add("Vary", "Accept-Encoding")
+			h.Del("Content-Length")
+			w.gz = gz
+		}
+	}
+	data := w.pending.Bytes()
+	w.pending = bytes.Buffer{}
+	if len(data) == 0 {
+		return nil
+	}
+	if w.gz != nil {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close sends anything still held back and ends the gzip stream.
+func (w *gzipWriter) close() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
+
+func compressible(contentType string) bool {
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") || strings.TrimSpace(name) == "*" {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+func gzipMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if gzipLevel == 0 || c.Request.Method == http.MethodHead || !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+		original := c.Writer
+		w := &gzipWriter{ResponseWriter: original, level: gzipLevel, minSize: gzipMinBytes}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = original
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Statistics Counters ----------
+
+// GET /stats is served from per-store counters kept in memory. A store is
+// loaded with the full computeStatistics scan the first time it is asked
+// for; after that every book and author write adjusts the counters, so the
+// catalog size no longer matters. The most expensive, cheapest and most
+// stocked books are reloaded (three LIMIT 1 queries) only when a write may
+// have displaced one of them. POST /stats/recompute rebuilds a store from
+// the database, for writes made behind the API's back (seeding, manual SQL).
+
+type storeCounters struct {
+	books      int
+	authors    int
+	lowStock   int
+	outOfStock int
+	value      float64
+	priceSum   float64
+	byYear     map[int]int
+	extremes   *statsExtremes // nil when they must be reloaded
+}
+
+type statsExtremes struct {
+	mostExpensive, cheapest, mostStocked BookWithAuthor
+}
+
+var counters = struct {
+	sync.Mutex
+	stores map[int]*storeCounters
+}{stores: map[int]*storeCounters{}}
+
+func getStatistics(c *gin.Context) {
+	stats, err := cachedStatistics(storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// recomputeStatistics serves POST /stats/recompute.
+func recomputeStatistics(c *gin.Context) {
+	store := storeID(c)
+	counters.Lock()
+	delete(counters.stores, store)
+	counters.Unlock()
+	readCache.invalidate("/stats")
+	stats, err := cachedStatistics(store)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// resetStatistics forgets every store, e.g. after the database is replaced.
+func resetStatistics() {
+	counters.Lock()
+	counters.stores = map[int]*storeCounters{}
+	counters.Unlock()
+}
+
+// cachedStatistics answers from the store's counters, loading them first if
+// needed. Nothing is kept when a load fails, so the next request retries
+// instead of serving zeros until the next recompute.
+func cachedStatistics(store int) (Statistics, error) {
+	counters.Lock()
+	defer counters.Unlock()
+	sc, ok := counters.stores[store]
+	if !ok {
+		var err error
+		if sc, err = loadCounters(store); err != nil {
+			return Statistics{}, err
+		}
+		counters.stores[store] = sc
+	}
+	if sc.extremes == nil {
+		e, err := loadExtremes(store)
+		if err != nil {
+			return Statistics{}, err
+		}
+		sc.extremes = e
+	}
+	stats := Statistics{
+		TotalBooks:    sc.books,
+		TotalAuthors:  sc.authors,
+		TotalValue:    sc.value,
+		LowStock:      sc.lowStock,
+		OutOfStock:    sc.outOfStock,
+		BooksByYear:   make(map[int]int, len(sc.byYear)),
+		MostExpensive: &sc.extremes.mostExpensive,
+		Cheapest:      &sc.extremes.cheapest,
+		MostStocked:   &sc.extremes.mostStocked,
+	}
+	if sc.books > 0 {
+		stats.AveragePrice = sc.priceSum / float64(sc.books)
+	}
+	for year, n := range sc.byYear {
+		stats.BooksByYear[year] = n
+	}
+	return stats, nil
+}
+
+func loadCounters(store int) (*storeCounters, error) {
+	stats, err := computeStatistics(store)
+	if err != nil {
+		return nil, err
+	}
+	sc := &storeCounters{
+		books:      stats.TotalBooks,
+		authors:    stats.TotalAuthors,
+		lowStock:   stats.LowStock,
+		outOfStock: stats.OutOfStock,
+		value:      stats.TotalValue,
+		byYear:     stats.BooksByYear,
+		extremes: &statsExtremes{
+			mostExpensive: *stats.MostExpensive,
+			cheapest:      *stats.Cheapest,
+			mostStocked:   *stats.MostStocked,
+		},
+	}
+	if err := db.QueryRow("SELECT COALESCE(SUM(price), 0) FROM books WHERE store_id = ? AND deleted_at IS NULL", store).Scan(&sc.priceSum); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func loadExtremes(store int) (*statsExtremes, error) {
+	var e statsExtremes
+	for _, x := range []struct {
+		order string
+		dest  *BookWithAuthor
+	}{
+		{"b.price DESC", &e.mostExpensive},
+		{"b.price ASC", &e.cheapest},
+		{"b.stock DESC", &e.mostStocked},
+	} {
+		b := x.dest
+		err := db.QueryRow(`
+		SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+			COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
+		FROM books b LEFT JOIN authors a ON b.author_id = a.id
+		WHERE b.store_id = ? AND b.deleted_at IS NULL
+		ORDER BY `+x.order+` LIMIT 1`, store).
+			Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version)
+		// an empty store has no row and keeps the zero book
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return &e, nil
+}
+
+// countBook adjusts a store's counters for one live book changing from
+// before to after. before is nil when the book enters the catalog (create,
+// restore), after is nil when it leaves it (trash).
+func countBook(store int, before, after *Book) {
+	counters.Lock()
+	defer counters.Unlock()
+	sc, ok := counters.stores[store]
+	if !ok {
+		return // not loaded yet; the first read scans the current rows
+	}
+	if before != nil {
+		sc.add(before, -1)
+	}
+	if after != nil {
+		sc.add(after, 1)
+	}
+}
+
+func (sc *storeCounters) add(b *Book, sign int) {
+	sc.books += sign
+	sc.value += float64(sign) * b.Price * float64(b.Stock)
+	sc.priceSum += float64(sign) * b.Price
+	sc.byYear[b.PublishedYear] += sign
+	if sc.byYear[b.PublishedYear] == 0 {
+		delete(sc.byYear, b.PublishedYear)
+	}
+	switch {
+	case b.Stock == 0:
+		sc.outOfStock += sign
+	case b.Stock < lowStockThreshold:
+		sc.lowStock += sign
+	}
+	if e := sc.extremes; e != nil {
+		holder := b.ID == e.mostExpensive.ID || b.ID == e.cheapest.ID || b.ID == e.mostStocked.ID
+		rival := sign > 0 && (b.Price >= e.mostExpensive.Price || b.Price <= e.cheapest.Price || b.Stock >= e.mostStocked.Stock)
+		if holder || rival || sc.books <= 1 {
+			sc.extremes = nil
+		}
+	}
+}
+
+// countAuthors adjusts a store's author total by delta.
+func countAuthors(store, delta int) {
+	counters.Lock()
+	defer counters.Unlock()
+	if sc, ok := counters.stores[store]; ok {
+		sc.authors += delta
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Admin Dashboard ----------
+
+const (
+	dashboardListSize   = 10
+	dashboardTopSellers = 5
+	dashboardTopDays    = 30
+)
+
+type LowStockBook struct {
+	BookID int    `json:"book_id"`
+	Title  string `json:"title"`
+	Stock  int    `json:"stock"`
+}
+
+type RecentOrder struct {
+	SaleID   int     `json:"sale_id"`
+	BookID   int     `json:"book_id"`
+	Title    string  `json:"title"`
+	Quantity int     `json:"quantity"`
+	Returned int     `json:"returned"`
+	Total    float64 `json:"total"`
+	SoldAt   string  `json:"sold_at"`
+}
+
+// Dashboard is GET /admin/dashboard, everything the admin page shows in one
+// document. TopSellers covers the last 30 days, net of returns.
+type Dashboard struct {
+	Stats        Statistics     `json:"stats"`
+	Threshold    int            `json:"low_stock_threshold"`
+	LowStock     []LowStockBook `json:"low_stock"`
+	LatestOrders []RecentOrder  `json:"latest_orders"`
+	RecentAudit  []AuditEntry   `json:"recent_audit"`
+	TopSellers   []TopBook      `json:"top_sellers"`
+	GeneratedAt  string         `json:"generated_at"`
+}
+
+func getDashboard(c *gin.Context) {
+	store := storeID(c)
+	ctx := c.Request.Context()
+	stats, err := computeStatistics(store)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	d := Dashboard{
+		Stats:        stats,
+		Threshold:    lowStockThreshold,
+		LowStock:     []LowStockBook{},
+		LatestOrders: []RecentOrder{},
+		RecentAudit:  []AuditEntry{},
+		TopSellers:   []TopBook{},
+		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id, title, stock FROM books
+		WHERE store_id = ? AND deleted_at IS NULL AND stock < ?
+		ORDER BY stock, id LIMIT ?`, store, lowStockThreshold, dashboardListSize)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b LowStockBook
+		if err := rows.Scan(&b.BookID, &b.Title, &b.Stock); err != nil {
+			internalError(c, err)
+			return
+		}
+		d.LowStock = append(d.LowStock, b)
+	}
+
+	rows, err = db.QueryContext(ctx, `SELECT s.id, s.book_id, b.title, s.quantity,
+			COALESCE((SELECT SUM(r.quantity) FROM sale_returns r WHERE r.sale_id = s.id), 0), s.total, s.sold_at
+		FROM sales s JOIN books b ON b.id = s.book_id
+		WHERE b.store_id = ?
+		ORDER BY s.id DESC LIMIT ?`, store, dashboardListSize)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o RecentOrder
+		if err := rows.Scan(&o.SaleID, &o.BookID, &o.Title, &o.Quantity, &o.Returned, &o.Total, &o.SoldAt); err != nil {
+			internalError(c, err)
+			return
+		}
+		d.LatestOrders = append(d.LatestOrders, o)
+	}
+
+	rows, err = db.QueryContext(ctx, auditSelect+" ORDER BY id DESC LIMIT ?", dashboardListSize)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d.RecentAudit = append(d.RecentAudit, scanAuditEntry(rows))
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -dashboardTopDays).Format(sqlTimeLayout)
+	rows, err = db.QueryContext(ctx, `SELECT s.book_id, b.title, SUM(s.quantity) AS units, SUM(s.total)
+		FROM `+salesLedger+` s JOIN books b ON b.id = s.book_id
+		WHERE b.store_id = ? AND s.sold_at >= ?
+		GROUP BY s.book_id, b.title
+		HAVING SUM(s.quantity) > 0
+		ORDER BY units DESC, s.book_id LIMIT ?`, store, since, dashboardTopSellers)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tb TopBook
+		if err := rows.Scan(&tb.BookID, &tb.Title, &tb.Units, &tb.Revenue); err != nil {
+			internalError(c, err)
+			return
+		}
+		d.TopSellers = append(d.TopSellers, tb)
+	}
+	c.JSON(http.StatusOK, d)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Duplicate ISBNs ----------
+
+// An ISBN may be live only once per store (idx_books_store_isbn). Creating
+// or editing a book onto a taken ISBN answers 409 with the existing book, and
+// merge-duplicate folds a second copy into the first.
+
+// DuplicateISBNResponse is the 409 body for a taken ISBN; clients should
+// restock ExistingBookID rather than create a second copy.
+type DuplicateISBNResponse struct {
+	ErrorResponse
+	ExistingBookID int `json:"existing_book_id"`
+}
+
+type MergeDuplicateRequest struct {
+	DuplicateID int `json:"duplicate_id" binding:"required,gt=0"`
+}
+
+type MergeDuplicateResponse struct {
+	Book       Book `json:"book"`
+	MergedFrom int  `json:"merged_from"`
+	StockAdded int  `json:"stock_added"`
+}
+
+// isbnVariants returns the stored forms an ISBN-13 may have: itself and, for
+// the 978 prefix, the ISBN-10 older rows were saved with.
+func isbnVariants(isbn13 string) []string {
+	variants := []string{isbn13}
+	if len(isbn13) == 13 && isbn13[:3] == "978" {
+		sum := 0
+		for i := 0; i < 9; i++ {
+			sum += int(isbn13[3+i]-'0') * (10 - i)
+		}
+		check := strconv.Itoa((11 - sum%11) % 11)
+		if check == "10" {
+			check = "X"
+		}
+		variants = append(variants, isbn13[3:12]+check)
+	}
+	return variants
+}
+
+// findDuplicateISBN returns the id of a live book in the context's store
+// with the same ISBN as isbn13, ignoring excludeID, or 0.
+func findDuplicateISBN(ctx context.Context, isbn13 string, excludeID int) (int, error) {
+	v := isbnVariants(isbn13)
+	args := []any{storeFrom(ctx), excludeID, v[0]}
+	query := "SELECT id FROM books WHERE store_id = ? AND deleted_at IS NULL AND id <> ? AND (isbn = ?"
+	if len(v) > 1 {
+		query += " OR isbn = ?"
+		args = append(args, v[1])
+	}
+	var id int
+	err := db.QueryRowContext(ctx, query+") ORDER BY id LIMIT 1", args...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func duplicateISBN(c *gin.Context, existingID int, isbn string) {
+	c.AbortWithStatusJSON(http.StatusConflict, DuplicateISBNResponse{
+		ErrorResponse: ErrorResponse{
+			Code:      CodeDuplicateISBN,
+			Message:   "A book with this ISBN already exists",
+			Details:   []ErrorDetail{{Field: "isbn", Message: fmt.Sprintf("%s is used by book %d; restock it instead", isbn, existingID)}},
+			RequestID: requestID(c),
+		},
+		ExistingBookID: existingID,
+	})
+}
+
+// mergeDuplicateBook serves POST /books/:id/merge-duplicate. The duplicate
+// (live or trashed, same store, same ISBN) gives its stock, sales history,
+// purchase orders and wishlist entries to :id and is moved to the trash.
+func mergeDuplicateBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	var req MergeDuplicateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if req.DuplicateID == id {
+		validationError(c, ErrorDetail{Field: "duplicate_id", Message: "cannot merge a book into itself"})
+		return
+	}
+	ctx := c.Request.Context()
+	target, err := bookRepo.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	dup := snapshotBook(req.DuplicateID)
+	var dupStore int
+	var dupLive bool
+	if dup != nil {
+		db.QueryRow("SELECT store_id, deleted_at IS NULL FROM books WHERE id = ?", dup.ID).Scan(&dupStore, &dupLive)
+	}
+	if dup == nil || dupStore != storeID(c) {
+		respondError(c, http.StatusNotFound, "Duplicate book not found")
+		return
+	}
+	if !sameISBN(target.ISBN, dup.ISBN) {
+		validationError(c, ErrorDetail{Field: "duplicate_id", Message: fmt.Sprintf("book %d has ISBN %s, not %s", dup.ID, dup.ISBN, target.ISBN)})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{"UPDATE books SET stock = stock + ?, version = version + 1 WHERE id = ?", []any{dup.Stock, id}},
+		{"UPDATE books SET stock = 0, version = version + 1, deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP) WHERE id = ?", []any{dup.ID}},
+		{"UPDATE sales SET book_id = ? WHERE book_id = ?", []any{id, dup.ID}},
+		{"UPDATE sale_returns SET book_id = ? WHERE book_id = ?", []any{id, dup.ID}},
+		{"UPDATE purchase_orders SET book_id = ? WHERE book_id = ?", []any{id, dup.ID}},
+		// Customers who wished for both keep the entry they already have.
+		{"DELETE FROM wishlist_items WHERE book_id = ? AND customer_id IN (SELECT customer_id FROM (SELECT customer_id FROM wishlist_items WHERE book_id = ?) kept)", []any{dup.ID, id}},
+		{"UPDATE wishlist_items SET book_id = ? WHERE book_id = ?", []any{id, dup.ID}},
+		{"DELETE FROM stock_subscriptions WHERE book_id = ? AND id IN (SELECT id FROM (SELECT d.id FROM stock_subscriptions d JOIN stock_subscriptions k ON k.channel = d.channel AND k.target = d.target WHERE d.book_id = ? AND k.book_id = ?) kept)", []any{dup.ID, dup.ID, id}},
+		{"UPDATE stock_subscriptions SET book_id = ? WHERE book_id = ?", []any{id, dup.ID}},
+	}
+	for _, s := range statements {
+		if _, err := tx.Exec(s.query, s.args...); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	// The copies leave the duplicate's ledger and enter this one's.
+	for _, m := range [][2]int{{id, dup.Stock}, {dup.ID, -dup.Stock}} {
+		if err := recordMovement(tx, c, m[0], m[1], MovementAdjustment); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	after := snapshotBookFrom(tx, id)
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	countBook(dupStore, &target.Book, after)
+	if dupLive {
+		countBook(dupStore, dup, nil)
+	}
+	recordAudit(c, "merge", "book", id, &target.Book, after)
+	recordAudit(c, "delete", "book", dup.ID, dup, nil)
+	notifyBackInStock(after, target.Stock)
+	c.JSON(http.StatusOK, MergeDuplicateResponse{Book: *after, MergedFrom: dup.ID, StockAdded: dup.Stock})
+}
+
+// sameISBN compares two stored ISBNs, either of which may predate
+// normalization.
+func sameISBN(a, b string) bool {
+	if n, err := normalizeISBN(a); err == nil {
+		a = n
+	}
+	if n, err := normalizeISBN(b); err == nil {
+		b = n
+	}
+	return a == b
+}
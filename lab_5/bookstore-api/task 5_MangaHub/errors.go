@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ---------- Error Responses ----------
+
+// ErrorResponse is the body of every 4xx/5xx answer. Code is a stable,
+// machine-readable identifier; Message is meant for humans and may change.
+type ErrorResponse struct {
+	Code      string        `json:"code"`
+	Message   string        `json:"message"`
+	Details   []ErrorDetail `json:"details"`
+	RequestID string        `json:"request_id,omitempty"`
+}
+
+// ErrorDetail points at one offending input, e.g. a JSON field that failed
+// validation.
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+const (
+	CodeValidationFailed  = "validation_failed"
+	CodeInvalidJSON       = "invalid_json"
+	CodeInsufficientStock = "insufficient_stock"
+	CodeVersionConflict   = "version_conflict"
+	CodeDuplicateISBN     = "duplicate_isbn"
+	CodeNoLendableCopies  = "no_lendable_copies"
+	CodeTimeout           = "timeout"
+)
+
+var errorCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusConflict:              "conflict",
+	http.StatusUnprocessableEntity:   "unprocessable_entity",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusInternalServerError:   "internal_error",
+	http.StatusNotImplemented:        "not_implemented",
+	http.StatusBadGateway:            "bad_gateway",
+	http.StatusServiceUnavailable:    "service_unavailable",
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+}
+
+// respondError aborts with the standard envelope, deriving the code from
+// the status.
+func respondError(c *gin.Context, status int, message string, details ...ErrorDetail) {
+	code, ok := errorCodes[status]
+	if !ok {
+		code = strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	}
+	respondErrorCode(c, status, code, message, details...)
+}
+
+func respondErrorCode(c *gin.Context, status int, code, message string, details ...ErrorDetail) {
+	if details == nil {
+		details = []ErrorDetail{}
+	}
+	c.AbortWithStatusJSON(status, ErrorResponse{Code: code, Message: message, Details: details, RequestID: requestID(c)})
+}
+
+// validationError answers 400 for input that binds but is semantically
+// wrong, such as a bad ISBN checksum.
+func validationError(c *gin.Context, details ...ErrorDetail) {
+	respondErrorCode(c, http.StatusBadRequest, CodeValidationFailed, "Request validation failed", details...)
+}
+
+// bindError turns a ShouldBind* failure into field-level details.
+func bindError(c *gin.Context, err error) {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		details := make([]ErrorDetail, 0, len(verrs))
+		for _, fe := range verrs {
+			details = append(details, ErrorDetail{Field: fieldPath(fe), Message: validationMessage(fe)})
+		}
+		validationError(c, details...)
+		return
+	}
+	if tooLargeError(c, err) {
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		validationError(c, ErrorDetail{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type.Kind())})
+		return
+	}
+	respondErrorCode(c, http.StatusBadRequest, CodeInvalidJSON, "Request body is not valid JSON")
+}
+
+// storeError maps a failed write: unique-constraint violations become 409,
+// anything else is an internal error. Driver messages are never sent to the
+// client.
+func storeError(c *gin.Context, err error) {
+	if isUniqueViolation(err) {
+		respondError(c, http.StatusConflict, "A record with the same unique value already exists")
+		return
+	}
+	internalError(c, err)
+}
+
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate")
+}
+
+// rowError is the client-safe text for a failed insert inside a batch
+// (bulk create, CSV import), where one row failing must not fail the rest.
+func rowError(err error) error {
+	if isUniqueViolation(err) {
+		return errors.New("a book with this ISBN already exists")
+	}
+	return errors.New("could not be saved")
+}
+
+// fieldPath drops the struct name from the validator namespace:
+// "BulkCreateRequest.books[0].title" -> "books[0].title".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func jsonTypeName(k reflect.Kind) string {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+func validationMessage(fe validator.FieldError) string {
+	unit := ""
+	if k := fe.Kind(); k == reflect.String {
+		unit = " characters"
+	} else if k == reflect.Slice || k == reflect.Map {
+		unit = " items"
+	}
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return fmt.Sprintf("failed the %q check", fe.Tag())
+}
+
+// useJSONFieldNames makes validation errors report json tag names
+// ("author_id") instead of Go field names ("AuthorID").
+func useJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+}
+
+func notFoundRoute(c *gin.Context) {
+	respondError(c, http.StatusNotFound, "No route for "+c.Request.Method+" "+c.Request.URL.Path)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- ETag ----------
+
+// bufferedWriter holds the response body so a validator can be computed
+// before anything is sent to the client.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMiddleware tags successful GET responses with a strong ETag derived from
+// the response body and answers 304 Not Modified when the client already has it.
+func etagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		original := c.Writer
+		buf := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buf
+		c.Next()
+		c.Writer = original
+
+		if buf.Status() != http.StatusOK {
+			original.Write(buf.body.Bytes())
+			return
+		}
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", etag)
+		original.Header().Set("Cache-Control", "no-cache")
+		if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+			original.Header().Del("Content-Type")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(buf.body.Bytes())
+	}
+}
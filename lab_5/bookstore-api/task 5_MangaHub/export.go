@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Export ----------
+
+var exportColumns = []string{
+	"id", "title", "author_id", "author_name", "isbn", "price", "stock", "published_year", "description",
+}
+
+// exportBooks streams the whole catalog row by row so large catalogs are never
+// held in memory. Supported formats: csv (default) and json.
+func exportBooks(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		respondError(c, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	rows, err := db.QueryContext(c.Request.Context(), `
+	SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+		COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
+	FROM books b LEFT JOIN authors a ON b.author_id = a.id
+	WHERE b.store_id = ? AND b.deleted_at IS NULL
+	ORDER BY b.id`, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("books_%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		w := csv.NewWriter(c.Writer)
+		w.Write(exportColumns)
+		for rows.Next() {
+			var b BookWithAuthor
+			if err := rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version); err != nil {
+				log.Printf("export: scan failed: %v", err)
+				break
+			}
+			w.Write([]string{
+				strconv.Itoa(b.ID), b.Title, strconv.Itoa(b.AuthorID), b.AuthorName, b.ISBN,
+				strconv.FormatFloat(b.Price, 'f', 2, 64), strconv.Itoa(b.Stock),
+				strconv.Itoa(b.PublishedYear), b.Description,
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			log.Printf("export: write failed: %v", err)
+		}
+		return
+	}
+
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	c.Writer.WriteString("[")
+	first := true
+	for rows.Next() {
+		var b BookWithAuthor
+		if err := rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version); err != nil {
+			log.Printf("export: scan failed: %v", err)
+			break
+		}
+		if !first {
+			c.Writer.WriteString(",")
+		}
+		first = false
+		if err := enc.Encode(b); err != nil {
+			log.Printf("export: write failed: %v", err)
+			return
+		}
+	}
+	c.Writer.WriteString("]\n")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Sparse Fieldsets ----------
+
+// bookFields maps the JSON names of BookWithAuthor to the SQL that produces
+// them, in response order. Queries join books b with authors a.
+var bookFields = []struct {
+	name string
+	expr string
+}{
+	{"id", "b.id"},
+	{"title", "b.title"},
+	{"author_id", "COALESCE(b.author_id, 0)"},
+	{"isbn", "b.isbn"},
+	{"price", "b.price"},
+	{"stock", "b.stock"},
+	{"published_year", "COALESCE(b.published_year, 0)"},
+	{"description", "COALESCE(b.description, '')"},
+	{"version", "b.version"},
+	{"author_name", "COALESCE(a.name, '')"},
+	{"author_bio", "COALESCE(a.bio, '')"},
+}
+
+// parseBookFields reads ?fields=id,title,price. It returns nil when the
+// parameter is absent, meaning "all fields".
+func parseBookFields(c *gin.Context) ([]string, error) {
+	raw, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, nil
+	}
+	known := map[string]bool{}
+	var names []string
+	for _, f := range bookFields {
+		known[f.name] = true
+		names = append(names, f.name)
+	}
+	fields := []string{}
+	seen := map[string]bool{}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		if !known[f] {
+			return nil, fmt.Errorf("unknown field %q (allowed: %s)", f, strings.Join(names, ", "))
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must name at least one of: %s", strings.Join(names, ", "))
+	}
+	return fields, nil
+}
+
+// queryBookFields selects only the requested columns. The id is always read
+// (callers need it for cursors) but only returned when asked for.
+func queryBookFields(ctx context.Context, fields []string, tail string, args ...any) ([]gin.H, []int, error) {
+	wanted := map[string]bool{}
+	for _, f := range fields {
+		wanted[f] = true
+	}
+	var cols, names []string
+	cols = append(cols, "b.id")
+	for _, f := range bookFields {
+		if wanted[f.name] && f.name != "id" {
+			cols = append(cols, f.expr)
+			names = append(names, f.name)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+strings.Join(cols, ", ")+" FROM books b LEFT JOIN authors a ON b.author_id = a.id "+tail, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	books := []gin.H{}
+	var ids []int
+	for rows.Next() {
+		var id int
+		values := make([]any, len(names))
+		dest := []any{&id}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		book := gin.H{}
+		if wanted["id"] {
+			book["id"] = id
+		}
+		for i, name := range names {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			book[name] = values[i]
+		}
+		books = append(books, book)
+		ids = append(ids, id)
+	}
+	return books, ids, rows.Err()
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+)
+
+// ---------- GraphQL ----------
+
+// Field names follow the REST JSON (snake_case) so both APIs describe the
+// same objects the same way.
+
+type GraphQLRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+const gqlBookColumns = `id, title, COALESCE(author_id, 0), isbn, price, stock, COALESCE(published_year, 0), COALESCE(description, ''), version`
+
+func gqlScanBook(row interface{ Scan(...any) error }) (Book, error) {
+	var b Book
+	err := row.Scan(&b.ID, &b.Title, &b.AuthorID, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version)
+	return b, err
+}
+
+func gqlBooks(query string, args ...any) ([]Book, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []Book{}
+	for rows.Next() {
+		b, err := gqlScanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+	return books, rows.Err()
+}
+
+func gqlAuthor(ctx context.Context, id any) (*Author, error) {
+	var a Author
+	err := db.QueryRow(`SELECT id, name, COALESCE(bio, ''), COALESCE(birth_year, 0), COALESCE(country, ''), created_at
+		FROM authors WHERE id = ? AND store_id = ? AND deleted_at IS NULL`, id, storeFrom(ctx)).
+		Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt)
+	if err != nil {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// ginContext recovers the request context passed to graphql.Do so mutations
+// can write audit entries with the caller's identity.
+func ginContext(p graphql.ResolveParams) *gin.Context {
+	c, _ := p.Context.(*gin.Context)
+	return c
+}
+
+var graphQLSchema = mustBuildGraphQLSchema()
+
+func mustBuildGraphQLSchema() graphql.Schema {
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"bio":        &graphql.Field{Type: graphql.String},
+			"birth_year": &graphql.Field{Type: graphql.Int},
+			"country":    &graphql.Field{Type: graphql.String},
+			"created_at": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"author_id":      &graphql.Field{Type: graphql.Int},
+			"isbn":           &graphql.Field{Type: graphql.String},
+			"price":          &graphql.Field{Type: graphql.Float},
+			"stock":          &graphql.Field{Type: graphql.Int},
+			"published_year": &graphql.Field{Type: graphql.Int},
+			"description":    &graphql.Field{Type: graphql.String},
+			"version":        &graphql.Field{Type: graphql.Int},
+			"author": &graphql.Field{
+				Type: authorType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return gqlAuthor(p.Context, p.Source.(Book).AuthorID)
+				},
+			},
+		},
+	})
+
+	// Declared after bookType to break the Author <-> Book cycle.
+	authorType.AddFieldConfig("books", &graphql.Field{
+		Type: graphql.NewList(bookType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return gqlBooks("SELECT "+gqlBookColumns+" FROM books WHERE author_id = ? AND store_id = ? AND deleted_at IS NULL ORDER BY id",
+				p.Source.(*Author).ID, storeFrom(p.Context))
+		},
+	})
+
+	bookInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"author_id":      &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"isbn":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"price":          &graphql.InputObjectFieldConfig{Type: graphql.Float},
+			"stock":          &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"published_year": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"description":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	authorInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AuthorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"bio":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"birth_year": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"country":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"books": &graphql.Field{
+				Type: graphql.NewList(bookType),
+				Args: graphql.FieldConfigArgument{
+					"limit":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20},
+					"offset":    &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"author_id": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					limit, _ := p.Args["limit"].(int)
+					offset, _ := p.Args["offset"].(int)
+					if limit < 1 || limit > 100 {
+						limit = 20
+					}
+					q, args := "SELECT "+gqlBookColumns+" FROM books WHERE store_id = ? AND deleted_at IS NULL", []any{storeFrom(p.Context)}
+					if authorID, ok := p.Args["author_id"].(int); ok {
+						q += " AND author_id = ?"
+						args = append(args, authorID)
+					}
+					return gqlBooks(q+" ORDER BY id LIMIT ? OFFSET ?", append(args, limit, offset)...)
+				},
+			},
+			"book": &graphql.Field{
+				Type: bookType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					b, err := gqlScanBook(db.QueryRow("SELECT "+gqlBookColumns+" FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NULL", p.Args["id"], storeFrom(p.Context)))
+					if err != nil {
+						return nil, nil
+					}
+					return b, nil
+				},
+			},
+			"authors": &graphql.Field{
+				Type: graphql.NewList(authorType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					rows, err := db.Query("SELECT id FROM authors WHERE store_id = ? AND deleted_at IS NULL ORDER BY id", storeFrom(p.Context))
+					if err != nil {
+						return nil, err
+					}
+					var ids []int
+					for rows.Next() {
+						var id int
+						rows.Scan(&id)
+						ids = append(ids, id)
+					}
+					rows.Close()
+					authors := []*Author{}
+					for _, id := range ids {
+						if a, _ := gqlAuthor(p.Context, id); a != nil {
+							authors = append(authors, a)
+						}
+					}
+					return authors, nil
+				},
+			},
+			"author": &graphql.Field{
+				Type: authorType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return gqlAuthor(p.Context, p.Args["id"])
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type: bookType,
+				Args: graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInput)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var b Book
+					applyBookInput(&b, p.Args["input"].(map[string]any))
+					if err := validateBookInput(p.Context, &b); err != nil {
+						return nil, err
+					}
+					res, err := db.Exec(`INSERT INTO books (title, author_id, isbn, price, stock, published_year, description, store_id)
+						VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, b.Title, b.AuthorID, b.ISBN, b.Price, b.Stock, b.PublishedYear, b.Description, storeFrom(p.Context))
+					if err != nil {
+						return nil, err
+					}
+					id, _ := res.LastInsertId()
+					b.ID = int(id)
+					b.Version = 1
+					logMovement(ginContext(p), b.ID, b.Stock, MovementAdjustment)
+					countBook(storeFrom(p.Context), nil, &b)
+					recordAudit(ginContext(p), "create", "book", b.ID, nil, &b)
+					publishEvent(EventBookCreated, b)
+					return b, nil
+				},
+			},
+			"updateBook": &graphql.Field{
+				Type: bookType,
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					before, err := gqlScanBook(db.QueryRow("SELECT "+gqlBookColumns+" FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NULL", p.Args["id"], storeFrom(p.Context)))
+					if err != nil {
+						return nil, errors.New("book not found")
+					}
+					b := before
+					applyBookInput(&b, p.Args["input"].(map[string]any))
+					if err := validateBookInput(p.Context, &b); err != nil {
+						return nil, err
+					}
+					res, err := db.Exec(`UPDATE books SET title = ?, author_id = ?, isbn = ?, price = ?, stock = ?, published_year = ?, description = ?,
+						version = version + 1 WHERE id = ? AND version = ?`, b.Title, b.AuthorID, b.ISBN, b.Price, b.Stock, b.PublishedYear, b.Description, b.ID, before.Version)
+					if err != nil {
+						return nil, err
+					}
+					if ra, _ := res.RowsAffected(); ra == 0 {
+						return nil, errors.New("book was modified concurrently, retry")
+					}
+					b.Version++
+					logMovement(ginContext(p), b.ID, b.Stock-before.Stock, MovementAdjustment)
+					countBook(storeFrom(p.Context), &before, &b)
+					recordAudit(ginContext(p), "update", "book", b.ID, &before, &b)
+					notifyBackInStock(&b, before.Stock)
+					return b, nil
+				},
+			},
+			"createAuthor": &graphql.Field{
+				Type: authorType,
+				Args: graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(authorInput)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var a Author
+					applyAuthorInput(&a, p.Args["input"].(map[string]any))
+					if err := binding.Validator.ValidateStruct(&a); err != nil {
+						return nil, err
+					}
+					res, err := db.Exec("INSERT INTO authors (name, bio, birth_year, country, store_id) VALUES (?, ?, ?, ?, ?)",
+						a.Name, a.Bio, a.BirthYear, a.Country, storeFrom(p.Context))
+					if err != nil {
+						return nil, err
+					}
+					id, _ := res.LastInsertId()
+					created := snapshotAuthor(id)
+					countAuthors(storeFrom(p.Context), 1)
+					recordAudit(ginContext(p), "create", "author", created.ID, nil, created)
+					return created, nil
+				},
+			},
+			"updateAuthor": &graphql.Field{
+				Type: authorType,
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(authorInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					before, _ := gqlAuthor(p.Context, p.Args["id"])
+					if before == nil {
+						return nil, errors.New("author not found")
+					}
+					a := *before
+					applyAuthorInput(&a, p.Args["input"].(map[string]any))
+					if err := binding.Validator.ValidateStruct(&a); err != nil {
+						return nil, err
+					}
+					if _, err := db.Exec("UPDATE authors SET name = ?, bio = ?, birth_year = ?, country = ? WHERE id = ?",
+						a.Name, a.Bio, a.BirthYear, a.Country, a.ID); err != nil {
+						return nil, err
+					}
+					recordAudit(ginContext(p), "update", "author", a.ID, before, &a)
+					return &a, nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	if err != nil {
+		panic(fmt.Sprintf("graphql schema: %v", err))
+	}
+	return schema
+}
+
+// applyBookInput copies the fields present in a BookInput onto b, leaving the
+// others untouched so updateBook behaves like a PATCH.
+func applyBookInput(b *Book, in map[string]any) {
+	if v, ok := in["title"].(string); ok {
+		b.Title = v
+	}
+	if v, ok := in["author_id"].(int); ok {
+		b.AuthorID = v
+	}
+	if v, ok := in["isbn"].(string); ok {
+		b.ISBN = v
+	}
+	if v, ok := in["price"].(float64); ok {
+		b.Price = v
+	}
+	if v, ok := in["stock"].(int); ok {
+		b.Stock = v
+	}
+	if v, ok := in["published_year"].(int); ok {
+		b.PublishedYear = v
+	}
+	if v, ok := in["description"].(string); ok {
+		b.Description = v
+	}
+}
+
+func applyAuthorInput(a *Author, in map[string]any) {
+	if v, ok := in["name"].(string); ok {
+		a.Name = v
+	}
+	if v, ok := in["bio"].(string); ok {
+		a.Bio = v
+	}
+	if v, ok := in["birth_year"].(int); ok {
+		a.BirthYear = v
+	}
+	if v, ok := in["country"].(string); ok {
+		a.Country = v
+	}
+}
+
+// validateBookInput applies the same rules as POST /books and normalizes
+// the ISBN.
+func validateBookInput(ctx context.Context, b *Book) error {
+	if err := binding.Validator.ValidateStruct(b); err != nil {
+		return err
+	}
+	isbn, err := normalizeISBN(b.ISBN)
+	if err != nil {
+		return err
+	}
+	b.ISBN = isbn
+	if err := validatePublishedYear(b.PublishedYear); err != nil {
+		return err
+	}
+	var exists bool
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM authors WHERE id = ? AND store_id = ? AND deleted_at IS NULL)", b.AuthorID, storeFrom(ctx)).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("author ID %d not found", b.AuthorID)
+	}
+	if existing, err := findDuplicateISBN(ctx, b.ISBN, b.ID); err != nil {
+		return err
+	} else if existing != 0 {
+		return fmt.Errorf("ISBN %s is already used by book %d", b.ISBN, existing)
+	}
+	return nil
+}
+
+// graphqlHandler serves POST /graphql with the standard
+// {"query", "operationName", "variables"} body.
+func graphqlHandler(c *gin.Context) {
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         graphQLSchema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        c,
+	})
+	c.JSON(http.StatusOK, result)
+}
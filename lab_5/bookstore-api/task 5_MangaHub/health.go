@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Health ----------
+
+var startedAt = time.Now()
+
+type HealthCheck struct {
+	Name    string  `json:"name"`
+	OK      bool    `json:"ok"`
+	Error   string  `json:"error,omitempty"`
+	Latency float64 `json:"latency_ms"`
+}
+
+type HealthResponse struct {
+	Status        string        `json:"status"`
+	UptimeSeconds int64         `json:"uptime_seconds"`
+	Checks        []HealthCheck `json:"checks,omitempty"`
+}
+
+// healthz only reports that the process is up and serving.
+func healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{
+		Status:        "ok",
+		UptimeSeconds: int64(time.Since(startedAt).Seconds()),
+	})
+}
+
+// readyz checks the dependencies a request needs: the database answers,
+// every migration is applied and the data directory accepts writes.
+// Any failing check turns the response into a 503.
+func readyz(c *gin.Context) {
+	checks := []HealthCheck{
+		runCheck("database", checkDatabase),
+		runCheck("migrations", checkMigrations),
+		runCheck("disk", checkDiskWritable),
+	}
+
+	resp := HealthResponse{Status: "ok", UptimeSeconds: int64(time.Since(startedAt).Seconds()), Checks: checks}
+	status := http.StatusOK
+	for _, check := range checks {
+		if !check.OK {
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(status, resp)
+}
+
+func runCheck(name string, fn func() error) HealthCheck {
+	start := time.Now()
+	err := fn()
+	check := HealthCheck{Name: name, OK: err == nil, Latency: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func checkDatabase() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
+func checkMigrations() error {
+	pending, err := pendingMigrations()
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d pending migration(s)", pending)
+	}
+	return nil
+}
+
+// checkDiskWritable writes a probe file next to the SQLite database, or into
+// the temp directory (used for uploads) for server-based databases.
+func checkDiskWritable() error {
+	dir := os.TempDir()
+	if db.Dialect == DialectSQLite {
+		var seq int
+		var name, file string
+		if err := db.QueryRow("PRAGMA database_list").Scan(&seq, &name, &file); err != nil {
+			return err
+		}
+		if file != "" {
+			dir = filepath.Dir(file)
+		}
+	}
+	f, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_, err = f.WriteString("ok")
+	f.Close()
+	os.Remove(name)
+	return err
+}
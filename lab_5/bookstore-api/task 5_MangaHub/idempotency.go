@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Idempotency Keys ----------
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	idempotencyTTL    = 24 * time.Hour
+)
+
+// idempotencyMu serialises the claim step so two concurrent retries with the
+// same key cannot both run the handler.
+var idempotencyMu sync.Mutex
+
+type storedResponse struct {
+	requestHash string
+	status      int
+	contentType string
+	body        string
+}
+
+// idempotent makes a mutating route safe to retry. The first request carrying
+// an Idempotency-Key runs normally and its response is stored; repeats of the
+// same key on the same route within 24h get the stored response replayed with
+// Idempotent-Replayed: true. Reusing a key with a different body is rejected.
+// Server errors are not stored, so the client may retry them.
+func idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			respondError(c, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Could not read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])
+		// the store is part of the route so tenants never replay each other's responses
+		path := "/stores/" + strconv.Itoa(storeID(c)) + c.Request.URL.Path
+
+		stored, claimed, err := claimIdempotencyKey(key, c.Request.Method, path, hash)
+		if err != nil {
+			internalError(c, err)
+			c.Abort()
+			return
+		}
+		if !claimed {
+			switch {
+			case stored.requestHash != hash:
+				respondError(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request body")
+			case stored.status == 0:
+				respondError(c, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(stored.status, stored.contentType, []byte(stored.body))
+				c.Abort()
+			}
+			return
+		}
+
+		original := c.Writer
+		buf := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buf
+		c.Next()
+		c.Writer = original
+
+		status := buf.Status()
+		if status >= 500 {
+			db.Exec("DELETE FROM idempotency_keys WHERE idem_key = ? AND method = ? AND path = ?", key, c.Request.Method, path)
+		} else {
+			db.Exec("UPDATE idempotency_keys SET status = ?, content_type = ?, body = ? WHERE idem_key = ? AND method = ? AND path = ?",
+				status, original.Header().Get("Content-Type"), buf.body.String(), key, c.Request.Method, path)
+		}
+		original.Write(buf.body.Bytes())
+	}
+}
+
+// claimIdempotencyKey records a pending entry for the key and reports true,
+// or returns the live entry already stored for it. Entries older than
+// idempotencyTTL are discarded first.
+func claimIdempotencyKey(key, method, path, hash string) (storedResponse, bool, error) {
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	now := time.Now()
+	if _, err := db.Exec("DELETE FROM idempotency_keys WHERE created_unix < ?", now.Add(-idempotencyTTL).Unix()); err != nil {
+		return storedResponse{}, false, err
+	}
+
+	var stored storedResponse
+	var contentType, body sql.NullString
+	err := db.QueryRow("SELECT request_hash, status, content_type, body FROM idempotency_keys WHERE idem_key = ? AND method = ? AND path = ?",
+		key, method, path).Scan(&stored.requestHash, &stored.status, &contentType, &body)
+	if err == nil {
+		stored.contentType, stored.body = contentType.String, body.String
+		return stored, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return storedResponse{}, false, err
+	}
+
+	_, err = db.Exec("INSERT INTO idempotency_keys (idem_key, method, path, request_hash, created_unix) VALUES (?, ?, ?, ?, ?)",
+		key, method, path, hash, now.Unix())
+	return storedResponse{}, err == nil, err
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Import ----------
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Title string `json:"title,omitempty"`
+	Error string `json:"error"`
+}
+
+type ImportReport struct {
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+// importRequiredColumns must be present in the CSV header. The export format
+// (see exportColumns) is accepted as-is; id and author_name are ignored.
+var importRequiredColumns = []string{"title", "author_id", "isbn", "price"}
+
+// parseImportRow maps one CSV record onto a Book using the header index.
+func parseImportRow(record []string, cols map[string]int) (Book, error) {
+	var book Book
+	get := func(name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	atoi := func(name string) (int, error) {
+		v := get(name)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be an integer", name)
+		}
+		return n, nil
+	}
+
+	var err error
+	book.Title = get("title")
+	book.ISBN = get("isbn")
+	book.Description = get("description")
+	if book.AuthorID, err = atoi("author_id"); err != nil {
+		return book, err
+	}
+	if book.Stock, err = atoi("stock"); err != nil {
+		return book, err
+	}
+	if book.PublishedYear, err = atoi("published_year"); err != nil {
+		return book, err
+	}
+	if book.Price, err = strconv.ParseFloat(get("price"), 64); err != nil {
+		return book, fmt.Errorf("price must be a number")
+	}
+
+	if len(book.Title) < 3 {
+		return book, fmt.Errorf("title must be at least 3 characters")
+	}
+	if book.AuthorID <= 0 {
+		return book, fmt.Errorf("author_id is required")
+	}
+	if book.Price < 0.01 || book.Price > 1000 {
+		return book, fmt.Errorf("price must be between 0.01 and 1000")
+	}
+	if book.Stock < 0 {
+		return book, fmt.Errorf("stock must be >= 0")
+	}
+	isbn, err := normalizeISBN(book.ISBN)
+	if err != nil {
+		return book, err
+	}
+	book.ISBN = isbn
+	if err := validatePublishedYear(book.PublishedYear); err != nil {
+		return book, err
+	}
+	return book, nil
+}
+
+// importBooks accepts a multipart CSV upload (field "file"), validates every
+// row and inserts the valid ones in a single transaction.
+func importBooks(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if tooLargeError(c, err) {
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "multipart field 'file' is required")
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Could not open uploaded file")
+		return
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "could not read CSV header")
+		return
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := cols[name]; !ok {
+			validationError(c, ErrorDetail{Field: name, Message: "CSV column is missing"})
+			return
+		}
+	}
+
+	tx, err := db.BeginTx(c.Request.Context(), nil)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO books (title, author_id, isbn, price, stock, published_year, description, store_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer stmt.Close()
+
+	knownAuthors := map[int]bool{}
+	var imported []Book
+	var report ImportReport
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		report.Total++
+		fail := func(title string, err error) {
+			report.Failed++
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Title: title, Error: err.Error()})
+		}
+		if err != nil {
+			fail("", err)
+			continue
+		}
+		book, err := parseImportRow(record, cols)
+		if err != nil {
+			fail(book.Title, err)
+			continue
+		}
+		exists, checked := knownAuthors[book.AuthorID]
+		if !checked {
+			tx.QueryRow("SELECT EXISTS(SELECT 1 FROM authors WHERE id=? AND store_id=? AND deleted_at IS NULL)", book.AuthorID, storeID(c)).Scan(&exists)
+			knownAuthors[book.AuthorID] = exists
+		}
+		if !exists {
+			fail(book.Title, fmt.Errorf("Author ID %d not found", book.AuthorID))
+			continue
+		}
+		res, err := stmt.Exec(book.Title, book.AuthorID, book.ISBN, book.Price, book.Stock, book.PublishedYear, book.Description, storeID(c))
+		if err != nil {
+			fail(book.Title, rowError(err))
+			continue
+		}
+		id, _ := res.LastInsertId()
+		book.ID = int(id)
+		if err := recordMovement(tx, c, book.ID, book.Stock, MovementAdjustment); err != nil {
+			internalError(c, err)
+			return
+		}
+		imported = append(imported, book)
+		report.Imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	// Audit entries are written after commit; SQLite allows a single writer.
+	for i := range imported {
+		countBook(storeID(c), nil, &imported[i])
+		recordAudit(c, "import", "book", imported[i].ID, nil, &imported[i])
+		publishEvent(EventBookCreated, imported[i])
+	}
+	status := http.StatusCreated
+	if report.Imported == 0 {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Loans (library mode) ----------
+
+// A book's lendable_copies are kept apart from its stock for sale. A loan
+// holds one copy until it is returned; checkout fails with 409 once every
+// lendable copy is out.
+
+const (
+	LoanStatusActive   = "active"
+	LoanStatusOverdue  = "overdue"
+	LoanStatusReturned = "returned"
+
+	defaultLoanDays = 14
+	sqlTimeLayout   = "2006-01-02 15:04:05"
+)
+
+type Loan struct {
+	ID           int     `json:"id"`
+	BookID       int     `json:"book_id" binding:"required,gt=0"`
+	BookTitle    string  `json:"book_title"`
+	CustomerID   int     `json:"customer_id" binding:"required,gt=0"`
+	CustomerName string  `json:"customer_name"`
+	Days         int     `json:"days,omitempty" binding:"omitempty,min=1,max=90"`
+	LoanedAt     string  `json:"loaned_at"`
+	DueAt        string  `json:"due_at"`
+	ReturnedAt   *string `json:"returned_at"`
+	Overdue      bool    `json:"overdue"`
+}
+
+type LendingRequest struct {
+	Copies int `json:"copies" binding:"gte=0,max=10000"`
+}
+
+type LendingStatus struct {
+	BookID         int `json:"book_id"`
+	LendableCopies int `json:"lendable_copies"`
+	OnLoan         int `json:"on_loan"`
+	Available      int `json:"available"`
+}
+
+const loanSelect = `SELECT l.id, l.book_id, b.title, l.customer_id, COALESCE(cu.name, ''), l.loaned_at, l.due_at, l.returned_at
+	FROM loans l
+	JOIN books b ON b.id = l.book_id
+	LEFT JOIN customers cu ON cu.id = l.customer_id`
+
+func scanLoan(row interface{ Scan(...any) error }, now string) (Loan, error) {
+	var l Loan
+	err := row.Scan(&l.ID, &l.BookID, &l.BookTitle, &l.CustomerID, &l.CustomerName, &l.LoanedAt, &l.DueAt, &l.ReturnedAt)
+	l.Overdue = l.ReturnedAt == nil && sqlTime(l.DueAt) < now
+	return l, err
+}
+
+// sqlTime brings a scanned timestamp ("2024-05-01T10:00:00Z" from SQLite,
+// "2024-05-01 10:00:00" elsewhere) to the layout used in comparisons.
+func sqlTime(s string) string {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC().Format(sqlTimeLayout)
+	}
+	return s
+}
+
+func lendingStatus(bookID int) (LendingStatus, error) {
+	st := LendingStatus{BookID: bookID}
+	err := db.QueryRow(`SELECT b.lendable_copies,
+		(SELECT COUNT(*) FROM loans l WHERE l.book_id = b.id AND l.returned_at IS NULL)
+		FROM books b WHERE b.id = ?`, bookID).Scan(&st.LendableCopies, &st.OnLoan)
+	st.Available = max(0, st.LendableCopies-st.OnLoan)
+	return st, err
+}
+
+// liveBook resolves :id to a live book of the current store, answering 404
+// itself when there is none.
+func liveBook(c *gin.Context) (int, bool) {
+	id, ok := paramID(c, "id")
+	if ok {
+		_, err := bookRepo.Get(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			internalError(c, err)
+			return 0, false
+		}
+		ok = err == nil
+	}
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+	}
+	return id, ok
+}
+
+func getLending(c *gin.Context) {
+	id, ok := liveBook(c)
+	if !ok {
+		return
+	}
+	st, err := lendingStatus(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
+
+// setLending serves PUT /books/:id/lending, sizing the lending pool. It
+// cannot shrink below the copies currently out on loan.
+func setLending(c *gin.Context) {
+	id, ok := liveBook(c)
+	if !ok {
+		return
+	}
+	var req LendingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	before, err := lendingStatus(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if req.Copies < before.OnLoan {
+		validationError(c, ErrorDetail{Field: "copies", Message: fmt.Sprintf("%d copies are on loan", before.OnLoan)})
+		return
+	}
+	if _, err := db.Exec("UPDATE books SET lendable_copies = ? WHERE id = ?", req.Copies, id); err != nil {
+		internalError(c, err)
+		return
+	}
+	after, _ := lendingStatus(id)
+	recordAudit(c, "lending", "book", id, before, after)
+	c.JSON(http.StatusOK, after)
+}
+
+// checkoutLoan serves POST /loans. The INSERT only happens while a lendable
+// copy is free, so concurrent checkouts cannot overdraw the pool.
+func checkoutLoan(c *gin.Context) {
+	var req Loan
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if !customerExists(req.CustomerID) {
+		validationError(c, ErrorDetail{Field: "customer_id", Message: fmt.Sprintf("customer %d not found", req.CustomerID)})
+		return
+	}
+	if _, err := bookRepo.Get(c.Request.Context(), req.BookID); errors.Is(err, ErrNotFound) {
+		validationError(c, ErrorDetail{Field: "book_id", Message: fmt.Sprintf("book %d not found", req.BookID)})
+		return
+	} else if err != nil {
+		internalError(c, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultLoanDays
+	}
+	now := time.Now().UTC()
+	res, err := db.Exec(`INSERT INTO loans (book_id, customer_id, loaned_at, due_at)
+		SELECT b.id, ?, ?, ? FROM books b
+		WHERE b.id = ? AND b.lendable_copies > (SELECT COUNT(*) FROM loans l WHERE l.book_id = b.id AND l.returned_at IS NULL)`,
+		req.CustomerID, now.Format(sqlTimeLayout), now.AddDate(0, 0, req.Days).Format(sqlTimeLayout), req.BookID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		st, _ := lendingStatus(req.BookID)
+		respondErrorCode(c, http.StatusConflict, CodeNoLendableCopies, "No lendable copies left",
+			ErrorDetail{Field: "book_id", Message: fmt.Sprintf("%d of %d copies are on loan", st.OnLoan, st.LendableCopies)})
+		return
+	}
+	id, _ := res.LastInsertId()
+	loan, err := scanLoan(db.QueryRow(loanSelect+" WHERE l.id = ?", id), now.Format(sqlTimeLayout))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	recordAudit(c, "checkout", "loan", loan.ID, nil, &loan)
+	c.JSON(http.StatusCreated, loan)
+}
+
+func returnLoan(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Loan not found")
+		return
+	}
+	now := time.Now().UTC().Format(sqlTimeLayout)
+	before, err := scanLoan(db.QueryRow(loanSelect+" WHERE l.id = ? AND b.store_id = ?", id, storeID(c)), now)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Loan not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	res, err := db.Exec("UPDATE loans SET returned_at = ? WHERE id = ? AND returned_at IS NULL", now, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusConflict, "Loan was already returned")
+		return
+	}
+	after, _ := scanLoan(db.QueryRow(loanSelect+" WHERE l.id = ?", id), now)
+	recordAudit(c, "return", "loan", id, &before, &after)
+	c.JSON(http.StatusOK, after)
+}
+
+// getLoans serves GET /loans?status=active|overdue|returned&customer_id=
+// &book_id=. Without status it lists loans still out, overdue first.
+func getLoans(c *gin.Context) {
+	now := time.Now().UTC().Format(sqlTimeLayout)
+	q, args := loanSelect+" WHERE b.store_id = ?", []any{storeID(c)}
+	switch status := c.DefaultQuery("status", LoanStatusActive); status {
+	case LoanStatusActive:
+		q += " AND l.returned_at IS NULL"
+	case LoanStatusOverdue:
+		q += " AND l.returned_at IS NULL AND l.due_at < ?"
+		args = append(args, now)
+	case LoanStatusReturned:
+		q += " AND l.returned_at IS NOT NULL"
+	default:
+		validationError(c, ErrorDetail{Field: "status", Message: "must be one of: active, overdue, returned"})
+		return
+	}
+	if v := parseIntQuery(c, "customer_id", 0); v > 0 {
+		q += " AND l.customer_id = ?"
+		args = append(args, v)
+	}
+	if v := parseIntQuery(c, "book_id", 0); v > 0 {
+		q += " AND l.book_id = ?"
+		args = append(args, v)
+	}
+	rows, err := db.Query(q+" ORDER BY l.due_at, l.id", args...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	loans := []Loan{}
+	for rows.Next() {
+		l, err := scanLoan(rows, now)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		loans = append(loans, l)
+	}
+	c.JSON(http.StatusOK, gin.H{"loans": loans, "count": len(loans)})
+}
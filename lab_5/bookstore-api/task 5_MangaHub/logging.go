@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Request Logging ----------
+
+const requestIDHeader = "X-Request-ID"
+
+var accessLog = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// newRequestID returns a random (version 4) UUID.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestID returns the ID assigned to the current request by requestLogger.
+func requestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
+// requestLogger assigns every request an ID (reusing a caller-supplied
+// X-Request-ID), echoes it in the response and writes one JSON log line
+// per request.
+func requestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		c.Set("request_id", id)
+		c.Header(requestIDHeader, id)
+
+		c.Next()
+
+		attrs := []any{
+			"request_id", id,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"query", c.Request.URL.RawQuery,
+			"status", c.Writer.Status(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		accessLog.Info("request", attrs...)
+	}
+}
+
+// internalError logs a database/server failure tagged with the request ID and
+// responds 500, so the client can quote the ID when reporting the problem.
+// The error itself stays in the log.
+func internalError(c *gin.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		timedOut(c)
+		return
+	}
+	accessLog.Error("internal error",
+		"request_id", requestID(c),
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"error", err.Error(),
+	)
+	respondError(c, http.StatusInternalServerError, "Internal server error")
+}
@@ -2,18 +2,23 @@ package main
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/gin-gonic/gin/binding"
 )
 
-var db *sql.DB
+var db *Store
 
 // ---------- Structs ----------
 
@@ -35,6 +40,7 @@ type Book struct {
 	Stock         int     `json:"stock" binding:"gte=0"`
 	PublishedYear int     `json:"published_year"`
 	Description   string  `json:"description"`
+	Version       int     `json:"version"`
 }
 
 type BookWithAuthor struct {
@@ -57,6 +63,17 @@ type PaginatedBooksResponse struct {
 	Pagination PaginationMeta   `json:"pagination"`
 }
 
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+}
+
+type CursorBooksResponse struct {
+	Books      []BookWithAuthor `json:"books"`
+	Pagination CursorMeta       `json:"pagination"`
+}
+
 type Statistics struct {
 	TotalBooks    int             `json:"total_books"`
 	TotalAuthors  int             `json:"total_authors"`
@@ -70,6 +87,19 @@ type Statistics struct {
 	AveragePrice  float64         `json:"average_price"`
 }
 
+// BookPatch is the body of PATCH /books/:id; fields left out keep their
+// current value.
+type BookPatch struct {
+	Title         *string  `json:"title"`
+	AuthorID      *int     `json:"author_id"`
+	ISBN          *string  `json:"isbn"`
+	Price         *float64 `json:"price"`
+	Stock         *int     `json:"stock"`
+	PublishedYear *int     `json:"published_year"`
+	Description   *string  `json:"description"`
+	Version       int      `json:"version"`
+}
+
 type RestockRequest struct {
 	Quantity int `json:"quantity" binding:"required,gt=0"`
 }
@@ -91,43 +121,27 @@ type BulkCreateResponse struct {
 
 // ---------- Database Init ----------
 
-func initDB() {
+// initDB opens the store and applies any pending migrations; the schema
+// itself lives in migrations/.
+func initDB(driver, dsn string) {
+	openDB(driver, dsn)
+	if err := migrateUp(); err != nil {
+		log.Fatalf("schema: %v", err)
+	}
+}
+
+func openDB(driver, dsn string) {
 	var err error
-	db, err = sql.Open("sqlite3", "./bookstore.db")
+	db, err = openStore(driver, dsn)
 	if err != nil {
 		log.Fatal(err)
 	}
+	authorRepo, bookRepo = newSQLAuthorRepository(db), newSQLBookRepository(db)
 
 	// Enable foreign keys
-	db.Exec("PRAGMA foreign_keys = ON;")
-
-	// Create authors table
-	createAuthorsSQL := `
-	CREATE TABLE IF NOT EXISTS authors (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL UNIQUE,
-		bio TEXT,
-		birth_year INTEGER,
-		country TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-	db.Exec(createAuthorsSQL)
-
-	// Create books table
-	createBooksSQL := `
-	CREATE TABLE IF NOT EXISTS books (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		title TEXT NOT NULL,
-		author_id INTEGER,
-		isbn TEXT NOT NULL,
-		price REAL NOT NULL,
-		stock INTEGER NOT NULL,
-		published_year INTEGER,
-		description TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY(author_id) REFERENCES authors(id) ON DELETE SET NULL
-	);`
-	db.Exec(createBooksSQL)
+	if db.Dialect == DialectSQLite {
+		db.Exec("PRAGMA foreign_keys = ON;")
+	}
 }
 
 // ---------- Helpers ----------
@@ -144,15 +158,44 @@ func parseIntQuery(c *gin.Context, key string, defaultValue int) int {
 	return value
 }
 
-func validateISBN(isbn string) error {
-	isbn = regexp.MustCompile(`-`).ReplaceAllString(isbn, "")
-	if len(isbn) != 10 && len(isbn) != 13 {
-		return fmt.Errorf("ISBN must be 10 or 13 digits")
+// normalizeISBN verifies the check digit of an ISBN-10 or ISBN-13 (hyphens
+// and spaces ignored) and returns it as a bare 13-digit ISBN.
+func normalizeISBN(isbn string) (string, error) {
+	isbn = regexp.MustCompile(`[-\s]`).ReplaceAllString(strings.ToUpper(isbn), "")
+	switch {
+	case regexp.MustCompile(`^\d{9}[\dX]$`).MatchString(isbn):
+		sum := 0
+		for i := 0; i < 10; i++ {
+			digit := int(isbn[i] - '0')
+			if isbn[i] == 'X' {
+				digit = 10
+			}
+			sum += digit * (10 - i)
+		}
+		if sum%11 != 0 {
+			return "", fmt.Errorf("invalid ISBN-10 check digit")
+		}
+		isbn13 := "978" + isbn[:9]
+		return isbn13 + isbn13CheckDigit(isbn13), nil
+	case regexp.MustCompile(`^\d{13}$`).MatchString(isbn):
+		if isbn13CheckDigit(isbn[:12]) != isbn[12:] {
+			return "", fmt.Errorf("invalid ISBN-13 check digit")
+		}
+		return isbn, nil
 	}
-	if !regexp.MustCompile(`^\d+$`).MatchString(isbn) {
-		return fmt.Errorf("ISBN must contain only digits")
+	return "", fmt.Errorf("ISBN must be 10 or 13 digits")
+}
+
+func isbn13CheckDigit(first12 string) string {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(first12[i]-'0') * weight
 	}
-	return nil
+	return strconv.Itoa((10 - sum%10) % 10)
 }
 
 func validatePublishedYear(year int) error {
@@ -165,29 +208,35 @@ func validatePublishedYear(year int) error {
 
 // ---------- Author Endpoints ----------
 
+// paramID parses an :id style path parameter. Ids that are not positive
+// integers cannot match a row, so callers answer them with 404.
+func paramID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	return id, err == nil && id > 0
+}
+
 func getAuthors(c *gin.Context) {
-	rows, err := db.Query("SELECT id, name, bio, birth_year, country, created_at FROM authors")
+	authors, err := authorRepo.List(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
-	defer rows.Close()
-	authors := []Author{}
-	for rows.Next() {
-		var a Author
-		rows.Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt)
-		authors = append(authors, a)
-	}
 	c.JSON(http.StatusOK, authors)
 }
 
 func getAuthor(c *gin.Context) {
-	id := c.Param("id")
-	var a Author
-	err := db.QueryRow("SELECT id, name, bio, birth_year, country, created_at FROM authors WHERE id = ?", id).
-		Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt)
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
+	a, err := authorRepo.Get(c.Request.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		internalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, a)
@@ -196,59 +245,92 @@ func getAuthor(c *gin.Context) {
 func createAuthor(c *gin.Context) {
 	var a Author
 	if err := c.ShouldBindJSON(&a); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
-	res, err := db.Exec("INSERT INTO authors (name, bio, birth_year, country) VALUES (?, ?, ?, ?)",
-		a.Name, a.Bio, a.BirthYear, a.Country)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := authorRepo.Create(c.Request.Context(), &a); err != nil {
+		storeError(c, err)
 		return
 	}
-	id, _ := res.LastInsertId()
-	a.ID = int(id)
+	countAuthors(storeID(c), 1)
+	recordAudit(c, "create", "author", a.ID, nil, &a)
 	c.JSON(http.StatusCreated, a)
 }
 
 func updateAuthor(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
 	var a Author
 	if err := c.ShouldBindJSON(&a); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	before, err := authorRepo.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Author not found")
 		return
 	}
-	_, err := db.Exec("UPDATE authors SET name=?, bio=?, birth_year=?, country=? WHERE id=?",
-		a.Name, a.Bio, a.BirthYear, a.Country, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
+		return
+	}
+	a.ID = id
+	if err := authorRepo.Update(ctx, &a); err != nil {
+		internalError(c, err)
 		return
 	}
+	a.CreatedAt = before.CreatedAt
+	recordAudit(c, "update", "author", id, before, &a)
 	c.JSON(http.StatusOK, a)
 }
 
 func deleteAuthor(c *gin.Context) {
-	id := c.Param("id")
-	var bookCount int
-	db.QueryRow("SELECT COUNT(*) FROM books WHERE author_id = ?", id).Scan(&bookCount)
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
+	ctx := c.Request.Context()
+	bookCount, err := bookRepo.CountByAuthor(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
 	if bookCount > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot delete author with existing books", "book_count": bookCount})
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("Cannot delete author with %d existing books", bookCount))
 		return
 	}
-	db.Exec("DELETE FROM authors WHERE id = ?", id)
-	c.JSON(http.StatusOK, gin.H{"message": "Author deleted"})
+	before, err := authorRepo.Get(ctx, id)
+	if err == nil {
+		err = authorRepo.Delete(ctx, id)
+	}
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	countAuthors(storeID(c), -1)
+	recordAudit(c, "delete", "author", id, before, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Author moved to trash"})
 }
 
 func getAuthorBooks(c *gin.Context) {
-	id := c.Param("id")
-	rows, _ := db.Query(`
-		SELECT b.id, b.title, b.author_id, a.name, b.isbn, b.price, b.stock, b.published_year, b.description
-		FROM books b LEFT JOIN authors a ON b.author_id = a.id WHERE b.author_id=?`, id)
-	defer rows.Close()
-	books := []BookWithAuthor{}
-	for rows.Next() {
-		var b BookWithAuthor
-		rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description)
-		books = append(books, b)
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
+	books, err := bookRepo.ListByAuthor(c.Request.Context(), id)
+	if err != nil {
+		internalError(c, err)
+		return
 	}
 	c.JSON(http.StatusOK, gin.H{"author_id": id, "books": books, "count": len(books)})
 }
@@ -264,55 +346,374 @@ func getBooksPaginated(c *gin.Context) {
 	if limit < 1 || limit > 100 {
 		limit = 20
 	}
+	fields, err := parseBookFields(c)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "fields", Message: err.Error()})
+		return
+	}
+	tagCond, tagArgs, err := bookTagFilter(c)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "tags", Message: err.Error()})
+		return
+	}
+	where := "WHERE b.store_id = ? AND b.deleted_at IS NULL" + tagCond
+	args := append([]any{storeID(c)}, tagArgs...)
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		getBooksByCursor(c, cursor, limit, fields, where, args)
+		return
+	}
+	ctx := c.Request.Context()
 	offset := (page - 1) * limit
 	var total int
-	db.QueryRow("SELECT COUNT(*) FROM books").Scan(&total)
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books b "+where, args...).Scan(&total); err != nil {
+		internalError(c, err)
+		return
+	}
+	totalPages := (total + limit - 1) / limit
+	pagination := PaginationMeta{
+		Page: page, Limit: limit, Total: total, TotalPages: totalPages, HasNext: page < totalPages, HasPrev: page > 1,
+	}
+
+	if fields != nil {
+		books, _, err := queryBookFields(ctx, fields, where+" ORDER BY b.id LIMIT ? OFFSET ?", append(args, limit, offset)...)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"books": books, "pagination": pagination})
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, `
+	SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+		COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
+	FROM books b LEFT JOIN authors a ON b.author_id = a.id
+	`+where+`
+	ORDER BY b.id LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+
+	books, err := scanBookList(rows)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedBooksResponse{Books: books, Pagination: pagination})
+}
+
+// getBooksByCursor serves GET /books?cursor=... with keyset pagination on id,
+// which stays fast on deep pages. An empty cursor starts from the beginning;
+// next_cursor is omitted on the last page. where and args hold the filters
+// shared with page/limit.
+func getBooksByCursor(c *gin.Context, cursor string, limit int, fields []string, where string, args []any) {
+	afterID := 0
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err == nil {
+			afterID, err = strconv.Atoi(strings.TrimPrefix(string(raw), "id:"))
+		}
+		if err != nil || afterID < 0 {
+			respondError(c, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	if fields != nil {
+		books, ids, err := queryBookFields(c.Request.Context(), fields, where+" AND b.id > ? ORDER BY b.id LIMIT ?", append(args, afterID, limit+1)...)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		meta := CursorMeta{Limit: limit}
+		if len(books) > limit {
+			books = books[:limit]
+			meta.HasNext = true
+			meta.NextCursor = encodeBookCursor(ids[limit-1])
+		}
+		c.JSON(http.StatusOK, gin.H{"books": books, "pagination": meta})
+		return
+	}
 
-	rows, _ := db.Query(`
-	SELECT b.id, b.title, b.author_id, a.name, b.isbn, b.price, b.stock, b.published_year, b.description
+	rows, err := db.QueryContext(c.Request.Context(), `
+	SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+		COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
 	FROM books b LEFT JOIN authors a ON b.author_id = a.id
-	ORDER BY b.id LIMIT ? OFFSET ?`, limit, offset)
+	`+where+` AND b.id > ?
+	ORDER BY b.id LIMIT ?`, append(args, afterID, limit+1)...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
 	defer rows.Close()
 
+	books, err := scanBookList(rows)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	meta := CursorMeta{Limit: limit}
+	if len(books) > limit {
+		books = books[:limit]
+		meta.HasNext = true
+		meta.NextCursor = encodeBookCursor(books[limit-1].ID)
+	}
+	c.JSON(http.StatusOK, CursorBooksResponse{Books: books, Pagination: meta})
+}
+
+// scanBookList reads the rows of a GET /books listing query.
+func scanBookList(rows *sql.Rows) ([]BookWithAuthor, error) {
 	books := []BookWithAuthor{}
 	for rows.Next() {
 		var b BookWithAuthor
-		rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description)
+		if err := rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version); err != nil {
+			return nil, err
+		}
 		books = append(books, b)
 	}
+	return books, rows.Err()
+}
 
-	totalPages := (total + limit - 1) / limit
-	pagination := PaginationMeta{
-		Page: page, Limit: limit, Total: total, TotalPages: totalPages, HasNext: page < totalPages, HasPrev: page > 1,
+func encodeBookCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("id:" + strconv.Itoa(id)))
+}
+
+// getBook serves GET /books/:id joined with its author; ?fields= limits the
+// columns returned, as on GET /books.
+func getBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
 	}
-	c.JSON(http.StatusOK, PaginatedBooksResponse{Books: books, Pagination: pagination})
+	fields, err := parseBookFields(c)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "fields", Message: err.Error()})
+		return
+	}
+	if fields != nil {
+		books, _, err := queryBookFields(c.Request.Context(), fields, "WHERE b.id = ? AND b.store_id = ? AND b.deleted_at IS NULL", id, storeID(c))
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		if len(books) == 0 {
+			respondError(c, http.StatusNotFound, "Book not found")
+			return
+		}
+		c.JSON(http.StatusOK, books[0])
+		return
+	}
+
+	b, err := bookRepo.Get(c.Request.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
 }
 
 func createBookEnhanced(c *gin.Context) {
 	var book Book
 	if err := c.ShouldBindJSON(&book); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
-	if err := validateISBN(book.ISBN); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !checkBook(c, &book) {
 		return
 	}
+	if err := bookRepo.Create(c.Request.Context(), &book); err != nil {
+		storeError(c, err)
+		return
+	}
+	logMovement(c, book.ID, book.Stock, MovementAdjustment)
+	countBook(storeID(c), nil, &book)
+	recordAudit(c, "create", "book", book.ID, nil, &book)
+	publishEvent(EventBookCreated, book)
+	c.JSON(http.StatusCreated, book)
+}
+
+// checkBook applies the rules binding tags cannot express: a valid ISBN
+// (normalized in place) not used by another book, a plausible year and an
+// existing author. It has answered the request when it returns false.
+func checkBook(c *gin.Context, book *Book) bool {
+	isbn, err := normalizeISBN(book.ISBN)
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "isbn", Message: err.Error()})
+		return false
+	}
+	book.ISBN = isbn
 	if err := validatePublishedYear(book.PublishedYear); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		validationError(c, ErrorDetail{Field: "published_year", Message: err.Error()})
+		return false
+	}
+	if _, err := authorRepo.Get(c.Request.Context(), book.AuthorID); errors.Is(err, ErrNotFound) {
+		validationError(c, ErrorDetail{Field: "author_id", Message: fmt.Sprintf("author %d not found", book.AuthorID)})
+		return false
+	} else if err != nil {
+		internalError(c, err)
+		return false
+	}
+	if existing, err := findDuplicateISBN(c.Request.Context(), book.ISBN, book.ID); err != nil {
+		internalError(c, err)
+		return false
+	} else if existing != 0 {
+		duplicateISBN(c, existing, book.ISBN)
+		return false
+	}
+	return true
+}
+
+// ---------- Book Updates (optimistic locking) ----------
+
+// Every write to a book bumps its version. PUT and PATCH must say which
+// version they were based on, so an edit made from a stale copy answers 409
+// instead of silently overwriting someone else's change.
+
+// updateBook serves PUT /books/:id, replacing every editable field.
+func updateBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
 		return
 	}
-	var exists bool
-	db.QueryRow("SELECT EXISTS(SELECT 1 FROM authors WHERE id=?)", book.AuthorID).Scan(&exists)
-	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Author ID %d not found", book.AuthorID)})
+	var book Book
+	if err := c.ShouldBindJSON(&book); err != nil {
+		bindError(c, err)
 		return
 	}
-	res, _ := db.Exec(`INSERT INTO books (title, author_id, isbn, price, stock, published_year, description)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`, book.Title, book.AuthorID, book.ISBN, book.Price, book.Stock, book.PublishedYear, book.Description)
-	id, _ := res.LastInsertId()
-	book.ID = int(id)
-	c.JSON(http.StatusCreated, book)
+	version, ok := expectedVersion(c, book.Version)
+	if !ok {
+		return
+	}
+	saveBook(c, id, version, func(b *Book) {
+		book.ID, book.Version = b.ID, b.Version
+		*b = book
+	})
+}
+
+// patchBook serves PATCH /books/:id, changing only the fields sent.
+func patchBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	var patch BookPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		bindError(c, err)
+		return
+	}
+	version, ok := expectedVersion(c, patch.Version)
+	if !ok {
+		return
+	}
+	saveBook(c, id, version, func(b *Book) {
+		if patch.Title != nil {
+			b.Title = *patch.Title
+		}
+		if patch.AuthorID != nil {
+			b.AuthorID = *patch.AuthorID
+		}
+		if patch.ISBN != nil {
+			b.ISBN = *patch.ISBN
+		}
+		if patch.Price != nil {
+			b.Price = *patch.Price
+		}
+		if patch.Stock != nil {
+			b.Stock = *patch.Stock
+		}
+		if patch.PublishedYear != nil {
+			b.PublishedYear = *patch.PublishedYear
+		}
+		if patch.Description != nil {
+			b.Description = *patch.Description
+		}
+	})
+}
+
+// expectedVersion reads the version an update was based on from If-Match
+// ("3", W/"3" or 3), falling back to the body's version field.
+func expectedVersion(c *gin.Context, bodyVersion int) (int, bool) {
+	if header := c.GetHeader("If-Match"); header != "" {
+		v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`))
+		if err != nil || v < 1 {
+			validationError(c, ErrorDetail{Field: "If-Match", Message: "must be the book's version number"})
+			return 0, false
+		}
+		return v, true
+	}
+	if bodyVersion > 0 {
+		return bodyVersion, true
+	}
+	respondError(c, http.StatusPreconditionRequired, "Send the version being edited in If-Match or the request body",
+		ErrorDetail{Field: "version", Message: "is required"})
+	return 0, false
+}
+
+// saveBook applies edit to the current row and writes it back only if the
+// row is still at version.
+func saveBook(c *gin.Context, id, version int, edit func(*Book)) {
+	current, err := bookRepo.Get(c.Request.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if current.Version != version {
+		versionConflict(c, current.Version)
+		return
+	}
+	before := current.Book
+	book := current.Book
+	edit(&book)
+	if err := binding.Validator.ValidateStruct(&book); err != nil {
+		bindError(c, err)
+		return
+	}
+	if !checkBook(c, &book) {
+		return
+	}
+	err = bookRepo.Update(c.Request.Context(), &book)
+	if errors.Is(err, ErrVersionConflict) {
+		// Someone else wrote between our read and this update.
+		latest, _ := bookRepo.Get(c.Request.Context(), id)
+		if latest == nil {
+			respondError(c, http.StatusNotFound, "Book not found")
+			return
+		}
+		versionConflict(c, latest.Version)
+		return
+	}
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	logMovement(c, id, book.Stock-before.Stock, MovementAdjustment)
+	countBook(storeID(c), &before, &book)
+	recordAudit(c, "update", "book", id, &before, &book)
+	notifyBackInStock(&book, before.Stock)
+	c.JSON(http.StatusOK, book)
+}
+
+func versionConflict(c *gin.Context, current int) {
+	respondErrorCode(c, http.StatusConflict, CodeVersionConflict, "Book was modified by someone else; reload it and retry",
+		ErrorDetail{Field: "version", Message: fmt.Sprintf("current version is %d", current)})
 }
 
 // ---------- Inventory Endpoints ----------
@@ -321,37 +722,98 @@ func restockBook(c *gin.Context) {
 	id := c.Param("id")
 	var req RestockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		internalError(c, err)
 		return
 	}
-	res, _ := db.Exec("UPDATE books SET stock = stock + ? WHERE id = ?", req.Quantity, id)
-	ra, _ := res.RowsAffected()
-	if ra == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, "UPDATE books SET stock = stock + ?, version = version + 1 WHERE id = ? AND store_id = ? AND deleted_at IS NULL", req.Quantity, id, storeID(c))
+	if err != nil {
+		internalError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Book restocked"})
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	after := snapshotBookFrom(tx, id)
+	if err := recordMovement(tx, c, after.ID, req.Quantity, MovementRestock); err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	before := *after
+	before.Stock -= req.Quantity
+	countBook(storeID(c), &before, after)
+	recordAudit(c, "restock", "book", after.ID, &before, after)
+	notifyBackInStock(after, before.Stock)
+	c.JSON(http.StatusOK, gin.H{"message": "Book restocked", "stock": after.Stock})
 }
 
+// sellBook decrements stock with a single conditional UPDATE so concurrent
+// sales can never take stock below zero.
 func sellBook(c *gin.Context) {
 	id := c.Param("id")
 	var req SellRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
-	var stock int
-	err := db.QueryRow("SELECT stock FROM books WHERE id=?", id).Scan(&stock)
+	ctx := c.Request.Context()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		internalError(c, err)
 		return
 	}
-	if stock < req.Quantity {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient stock", "available": stock})
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, "UPDATE books SET stock = stock - ?, version = version + 1 WHERE id = ? AND store_id = ? AND deleted_at IS NULL AND stock >= ?", req.Quantity, id, storeID(c), req.Quantity)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		var stock int
+		if err := tx.QueryRowContext(ctx, "SELECT stock FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NULL", id, storeID(c)).Scan(&stock); err != nil {
+			respondError(c, http.StatusNotFound, "Book not found")
+			return
+		}
+		respondErrorCode(c, http.StatusBadRequest, CodeInsufficientStock, "Insufficient stock",
+			ErrorDetail{Field: "quantity", Message: fmt.Sprintf("only %d available", stock)})
 		return
 	}
-	db.Exec("UPDATE books SET stock = stock - ? WHERE id=?", req.Quantity, id)
-	c.JSON(http.StatusOK, gin.H{"message": "Book sold"})
+	after := snapshotBookFrom(tx, id)
+	sale, err := tx.ExecContext(ctx, "INSERT INTO sales (book_id, quantity, unit_price, total) VALUES (?, ?, ?, ?)",
+		after.ID, req.Quantity, after.Price, after.Price*float64(req.Quantity))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	saleID, _ := sale.LastInsertId()
+	if err := recordMovement(tx, c, after.ID, -req.Quantity, MovementSell); err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	before := *after
+	before.Stock += req.Quantity
+	countBook(storeID(c), &before, after)
+	recordAudit(c, "sell", "book", after.ID, &before, after)
+	publishEvent(EventBookSold, gin.H{"book_id": after.ID, "title": after.Title, "quantity": req.Quantity, "stock": after.Stock})
+	if after.Stock < lowStockThreshold {
+		publishEvent(EventStockLow, gin.H{"book_id": after.ID, "title": after.Title, "stock": after.Stock, "threshold": lowStockThreshold})
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Book sold", "stock": after.Stock, "sale_id": saleID})
 }
 
 // ---------- Bulk Create ----------
@@ -359,25 +821,33 @@ func sellBook(c *gin.Context) {
 func createBulkBooks(c *gin.Context) {
 	var req BulkCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
+	ctx := c.Request.Context()
 	var resp BulkCreateResponse
 	for _, book := range req.Books {
-		if err := validateISBN(book.ISBN); err != nil {
+		isbn, err := normalizeISBN(book.ISBN)
+		if err != nil {
 			resp.Failed++
 			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", book.Title, err))
 			continue
 		}
-		res, err := db.Exec(`INSERT INTO books (title, author_id, isbn, price, stock, published_year, description)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`, book.Title, book.AuthorID, book.ISBN, book.Price, book.Stock, book.PublishedYear, book.Description)
-		if err != nil {
+		book.ISBN = isbn
+		if _, err := authorRepo.Get(ctx, book.AuthorID); err != nil {
 			resp.Failed++
-			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", book.Title, err))
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: author %d not found", book.Title, book.AuthorID))
+			continue
+		}
+		if err := bookRepo.Create(ctx, &book); err != nil {
+			resp.Failed++
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", book.Title, rowError(err)))
 			continue
 		}
-		id, _ := res.LastInsertId()
-		book.ID = int(id)
+		logMovement(c, book.ID, book.Stock, MovementAdjustment)
+		countBook(storeID(c), nil, &book)
+		recordAudit(c, "create", "book", book.ID, nil, &book)
+		publishEvent(EventBookCreated, book)
 		resp.CreatedBooks = append(resp.CreatedBooks, book)
 		resp.Success++
 	}
@@ -386,69 +856,146 @@ func createBulkBooks(c *gin.Context) {
 
 // ---------- Statistics ----------
 
-func getStatistics(c *gin.Context) {
+// computeStatistics scans a store's catalog in full. GET /stats only runs it
+// to load its counters (see counters.go); the snapshot job and the dashboard
+// call it directly. Any failed query fails the whole scan, so callers never
+// mistake a database error for an empty catalog.
+func computeStatistics(store int) (Statistics, error) {
 	var stats Statistics
-	db.QueryRow("SELECT COUNT(*) FROM books").Scan(&stats.TotalBooks)
-	db.QueryRow("SELECT COUNT(*) FROM authors").Scan(&stats.TotalAuthors)
-	db.QueryRow("SELECT SUM(price*stock) FROM books").Scan(&stats.TotalValue)
-	db.QueryRow("SELECT COUNT(*) FROM books WHERE stock < 10 AND stock > 0").Scan(&stats.LowStock)
-	db.QueryRow("SELECT COUNT(*) FROM books WHERE stock = 0").Scan(&stats.OutOfStock)
-	db.QueryRow("SELECT AVG(price) FROM books").Scan(&stats.AveragePrice)
-
-	// Most expensive
-	row := db.QueryRow(`
-	SELECT b.id, b.title, b.author_id, a.name, b.isbn, b.price, b.stock, b.published_year, b.description
-	FROM books b LEFT JOIN authors a ON b.author_id = a.id
-	ORDER BY b.price DESC LIMIT 1`)
-	var me BookWithAuthor
-	row.Scan(&me.ID, &me.Title, &me.AuthorID, &me.AuthorName, &me.ISBN, &me.Price, &me.Stock, &me.PublishedYear, &me.Description)
-	stats.MostExpensive = &me
-
-	// Cheapest
-	row = db.QueryRow(`
-	SELECT b.id, b.title, b.author_id, a.name, b.isbn, b.price, b.stock, b.published_year, b.description
-	FROM books b LEFT JOIN authors a ON b.author_id = a.id
-	ORDER BY b.price ASC LIMIT 1`)
-	var ch BookWithAuthor
-	row.Scan(&ch.ID, &ch.Title, &ch.AuthorID, &ch.AuthorName, &ch.ISBN, &ch.Price, &ch.Stock, &ch.PublishedYear, &ch.Description)
-	stats.Cheapest = &ch
-
-	// Most stocked
-	row = db.QueryRow(`
-	SELECT b.id, b.title, b.author_id, a.name, b.isbn, b.price, b.stock, b.published_year, b.description
-	FROM books b LEFT JOIN authors a ON b.author_id = a.id
-	ORDER BY b.stock DESC LIMIT 1`)
-	var ms BookWithAuthor
-	row.Scan(&ms.ID, &ms.Title, &ms.AuthorID, &ms.AuthorName, &ms.ISBN, &ms.Price, &ms.Stock, &ms.PublishedYear, &ms.Description)
-	stats.MostStocked = &ms
+	for _, q := range []struct {
+		query string
+		args  []any
+		dest  any
+	}{
+		{"SELECT COUNT(*) FROM books WHERE store_id = ? AND deleted_at IS NULL", nil, &stats.TotalBooks},
+		{"SELECT COUNT(*) FROM authors WHERE store_id = ? AND deleted_at IS NULL", nil, &stats.TotalAuthors},
+		{"SELECT COALESCE(SUM(price*stock), 0) FROM books WHERE store_id = ? AND deleted_at IS NULL", nil, &stats.TotalValue},
+		{"SELECT COUNT(*) FROM books WHERE store_id = ? AND stock < ? AND stock > 0 AND deleted_at IS NULL", []any{lowStockThreshold}, &stats.LowStock},
+		{"SELECT COUNT(*) FROM books WHERE store_id = ? AND stock = 0 AND deleted_at IS NULL", nil, &stats.OutOfStock},
+		{"SELECT COALESCE(AVG(price), 0) FROM books WHERE store_id = ? AND deleted_at IS NULL", nil, &stats.AveragePrice},
+	} {
+		if err := db.QueryRow(q.query, append([]any{store}, q.args...)...).Scan(q.dest); err != nil {
+			return Statistics{}, err
+		}
+	}
+
+	// Most expensive, cheapest and most stocked; all zero for an empty store
+	for _, x := range []struct {
+		order string
+		dest  **BookWithAuthor
+	}{
+		{"b.price DESC", &stats.MostExpensive},
+		{"b.price ASC", &stats.Cheapest},
+		{"b.stock DESC", &stats.MostStocked},
+	} {
+		var b BookWithAuthor
+		err := db.QueryRow(`
+		SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+			COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
+		FROM books b LEFT JOIN authors a ON b.author_id = a.id
+		WHERE b.store_id = ? AND b.deleted_at IS NULL
+		ORDER BY `+x.order+` LIMIT 1`, store).
+			Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return Statistics{}, err
+		}
+		*x.dest = &b
+	}
 
 	stats.BooksByYear = make(map[int]int)
-	rows, _ := db.Query("SELECT published_year, COUNT(*) FROM books GROUP BY published_year")
+	rows, err := db.Query("SELECT COALESCE(published_year, 0), COUNT(*) FROM books WHERE store_id = ? AND deleted_at IS NULL GROUP BY published_year", store)
+	if err != nil {
+		return Statistics{}, err
+	}
 	defer rows.Close()
 	for rows.Next() {
 		var year, count int
-		rows.Scan(&year, &count)
+		if err := rows.Scan(&year, &count); err != nil {
+			return Statistics{}, err
+		}
 		stats.BooksByYear[year] = count
 	}
-
-	c.JSON(http.StatusOK, stats)
+	if err := rows.Err(); err != nil {
+		return Statistics{}, err
+	}
+	return stats, nil
 }
 
 // ---------- API Documentation ----------
 
 func getAPIDocumentation(c *gin.Context) {
-	docs := gin.H{"message": "Bookstore API - full endpoints"}
-	c.JSON(http.StatusOK, docs)
+	c.JSON(http.StatusOK, openAPISpec)
 }
 
 // ---------- Main ----------
 
 func main() {
-	initDB()
-	router := gin.Default()
+	driver := flag.String("driver", os.Getenv("DB_DRIVER"), "database driver: sqlite, mysql or postgres (env DB_DRIVER)")
+	dsn := flag.String("dsn", os.Getenv("DB_DSN"), "data source name, defaults to ./bookstore.db for sqlite (env DB_DSN)")
+	migrate := flag.String("migrate", "", "run migrations and exit: up, down or status")
+	steps := flag.Int("steps", 1, "number of migrations to revert with -migrate down")
+	seed := flag.Int("seed", 0, "generate this many fake books (and authors) and exit")
+	seedStore := flag.Int("seed-store", 1, "store that -seed fills")
+	flag.Parse()
+
+	if *migrate != "" {
+		openDB(*driver, *dsn)
+		if err := runMigrateCommand(*migrate, *steps); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	initDB(*driver, *dsn)
+	if *seed > 0 {
+		authors, books, err := seedCatalog(*seed, *seedStore)
+		if err != nil {
+			log.Fatalf("seed: %v", err)
+		}
+		log.Printf("seed: added %d authors and %d books to store %d", authors, books, *seedStore)
+		return
+	}
+	startWebhookDispatcher()
+	startAlertScheduler()
+	startStockNotifier()
+	startStatsSnapshotScheduler()
+	handler := newRouter()
+
+	fmt.Println("🚀 Bookstore API running on :8080")
+	serve(handler, ":8080")
+}
+
+// newRouter wires every route and middleware against the open db. It starts
+// no background jobs, so an httptest.Server can drive it against a
+// throwaway database.
+func newRouter() http.Handler {
+	useJSONFieldNames()
+	router := gin.New()
+	// lets *gin.Context (e.g. GraphQL resolver contexts) see the store
+	// scoped into the request context
+	router.ContextWithFallback = true
+	router.Use(requestLogger(), gin.CustomRecovery(recoverPanic), gzipMiddleware(), timeoutMiddleware(requestTimeout))
+	router.NoRoute(notFoundRoute)
+
+	// Health probes are registered before the rate limiter so load balancers
+	// polling them never get throttled
+	router.GET("/healthz", healthz)
+	router.GET("/readyz", readyz)
+
+	// Rate limits (requests per minute / burst), bulk endpoints are stricter
+	defaultLimiter := newRateLimiter(envInt("RATE_LIMIT_RPM", 300), envInt("RATE_LIMIT_BURST", 60))
+	bulkLimiter := newRateLimiter(envInt("BULK_RATE_LIMIT_RPM", 10), envInt("BULK_RATE_LIMIT_BURST", 3))
+	router.Use(defaultLimiter.middleware())
+	router.Use(storeScope())
+	router.Use(readCache.invalidateOnWrite())
+
+	// Stores
+	router.GET("/stores", getStores)
+	router.GET("/stores/:id", getStore)
+	router.POST("/stores", createStore)
 
 	// Authors
-	router.GET("/authors", getAuthors)
+	router.GET("/authors", readCache.middleware(), getAuthors)
 	router.GET("/authors/:id", getAuthor)
 	router.POST("/authors", createAuthor)
 	router.PUT("/authors/:id", updateAuthor)
@@ -456,18 +1003,110 @@ func main() {
 	router.GET("/authors/:id/books", getAuthorBooks)
 
 	// Books
-	router.GET("/books", getBooksPaginated)
-	router.POST("/books", createBookEnhanced)
+	router.GET("/books", etagMiddleware(), readCache.middleware(), getBooksPaginated)
+	router.GET("/books/suggest", readCache.middleware(), suggestBooks)
+	router.GET("/books/search", readCache.middleware(), searchBooks)
+	router.GET("/books/:id", etagMiddleware(), readCache.middleware(), getBook)
+	router.POST("/books", idempotent(), createBookEnhanced)
+	router.POST("/books/lookup/:isbn", lookupBook)
 	router.POST("/books/:id/restock", restockBook)
-	router.POST("/books/:id/sell", sellBook)
-	router.POST("/books/bulk", createBulkBooks)
+	router.POST("/books/:id/return", returnBook)
+	router.GET("/books/:id/movements", getBookMovements)
+	router.POST("/books/:id/sell", idempotent(), sellBook)
+	router.PUT("/books/:id", updateBook)
+	router.PATCH("/books/:id", patchBook)
+	router.DELETE("/books/:id", deleteBook)
+	router.POST("/books/:id/restore", restoreBook)
+	router.POST("/books/:id/merge-duplicate", mergeDuplicateBook)
+	router.GET("/books/:id/tags", getBookTags)
+	router.POST("/books/:id/tags", addBookTags)
+	router.DELETE("/books/:id/tags/:tag", removeBookTag)
+	router.GET("/tags", readCache.middleware(), getTags)
+	router.POST("/authors/:id/restore", restoreAuthor)
+	router.POST("/books/bulk", bulkLimiter.middleware(), maxBodySize(bulkMaxBodyBytes), createBulkBooks)
+	router.POST("/books/import", bulkLimiter.middleware(), maxBodySize(importMaxBodyBytes), importBooks)
+	router.GET("/books/export", bulkLimiter.middleware(), exportBooks)
+
+	// Trash
+	router.GET("/trash/books", getTrashedBooks)
+	router.GET("/trash/authors", getTrashedAuthors)
+	router.DELETE("/trash/books/:id", purgeBook)
+	router.DELETE("/trash/authors/:id", purgeAuthor)
+
+	// Audit
+	router.GET("/audit", getAuditLog)
+	router.GET("/alerts", getAlerts)
+
+	// Loans (library mode)
+	router.GET("/books/:id/lending", getLending)
+	router.PUT("/books/:id/lending", setLending)
+	router.GET("/loans", getLoans)
+	router.POST("/loans", checkoutLoan)
+	router.POST("/loans/:id/return", returnLoan)
+
+	// Series & volumes
+	router.GET("/series", getSeriesList)
+	router.GET("/series/:id", getSeries)
+	router.POST("/series", createSeries)
+	router.POST("/series/:id/volumes/:book_id", addSeriesVolume)
+	router.DELETE("/series/:id/volumes/:book_id", removeSeriesVolume)
+
+	// Availability notifications
+	router.POST("/books/:id/notify-me", notifyMe)
+	router.GET("/books/:id/subscribers", getSubscribers)
+
+	// Carts & orders
+	router.POST("/carts", createCart)
+	router.GET("/carts/:id", getCart)
+	router.POST("/carts/:id/items", addCartItem)
+	router.PUT("/carts/:id/items/:book_id", updateCartItem)
+	router.DELETE("/carts/:id/items/:book_id", removeCartItem)
+	router.POST("/carts/:id/checkout", checkoutCart)
+	router.GET("/orders/:id", getOrder)
+
+	// Customers & wishlists
+	router.POST("/customers", createCustomer)
+	router.GET("/customers/:id", getCustomer)
+	router.GET("/customers/:id/wishlist", getWishlist)
+	router.POST("/customers/:id/wishlist/:book_id", addToWishlist)
+	router.DELETE("/customers/:id/wishlist/:book_id", removeFromWishlist)
+	router.GET("/customers/:id/progress", getProgress)
+	router.PUT("/customers/:id/progress/:book_id", updateProgress)
+
+	// Suppliers & purchase orders
+	router.GET("/suppliers", getSuppliers)
+	router.GET("/suppliers/:id", getSupplier)
+	router.POST("/suppliers", createSupplier)
+	router.GET("/purchase-orders", getPurchaseOrders)
+	router.GET("/purchase-orders/:id", getPurchaseOrder)
+	router.POST("/purchase-orders", createPurchaseOrder)
+	router.POST("/purchase-orders/:id/receive", receivePurchaseOrder)
+	router.POST("/purchase-orders/:id/cancel", cancelPurchaseOrder)
+
+	// GraphQL
+	router.POST("/graphql", graphqlHandler)
+
+	// Analytics
+	router.GET("/analytics/sales", getSalesAnalytics)
+	router.GET("/analytics/books/:id", getBookSalesAnalytics)
+
+	// Webhooks
+	router.POST("/webhooks", createWebhook)
+	router.GET("/webhooks", getWebhooks)
+	router.DELETE("/webhooks/:id", deleteWebhook)
 
 	// Statistics
-	router.GET("/stats", getStatistics)
+	router.GET("/stats", etagMiddleware(), readCache.middleware(), getStatistics)
+	router.POST("/stats/recompute", recomputeStatistics)
+	router.GET("/stats/report", getStatsReport)
+	router.GET("/admin/dashboard", getDashboard)
+	router.POST("/admin/backup", backupDatabase)
+	router.POST("/admin/restore", maxBodySize(restoreMaxBodyBytes), restoreDatabase)
+	router.GET("/stats/history", getStatsHistory)
 
 	// Documentation
 	router.GET("/", getAPIDocumentation)
-
-	fmt.Println("🚀 Bookstore API running on :8080")
-	router.Run(":8080")
+	router.GET("/openapi.json", getAPIDocumentation)
+	openAPISpec = buildOpenAPISpec(router.Routes())
+	return storePathPrefix(router)
 }
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestServer runs the full router against a fresh SQLite file in a temp
+// dir. Package-level caches are reset so no state leaks between tests.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("RATE_LIMIT_RPM", "100000")
+	t.Setenv("RATE_LIMIT_BURST", "100000")
+	initDB("sqlite", filepath.Join(t.TempDir(), "bookstore.db"))
+	readCache = newResponseCache(30 * time.Second)
+	counters.Lock()
+	counters.stores = map[int]*storeCounters{}
+	counters.Unlock()
+
+	srv := httptest.NewServer(newRouter())
+	t.Cleanup(func() {
+		srv.Close()
+		db.Close()
+	})
+	return srv
+}
+
+// doJSON sends body (if any) as JSON and returns the response; the caller
+// reads it with decodeJSON.
+func doJSON(t *testing.T, srv *httptest.Server, method, path string, body any) *http.Response {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if raw, ok := body.(string); ok {
+			buf.WriteString(raw)
+		} else if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// decodeJSON reads and closes resp, failing the test unless the status is
+// want. v may be nil to discard the body.
+func decodeJSON(t *testing.T, resp *http.Response, want int, v any) {
+	t.Helper()
+	defer resp.Body.Close()
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		t.Fatalf("%s %s: decode body: %v", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: status %d, want %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, want, raw)
+	}
+	if v != nil {
+		if err := json.Unmarshal(raw, v); err != nil {
+			t.Fatalf("%s %s: unmarshal %s: %v", resp.Request.Method, resp.Request.URL.Path, raw, err)
+		}
+	}
+}
+
+// testISBN returns a valid, distinct ISBN-13 for n.
+func testISBN(n int) string {
+	first12 := fmt.Sprintf("978000%06d", n)
+	return first12 + isbn13CheckDigit(first12)
+}
+
+func seedAuthor(t *testing.T, srv *httptest.Server, name string) Author {
+	t.Helper()
+	var a Author
+	decodeJSON(t, doJSON(t, srv, http.MethodPost, "/authors", Author{Name: name}), http.StatusCreated, &a)
+	return a
+}
+
+// seedBooks creates n books by author, numbered from 1, each with stock.
+func seedBooks(t *testing.T, srv *httptest.Server, author Author, n, stock int) []Book {
+	t.Helper()
+	books := make([]Book, 0, n)
+	for i := 1; i <= n; i++ {
+		var b Book
+		decodeJSON(t, doJSON(t, srv, http.MethodPost, "/books", Book{
+			Title: fmt.Sprintf("Volume %02d", i), AuthorID: author.ID, ISBN: testISBN(i),
+			Price: 9.99, Stock: stock, PublishedYear: 2000,
+		}), http.StatusCreated, &b)
+		books = append(books, b)
+	}
+	return books
+}
+
+func TestBookCRUD(t *testing.T) {
+	srv := newTestServer(t)
+	author := seedAuthor(t, srv, "Akira Toriyama")
+	book := seedBooks(t, srv, author, 1, 5)[0]
+	path := "/books/" + strconv.Itoa(book.ID)
+
+	var got BookWithAuthor
+	decodeJSON(t, doJSON(t, srv, http.MethodGet, path, nil), http.StatusOK, &got)
+	if got.Title != book.Title || got.AuthorName != author.Name || got.Version != 1 {
+		t.Fatalf("get: got %+v", got)
+	}
+
+	update := book
+	update.Title = "Dragon Ball"
+	update.Price = 12.50
+	var updated Book
+	decodeJSON(t, doJSON(t, srv, http.MethodPut, path, update), http.StatusOK, &updated)
+	if updated.Title != "Dragon Ball" || updated.Version != 2 {
+		t.Fatalf("update: got %+v", updated)
+	}
+
+	// the edit above was based on version 1, so replaying it is stale
+	var conflict ErrorResponse
+	decodeJSON(t, doJSON(t, srv, http.MethodPut, path, update), http.StatusConflict, &conflict)
+	if conflict.Code != CodeVersionConflict {
+		t.Fatalf("stale update: got %+v", conflict)
+	}
+
+	decodeJSON(t, doJSON(t, srv, http.MethodDelete, path, nil), http.StatusOK, nil)
+	decodeJSON(t, doJSON(t, srv, http.MethodGet, path, nil), http.StatusNotFound, nil)
+	decodeJSON(t, doJSON(t, srv, http.MethodDelete, path, nil), http.StatusNotFound, nil)
+}
+
+func TestBooksPagination(t *testing.T) {
+	srv := newTestServer(t)
+	seedBooks(t, srv, seedAuthor(t, srv, "Hirohiko Araki"), 5, 1)
+
+	tests := []struct {
+		query     string
+		wantPage  int
+		wantLimit int
+		wantBooks int
+		wantFirst string
+		hasNext   bool
+		hasPrev   bool
+	}{
+		{"?page=1&limit=2", 1, 2, 2, "Volume 01", true, false},
+		{"?page=3&limit=2", 3, 2, 1, "Volume 05", false, true},
+		{"?page=0&limit=2", 1, 2, 2, "Volume 01", true, false},
+		{"?page=-4&limit=2", 1, 2, 2, "Volume 01", true, false},
+		{"?page=9&limit=2", 9, 2, 0, "", false, true},
+		{"?limit=abc", 1, 20, 5, "Volume 01", false, false},
+		{"?limit=0", 1, 20, 5, "Volume 01", false, false},
+		{"?limit=101", 1, 20, 5, "Volume 01", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got PaginatedBooksResponse
+			decodeJSON(t, doJSON(t, srv, http.MethodGet, "/books"+tt.query, nil), http.StatusOK, &got)
+			p := got.Pagination
+			if p.Page != tt.wantPage || p.Limit != tt.wantLimit || p.Total != 5 || p.HasNext != tt.hasNext || p.HasPrev != tt.hasPrev {
+				t.Fatalf("pagination: got %+v", p)
+			}
+			if len(got.Books) != tt.wantBooks {
+				t.Fatalf("got %d books, want %d", len(got.Books), tt.wantBooks)
+			}
+			if tt.wantBooks > 0 && got.Books[0].Title != tt.wantFirst {
+				t.Fatalf("first book %q, want %q", got.Books[0].Title, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestCreateBookValidation(t *testing.T) {
+	srv := newTestServer(t)
+	author := seedAuthor(t, srv, "Kentaro Miura")
+	valid := func() map[string]any {
+		return map[string]any{"title": "Berserk", "author_id": author.ID, "isbn": testISBN(1), "price": 9.99, "stock": 1, "published_year": 1990}
+	}
+
+	tests := []struct {
+		name      string
+		edit      func(map[string]any)
+		wantCode  string
+		wantField string
+	}{
+		{"missing title", func(b map[string]any) { delete(b, "title") }, CodeValidationFailed, "title"},
+		{"short title", func(b map[string]any) { b["title"] = "Be" }, CodeValidationFailed, "title"},
+		{"zero price", func(b map[string]any) { b["price"] = 0 }, CodeValidationFailed, "price"},
+		{"price too high", func(b map[string]any) { b["price"] = 1000.01 }, CodeValidationFailed, "price"},
+		{"negative stock", func(b map[string]any) { b["stock"] = -1 }, CodeValidationFailed, "stock"},
+		{"bad ISBN check digit", func(b map[string]any) { b["isbn"] = "9780000000018" }, CodeValidationFailed, "isbn"},
+		{"year too early", func(b map[string]any) { b["published_year"] = 1700 }, CodeValidationFailed, "published_year"},
+		{"unknown author", func(b map[string]any) { b["author_id"] = author.ID + 100 }, CodeValidationFailed, "author_id"},
+		{"price as string", func(b map[string]any) { b["price"] = "cheap" }, CodeValidationFailed, "price"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := valid()
+			tt.edit(body)
+			var got ErrorResponse
+			decodeJSON(t, doJSON(t, srv, http.MethodPost, "/books", body), http.StatusBadRequest, &got)
+			if got.Code != tt.wantCode || len(got.Details) == 0 || got.Details[0].Field != tt.wantField {
+				t.Fatalf("got %+v, want %s on %s", got, tt.wantCode, tt.wantField)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		var got ErrorResponse
+		decodeJSON(t, doJSON(t, srv, http.MethodPost, "/books", `{"title":`), http.StatusBadRequest, &got)
+		if got.Code != CodeInvalidJSON {
+			t.Fatalf("got %+v, want %s", got, CodeInvalidJSON)
+		}
+	})
+
+	t.Run("duplicate ISBN", func(t *testing.T) {
+		decodeJSON(t, doJSON(t, srv, http.MethodPost, "/books", valid()), http.StatusCreated, nil)
+		var got ErrorResponse
+		decodeJSON(t, doJSON(t, srv, http.MethodPost, "/books", valid()), http.StatusConflict, &got)
+		if got.Code != CodeDuplicateISBN {
+			t.Fatalf("got %+v, want %s", got, CodeDuplicateISBN)
+		}
+	})
+}
+
+// TestConcurrentSells races more buyers than there is stock; the
+// conditional UPDATE in sellBook must sell exactly the stock and no more.
+func TestConcurrentSells(t *testing.T) {
+	srv := newTestServer(t)
+	const stock, buyers = 10, 30
+	book := seedBooks(t, srv, seedAuthor(t, srv, "Takehiko Inoue"), 1, stock)[0]
+	path := "/books/" + strconv.Itoa(book.ID)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = map[int]int{}
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// t.Fatal must not run off the test goroutine, so errors are
+			// counted as status 0 instead of going through doJSON
+			status := 0
+			resp, err := srv.Client().Post(srv.URL+path+"/sell", "application/json", strings.NewReader(`{"quantity":1}`))
+			if err == nil {
+				status = resp.StatusCode
+				resp.Body.Close()
+			}
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if statuses[http.StatusOK] != stock || statuses[http.StatusBadRequest] != buyers-stock {
+		t.Fatalf("statuses %v, want %d x 200 and %d x 400", statuses, stock, buyers-stock)
+	}
+	var got BookWithAuthor
+	decodeJSON(t, doJSON(t, srv, http.MethodGet, path, nil), http.StatusOK, &got)
+	if got.Stock != 0 || got.Version != 1+stock {
+		t.Fatalf("after sells: stock %d version %d, want 0 and %d", got.Stock, got.Version, 1+stock)
+	}
+	var sold int
+	if err := db.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM sales WHERE book_id = ?", book.ID).Scan(&sold); err != nil {
+		t.Fatal(err)
+	}
+	if sold != stock {
+		t.Fatalf("sales rows add up to %d, want %d", sold, stock)
+	}
+}
+
+// TestStatsSnapshotDBError checks that a failing scan records nothing, so
+// the next run can still write the day.
+func TestStatsSnapshotDBError(t *testing.T) {
+	newTestServer(t)
+	if _, err := computeStatistics(DefaultStoreID); err != nil {
+		t.Fatalf("empty store: %v", err)
+	}
+	if _, err := db.Exec("DROP TABLE authors"); err != nil {
+		t.Fatal(err)
+	}
+	if err := takeStoreSnapshot(DefaultStoreID, "2026-01-02"); err == nil {
+		t.Fatal("snapshot succeeded without an authors table")
+	}
+	var n int
+	db.QueryRow("SELECT COUNT(*) FROM stats_snapshots").Scan(&n)
+	if n != 0 {
+		t.Fatalf("%d snapshots stored after a failed scan, want 0", n)
+	}
+}
+
+// TestStatsCountersNotCachedOnError checks that a failed first load answers
+// 500 and leaves the store unloaded for the next request to retry.
+func TestStatsCountersNotCachedOnError(t *testing.T) {
+	srv := newTestServer(t)
+	if _, err := db.Exec("DROP TABLE authors"); err != nil {
+		t.Fatal(err)
+	}
+	decodeJSON(t, doJSON(t, srv, http.MethodGet, "/stats", nil), http.StatusInternalServerError, nil)
+	counters.Lock()
+	_, loaded := counters.stores[DefaultStoreID]
+	counters.Unlock()
+	if loaded {
+		t.Fatal("counters were kept after a failed load")
+	}
+}
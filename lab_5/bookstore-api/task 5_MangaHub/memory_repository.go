@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ---------- In-Memory Repositories ----------
+
+// memStore backs memAuthorRepository and memBookRepository so books can be
+// joined with their authors the way the SQL queries do. It is meant for
+// handler tests: wire it with useMemoryRepositories and no database is
+// needed.
+type memStore struct {
+	mu           sync.Mutex
+	authors      map[int]Author
+	books        map[int]Book
+	deleted      map[int]bool // trashed author ids
+	deletedBooks map[int]bool
+	storeOf      map[string]int // "author:1" / "book:1" -> store id
+	nextAuthorID int
+	nextBookID   int
+}
+
+type memAuthorRepository struct{ s *memStore }
+
+type memBookRepository struct{ s *memStore }
+
+func newMemoryRepositories() (AuthorRepository, BookRepository) {
+	s := &memStore{authors: map[int]Author{}, books: map[int]Book{}, deleted: map[int]bool{}, deletedBooks: map[int]bool{}, storeOf: map[string]int{}}
+	return &memAuthorRepository{s}, &memBookRepository{s}
+}
+
+// useMemoryRepositories points the handlers at a fresh in-memory store.
+func useMemoryRepositories() {
+	authorRepo, bookRepo = newMemoryRepositories()
+}
+
+// visible reports whether a row exists in the context's store.
+func (s *memStore) visible(ctx context.Context, kind string, id int) bool {
+	return s.storeOf[kind+":"+strconv.Itoa(id)] == storeFrom(ctx)
+}
+
+func (r *memAuthorRepository) List(ctx context.Context) ([]Author, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	authors := []Author{}
+	for id, a := range r.s.authors {
+		if !r.s.deleted[id] && r.s.visible(ctx, "author", id) {
+			authors = append(authors, a)
+		}
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].ID < authors[j].ID })
+	return authors, nil
+}
+
+func (r *memAuthorRepository) Get(ctx context.Context, id int) (*Author, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.authors[id]
+	if !ok || r.s.deleted[id] || !r.s.visible(ctx, "author", id) {
+		return nil, ErrNotFound
+	}
+	return &a, nil
+}
+
+func (r *memAuthorRepository) Create(ctx context.Context, a *Author) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.nextAuthorID++
+	a.ID = r.s.nextAuthorID
+	a.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	r.s.authors[a.ID] = *a
+	r.s.storeOf["author:"+strconv.Itoa(a.ID)] = storeFrom(ctx)
+	return nil
+}
+
+func (r *memAuthorRepository) Update(ctx context.Context, a *Author) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	old, ok := r.s.authors[a.ID]
+	if !ok || r.s.deleted[a.ID] || !r.s.visible(ctx, "author", a.ID) {
+		return ErrNotFound
+	}
+	a.CreatedAt = old.CreatedAt
+	r.s.authors[a.ID] = *a
+	return nil
+}
+
+func (r *memAuthorRepository) Delete(ctx context.Context, id int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.authors[id]; !ok || r.s.deleted[id] || !r.s.visible(ctx, "author", id) {
+		return ErrNotFound
+	}
+	r.s.deleted[id] = true
+	return nil
+}
+
+func (r *memBookRepository) withAuthor(b Book) BookWithAuthor {
+	a := r.s.authors[b.AuthorID]
+	return BookWithAuthor{Book: b, AuthorName: a.Name, AuthorBio: a.Bio}
+}
+
+func (r *memBookRepository) Get(ctx context.Context, id int) (*BookWithAuthor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	b, ok := r.s.books[id]
+	if !ok || r.s.deletedBooks[id] || !r.s.visible(ctx, "book", id) {
+		return nil, ErrNotFound
+	}
+	bw := r.withAuthor(b)
+	return &bw, nil
+}
+
+func (r *memBookRepository) ListByAuthor(ctx context.Context, authorID int) ([]BookWithAuthor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	books := []BookWithAuthor{}
+	for _, b := range r.s.books {
+		if b.AuthorID == authorID && !r.s.deletedBooks[b.ID] && r.s.visible(ctx, "book", b.ID) {
+			books = append(books, r.withAuthor(b))
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books, nil
+}
+
+func (r *memBookRepository) CountByAuthor(ctx context.Context, authorID int) (int, error) {
+	books, err := r.ListByAuthor(ctx, authorID)
+	return len(books), err
+}
+
+func (r *memBookRepository) Create(ctx context.Context, b *Book) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.nextBookID++
+	b.ID = r.s.nextBookID
+	b.Version = 1
+	r.s.books[b.ID] = *b
+	r.s.storeOf["book:"+strconv.Itoa(b.ID)] = storeFrom(ctx)
+	return nil
+}
+
+func (r *memBookRepository) Update(ctx context.Context, b *Book) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	old, ok := r.s.books[b.ID]
+	if !ok || r.s.deletedBooks[b.ID] || !r.s.visible(ctx, "book", b.ID) {
+		return ErrNotFound
+	}
+	if old.Version != b.Version {
+		return ErrVersionConflict
+	}
+	b.Version++
+	r.s.books[b.ID] = *b
+	return nil
+}
+
+func (r *memBookRepository) Delete(ctx context.Context, id int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.books[id]; !ok || r.s.deletedBooks[id] || !r.s.visible(ctx, "book", id) {
+		return ErrNotFound
+	}
+	r.s.deletedBooks[id] = true
+	return nil
+}
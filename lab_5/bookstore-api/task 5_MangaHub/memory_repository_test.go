@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryRouter wires the repository-backed author and book handlers against
+// a fresh in-memory store. Routes that still query db directly are left out.
+func memoryRouter(t *testing.T) http.Handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	useJSONFieldNames()
+	prevDB, prevAuthors, prevBooks := db, authorRepo, bookRepo
+	db = nil
+	useMemoryRepositories()
+	t.Cleanup(func() { db, authorRepo, bookRepo = prevDB, prevAuthors, prevBooks })
+
+	router := gin.New()
+	router.GET("/authors", getAuthors)
+	router.GET("/authors/:id", getAuthor)
+	router.POST("/authors", createAuthor)
+	router.PUT("/authors/:id", updateAuthor)
+	router.DELETE("/authors/:id", deleteAuthor)
+	router.GET("/authors/:id/books", getAuthorBooks)
+	router.GET("/books/:id", getBook)
+	return router
+}
+
+func memRequest(t *testing.T, h http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestMemoryAuthorCRUD(t *testing.T) {
+	h := memoryRouter(t)
+
+	w := memRequest(t, h, http.MethodPost, "/authors", Author{Name: "Eiichiro Oda", Country: "Japan"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", w.Code, w.Body)
+	}
+	var created Author
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ID == 0 || created.CreatedAt == "" {
+		t.Fatalf("create: got %+v, want an id and created_at", created)
+	}
+	path := "/authors/" + strconv.Itoa(created.ID)
+
+	w = memRequest(t, h, http.MethodPut, path, Author{Name: "Oda Eiichiro", Country: "Japan"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: status %d, body %s", w.Code, w.Body)
+	}
+	var got Author
+	w = memRequest(t, h, http.MethodGet, path, nil)
+	json.Unmarshal(w.Body.Bytes(), &got)
+	if got.Name != "Oda Eiichiro" || got.CreatedAt != created.CreatedAt {
+		t.Fatalf("get after update: got %+v", got)
+	}
+
+	if w = memRequest(t, h, http.MethodDelete, path, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete: status %d, body %s", w.Code, w.Body)
+	}
+	if w = memRequest(t, h, http.MethodGet, path, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("get after delete: status %d, want 404", w.Code)
+	}
+	var list []Author
+	json.Unmarshal(memRequest(t, h, http.MethodGet, "/authors", nil).Body.Bytes(), &list)
+	if len(list) != 0 {
+		t.Fatalf("list after delete: got %d authors, want 0", len(list))
+	}
+}
+
+func TestMemoryCreateAuthorValidation(t *testing.T) {
+	h := memoryRouter(t)
+
+	w := memRequest(t, h, http.MethodPost, "/authors", map[string]any{"bio": "no name"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want 400", w.Code)
+	}
+	var resp ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Code != CodeValidationFailed || len(resp.Details) != 1 || resp.Details[0].Field != "name" {
+		t.Fatalf("got %+v, want a validation_failed detail on name", resp)
+	}
+}
+
+func TestMemoryDeleteAuthorWithBooks(t *testing.T) {
+	h := memoryRouter(t)
+	ctx := context.Background()
+	a := Author{Name: "Naoki Urasawa"}
+	authorRepo.Create(ctx, &a)
+	b := Book{Title: "Monster", AuthorID: a.ID, ISBN: "9781421569062", Price: 14.99, Stock: 3}
+	bookRepo.Create(ctx, &b)
+
+	if w := memRequest(t, h, http.MethodDelete, "/authors/"+strconv.Itoa(a.ID), nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("delete: status %d, want 400 while the author has books", w.Code)
+	}
+
+	var books struct {
+		Books []BookWithAuthor `json:"books"`
+		Count int              `json:"count"`
+	}
+	json.Unmarshal(memRequest(t, h, http.MethodGet, "/authors/"+strconv.Itoa(a.ID)+"/books", nil).Body.Bytes(), &books)
+	if books.Count != 1 || books.Books[0].AuthorName != a.Name {
+		t.Fatalf("author books: got %+v", books)
+	}
+
+	var got BookWithAuthor
+	w := memRequest(t, h, http.MethodGet, "/books/"+strconv.Itoa(b.ID), nil)
+	json.Unmarshal(w.Body.Bytes(), &got)
+	if w.Code != http.StatusOK || got.Title != "Monster" || got.Version != 1 {
+		t.Fatalf("get book: status %d, got %+v", w.Code, got)
+	}
+}
+
+func TestMemoryStoreIsolation(t *testing.T) {
+	h := memoryRouter(t)
+	other := context.WithValue(context.Background(), storeCtxKey{}, 2)
+	a := Author{Name: "Rumiko Takahashi"}
+	authorRepo.Create(other, &a)
+	b := Book{Title: "Ranma 1/2", AuthorID: a.ID, ISBN: "9781421565941", Price: 9.99}
+	bookRepo.Create(other, &b)
+
+	if w := memRequest(t, h, http.MethodGet, "/authors/"+strconv.Itoa(a.ID), nil); w.Code != http.StatusNotFound {
+		t.Fatalf("author from store 2: status %d, want 404 in the default store", w.Code)
+	}
+	if w := memRequest(t, h, http.MethodGet, "/books/"+strconv.Itoa(b.ID), nil); w.Code != http.StatusNotFound {
+		t.Fatalf("book from store 2: status %d, want 404 in the default store", w.Code)
+	}
+	if got, _ := bookRepo.Get(other, b.ID); got == nil {
+		t.Fatal("book is missing from its own store")
+	}
+}
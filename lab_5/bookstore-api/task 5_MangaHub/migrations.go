@@ -0,0 +1,310 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ---------- Migrations ----------
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one numbered schema change, loaded from
+// migrations/NNNN_name.up.sql and its optional .down.sql twin. A file named
+// NNNN_name.<dialect>.up.sql (dialect sqlite, mysql or postgres) replaces the
+// generic script on that database only.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	Applied   bool   `json:"applied"`
+	AppliedAt string `json:"applied_at,omitempty"`
+}
+
+const createSchemaMigrationsSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);`
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		file := e.Name()
+		base, direction := "", ""
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			base, direction = strings.TrimSuffix(file, ".up.sql"), "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			base, direction = strings.TrimSuffix(file, ".down.sql"), "down"
+		default:
+			continue
+		}
+		specific := false
+		if i := strings.LastIndex(base, "."); i >= 0 {
+			dialect, err := parseDialect(base[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("migration %s: %w", file, err)
+			}
+			if dialect != db.Dialect {
+				continue
+			}
+			base, specific = base[:i], true
+		}
+		num, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(num)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: version prefix must be numeric", file)
+		}
+		data, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" && (m.Up == "" || specific) {
+			m.Up = string(data)
+		} else if direction == "down" && (m.Down == "" || specific) {
+			m.Down = string(data)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no .up.sql", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitSQL splits a script into statements on trailing semicolons, keeping
+// trigger bodies (BEGIN ... END;) together.
+func splitSQL(script string) []string {
+	var statements []string
+	var current strings.Builder
+	inBody := false
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		upper := strings.ToUpper(trimmed)
+		if upper == "BEGIN" || strings.HasSuffix(upper, " BEGIN") {
+			inBody = true
+		}
+		if !strings.HasSuffix(trimmed, ";") {
+			continue
+		}
+		if inBody && upper != "END;" {
+			continue
+		}
+		inBody = false
+		stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+		statements = append(statements, stmt)
+		current.Reset()
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// isAlreadyApplied recognises errors from databases that already contain a
+// change, e.g. ones created by initDB before migrations existed.
+func isAlreadyApplied(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "duplicate column") ||
+		strings.Contains(msg, "already exists") ||
+		strings.Contains(msg, "duplicate key name")
+}
+
+// runMigrationScript executes one up/down script inside a transaction and
+// records (or removes) the version row.
+func runMigrationScript(m Migration, script string, up bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	// A failed statement aborts the whole Postgres transaction, so each one
+	// runs under a savepoint that can be rolled back when the error is benign.
+	savepoints := db.Dialect == DialectPostgres
+	for _, stmt := range splitSQL(script) {
+		if savepoints {
+			if _, err := tx.Tx.Exec("SAVEPOINT migration_stmt"); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Tx.Exec(translateDDL(db.Dialect, stmt))
+		if err != nil {
+			if !up || !isAlreadyApplied(err) {
+				return fmt.Errorf("migration %04d_%s: %w\n%s", m.Version, m.Name, err, stmt)
+			}
+			log.Printf("migrate: %04d_%s: skipping, %v", m.Version, m.Name, err)
+			if savepoints {
+				if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT migration_stmt"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if up {
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name)
+	} else {
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func appliedMigrations() (map[int]string, error) {
+	if err := db.ExecDDL(createSchemaMigrationsSQL); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int]string{}
+	for rows.Next() {
+		var version int
+		var at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// migrateUp applies every pending migration in version order.
+func migrateUp() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := runMigrationScript(m, m.Up, true); err != nil {
+			return err
+		}
+		log.Printf("migrate: applied %04d_%s", m.Version, m.Name)
+	}
+	return nil
+}
+
+// migrateDown reverts the newest steps applied migrations.
+func migrateDown(steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %04d_%s cannot be reverted (no .down.sql)", m.Version, m.Name)
+		}
+		if err := runMigrationScript(m, m.Down, false); err != nil {
+			return err
+		}
+		log.Printf("migrate: reverted %04d_%s", m.Version, m.Name)
+		steps--
+	}
+	return nil
+}
+
+func migrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var status []MigrationStatus
+	for _, m := range migrations {
+		at, ok := applied[m.Version]
+		status = append(status, MigrationStatus{Version: m.Version, Name: m.Name, Applied: ok, AppliedAt: at})
+	}
+	return status, nil
+}
+
+// runMigrateCommand handles the -migrate flag (up, down, status).
+func runMigrateCommand(command string, steps int) error {
+	switch command {
+	case "up":
+		return migrateUp()
+	case "down":
+		return migrateDown(steps)
+	case "status":
+		status, err := migrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %q (use up, down or status)", command)
+}
+
+// pendingMigrations reports how many embedded migrations are not yet applied.
+func pendingMigrations() (int, error) {
+	status, err := migrationStatus()
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range status {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
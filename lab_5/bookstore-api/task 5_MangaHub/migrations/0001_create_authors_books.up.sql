@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS authors (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	bio TEXT,
+	birth_year INTEGER,
+	country TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS books (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	author_id INTEGER,
+	isbn TEXT NOT NULL,
+	price REAL NOT NULL,
+	stock INTEGER NOT NULL,
+	published_year INTEGER,
+	description TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(author_id) REFERENCES authors(id) ON DELETE SET NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE books DROP COLUMN deleted_at;
+ALTER TABLE authors DROP COLUMN deleted_at;
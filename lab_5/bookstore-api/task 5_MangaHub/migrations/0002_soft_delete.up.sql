@@ -0,0 +1,2 @@
+ALTER TABLE authors ADD COLUMN deleted_at DATETIME;
+ALTER TABLE books ADD COLUMN deleted_at DATETIME;
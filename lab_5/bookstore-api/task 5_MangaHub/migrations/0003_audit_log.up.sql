@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	action VARCHAR(32) NOT NULL,
+	entity VARCHAR(32) NOT NULL,
+	entity_id INTEGER,
+	actor TEXT,
+	before_json TEXT,
+	after_json TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_entity ON audit_log(entity, entity_id);
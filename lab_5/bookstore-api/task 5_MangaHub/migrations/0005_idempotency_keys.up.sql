@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	idem_key VARCHAR(255) NOT NULL,
+	method VARCHAR(16) NOT NULL,
+	path VARCHAR(255) NOT NULL,
+	request_hash VARCHAR(64) NOT NULL,
+	status INTEGER NOT NULL DEFAULT 0,
+	content_type VARCHAR(128),
+	body TEXT,
+	created_unix INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_key ON idempotency_keys(idem_key, method, path);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS alerts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	type VARCHAR(32) NOT NULL,
+	book_id INTEGER NOT NULL,
+	title TEXT,
+	stock INTEGER NOT NULL,
+	threshold INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	resolved_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_book ON alerts(book_id, resolved_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS sales (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	book_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	unit_price REAL NOT NULL,
+	total REAL NOT NULL,
+	sold_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sales_book ON sales(book_id, sold_at);
+CREATE INDEX IF NOT EXISTS idx_sales_sold_at ON sales(sold_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	email TEXT,
+	phone TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	supplier_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	unit_cost REAL NOT NULL,
+	status VARCHAR(16) NOT NULL DEFAULT 'pending',
+	notes TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	received_at DATETIME,
+	FOREIGN KEY(supplier_id) REFERENCES suppliers(id),
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
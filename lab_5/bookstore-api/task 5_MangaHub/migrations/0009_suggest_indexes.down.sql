@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_books_title_lower;
+DROP INDEX IF EXISTS idx_authors_name_lower;
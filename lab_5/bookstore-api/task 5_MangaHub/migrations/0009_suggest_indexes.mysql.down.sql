@@ -0,0 +1 @@
+DROP INDEX idx_books_title_prefix ON books;
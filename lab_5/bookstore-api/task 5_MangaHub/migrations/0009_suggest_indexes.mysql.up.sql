@@ -0,0 +1,3 @@
+-- MySQL compares case-insensitively already; TEXT keys need a prefix length.
+-- authors.name is VARCHAR with a unique index that serves prefix lookups.
+CREATE INDEX idx_books_title_prefix ON books(title(100));
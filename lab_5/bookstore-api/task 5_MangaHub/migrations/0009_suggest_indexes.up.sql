@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_books_title_lower ON books(LOWER(title));
+CREATE INDEX IF NOT EXISTS idx_authors_name_lower ON authors(LOWER(name));
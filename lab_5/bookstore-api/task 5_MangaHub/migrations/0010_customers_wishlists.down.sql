@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wishlist_items;
+DROP TABLE IF EXISTS customers;
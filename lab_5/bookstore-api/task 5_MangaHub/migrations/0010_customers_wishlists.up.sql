@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS customers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL UNIQUE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+	customer_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	notified_at DATETIME,
+	PRIMARY KEY (customer_id, book_id),
+	FOREIGN KEY(customer_id) REFERENCES customers(id) ON DELETE CASCADE,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_book ON wishlist_items(book_id);
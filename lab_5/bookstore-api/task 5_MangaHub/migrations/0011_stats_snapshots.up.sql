@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS stats_snapshots (
+	snapshot_date VARCHAR(10) PRIMARY KEY,
+	total_books INTEGER NOT NULL,
+	total_authors INTEGER NOT NULL,
+	total_value REAL NOT NULL,
+	low_stock INTEGER NOT NULL,
+	out_of_stock INTEGER NOT NULL,
+	average_price REAL NOT NULL,
+	stats_json TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
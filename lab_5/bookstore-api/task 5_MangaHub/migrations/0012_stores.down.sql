@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS stats_snapshots;
+CREATE TABLE IF NOT EXISTS stats_snapshots (
+	snapshot_date VARCHAR(10) PRIMARY KEY,
+	total_books INTEGER NOT NULL,
+	total_authors INTEGER NOT NULL,
+	total_value REAL NOT NULL,
+	low_stock INTEGER NOT NULL,
+	out_of_stock INTEGER NOT NULL,
+	average_price REAL NOT NULL,
+	stats_json TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP INDEX IF EXISTS idx_books_store;
+DROP INDEX IF EXISTS idx_authors_store;
+ALTER TABLE books DROP COLUMN store_id;
+ALTER TABLE authors DROP COLUMN store_id;
+DROP TABLE IF EXISTS stores;
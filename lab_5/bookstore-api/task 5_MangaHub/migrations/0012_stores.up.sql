@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS stores (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing catalogs become the default store (id 1).
+INSERT INTO stores (name) VALUES ('Default');
+
+ALTER TABLE authors ADD COLUMN store_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE books ADD COLUMN store_id INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_authors_store ON authors(store_id);
+CREATE INDEX IF NOT EXISTS idx_books_store ON books(store_id);
+
+-- Snapshots are per store now; the daily job rebuilds them.
+DROP TABLE IF EXISTS stats_snapshots;
+CREATE TABLE IF NOT EXISTS stats_snapshots (
+	store_id INTEGER NOT NULL DEFAULT 1,
+	snapshot_date VARCHAR(10) NOT NULL,
+	total_books INTEGER NOT NULL,
+	total_authors INTEGER NOT NULL,
+	total_value REAL NOT NULL,
+	low_stock INTEGER NOT NULL,
+	out_of_stock INTEGER NOT NULL,
+	average_price REAL NOT NULL,
+	stats_json TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (store_id, snapshot_date)
+);
@@ -0,0 +1,16 @@
+-- Older rows may still carry hyphens or spaces.
+UPDATE books SET isbn = REPLACE(REPLACE(isbn, '-', ''), ' ', '');
+
+-- Later copies of an ISBN that is already live in the same store go to the
+-- trash; POST /books/:id/merge-duplicate folds their stock back in.
+UPDATE books b JOIN (
+	SELECT store_id, isbn, MIN(id) AS keep_id FROM books
+	WHERE deleted_at IS NULL GROUP BY store_id, isbn HAVING COUNT(*) > 1
+) d ON b.store_id = d.store_id AND b.isbn = d.isbn
+SET b.deleted_at = CURRENT_TIMESTAMP
+WHERE b.deleted_at IS NULL AND b.id > d.keep_id;
+
+-- MySQL has no partial indexes: live_isbn is NULL for trashed rows, and
+-- NULLs never collide in a unique index.
+ALTER TABLE books ADD COLUMN live_isbn VARCHAR(32) AS (IF(deleted_at IS NULL, isbn, NULL)) STORED;
+CREATE UNIQUE INDEX idx_books_store_isbn ON books(store_id, live_isbn);
@@ -0,0 +1,13 @@
+-- Older rows may still carry hyphens or spaces.
+UPDATE books SET isbn = REPLACE(REPLACE(isbn, '-', ''), ' ', '');
+
+-- Later copies of an ISBN that is already live in the same store go to the
+-- trash; POST /books/:id/merge-duplicate folds their stock back in.
+UPDATE books SET deleted_at = CURRENT_TIMESTAMP
+WHERE deleted_at IS NULL AND EXISTS (
+	SELECT 1 FROM books older
+	WHERE older.store_id = books.store_id AND older.isbn = books.isbn
+		AND older.deleted_at IS NULL AND older.id < books.id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_store_isbn ON books(store_id, isbn) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS book_tags (
+	book_id INTEGER NOT NULL,
+	tag VARCHAR(40) NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (book_id, tag),
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_tags_tag ON book_tags(tag);
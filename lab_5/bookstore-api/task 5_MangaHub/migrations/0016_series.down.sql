@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS series_volumes;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS series (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	store_id INTEGER NOT NULL DEFAULT 1,
+	title VARCHAR(200) NOT NULL,
+	description TEXT,
+	status VARCHAR(16) NOT NULL DEFAULT 'ongoing',
+	total_volumes INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (store_id, title)
+);
+
+-- A book is one volume of at most one series.
+CREATE TABLE IF NOT EXISTS series_volumes (
+	series_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL UNIQUE,
+	volume_number INTEGER NOT NULL,
+	PRIMARY KEY (series_id, volume_number),
+	FOREIGN KEY(series_id) REFERENCES series(id) ON DELETE CASCADE,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+-- finished_at is set once the customer has read the whole book.
+CREATE TABLE IF NOT EXISTS reading_progress (
+	customer_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL,
+	chapter INTEGER NOT NULL DEFAULT 0,
+	finished_at DATETIME,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (customer_id, book_id),
+	FOREIGN KEY(customer_id) REFERENCES customers(id) ON DELETE CASCADE,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
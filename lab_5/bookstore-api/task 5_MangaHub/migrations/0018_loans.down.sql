@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS loans;
+ALTER TABLE books DROP COLUMN lendable_copies;
@@ -0,0 +1,16 @@
+-- Copies kept for lending are a separate pool from the stock for sale.
+ALTER TABLE books ADD COLUMN lendable_copies INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS loans (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	book_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	loaned_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	due_at DATETIME NOT NULL,
+	returned_at DATETIME,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE,
+	FOREIGN KEY(customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_book ON loans(book_id, returned_at);
+CREATE INDEX IF NOT EXISTS idx_loans_due ON loans(due_at);
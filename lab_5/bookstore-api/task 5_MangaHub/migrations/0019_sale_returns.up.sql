@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sale_returns (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	sale_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	refund REAL NOT NULL,
+	reason TEXT NOT NULL,
+	returned_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(sale_id) REFERENCES sales(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_returns_sale ON sale_returns(sale_id);
+CREATE INDEX IF NOT EXISTS idx_sale_returns_book ON sale_returns(book_id, returned_at);
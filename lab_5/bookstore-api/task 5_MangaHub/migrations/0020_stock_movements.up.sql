@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS stock_movements (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	book_id INTEGER NOT NULL,
+	delta INTEGER NOT NULL,
+	reason VARCHAR(16) NOT NULL,
+	actor TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_book ON stock_movements(book_id, id);
+
+-- Opening balance, so every book's movements sum to its stock from here on.
+INSERT INTO stock_movements (book_id, delta, reason, actor)
+	SELECT id, stock, 'adjustment', 'migration' FROM books WHERE stock <> 0;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sales_order;
+ALTER TABLE sales DROP COLUMN order_id;
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,5 @@
+DROP INDEX idx_sales_order ON sales;
+ALTER TABLE sales DROP COLUMN order_id;
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
+DROP TABLE IF EXISTS orders;
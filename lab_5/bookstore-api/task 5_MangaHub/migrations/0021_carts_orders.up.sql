@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	store_id INTEGER NOT NULL DEFAULT 1,
+	customer_id INTEGER,
+	total REAL NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(customer_id) REFERENCES customers(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS carts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	store_id INTEGER NOT NULL DEFAULT 1,
+	customer_id INTEGER,
+	status VARCHAR(16) NOT NULL DEFAULT 'open',
+	order_id INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(customer_id) REFERENCES customers(id) ON DELETE SET NULL,
+	FOREIGN KEY(order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+	cart_id INTEGER NOT NULL,
+	book_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (cart_id, book_id),
+	FOREIGN KEY(cart_id) REFERENCES carts(id) ON DELETE CASCADE,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_carts_customer ON carts(customer_id, status);
+
+-- Each sale made by a checkout points back at its order.
+ALTER TABLE sales ADD COLUMN order_id INTEGER;
+CREATE INDEX IF NOT EXISTS idx_sales_order ON sales(order_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	book_id INTEGER NOT NULL,
+	channel VARCHAR(16) NOT NULL,
+	target VARCHAR(255) NOT NULL,
+	secret VARCHAR(64),
+	status VARCHAR(16) NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at DATETIME,
+	notified_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_subscriptions_target ON stock_subscriptions(book_id, channel, target);
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_due ON stock_subscriptions(status, next_attempt_at);
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Stock Movements ----------
+
+// Every change to books.stock appends a row to stock_movements, written in
+// the same transaction as the change where there is one. The deltas of a
+// book always add up to its stock; GET /books/:id/movements shows both.
+
+const (
+	MovementSell       = "sell"
+	MovementRestock    = "restock"
+	MovementReturn     = "return"
+	MovementAdjustment = "adjustment"
+)
+
+type StockMovement struct {
+	ID        int    `json:"id"`
+	BookID    int    `json:"book_id"`
+	Delta     int    `json:"delta"`
+	Reason    string `json:"reason"`
+	Actor     string `json:"actor"`
+	CreatedAt string `json:"created_at"`
+}
+
+type MovementLedger struct {
+	BookID     int             `json:"book_id"`
+	Stock      int             `json:"stock"`
+	Balance    int             `json:"balance"`
+	Reconciled bool            `json:"reconciled"`
+	Movements  []StockMovement `json:"movements"`
+}
+
+// execer is satisfied by both *Store and *Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// recordMovement appends one movement through q. A zero delta is skipped.
+func recordMovement(q execer, c *gin.Context, bookID, delta int, reason string) error {
+	if delta == 0 {
+		return nil
+	}
+	_, err := q.Exec("INSERT INTO stock_movements (book_id, delta, reason, actor) VALUES (?, ?, ?, ?)",
+		bookID, delta, reason, auditActor(c))
+	return err
+}
+
+// logMovement records a movement for changes made outside a transaction
+// (the repository writes). Like recordAudit it never fails the request.
+func logMovement(c *gin.Context, bookID, delta int, reason string) {
+	if db == nil {
+		return // in-memory repositories, nowhere to write
+	}
+	if err := recordMovement(db, c, bookID, delta, reason); err != nil {
+		log.Printf("movements: book %d %+d %s: %v", bookID, delta, reason, err)
+	}
+}
+
+// getBookMovements serves GET /books/:id/movements?limit=100, newest first.
+// Trashed books keep their ledger.
+func getBookMovements(c *gin.Context) {
+	ledger := MovementLedger{Movements: []StockMovement{}}
+	err := db.QueryRow("SELECT id, stock FROM books WHERE id = ? AND store_id = ?", c.Param("id"), storeID(c)).
+		Scan(&ledger.BookID, &ledger.Stock)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := db.QueryRow("SELECT COALESCE(SUM(delta), 0) FROM stock_movements WHERE book_id = ?", ledger.BookID).Scan(&ledger.Balance); err != nil {
+		internalError(c, err)
+		return
+	}
+	ledger.Reconciled = ledger.Balance == ledger.Stock
+
+	limit := parseIntQuery(c, "limit", 100)
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := db.Query(`SELECT id, book_id, delta, reason, COALESCE(actor, ''), created_at
+		FROM stock_movements WHERE book_id = ? ORDER BY id DESC LIMIT ?`, ledger.BookID, limit)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m StockMovement
+		if err := rows.Scan(&m.ID, &m.BookID, &m.Delta, &m.Reason, &m.Actor, &m.CreatedAt); err != nil {
+			internalError(c, err)
+			return
+		}
+		ledger.Movements = append(ledger.Movements, m)
+	}
+	c.JSON(http.StatusOK, ledger)
+}
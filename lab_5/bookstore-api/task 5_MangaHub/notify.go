@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Availability Notifications ----------
+
+// POST /books/:id/notify-me registers an email address or a webhook URL to
+// hear once when an out-of-stock book can be bought again. When stock rises
+// from zero the book's pending subscriptions are queued; a worker delivers
+// them and retries failures with exponential backoff. The queue lives in
+// the stock_subscriptions table, so retries survive a restart.
+
+const (
+	SubscriptionPending = "pending" // waiting for the book to come back
+	SubscriptionQueued  = "queued"  // due for (another) delivery attempt
+	SubscriptionSent    = "sent"
+	SubscriptionFailed  = "failed" // gave up after notifyMaxAttempts
+
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+
+	EventBookAvailable = "book.available"
+
+	notifyMaxAttempts  = 5
+	notifyBatchSize    = 50
+	notifyInitialDelay = 30 * time.Second
+)
+
+// notifyInterval is how often the worker looks for due deliveries besides
+// being woken by a restock: NOTIFY_INTERVAL_SECONDS, default 15.
+var notifyInterval = time.Duration(envInt("NOTIFY_INTERVAL_SECONDS", 15)) * time.Second
+
+var notifyWake = make(chan struct{}, 1)
+
+type NotifyMeRequest struct {
+	Email      string `json:"email" binding:"omitempty,email,max=255"`
+	WebhookURL string `json:"webhook_url" binding:"omitempty,url,max=255"`
+}
+
+type StockSubscription struct {
+	ID            int     `json:"id"`
+	BookID        int     `json:"book_id"`
+	Channel       string  `json:"channel"`
+	Target        string  `json:"target"`
+	Secret        string  `json:"secret,omitempty"`
+	Status        string  `json:"status"`
+	Attempts      int     `json:"attempts"`
+	LastError     *string `json:"last_error"`
+	NextAttemptAt *string `json:"next_attempt_at"`
+	NotifiedAt    *string `json:"notified_at"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+const subscriptionSelect = `SELECT id, book_id, channel, target, status, attempts, last_error, next_attempt_at, notified_at, created_at
+	FROM stock_subscriptions`
+
+func scanSubscription(row interface{ Scan(...any) error }) (StockSubscription, error) {
+	var s StockSubscription
+	err := row.Scan(&s.ID, &s.BookID, &s.Channel, &s.Target, &s.Status, &s.Attempts, &s.LastError, &s.NextAttemptAt, &s.NotifiedAt, &s.CreatedAt)
+	return s, err
+}
+
+// notifyMe serves POST /books/:id/notify-me with exactly one of email or
+// webhook_url. Subscribing again to the same target answers 200 and re-arms
+// the subscription if it was already sent or gave up.
+func notifyMe(c *gin.Context) {
+	id, ok := liveBook(c)
+	if !ok {
+		return
+	}
+	var req NotifyMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	channel, target := ChannelEmail, req.Email
+	switch {
+	case req.Email != "" && req.WebhookURL != "":
+		validationError(c, ErrorDetail{Field: "webhook_url", Message: "send either email or webhook_url, not both"})
+		return
+	case req.WebhookURL != "":
+		if u, err := url.Parse(req.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			validationError(c, ErrorDetail{Field: "webhook_url", Message: "must be an http(s) URL"})
+			return
+		}
+		channel, target = ChannelWebhook, req.WebhookURL
+	case req.Email == "":
+		validationError(c, ErrorDetail{Field: "email", Message: "email or webhook_url is required"})
+		return
+	}
+	book := snapshotBook(id)
+	if book.Stock > 0 {
+		respondError(c, http.StatusConflict, fmt.Sprintf("Book is in stock (%d available)", book.Stock))
+		return
+	}
+
+	status := http.StatusCreated
+	var exists bool
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM stock_subscriptions WHERE book_id = ? AND channel = ? AND target = ?)", id, channel, target).Scan(&exists)
+	var err error
+	if exists {
+		status = http.StatusOK
+		_, err = db.Exec(`UPDATE stock_subscriptions SET status = ?, attempts = 0, last_error = NULL, next_attempt_at = NULL, notified_at = NULL
+			WHERE book_id = ? AND channel = ? AND target = ? AND status IN (?, ?)`,
+			SubscriptionPending, id, channel, target, SubscriptionSent, SubscriptionFailed)
+	} else {
+		var secret any
+		if channel == ChannelWebhook {
+			secret = randomHex(16)
+		}
+		_, err = db.Exec("INSERT INTO stock_subscriptions (book_id, channel, target, secret, status) VALUES (?, ?, ?, ?, ?)",
+			id, channel, target, secret, SubscriptionPending)
+	}
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	sub, err := scanSubscription(db.QueryRow(subscriptionSelect+" WHERE book_id = ? AND channel = ? AND target = ?", id, channel, target))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	// Like webhook secrets, this is only shown when subscribing.
+	db.QueryRow("SELECT COALESCE(secret, '') FROM stock_subscriptions WHERE id = ?", sub.ID).Scan(&sub.Secret)
+	c.JSON(status, sub)
+}
+
+// getSubscribers serves GET /books/:id/subscribers?status= for staff.
+func getSubscribers(c *gin.Context) {
+	id, ok := liveBook(c)
+	if !ok {
+		return
+	}
+	q, args := subscriptionSelect+" WHERE book_id = ?", []any{id}
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case SubscriptionPending, SubscriptionQueued, SubscriptionSent, SubscriptionFailed:
+		default:
+			validationError(c, ErrorDetail{Field: "status", Message: "must be one of: pending, queued, sent, failed"})
+			return
+		}
+		q += " AND status = ?"
+		args = append(args, status)
+	}
+	rows, err := db.Query(q+" ORDER BY id", args...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	subs := []StockSubscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		subs = append(subs, s)
+	}
+	c.JSON(http.StatusOK, gin.H{"book_id": id, "subscribers": subs, "count": len(subs)})
+}
+
+// queueStockNotifications moves a book's pending subscriptions into the
+// delivery queue and wakes the worker. Call it when stock rises from zero.
+func queueStockNotifications(bookID int) {
+	now := time.Now().UTC().Format(sqlTimeLayout)
+	res, err := db.Exec("UPDATE stock_subscriptions SET status = ?, next_attempt_at = ? WHERE book_id = ? AND status = ?",
+		SubscriptionQueued, now, bookID, SubscriptionPending)
+	if err != nil {
+		log.Printf("notify: queue book %d: %v", bookID, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra > 0 {
+		select {
+		case notifyWake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// startStockNotifier runs the delivery worker.
+func startStockNotifier() {
+	if notifyInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(notifyInterval)
+		defer ticker.Stop()
+		for {
+			if err := deliverDueNotifications(); err != nil {
+				log.Printf("notify: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-notifyWake:
+			}
+		}
+	}()
+}
+
+type dueNotification struct {
+	StockSubscription
+	secret string
+	book   Book
+}
+
+// deliverDueNotifications makes one attempt at every queued subscription
+// whose next attempt is due, in batches.
+func deliverDueNotifications() error {
+	for {
+		now := time.Now().UTC()
+		rows, err := db.Query(`SELECT s.id, s.book_id, s.channel, s.target, COALESCE(s.secret, ''), s.attempts, b.title, b.price, b.stock
+			FROM stock_subscriptions s JOIN books b ON b.id = s.book_id
+			WHERE s.status = ? AND s.next_attempt_at <= ?
+			ORDER BY s.next_attempt_at, s.id LIMIT ?`, SubscriptionQueued, now.Format(sqlTimeLayout), notifyBatchSize)
+		if err != nil {
+			return err
+		}
+		var due []dueNotification
+		for rows.Next() {
+			var n dueNotification
+			if err := rows.Scan(&n.ID, &n.BookID, &n.Channel, &n.Target, &n.secret, &n.Attempts, &n.book.Title, &n.book.Price, &n.book.Stock); err != nil {
+				rows.Close()
+				return err
+			}
+			n.book.ID = n.BookID
+			due = append(due, n)
+		}
+		rows.Close()
+		if len(due) == 0 {
+			return nil
+		}
+		for _, n := range due {
+			recordDelivery(n, sendStockNotification(n), now)
+		}
+		if len(due) < notifyBatchSize {
+			return nil
+		}
+	}
+}
+
+func sendStockNotification(n dueNotification) error {
+	switch n.Channel {
+	case ChannelEmail:
+		body := fmt.Sprintf("Hello,\r\n\r\n%q is back in stock (%d available at %.2f).\r\n", n.book.Title, n.book.Stock, n.book.Price)
+		return sendMail([]string{n.Target}, "[Bookstore] Available again: "+n.book.Title, body)
+	case ChannelWebhook:
+		ev := WebhookEvent{
+			ID:        randomHex(8),
+			Type:      EventBookAvailable,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			Data:      gin.H{"subscription_id": n.ID, "book_id": n.BookID, "title": n.book.Title, "price": n.book.Price, "stock": n.book.Stock},
+		}
+		body, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPost, n.Target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Event", ev.Type)
+		req.Header.Set("X-Webhook-Delivery", ev.ID)
+		req.Header.Set("X-Webhook-Signature", "sha256="+signPayload(n.secret, body))
+		resp, err := webhookClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+	return errors.New("unknown channel " + n.Channel)
+}
+
+// recordDelivery stores the outcome of one attempt. Failures are retried
+// after 30s, 1m, 2m, ... until notifyMaxAttempts.
+func recordDelivery(n dueNotification, err error, now time.Time) {
+	attempts := n.Attempts + 1
+	var dbErr error
+	switch {
+	case err == nil:
+		_, dbErr = db.Exec("UPDATE stock_subscriptions SET status = ?, attempts = ?, last_error = NULL, next_attempt_at = NULL, notified_at = ? WHERE id = ?",
+			SubscriptionSent, attempts, now.Format(sqlTimeLayout), n.ID)
+	case attempts >= notifyMaxAttempts:
+		log.Printf("notify: subscription %d gave up after %d attempts: %v", n.ID, attempts, err)
+		_, dbErr = db.Exec("UPDATE stock_subscriptions SET status = ?, attempts = ?, last_error = ?, next_attempt_at = NULL WHERE id = ?",
+			SubscriptionFailed, attempts, err.Error(), n.ID)
+	default:
+		log.Printf("notify: subscription %d (attempt %d/%d): %v", n.ID, attempts, notifyMaxAttempts, err)
+		next := now.Add(notifyInitialDelay << (attempts - 1))
+		_, dbErr = db.Exec("UPDATE stock_subscriptions SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+			attempts, err.Error(), next.Format(sqlTimeLayout), n.ID)
+	}
+	if dbErr != nil {
+		log.Printf("notify: record subscription %d: %v", n.ID, dbErr)
+	}
+}
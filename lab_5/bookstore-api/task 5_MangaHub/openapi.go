@@ -0,0 +1,296 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- OpenAPI ----------
+
+// routeDoc describes one route for the OpenAPI document. Body and Response are
+// zero values of the Go types the handler binds and returns; their schemas are
+// derived by reflection so the spec follows the structs.
+type routeDoc struct {
+	Summary  string
+	Tag      string
+	Query    []string
+	Headers  []string
+	Body     any
+	Response any
+	Status   int
+}
+
+var routeDocs = map[string]routeDoc{
+	"GET /authors":           {Summary: "List authors", Tag: "authors", Response: []Author{}},
+	"GET /authors/:id":       {Summary: "Get an author", Tag: "authors", Response: Author{}},
+	"POST /authors":          {Summary: "Create an author", Tag: "authors", Body: Author{}, Response: Author{}, Status: http.StatusCreated},
+	"PUT /authors/:id":       {Summary: "Update an author", Tag: "authors", Body: Author{}, Response: Author{}},
+	"DELETE /authors/:id":    {Summary: "Move an author without books to the trash", Tag: "authors"},
+	"GET /authors/:id/books": {Summary: "List books of an author", Tag: "authors", Response: []BookWithAuthor{}},
+
+	"GET /books":               {Summary: "List books (page/limit, or keyset pagination with cursor and next_cursor)", Tag: "books", Query: []string{"page", "limit", "cursor", "fields", "tags", "tag_mode"}, Response: PaginatedBooksResponse{}},
+	"GET /books/search":        {Summary: "Ranked search: exact ISBN, then title, author and description matches, with one-typo tolerance; each result has a score", Tag: "books", Query: []string{"q", "limit"}},
+	"GET /books/suggest":       {Summary: "Typeahead: up to 10 books/authors whose title or name starts with q", Tag: "books", Query: []string{"q"}},
+	"GET /books/:id":           {Summary: "Get a book with its author", Tag: "books", Query: []string{"fields"}, Response: BookWithAuthor{}},
+	"POST /books":              {Summary: "Create a book", Tag: "books", Headers: []string{idempotencyHeader}, Body: Book{}, Response: Book{}, Status: http.StatusCreated},
+	"PUT /books/:id":           {Summary: "Replace a book; the version edited goes in If-Match or the body, 409 if it is stale", Tag: "books", Headers: []string{"If-Match"}, Body: Book{}, Response: Book{}},
+	"PATCH /books/:id":         {Summary: "Change some fields of a book; versioned like PUT", Tag: "books", Headers: []string{"If-Match"}, Body: BookPatch{}, Response: Book{}},
+	"POST /books/lookup/:isbn": {Summary: "Prefill a book from Open Library by ISBN (nothing is stored)", Tag: "books", Response: BookLookup{}},
+	"POST /books/:id/restock":  {Summary: "Add stock to a book", Tag: "inventory", Body: RestockRequest{}},
+	"GET /books/:id/movements": {Summary: "Stock ledger of a book (sell, restock, return, adjustment), newest first, with its balance against current stock", Tag: "inventory", Query: []string{"limit"}, Response: MovementLedger{}},
+	"POST /books/:id/return":   {Summary: "Return copies of a sale: restores stock and refunds against sales analytics", Tag: "inventory", Body: ReturnRequest{}, Response: SaleReturn{}, Status: http.StatusCreated},
+	"POST /books/:id/sell":     {Summary: "Sell copies of a book", Tag: "inventory", Headers: []string{idempotencyHeader}, Body: SellRequest{}},
+	"POST /books/bulk":         {Summary: "Create many books", Tag: "books", Body: BulkCreateRequest{}, Response: BulkCreateResponse{}, Status: http.StatusCreated},
+	"POST /books/import":       {Summary: "Import books from a CSV upload (multipart field 'file')", Tag: "books", Response: ImportReport{}, Status: http.StatusCreated},
+	"GET /books/export":        {Summary: "Download the catalog as CSV or JSON", Tag: "books", Query: []string{"format"}},
+
+	"GET /books/:id/tags":         {Summary: "List a book's tags", Tag: "tags"},
+	"POST /books/:id/tags":        {Summary: "Add tags to a book (lowercased; existing ones are ignored)", Tag: "tags", Body: TagsRequest{}},
+	"DELETE /books/:id/tags/:tag": {Summary: "Remove a tag from a book", Tag: "tags"},
+	"GET /tags":                   {Summary: "Tags in use with their book counts; filter books with GET /books?tags=a,b&tag_mode=all|any", Tag: "tags", Response: []TagCount{}},
+
+	"DELETE /books/:id":               {Summary: "Move a book to the trash", Tag: "books"},
+	"POST /books/:id/restore":         {Summary: "Restore a trashed book", Tag: "trash"},
+	"POST /books/:id/merge-duplicate": {Summary: "Fold a live or trashed copy with the same ISBN into this book (stock, sales, orders, wishlists)", Tag: "books", Body: MergeDuplicateRequest{}, Response: MergeDuplicateResponse{}},
+	"POST /authors/:id/restore":       {Summary: "Restore a trashed author", Tag: "trash"},
+	"GET /trash/books":                {Summary: "List trashed books", Tag: "trash"},
+	"GET /trash/authors":              {Summary: "List trashed authors", Tag: "trash"},
+	"DELETE /trash/books/:id":         {Summary: "Permanently delete a trashed book", Tag: "trash"},
+	"DELETE /trash/authors/:id":       {Summary: "Permanently delete a trashed author", Tag: "trash"},
+
+	"GET /audit":  {Summary: "Query the audit trail", Tag: "audit", Query: []string{"entity", "id", "action", "limit"}},
+	"GET /alerts": {Summary: "List low-stock alerts raised by the inventory scan", Tag: "alerts", Query: []string{"status", "limit"}},
+
+	"POST /webhooks":       {Summary: "Register a webhook (events: book.created, book.sold, stock.low, alert.raised, wishlist.back_in_stock)", Tag: "webhooks", Body: Webhook{}, Response: Webhook{}, Status: http.StatusCreated},
+	"GET /webhooks":        {Summary: "List webhooks", Tag: "webhooks", Response: []Webhook{}},
+	"DELETE /webhooks/:id": {Summary: "Remove a webhook", Tag: "webhooks"},
+
+	"GET /books/:id/lending": {Summary: "Lendable copies of a book, how many are on loan and how many are free", Tag: "loans", Response: LendingStatus{}},
+	"PUT /books/:id/lending": {Summary: "Set how many copies are kept for lending (separate from stock for sale)", Tag: "loans", Body: LendingRequest{}, Response: LendingStatus{}},
+	"GET /loans":             {Summary: "List loans; status=active (default), overdue or returned", Tag: "loans", Query: []string{"status", "customer_id", "book_id"}},
+	"POST /loans":            {Summary: "Check a book out to a customer for days (default 14); 409 when no lendable copy is free", Tag: "loans", Body: Loan{}, Response: Loan{}, Status: http.StatusCreated},
+	"POST /loans/:id/return": {Summary: "Return a loaned copy", Tag: "loans", Response: Loan{}},
+
+	"POST /books/:id/notify-me":  {Summary: "Get notified once (email or webhook_url) when an out-of-stock book is back; 409 if it is in stock", Tag: "notifications", Body: NotifyMeRequest{}, Response: StockSubscription{}, Status: http.StatusCreated},
+	"GET /books/:id/subscribers": {Summary: "List a book's notify-me subscriptions and their delivery state (staff)", Tag: "notifications", Query: []string{"status"}},
+
+	"POST /carts":                      {Summary: "Open a cart, optionally for customer_id", Tag: "carts", Body: CartRequest{}, Response: Cart{}, Status: http.StatusCreated},
+	"GET /carts/:id":                   {Summary: "Get a cart with its lines priced at current prices", Tag: "carts", Response: Cart{}},
+	"POST /carts/:id/items":            {Summary: "Add a book to a cart; adding it again raises the quantity", Tag: "carts", Body: CartItem{}, Response: Cart{}, Status: http.StatusCreated},
+	"PUT /carts/:id/items/:book_id":    {Summary: "Set a line's quantity; 0 removes it", Tag: "carts", Body: CartQuantityRequest{}, Response: Cart{}},
+	"DELETE /carts/:id/items/:book_id": {Summary: "Remove a book from a cart", Tag: "carts", Response: Cart{}},
+	"POST /carts/:id/checkout":         {Summary: "Turn a cart into an order atomically; 400 insufficient_stock lists every line that cannot be filled", Tag: "carts", Response: Order{}, Status: http.StatusCreated},
+	"GET /orders/:id":                  {Summary: "Get an order with its sales", Tag: "carts", Response: Order{}},
+
+	"GET /series":                         {Summary: "List series", Tag: "series", Response: []Series{}},
+	"GET /series/:id":                     {Summary: "Get a series with its volumes in order, missing volumes and completion", Tag: "series", Response: SeriesDetail{}},
+	"POST /series":                        {Summary: "Create a series (status: ongoing, completed, hiatus)", Tag: "series", Body: Series{}, Response: Series{}, Status: http.StatusCreated},
+	"POST /series/:id/volumes/:book_id":   {Summary: "Add a book as a volume; body {\"volume\": n} is optional (default: next number)", Tag: "series", Body: AddVolumeRequest{}, Status: http.StatusCreated},
+	"DELETE /series/:id/volumes/:book_id": {Summary: "Remove a volume from a series", Tag: "series"},
+
+	"POST /customers":                         {Summary: "Create a customer", Tag: "customers", Body: Customer{}, Response: Customer{}, Status: http.StatusCreated},
+	"GET /customers/:id":                      {Summary: "Get a customer", Tag: "customers", Response: Customer{}},
+	"GET /customers/:id/wishlist":             {Summary: "List a customer's wishlist with stock status", Tag: "customers", Response: []WishlistItem{}},
+	"POST /customers/:id/wishlist/:book_id":   {Summary: "Add a book to the wishlist (notified via wishlist.back_in_stock when restocked)", Tag: "customers", Status: http.StatusCreated},
+	"GET /customers/:id/progress":             {Summary: "Reading progress per book and completion percentage per series", Tag: "customers"},
+	"PUT /customers/:id/progress/:book_id":    {Summary: "Record the last chapter read and/or mark a volume finished", Tag: "customers", Body: ProgressUpdate{}, Response: ReadingProgress{}},
+	"DELETE /customers/:id/wishlist/:book_id": {Summary: "Remove a book from the wishlist", Tag: "customers"},
+
+	"GET /suppliers":                    {Summary: "List suppliers", Tag: "suppliers", Response: []Supplier{}},
+	"GET /suppliers/:id":                {Summary: "Get a supplier", Tag: "suppliers", Response: Supplier{}},
+	"POST /suppliers":                   {Summary: "Create a supplier", Tag: "suppliers", Body: Supplier{}, Response: Supplier{}, Status: http.StatusCreated},
+	"GET /purchase-orders":              {Summary: "List purchase orders (outstanding by default)", Tag: "suppliers", Query: []string{"status", "supplier_id", "book_id"}, Response: []PurchaseOrder{}},
+	"GET /purchase-orders/:id":          {Summary: "Get a purchase order", Tag: "suppliers", Response: PurchaseOrder{}},
+	"POST /purchase-orders":             {Summary: "Order stock for a book from a supplier", Tag: "suppliers", Body: PurchaseOrder{}, Response: PurchaseOrder{}, Status: http.StatusCreated},
+	"POST /purchase-orders/:id/receive": {Summary: "Mark an order received and add its quantity to stock", Tag: "suppliers"},
+	"POST /purchase-orders/:id/cancel":  {Summary: "Cancel a pending order", Tag: "suppliers", Response: PurchaseOrder{}},
+
+	"POST /graphql": {Summary: "GraphQL endpoint: books, book, authors, author; createBook, updateBook, createAuthor, updateAuthor", Tag: "graphql", Body: GraphQLRequest{}},
+
+	"GET /admin/dashboard":     {Summary: "Stats, low-stock books, latest orders, recent audit entries and 30-day top sellers in one call", Tag: "statistics", Response: Dashboard{}},
+	"POST /admin/backup":       {Summary: "Download a consistent copy of the SQLite database (VACUUM INTO); 501 on other databases", Tag: "admin"},
+	"POST /admin/restore":      {Summary: "Replace the SQLite database with an uploaded backup (multipart field file), after an integrity and schema check", Tag: "admin"},
+	"GET /analytics/sales":     {Summary: "Revenue, units and top sellers per day, week or month", Tag: "analytics", Query: []string{"group_by", "from", "to", "top"}, Response: SalesReport{}},
+	"GET /analytics/books/:id": {Summary: "Sales curve of a single book", Tag: "analytics", Query: []string{"group_by", "from", "to"}, Response: BookSalesReport{}},
+
+	"GET /stores":     {Summary: "List stores; scope any other route with X-Store-ID or a /stores/:store_id prefix", Tag: "stores", Response: []Tenant{}},
+	"GET /stores/:id": {Summary: "Get a store", Tag: "stores", Response: Tenant{}},
+	"POST /stores":    {Summary: "Create a store", Tag: "stores", Body: Tenant{}, Response: Tenant{}, Status: http.StatusCreated},
+
+	"GET /stats":            {Summary: "Catalog statistics", Tag: "statistics", Response: Statistics{}},
+	"POST /stats/recompute": {Summary: "Rebuild the statistics counters from the database", Tag: "statistics", Response: Statistics{}},
+	"GET /stats/report":     {Summary: "Downloadable report (csv, default, or json): summary, books per year, low stock, top authors by inventory value", Tag: "statistics", Query: []string{"format"}, Response: StatsReport{}},
+	"GET /stats/history":    {Summary: "Daily statistics snapshots for trend charts", Tag: "statistics", Query: []string{"days"}},
+	"GET /healthz":          {Summary: "Liveness probe", Tag: "health", Response: HealthResponse{}},
+	"GET /readyz":           {Summary: "Readiness probe (database, migrations, disk); 503 when not ready", Tag: "health", Response: HealthResponse{}},
+	"GET /openapi.json":     {Summary: "This document", Tag: "docs"},
+	"GET /":                 {Summary: "This document", Tag: "docs"},
+}
+
+var openAPISpec gin.H
+
+// buildOpenAPISpec walks the registered routes so every route shows up in the
+// document, even ones without an entry in routeDocs.
+func buildOpenAPISpec(routes gin.RoutesInfo) gin.H {
+	paths := gin.H{}
+	errorContent := gin.H{"application/json": gin.H{"schema": schemaFor(reflect.TypeOf(ErrorResponse{}))}}
+	for _, r := range routes {
+		doc, ok := routeDocs[r.Method+" "+r.Path]
+		if !ok {
+			doc = routeDoc{Summary: r.Method + " " + r.Path}
+		}
+
+		var params []gin.H
+		var segments []string
+		for _, seg := range strings.Split(r.Path, "/") {
+			if strings.HasPrefix(seg, ":") {
+				name := seg[1:]
+				params = append(params, gin.H{"name": name, "in": "path", "required": true, "schema": gin.H{"type": "string"}})
+				seg = "{" + name + "}"
+			}
+			segments = append(segments, seg)
+		}
+		for _, q := range doc.Query {
+			params = append(params, gin.H{"name": q, "in": "query", "schema": gin.H{"type": "string"}})
+		}
+		for _, h := range doc.Headers {
+			params = append(params, gin.H{"name": h, "in": "header", "schema": gin.H{"type": "string"}})
+		}
+
+		status := doc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := gin.H{"description": http.StatusText(status)}
+		if doc.Response != nil {
+			response["content"] = gin.H{"application/json": gin.H{"schema": schemaFor(reflect.TypeOf(doc.Response))}}
+		}
+		op := gin.H{
+			"summary": doc.Summary,
+			"responses": gin.H{
+				strconv.Itoa(status): response,
+				"400":                gin.H{"description": "Invalid request", "content": errorContent},
+				"default":            gin.H{"description": "Error", "content": errorContent},
+			},
+		}
+		if doc.Tag != "" {
+			op["tags"] = []string{doc.Tag}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if doc.Body != nil {
+			op["requestBody"] = gin.H{
+				"required": true,
+				"content":  gin.H{"application/json": gin.H{"schema": schemaFor(reflect.TypeOf(doc.Body))}},
+			}
+		}
+
+		path := strings.Join(segments, "/")
+		item, _ := paths[path].(gin.H)
+		if item == nil {
+			item = gin.H{}
+			paths[path] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+
+	return gin.H{
+		"openapi": "3.0.3",
+		"info": gin.H{
+			"title":   "MangaHub Bookstore API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+	}
+}
+
+// schemaFor converts a Go type into an OpenAPI schema using json and binding tags.
+func schemaFor(t reflect.Type) gin.H {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return gin.H{"type": "string"}
+	case reflect.Bool:
+		return gin.H{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return gin.H{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return gin.H{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return gin.H{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return gin.H{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		props := gin.H{}
+		var required []string
+		addStructFields(t, props, &required)
+		schema := gin.H{"type": "object", "properties": props}
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+		return schema
+	}
+	return gin.H{}
+}
+
+func addStructFields(t reflect.Type, props gin.H, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			addStructFields(f.Type, props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema := schemaFor(f.Type)
+		for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+			key, value, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				*required = append(*required, name)
+			case "min", "gte":
+				applyBound(schema, "minimum", "minLength", "minItems", value)
+			case "max", "lte":
+				applyBound(schema, "maximum", "maxLength", "maxItems", value)
+			case "gt":
+				applyBound(schema, "minimum", "minLength", "minItems", value)
+				schema["exclusiveMinimum"] = true
+			}
+		}
+		props[name] = schema
+	}
+}
+
+// applyBound maps a validator bound onto the keyword matching the schema type.
+func applyBound(schema gin.H, numKey, strKey, arrKey, value string) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema["type"] {
+	case "string":
+		schema[strKey] = int(n)
+	case "array":
+		schema[arrKey] = int(n)
+	default:
+		schema[numKey] = n
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Open Library Lookup ----------
+
+var openLibraryClient = &http.Client{Timeout: 5 * time.Second}
+
+// BookLookup is a prefilled create request built from Open Library data.
+// AuthorID is set when an author with the same name already exists.
+type BookLookup struct {
+	Title         string `json:"title"`
+	ISBN          string `json:"isbn"`
+	AuthorID      int    `json:"author_id,omitempty"`
+	AuthorName    string `json:"author_name"`
+	PublishedYear int    `json:"published_year,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Source        string `json:"source"`
+}
+
+type openLibraryBook struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Authors  []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	PublishDate string `json:"publish_date"`
+	Notes       any    `json:"notes"`
+}
+
+func openLibraryURL() string {
+	if u := os.Getenv("OPENLIBRARY_URL"); u != "" {
+		return u
+	}
+	return "https://openlibrary.org"
+}
+
+// fetchOpenLibrary queries the Books API for one ISBN. It returns nil when
+// Open Library has no record.
+func fetchOpenLibrary(isbn string) (*openLibraryBook, error) {
+	key := "ISBN:" + isbn
+	q := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"data"}}
+	resp, err := openLibraryClient.Get(openLibraryURL() + "/api/books?" + q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("open library returned %s", resp.Status)
+	}
+	var result map[string]openLibraryBook
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	book, ok := result[key]
+	if !ok {
+		return nil, nil
+	}
+	return &book, nil
+}
+
+// lookupBook serves POST /books/lookup/:isbn. Nothing is stored; the result
+// can be edited and sent to POST /books.
+func lookupBook(c *gin.Context) {
+	isbn, err := normalizeISBN(c.Param("isbn"))
+	if err != nil {
+		validationError(c, ErrorDetail{Field: "isbn", Message: err.Error()})
+		return
+	}
+	ol, err := fetchOpenLibrary(isbn)
+	if err != nil {
+		respondError(c, http.StatusBadGateway, "Open Library lookup failed")
+		return
+	}
+	if ol == nil {
+		respondError(c, http.StatusNotFound, "No Open Library record for ISBN "+isbn)
+		return
+	}
+
+	lookup := BookLookup{Title: ol.Title, ISBN: isbn, Source: "openlibrary"}
+	if ol.Subtitle != "" {
+		lookup.Title += ": " + ol.Subtitle
+	}
+	if year := regexp.MustCompile(`\d{4}`).FindString(ol.PublishDate); year != "" {
+		lookup.PublishedYear, _ = strconv.Atoi(year)
+	}
+	if notes, ok := ol.Notes.(string); ok {
+		lookup.Description = notes
+	}
+	if len(ol.Authors) > 0 {
+		lookup.AuthorName = ol.Authors[0].Name
+		db.QueryRow("SELECT id FROM authors WHERE LOWER(name) = LOWER(?) AND store_id = ? AND deleted_at IS NULL", lookup.AuthorName, storeID(c)).Scan(&lookup.AuthorID)
+	}
+	c.JSON(http.StatusOK, lookup)
+}
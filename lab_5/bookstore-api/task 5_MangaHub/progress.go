@@ -0,0 +1,190 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Reading Progress ----------
+
+// ProgressUpdate is the body of PUT /customers/:id/progress/:book_id.
+// Chapter is the last chapter read; Finished marks the whole volume as read.
+// Fields left out keep their value.
+type ProgressUpdate struct {
+	Chapter  *int  `json:"chapter" binding:"omitempty,gte=0"`
+	Finished *bool `json:"finished"`
+}
+
+type ReadingProgress struct {
+	BookID     int     `json:"book_id"`
+	Title      string  `json:"title"`
+	SeriesID   *int    `json:"series_id,omitempty"`
+	Volume     *int    `json:"volume,omitempty"`
+	Chapter    int     `json:"chapter"`
+	Finished   bool    `json:"finished"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+// SeriesProgress summarizes a series the customer has started. Volumes is the
+// published count when known, otherwise the volumes in the catalog.
+type SeriesProgress struct {
+	SeriesID    int     `json:"series_id"`
+	Title       string  `json:"title"`
+	VolumesRead int     `json:"volumes_read"`
+	Volumes     int     `json:"volumes"`
+	Percent     float64 `json:"percent"`
+}
+
+const readingProgressSelect = `SELECT p.book_id, b.title, v.series_id, v.volume_number, p.chapter, p.finished_at, p.updated_at
+	FROM reading_progress p
+	JOIN books b ON b.id = p.book_id
+	LEFT JOIN series_volumes v ON v.book_id = p.book_id`
+
+func scanReadingProgress(row interface{ Scan(...any) error }) (ReadingProgress, error) {
+	var p ReadingProgress
+	err := row.Scan(&p.BookID, &p.Title, &p.SeriesID, &p.Volume, &p.Chapter, &p.FinishedAt, &p.UpdatedAt)
+	p.Finished = p.FinishedAt != nil
+	return p, err
+}
+
+// updateProgress serves PUT /customers/:id/progress/:book_id, creating the
+// entry on first use.
+func updateProgress(c *gin.Context) {
+	id := c.Param("id")
+	if !customerExists(id) {
+		respondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+	bookID, ok := paramID(c, "book_id")
+	if ok {
+		_, err := bookRepo.Get(c.Request.Context(), bookID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			internalError(c, err)
+			return
+		}
+		ok = err == nil
+	}
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	var req ProgressUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if req.Chapter == nil && req.Finished == nil {
+		validationError(c, ErrorDetail{Message: "send chapter, finished or both"})
+		return
+	}
+
+	var chapter int
+	var finishedAt *string
+	err := db.QueryRow("SELECT chapter, finished_at FROM reading_progress WHERE customer_id = ? AND book_id = ?", id, bookID).
+		Scan(&chapter, &finishedAt)
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		internalError(c, err)
+		return
+	}
+	if req.Chapter != nil {
+		chapter = *req.Chapter
+	}
+	finished := finishedAt != nil
+	if req.Finished != nil {
+		finished = *req.Finished
+	}
+	finishedExpr := "NULL"
+	if finished {
+		finishedExpr = "CURRENT_TIMESTAMP"
+	}
+	if exists {
+		if finished {
+			// Keep the original finish time when a finished book is touched again.
+			finishedExpr = "COALESCE(finished_at, CURRENT_TIMESTAMP)"
+		}
+		_, err = db.Exec("UPDATE reading_progress SET chapter = ?, finished_at = "+finishedExpr+", updated_at = CURRENT_TIMESTAMP WHERE customer_id = ? AND book_id = ?",
+			chapter, id, bookID)
+	} else {
+		_, err = db.Exec("INSERT INTO reading_progress (customer_id, book_id, chapter, finished_at) VALUES (?, ?, ?, "+finishedExpr+")",
+			id, bookID, chapter)
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	p, err := scanReadingProgress(db.QueryRow(readingProgressSelect+" WHERE p.customer_id = ? AND p.book_id = ?", id, bookID))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// getProgress serves GET /customers/:id/progress: every book the customer
+// has started in the current store, plus completion per series.
+func getProgress(c *gin.Context) {
+	id := c.Param("id")
+	if !customerExists(id) {
+		respondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+	rows, err := db.Query(readingProgressSelect+` WHERE p.customer_id = ? AND b.store_id = ? AND b.deleted_at IS NULL
+		ORDER BY p.updated_at DESC, p.book_id`, id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	books := []ReadingProgress{}
+	for rows.Next() {
+		p, err := scanReadingProgress(rows)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		books = append(books, p)
+	}
+	rows.Close()
+
+	srows, err := db.Query(`SELECT s.id, s.title, s.total_volumes,
+			(SELECT COUNT(*) FROM series_volumes v2 JOIN books b2 ON b2.id = v2.book_id
+				WHERE v2.series_id = s.id AND b2.deleted_at IS NULL),
+			COUNT(p.finished_at)
+		FROM series s
+		JOIN series_volumes v ON v.series_id = s.id
+		JOIN reading_progress p ON p.book_id = v.book_id AND p.customer_id = ?
+		WHERE s.store_id = ?
+		GROUP BY s.id, s.title, s.total_volumes
+		ORDER BY s.title`, id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer srows.Close()
+	series := []SeriesProgress{}
+	for srows.Next() {
+		var sp SeriesProgress
+		var total, inCatalog int
+		if err := srows.Scan(&sp.SeriesID, &sp.Title, &total, &inCatalog, &sp.VolumesRead); err != nil {
+			internalError(c, err)
+			return
+		}
+		sp.Volumes = inCatalog
+		if total > 0 {
+			sp.Volumes = total
+		}
+		if sp.Volumes > 0 {
+			sp.Percent = math.Min(100, math.Round(float64(sp.VolumesRead)/float64(sp.Volumes)*1000)/10)
+		}
+		series = append(series, sp)
+	}
+	customerID, _ := strconv.Atoi(id)
+	c.JSON(http.StatusOK, gin.H{"customer_id": customerID, "books": books, "series": series})
+}
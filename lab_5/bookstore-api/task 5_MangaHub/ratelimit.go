@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Rate Limiting ----------
+
+// tokenBucket refills continuously at rate tokens/second up to burst.
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+// newRateLimiter allows perMinute requests per client on average, with bursts
+// of up to burst requests.
+func newRateLimiter(perMinute, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   burst,
+		buckets: map[string]*tokenBucket{},
+		swept:   time.Now(),
+	}
+}
+
+// envInt reads an integer setting from the environment.
+func envInt(name string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// rateLimitKey identifies the caller: API key when present, otherwise client IP.
+func rateLimitKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return "key:" + key
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// take consumes one token for key and reports whether the request may proceed,
+// the tokens left and how long until the next token is available.
+func (rl *rateLimiter) take(key string) (bool, int, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(rl.swept) > 10*time.Minute {
+		for k, b := range rl.buckets {
+			if now.Sub(b.lastSeen) > 10*time.Minute {
+				delete(rl.buckets, k)
+			}
+		}
+		rl.swept = now
+	}
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(rl.burst), lastSeen: now}
+		rl.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(rl.burst), b.tokens+now.Sub(b.lastSeen).Seconds()*rl.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+	var reset time.Duration
+	if b.tokens < 1 {
+		reset = time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	}
+	return true, int(b.tokens), reset
+}
+
+// middleware rejects callers that exhausted their bucket with 429 and
+// reports the bucket state in X-RateLimit-* headers.
+func (rl *rateLimiter) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, remaining, reset := rl.take(rateLimitKey(c))
+		resetSeconds := int(math.Ceil(reset.Seconds()))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(resetSeconds))
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(resetSeconds))
+			respondError(c, http.StatusTooManyRequests, fmt.Sprintf("Rate limit exceeded, retry in %d seconds", resetSeconds))
+			return
+		}
+		c.Next()
+	}
+}
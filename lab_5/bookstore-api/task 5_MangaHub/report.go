@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Statistics Report ----------
+
+// GET /stats/report?format=csv|json is a downloadable statistics report:
+// summary, books per year, the low-stock list and the top authors by
+// inventory value. Every section is read in one transaction, so the numbers
+// agree with each other even while the catalog is being written to.
+
+const reportTopAuthors = 10
+
+type StatsReport struct {
+	StoreID     int            `json:"store_id"`
+	GeneratedAt string         `json:"generated_at"`
+	Summary     ReportSummary  `json:"summary"`
+	BooksByYear []YearCount    `json:"books_by_year"`
+	LowStock    []LowStockBook `json:"low_stock"`
+	TopAuthors  []AuthorValue  `json:"top_authors"`
+}
+
+type ReportSummary struct {
+	TotalBooks   int     `json:"total_books"`
+	TotalAuthors int     `json:"total_authors"`
+	TotalUnits   int     `json:"total_units"`
+	TotalValue   float64 `json:"total_value"`
+	AveragePrice float64 `json:"average_price"`
+	LowStock     int     `json:"low_stock"`
+	OutOfStock   int     `json:"out_of_stock"`
+	Threshold    int     `json:"low_stock_threshold"`
+}
+
+type YearCount struct {
+	Year  int `json:"year"`
+	Books int `json:"books"`
+}
+
+type AuthorValue struct {
+	AuthorID int     `json:"author_id"`
+	Name     string  `json:"name"`
+	Books    int     `json:"books"`
+	Units    int     `json:"units"`
+	Value    float64 `json:"value"`
+}
+
+func getStatsReport(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		respondError(c, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+	report, err := buildStatsReport(c)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("stats_report_%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	writeStatsReportCSV(csv.NewWriter(c.Writer), report)
+}
+
+func buildStatsReport(c *gin.Context) (StatsReport, error) {
+	store := storeID(c)
+	r := StatsReport{
+		StoreID:     store,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		BooksByYear: []YearCount{},
+		LowStock:    []LowStockBook{},
+		TopAuthors:  []AuthorValue{},
+	}
+	tx, err := db.BeginTx(c.Request.Context(), nil)
+	if err != nil {
+		return r, err
+	}
+	defer tx.Rollback()
+
+	s := &r.Summary
+	s.Threshold = lowStockThreshold
+	err = tx.QueryRow(`SELECT COUNT(*), COALESCE(SUM(stock), 0), COALESCE(SUM(price*stock), 0), COALESCE(AVG(price), 0),
+			COALESCE(SUM(CASE WHEN stock > 0 AND stock < ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN stock = 0 THEN 1 ELSE 0 END), 0)
+		FROM books WHERE store_id = ? AND deleted_at IS NULL`, lowStockThreshold, store).
+		Scan(&s.TotalBooks, &s.TotalUnits, &s.TotalValue, &s.AveragePrice, &s.LowStock, &s.OutOfStock)
+	if err != nil {
+		return r, err
+	}
+	if err := tx.QueryRow("SELECT COUNT(*) FROM authors WHERE store_id = ? AND deleted_at IS NULL", store).Scan(&s.TotalAuthors); err != nil {
+		return r, err
+	}
+
+	rows, err := tx.Query(`SELECT COALESCE(published_year, 0), COUNT(*) FROM books
+		WHERE store_id = ? AND deleted_at IS NULL
+		GROUP BY COALESCE(published_year, 0) ORDER BY 1`, store)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var y YearCount
+		if err := rows.Scan(&y.Year, &y.Books); err != nil {
+			return r, err
+		}
+		r.BooksByYear = append(r.BooksByYear, y)
+	}
+
+	rows, err = tx.Query(`SELECT id, title, stock FROM books
+		WHERE store_id = ? AND deleted_at IS NULL AND stock < ?
+		ORDER BY stock, id`, store, lowStockThreshold)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b LowStockBook
+		if err := rows.Scan(&b.BookID, &b.Title, &b.Stock); err != nil {
+			return r, err
+		}
+		r.LowStock = append(r.LowStock, b)
+	}
+
+	rows, err = tx.Query(`SELECT a.id, a.name, COUNT(b.id), SUM(b.stock), SUM(b.price*b.stock) AS value
+		FROM authors a JOIN books b ON b.author_id = a.id AND b.deleted_at IS NULL
+		WHERE a.store_id = ? AND a.deleted_at IS NULL
+		GROUP BY a.id, a.name
+		ORDER BY value DESC, a.id LIMIT ?`, store, reportTopAuthors)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a AuthorValue
+		if err := rows.Scan(&a.AuthorID, &a.Name, &a.Books, &a.Units, &a.Value); err != nil {
+			return r, err
+		}
+		r.TopAuthors = append(r.TopAuthors, a)
+	}
+	return r, rows.Err()
+}
+
+// writeStatsReportCSV writes one titled block per section, separated by
+// blank lines, so the file opens cleanly in a spreadsheet.
+func writeStatsReportCSV(w *csv.Writer, r StatsReport) {
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	s := r.Summary
+	w.Write([]string{"Summary"})
+	w.Write([]string{"metric", "value"})
+	for _, kv := range [][2]string{
+		{"store_id", strconv.Itoa(r.StoreID)},
+		{"generated_at", r.GeneratedAt},
+		{"total_books", strconv.Itoa(s.TotalBooks)},
+		{"total_authors", strconv.Itoa(s.TotalAuthors)},
+		{"total_units", strconv.Itoa(s.TotalUnits)},
+		{"total_value", money(s.TotalValue)},
+		{"average_price", money(s.AveragePrice)},
+		{"low_stock", strconv.Itoa(s.LowStock)},
+		{"out_of_stock", strconv.Itoa(s.OutOfStock)},
+		{"low_stock_threshold", strconv.Itoa(s.Threshold)},
+	} {
+		w.Write(kv[:])
+	}
+
+	w.Write(nil)
+	w.Write([]string{"Books by year"})
+	w.Write([]string{"year", "books"})
+	for _, y := range r.BooksByYear {
+		w.Write([]string{strconv.Itoa(y.Year), strconv.Itoa(y.Books)})
+	}
+
+	w.Write(nil)
+	w.Write([]string{"Low stock"})
+	w.Write([]string{"book_id", "title", "stock"})
+	for _, b := range r.LowStock {
+		w.Write([]string{strconv.Itoa(b.BookID), b.Title, strconv.Itoa(b.Stock)})
+	}
+
+	w.Write(nil)
+	w.Write([]string{"Top authors by inventory value"})
+	w.Write([]string{"author_id", "name", "books", "units", "value"})
+	for _, a := range r.TopAuthors {
+		w.Write([]string{strconv.Itoa(a.AuthorID), a.Name, strconv.Itoa(a.Books), strconv.Itoa(a.Units), money(a.Value)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("report: write failed: %v", err)
+	}
+}
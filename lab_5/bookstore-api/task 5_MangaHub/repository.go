@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ---------- Repositories ----------
+
+// ErrNotFound is returned by repositories when no live (non-trashed) row
+// matches in the store the context is scoped to (see storeFrom).
+var ErrNotFound = errors.New("not found")
+
+// ErrVersionConflict is returned by BookRepository.Update when the row is no
+// longer at the version the caller read.
+var ErrVersionConflict = errors.New("version conflict")
+
+// AuthorRepository is the storage the author handlers depend on. The SQL
+// implementation is used in production; memAuthorRepository stands in for it
+// in handler tests.
+type AuthorRepository interface {
+	List(ctx context.Context) ([]Author, error)
+	Get(ctx context.Context, id int) (*Author, error)
+	Create(ctx context.Context, a *Author) error
+	Update(ctx context.Context, a *Author) error
+	// Delete moves the author to the trash.
+	Delete(ctx context.Context, id int) error
+}
+
+// BookRepository is the storage the book handlers depend on.
+type BookRepository interface {
+	Get(ctx context.Context, id int) (*BookWithAuthor, error)
+	ListByAuthor(ctx context.Context, authorID int) ([]BookWithAuthor, error)
+	CountByAuthor(ctx context.Context, authorID int) (int, error)
+	Create(ctx context.Context, b *Book) error
+	// Update writes b only if the row is still at b.Version, then bumps
+	// b.Version.
+	Update(ctx context.Context, b *Book) error
+	// Delete moves the book to the trash.
+	Delete(ctx context.Context, id int) error
+}
+
+var (
+	authorRepo AuthorRepository
+	bookRepo   BookRepository
+)
+
+// ---------- SQL Implementation ----------
+
+type sqlAuthorRepository struct{ store *Store }
+
+type sqlBookRepository struct{ store *Store }
+
+func newSQLAuthorRepository(store *Store) AuthorRepository { return &sqlAuthorRepository{store} }
+
+func newSQLBookRepository(store *Store) BookRepository { return &sqlBookRepository{store} }
+
+const authorSelect = "SELECT id, name, COALESCE(bio, ''), COALESCE(birth_year, 0), COALESCE(country, ''), created_at FROM authors"
+
+func scanAuthor(row interface{ Scan(...any) error }) (Author, error) {
+	var a Author
+	err := row.Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt)
+	return a, err
+}
+
+func (r *sqlAuthorRepository) List(ctx context.Context) ([]Author, error) {
+	rows, err := r.store.QueryContext(ctx, authorSelect+" WHERE store_id = ? AND deleted_at IS NULL ORDER BY id", storeFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	authors := []Author{}
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+func (r *sqlAuthorRepository) Get(ctx context.Context, id int) (*Author, error) {
+	a, err := scanAuthor(r.store.QueryRowContext(ctx, authorSelect+" WHERE id = ? AND store_id = ? AND deleted_at IS NULL", id, storeFrom(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *sqlAuthorRepository) Create(ctx context.Context, a *Author) error {
+	res, err := r.store.ExecContext(ctx, "INSERT INTO authors (name, bio, birth_year, country, store_id) VALUES (?, ?, ?, ?, ?)",
+		a.Name, a.Bio, a.BirthYear, a.Country, storeFrom(ctx))
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+	return r.store.QueryRowContext(ctx, "SELECT created_at FROM authors WHERE id = ?", a.ID).Scan(&a.CreatedAt)
+}
+
+func (r *sqlAuthorRepository) Update(ctx context.Context, a *Author) error {
+	res, err := r.store.ExecContext(ctx, "UPDATE authors SET name=?, bio=?, birth_year=?, country=? WHERE id=? AND store_id=? AND deleted_at IS NULL",
+		a.Name, a.Bio, a.BirthYear, a.Country, a.ID, storeFrom(ctx))
+	return rowsAffectedOrNotFound(res, err)
+}
+
+func (r *sqlAuthorRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.store.ExecContext(ctx, "UPDATE authors SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND store_id = ? AND deleted_at IS NULL", id, storeFrom(ctx))
+	return rowsAffectedOrNotFound(res, err)
+}
+
+const bookWithAuthorSelect = `SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), COALESCE(a.bio, ''), b.isbn, b.price, b.stock,
+	COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version
+	FROM books b LEFT JOIN authors a ON b.author_id = a.id`
+
+func scanBookWithAuthor(row interface{ Scan(...any) error }) (BookWithAuthor, error) {
+	var b BookWithAuthor
+	err := row.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.AuthorBio, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version)
+	return b, err
+}
+
+func (r *sqlBookRepository) Get(ctx context.Context, id int) (*BookWithAuthor, error) {
+	b, err := scanBookWithAuthor(r.store.QueryRowContext(ctx, bookWithAuthorSelect+" WHERE b.id = ? AND b.store_id = ? AND b.deleted_at IS NULL", id, storeFrom(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *sqlBookRepository) ListByAuthor(ctx context.Context, authorID int) ([]BookWithAuthor, error) {
+	rows, err := r.store.QueryContext(ctx, bookWithAuthorSelect+" WHERE b.author_id = ? AND b.store_id = ? AND b.deleted_at IS NULL ORDER BY b.id", authorID, storeFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []BookWithAuthor{}
+	for rows.Next() {
+		b, err := scanBookWithAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+	return books, rows.Err()
+}
+
+func (r *sqlBookRepository) CountByAuthor(ctx context.Context, authorID int) (int, error) {
+	var n int
+	err := r.store.QueryRowContext(ctx, "SELECT COUNT(*) FROM books WHERE author_id = ? AND store_id = ? AND deleted_at IS NULL", authorID, storeFrom(ctx)).Scan(&n)
+	return n, err
+}
+
+func (r *sqlBookRepository) Create(ctx context.Context, b *Book) error {
+	res, err := r.store.ExecContext(ctx, `INSERT INTO books (title, author_id, isbn, price, stock, published_year, description, store_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, b.Title, b.AuthorID, b.ISBN, b.Price, b.Stock, b.PublishedYear, b.Description, storeFrom(ctx))
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	b.ID = int(id)
+	b.Version = 1
+	return err
+}
+
+func (r *sqlBookRepository) Update(ctx context.Context, b *Book) error {
+	res, err := r.store.ExecContext(ctx, `UPDATE books SET title = ?, author_id = ?, isbn = ?, price = ?, stock = ?, published_year = ?, description = ?,
+		version = version + 1 WHERE id = ? AND store_id = ? AND deleted_at IS NULL AND version = ?`,
+		b.Title, b.AuthorID, b.ISBN, b.Price, b.Stock, b.PublishedYear, b.Description, b.ID, storeFrom(ctx), b.Version)
+	if err = rowsAffectedOrNotFound(res, err); errors.Is(err, ErrNotFound) {
+		// Either the book is gone or someone else wrote first.
+		if _, getErr := r.Get(ctx, b.ID); getErr == nil {
+			return ErrVersionConflict
+		}
+	}
+	if err != nil {
+		return err
+	}
+	b.Version++
+	return nil
+}
+
+func (r *sqlBookRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.store.ExecContext(ctx, "UPDATE books SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND store_id = ? AND deleted_at IS NULL", id, storeFrom(ctx))
+	return rowsAffectedOrNotFound(res, err)
+}
+
+func rowsAffectedOrNotFound(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
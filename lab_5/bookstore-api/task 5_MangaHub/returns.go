@@ -0,0 +1,116 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Returns & Refunds ----------
+
+// ReturnRequest is the body of POST /books/:id/return. SaleID is the sale
+// the copies came from (the sale_id answered by POST /books/:id/sell); the
+// refund is its unit price times Quantity.
+type ReturnRequest struct {
+	SaleID   int    `json:"sale_id" binding:"required,gt=0"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+	Reason   string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+type SaleReturn struct {
+	ID         int     `json:"id"`
+	SaleID     int     `json:"sale_id"`
+	BookID     int     `json:"book_id"`
+	Quantity   int     `json:"quantity"`
+	Refund     float64 `json:"refund"`
+	Reason     string  `json:"reason"`
+	ReturnedAt string  `json:"returned_at"`
+	Stock      int     `json:"stock"`
+}
+
+// returnBook serves POST /books/:id/return: the copies go back into stock and
+// the refund is booked against sales analytics. A sale can never be returned
+// beyond the quantity it sold, even by concurrent requests, since the INSERT
+// re-checks what is left.
+func returnBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if ok {
+		_, err := bookRepo.Get(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			internalError(c, err)
+			return
+		}
+		ok = err == nil
+	}
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	var req ReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	res, err := tx.Exec(`INSERT INTO sale_returns (sale_id, book_id, quantity, refund, reason)
+		SELECT s.id, s.book_id, ?, s.unit_price * ?, ? FROM sales s
+		WHERE s.id = ? AND s.book_id = ?
+			AND s.quantity - COALESCE((SELECT SUM(r.quantity) FROM sale_returns r WHERE r.sale_id = s.id), 0) >= ?`,
+		req.Quantity, req.Quantity, req.Reason, req.SaleID, id, req.Quantity)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		var sold, returned int
+		err := tx.QueryRow(`SELECT s.quantity, COALESCE((SELECT SUM(r.quantity) FROM sale_returns r WHERE r.sale_id = s.id), 0)
+			FROM sales s WHERE s.id = ? AND s.book_id = ?`, req.SaleID, id).Scan(&sold, &returned)
+		if errors.Is(err, sql.ErrNoRows) {
+			validationError(c, ErrorDetail{Field: "sale_id", Message: fmt.Sprintf("sale %d is not a sale of book %d", req.SaleID, id)})
+			return
+		}
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		validationError(c, ErrorDetail{Field: "quantity",
+			Message: fmt.Sprintf("sale %d sold %d, %d already returned; at most %d can be returned", req.SaleID, sold, returned, sold-returned)})
+		return
+	}
+	returnID, _ := res.LastInsertId()
+	if _, err := tx.Exec("UPDATE books SET stock = stock + ?, version = version + 1 WHERE id = ?", req.Quantity, id); err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := recordMovement(tx, c, id, req.Quantity, MovementReturn); err != nil {
+		internalError(c, err)
+		return
+	}
+	ret := SaleReturn{ID: int(returnID)}
+	err = tx.QueryRow("SELECT sale_id, book_id, quantity, refund, reason, returned_at FROM sale_returns WHERE id = ?", returnID).
+		Scan(&ret.SaleID, &ret.BookID, &ret.Quantity, &ret.Refund, &ret.Reason, &ret.ReturnedAt)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	after := snapshotBookFrom(tx, id)
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	before := *after
+	before.Stock -= req.Quantity
+	countBook(storeID(c), &before, after)
+	recordAudit(c, "return", "book", id, &before, after)
+	notifyBackInStock(after, before.Stock)
+	ret.Stock = after.Stock
+	c.JSON(http.StatusCreated, ret)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Search ----------
+
+// GET /books/search ranks books against a free-text query. An exact ISBN
+// wins outright, then title matches (exact, prefix, substring, every word),
+// then author names, then descriptions. When that finds fewer books than
+// asked for, titles are scanned word by word, accepting words one edit away
+// from the query's, so "naruot" still finds "Naruto".
+
+const (
+	scoreISBN        = 100
+	scoreTitleExact  = 60
+	scoreTitlePrefix = 45
+	scoreTitle       = 35
+	scoreTitleWords  = 25
+	scoreAuthor      = 20
+	scoreDescription = 10
+	scoreFuzzy       = 5
+
+	maxSearchCandidates = 500
+	minFuzzyTermLength  = 4
+)
+
+type SearchResult struct {
+	BookWithAuthor
+	Score   int    `json:"score"`
+	Matched string `json:"matched"`
+}
+
+// searchBooks serves GET /books/search?q=&limit=20 (max 100).
+func searchBooks(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		respondError(c, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit := min(max(parseIntQuery(c, "limit", 20), 1), 100)
+	ctx := c.Request.Context()
+	lower := strings.ToLower(q)
+	pattern := "%" + strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(lower) + "%"
+	isbn := q
+	if n, err := normalizeISBN(q); err == nil {
+		isbn = n
+	}
+
+	rows, err := db.QueryContext(ctx, bookWithAuthorSelect+`
+		WHERE b.store_id = ? AND b.deleted_at IS NULL AND (b.isbn = ?
+			OR LOWER(b.title) LIKE ? ESCAPE '!' OR LOWER(COALESCE(a.name, '')) LIKE ? ESCAPE '!'
+			OR LOWER(COALESCE(b.description, '')) LIKE ? ESCAPE '!')
+		LIMIT ?`, storeID(c), isbn, pattern, pattern, pattern, maxSearchCandidates)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	results := []SearchResult{}
+	seen := map[int]bool{}
+	for rows.Next() {
+		b, err := scanBookWithAuthor(rows)
+		if err != nil {
+			rows.Close()
+			internalError(c, err)
+			return
+		}
+		r := SearchResult{BookWithAuthor: b}
+		r.Score, r.Matched = scoreBook(&b, lower, isbn)
+		results = append(results, r)
+		seen[b.ID] = true
+	}
+	rows.Close()
+
+	// The scan also finds titles holding every query word apart from each
+	// other, which the phrase LIKE above misses.
+	if terms := searchTerms(lower); len(results) < limit && len(terms) > 0 {
+		ids, err := fuzzyTitleMatches(c, terms, seen, limit-len(results))
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		for _, id := range ids {
+			b, err := bookRepo.Get(ctx, id)
+			if err != nil {
+				continue // trashed in the meantime
+			}
+			r := SearchResult{BookWithAuthor: *b, Score: scoreFuzzy, Matched: "fuzzy"}
+			if containsAll(strings.ToLower(b.Title), terms) {
+				r.Score, r.Matched = scoreTitleWords, "title"
+			}
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return strings.ToLower(results[i].Title) < strings.ToLower(results[j].Title)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"query": q, "results": results, "count": len(results)})
+}
+
+// scoreBook rates one candidate; q is lower-cased.
+func scoreBook(b *BookWithAuthor, q, isbn string) (int, string) {
+	title := strings.ToLower(b.Title)
+	switch {
+	case b.ISBN == isbn:
+		return scoreISBN, "isbn"
+	case title == q:
+		return scoreTitleExact, "title"
+	case strings.HasPrefix(title, q):
+		return scoreTitlePrefix, "title"
+	case strings.Contains(title, q):
+		return scoreTitle, "title"
+	case containsAll(title, searchTerms(q)):
+		return scoreTitleWords, "title"
+	case strings.Contains(strings.ToLower(b.AuthorName), q):
+		return scoreAuthor, "author"
+	}
+	return scoreDescription, "description"
+}
+
+func fuzzyTitleMatches(c *gin.Context, terms []string, seen map[int]bool, want int) ([]int, error) {
+	rows, err := db.QueryContext(c.Request.Context(), "SELECT id, title FROM books WHERE store_id = ? AND deleted_at IS NULL ORDER BY id", storeID(c))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int
+	for rows.Next() && len(ids) < want {
+		var id int
+		var title string
+		if err := rows.Scan(&id, &title); err != nil {
+			return nil, err
+		}
+		if !seen[id] && fuzzyMatch(searchTerms(strings.ToLower(title)), terms) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+// fuzzyMatch reports whether every term is a word of the title or one edit
+// away from one. Short terms must match exactly.
+func fuzzyMatch(words, terms []string) bool {
+	for _, t := range terms {
+		found := false
+		for _, w := range words {
+			if w == t || (len([]rune(t)) >= minFuzzyTermLength && oneEditApart(w, t)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func searchTerms(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+func containsAll(s string, terms []string) bool {
+	for _, t := range terms {
+		if !strings.Contains(s, t) {
+			return false
+		}
+	}
+	return len(terms) > 0
+}
+
+// oneEditApart reports whether a and b differ by exactly one insertion,
+// deletion, substitution or swap of adjacent letters.
+func oneEditApart(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(ra)-len(rb) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(rb) && ra[i] == rb[i] {
+		i++
+	}
+	if i == len(rb) {
+		return len(ra) != len(rb)
+	}
+	if len(ra) != len(rb) {
+		return string(ra[i+1:]) == string(rb[i:])
+	}
+	if string(ra[i+1:]) == string(rb[i+1:]) {
+		return true
+	}
+	return i+1 < len(ra) && ra[i] == rb[i+1] && ra[i+1] == rb[i] && string(ra[i+2:]) == string(rb[i+2:])
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// ---------- Seed Data ----------
+
+// seedCatalog fills a store with n generated books by roughly n/5 new
+// authors, for exercising pagination, search and performance with real
+// volumes. ISBNs are valid and unique, and each book's stock is entered in
+// the movement ledger.
+
+var (
+	seedFirstNames = []string{
+		"Akira", "Haruki", "Yuki", "Hiro", "Kenji", "Sakura", "Naoko", "Rumiko", "Eiichiro", "Masashi",
+		"Hajime", "Kentaro", "Moto", "Osamu", "Riyoko", "Takehiko", "Tatsuki", "Yoshihiro", "Ai", "Mei",
+		"Anna", "Lucas", "Sofia", "Mateo", "Elena", "Noah", "Clara", "Leo", "Maya", "Jonas",
+	}
+	seedLastNames = []string{
+		"Tanaka", "Suzuki", "Takahashi", "Watanabe", "Ito", "Yamamoto", "Nakamura", "Kobayashi", "Kato", "Yoshida",
+		"Yamada", "Sasaki", "Matsumoto", "Inoue", "Kimura", "Hayashi", "Shimizu", "Mori", "Ikeda", "Hashimoto",
+		"Novak", "Berg", "Moreau", "Rossi", "Silva", "Larsen", "Weber", "Costa", "Nguyen", "Tran",
+	}
+	seedCountries  = []string{"Japan", "Japan", "Japan", "South Korea", "France", "Italy", "USA", "Vietnam", "Brazil", "Germany"}
+	seedAdjectives = []string{
+		"Silent", "Crimson", "Hidden", "Last", "Endless", "Broken", "Golden", "Midnight", "Wandering", "Frozen",
+		"Burning", "Forgotten", "Iron", "Paper", "Celestial", "Savage", "Gentle", "Phantom", "Electric", "Little",
+	}
+	seedNouns = []string{
+		"Blade", "Garden", "Academy", "Dragon", "Detective", "Kingdom", "Festival", "Samurai", "Witch", "Island",
+		"Cafe", "Pilot", "Shrine", "Archive", "Summer", "Moon", "Circus", "Hunter", "Orchestra", "Alchemist",
+	}
+	seedThemes = []string{
+		"a coming-of-age story", "a slow-burn mystery", "an action-packed adventure", "a workplace comedy",
+		"a dark fantasy epic", "a heartfelt romance", "a sports drama", "a science-fiction thriller",
+	}
+)
+
+func seedCatalog(n, store int) (authors, books int, err error) {
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(n)))
+
+	names, err := seedExisting("SELECT name FROM authors")
+	if err != nil {
+		return 0, 0, err
+	}
+	isbns, err := seedExisting("SELECT isbn FROM books")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	var authorIDs []int
+	for len(authorIDs) < max(1, n/5) {
+		name := seedFirstNames[rng.IntN(len(seedFirstNames))] + " " + seedLastNames[rng.IntN(len(seedLastNames))]
+		for suffix := 2; names[name]; suffix++ {
+			// the first/last combinations run out on big seeds
+			name = fmt.Sprintf("%s %s %d", seedFirstNames[rng.IntN(len(seedFirstNames))], seedLastNames[rng.IntN(len(seedLastNames))], suffix)
+		}
+		names[name] = true
+		res, err := tx.Exec("INSERT INTO authors (name, bio, birth_year, country, store_id) VALUES (?, ?, ?, ?, ?)",
+			name, "Seeded author.", 1940+rng.IntN(65), seedCountries[rng.IntN(len(seedCountries))], store)
+		if err != nil {
+			return 0, 0, err
+		}
+		id, _ := res.LastInsertId()
+		authorIDs = append(authorIDs, int(id))
+	}
+
+	for books < n {
+		isbn := seedISBN(rng)
+		if isbns[isbn] {
+			continue
+		}
+		isbns[isbn] = true
+		title := seedTitle(rng)
+		price := math.Round((2.99+rng.Float64()*57)*100) / 100
+		stock := rng.IntN(101)
+		res, err := tx.Exec(`INSERT INTO books (title, author_id, isbn, price, stock, published_year, description, store_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			title, authorIDs[rng.IntN(len(authorIDs))], isbn, price, stock, 1950+rng.IntN(76),
+			fmt.Sprintf("%s: %s.", title, seedThemes[rng.IntN(len(seedThemes))]), store)
+		if err != nil {
+			return 0, 0, err
+		}
+		id, _ := res.LastInsertId()
+		if stock > 0 {
+			if _, err := tx.Exec("INSERT INTO stock_movements (book_id, delta, reason, actor) VALUES (?, ?, ?, ?)",
+				id, stock, MovementAdjustment, "seed"); err != nil {
+				return 0, 0, err
+			}
+		}
+		books++
+	}
+	return len(authorIDs), books, tx.Commit()
+}
+
+// seedExisting loads a column into a set so generated values never collide
+// with what the database already holds.
+func seedExisting(query string) (map[string]bool, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	set := map[string]bool{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		set[v] = true
+	}
+	return set, rows.Err()
+}
+
+func seedTitle(rng *rand.Rand) string {
+	title := "The " + seedAdjectives[rng.IntN(len(seedAdjectives))] + " " + seedNouns[rng.IntN(len(seedNouns))]
+	if rng.IntN(3) == 0 {
+		title += fmt.Sprintf(", Vol. %d", 1+rng.IntN(30))
+	}
+	return title
+}
+
+// seedISBN returns a random ISBN-13 with a valid check digit.
+func seedISBN(rng *rand.Rand) string {
+	var b strings.Builder
+	b.WriteString("978")
+	for i := 0; i < 9; i++ {
+		b.WriteByte(byte('0' + rng.IntN(10)))
+	}
+	digits := b.String()
+	sum := 0
+	for i, d := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+	b.WriteByte(byte('0' + (10-sum%10)%10))
+	return b.String()
+}
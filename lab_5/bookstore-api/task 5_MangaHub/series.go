@@ -0,0 +1,237 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Series & Volumes ----------
+
+const (
+	SeriesOngoing   = "ongoing"
+	SeriesCompleted = "completed"
+	SeriesHiatus    = "hiatus"
+)
+
+// Series groups books as numbered volumes. TotalVolumes is how many volumes
+// the series has been published with (0 while unknown); the catalog may hold
+// fewer.
+type Series struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title" binding:"required,min=1,max=200"`
+	Description  string `json:"description" binding:"max=2000"`
+	Status       string `json:"status" binding:"omitempty,oneof=ongoing completed hiatus"`
+	TotalVolumes int    `json:"total_volumes" binding:"gte=0"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type SeriesVolume struct {
+	Volume  int     `json:"volume"`
+	BookID  int     `json:"book_id"`
+	Title   string  `json:"title"`
+	Price   float64 `json:"price"`
+	Stock   int     `json:"stock"`
+	InStock bool    `json:"in_stock"`
+}
+
+// SeriesDetail is GET /series/:id. Complete means the catalog holds every
+// volume from 1 to TotalVolumes; MissingVolumes lists the gaps up to the
+// highest volume known.
+type SeriesDetail struct {
+	Series
+	Volumes        []SeriesVolume `json:"volumes"`
+	OwnedVolumes   int            `json:"owned_volumes"`
+	MissingVolumes []int          `json:"missing_volumes"`
+	Complete       bool           `json:"complete"`
+}
+
+type AddVolumeRequest struct {
+	Volume int `json:"volume" binding:"gte=0"`
+}
+
+const seriesSelect = "SELECT id, title, COALESCE(description, ''), status, total_volumes, created_at FROM series"
+
+func scanSeries(row interface{ Scan(...any) error }) (Series, error) {
+	var s Series
+	err := row.Scan(&s.ID, &s.Title, &s.Description, &s.Status, &s.TotalVolumes, &s.CreatedAt)
+	return s, err
+}
+
+// seriesByID loads :id from the current store, answering 404 otherwise.
+func seriesByID(c *gin.Context) (*Series, bool) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Series not found")
+		return nil, false
+	}
+	s, err := scanSeries(db.QueryRow(seriesSelect+" WHERE id = ? AND store_id = ?", id, storeID(c)))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Series not found")
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return &s, true
+}
+
+func getSeriesList(c *gin.Context) {
+	rows, err := db.Query(seriesSelect+" WHERE store_id = ? ORDER BY title", storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	list := []Series{}
+	for rows.Next() {
+		s, err := scanSeries(rows)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		list = append(list, s)
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func createSeries(c *gin.Context) {
+	var s Series
+	if err := c.ShouldBindJSON(&s); err != nil {
+		bindError(c, err)
+		return
+	}
+	if s.Status == "" {
+		s.Status = SeriesOngoing
+	}
+	res, err := db.Exec("INSERT INTO series (store_id, title, description, status, total_volumes) VALUES (?, ?, ?, ?, ?)",
+		storeID(c), s.Title, s.Description, s.Status, s.TotalVolumes)
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	created, err := scanSeries(db.QueryRow(seriesSelect+" WHERE id = ?", id))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	recordAudit(c, "create", "series", created.ID, nil, &created)
+	c.JSON(http.StatusCreated, created)
+}
+
+// getSeries serves GET /series/:id with its volumes in reading order.
+// Trashed books are left out and count as missing.
+func getSeries(c *gin.Context) {
+	s, ok := seriesByID(c)
+	if !ok {
+		return
+	}
+	rows, err := db.Query(`SELECT v.volume_number, b.id, b.title, b.price, b.stock
+		FROM series_volumes v JOIN books b ON b.id = v.book_id
+		WHERE v.series_id = ? AND b.deleted_at IS NULL
+		ORDER BY v.volume_number`, s.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	detail := SeriesDetail{Series: *s, Volumes: []SeriesVolume{}, MissingVolumes: []int{}}
+	owned := map[int]bool{}
+	last := s.TotalVolumes
+	for rows.Next() {
+		var v SeriesVolume
+		if err := rows.Scan(&v.Volume, &v.BookID, &v.Title, &v.Price, &v.Stock); err != nil {
+			internalError(c, err)
+			return
+		}
+		v.InStock = v.Stock > 0
+		detail.Volumes = append(detail.Volumes, v)
+		owned[v.Volume] = true
+		last = max(last, v.Volume)
+	}
+	for n := 1; n <= last; n++ {
+		if !owned[n] {
+			detail.MissingVolumes = append(detail.MissingVolumes, n)
+		}
+	}
+	detail.OwnedVolumes = len(detail.Volumes)
+	detail.Complete = s.TotalVolumes > 0 && len(detail.MissingVolumes) == 0
+	c.JSON(http.StatusOK, detail)
+}
+
+// addSeriesVolume serves POST /series/:id/volumes/:book_id. The optional
+// body {"volume": 3} picks the number; without it the book becomes the next
+// volume after the highest one.
+func addSeriesVolume(c *gin.Context) {
+	s, ok := seriesByID(c)
+	if !ok {
+		return
+	}
+	bookID, ok := paramID(c, "book_id")
+	if ok {
+		_, err := bookRepo.Get(c.Request.Context(), bookID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			internalError(c, err)
+			return
+		}
+		ok = err == nil
+	}
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	var req AddVolumeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	var current int
+	err := db.QueryRow("SELECT series_id FROM series_volumes WHERE book_id = ?", bookID).Scan(&current)
+	if err == nil {
+		respondError(c, http.StatusConflict, fmt.Sprintf("Book is already a volume of series %d", current))
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		internalError(c, err)
+		return
+	}
+	if req.Volume == 0 {
+		db.QueryRow("SELECT COALESCE(MAX(volume_number), 0) + 1 FROM series_volumes WHERE series_id = ?", s.ID).Scan(&req.Volume)
+	}
+	if _, err := db.Exec("INSERT INTO series_volumes (series_id, book_id, volume_number) VALUES (?, ?, ?)", s.ID, bookID, req.Volume); err != nil {
+		if isUniqueViolation(err) {
+			respondError(c, http.StatusConflict, fmt.Sprintf("Volume %d of this series is already assigned", req.Volume))
+			return
+		}
+		internalError(c, err)
+		return
+	}
+	recordAudit(c, "add_volume", "series", s.ID, nil, gin.H{"book_id": bookID, "volume": req.Volume})
+	c.JSON(http.StatusCreated, gin.H{"series_id": s.ID, "book_id": bookID, "volume": req.Volume})
+}
+
+func removeSeriesVolume(c *gin.Context) {
+	s, ok := seriesByID(c)
+	if !ok {
+		return
+	}
+	res, err := db.Exec("DELETE FROM series_volumes WHERE series_id = ? AND book_id = ?", s.ID, c.Param("book_id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book is not a volume of this series")
+		return
+	}
+	recordAudit(c, "remove_volume", "series", s.ID, gin.H{"book_id": c.Param("book_id")}, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Volume removed from series"})
+}
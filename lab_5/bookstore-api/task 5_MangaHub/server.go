@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ---------- Server ----------
+
+// serve runs the router until SIGINT/SIGTERM, then stops accepting
+// connections, lets in-flight requests finish within SHUTDOWN_TIMEOUT_SECONDS
+// and closes the database.
+func serve(handler http.Handler, addr string) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-errCh:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server: %v", err)
+		}
+		return
+	case <-ctx.Done():
+	}
+	stop()
+
+	timeout := time.Duration(envInt("SHUTDOWN_TIMEOUT_SECONDS", 15)) * time.Second
+	log.Printf("shutting down, draining requests for up to %s", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		log.Printf("close database: %v", err)
+	}
+	log.Println("server stopped")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Statistics Snapshots ----------
+
+const maxHistoryDays = 365
+
+// StatsSnapshot is one day of the trend series behind GET /stats/history.
+type StatsSnapshot struct {
+	Date         string  `json:"date"`
+	TotalBooks   int     `json:"total_books"`
+	TotalAuthors int     `json:"total_authors"`
+	TotalValue   float64 `json:"total_value"`
+	LowStock     int     `json:"low_stock"`
+	OutOfStock   int     `json:"out_of_stock"`
+	AveragePrice float64 `json:"average_price"`
+}
+
+// startStatsSnapshotScheduler checks every STATS_SNAPSHOT_INTERVAL_SECONDS
+// (default 3600, 0 disables the job) whether today's snapshot exists and
+// writes it if not, so a restarted server still records each day once.
+func startStatsSnapshotScheduler() {
+	interval := time.Duration(envInt("STATS_SNAPSHOT_INTERVAL_SECONDS", 3600)) * time.Second
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := takeStatsSnapshot(time.Now().UTC()); err != nil {
+				log.Printf("stats: snapshot failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// takeStatsSnapshot stores the statistics of every store for now's (UTC)
+// date unless that day was already recorded.
+func takeStatsSnapshot(now time.Time) error {
+	rows, err := db.Query("SELECT id FROM stores ORDER BY id")
+	if err != nil {
+		return err
+	}
+	var stores []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		stores = append(stores, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, store := range stores {
+		if err := takeStoreSnapshot(store, now.Format("2006-01-02")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func takeStoreSnapshot(store int, date string) error {
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM stats_snapshots WHERE store_id = ? AND snapshot_date = ?)", store, date).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	stats, err := computeStatistics(store)
+	if err != nil {
+		return err // a zeroed snapshot would mark the day as recorded
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`INSERT INTO stats_snapshots
+		(store_id, snapshot_date, total_books, total_authors, total_value, low_stock, out_of_stock, average_price, stats_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		store, date, stats.TotalBooks, stats.TotalAuthors, stats.TotalValue, stats.LowStock, stats.OutOfStock, stats.AveragePrice, string(data))
+	if err != nil && isUniqueViolation(err) {
+		return nil // another instance got there first
+	}
+	return err
+}
+
+// getStatsHistory serves GET /stats/history?days=30, oldest day first.
+// Days without a snapshot (server down) are simply missing.
+func getStatsHistory(c *gin.Context) {
+	days := parseIntQuery(c, "days", 30)
+	if days < 1 || days > maxHistoryDays {
+		validationError(c, ErrorDetail{Field: "days", Message: "must be between 1 and 365"})
+		return
+	}
+	since := time.Now().UTC().AddDate(0, 0, 1-days).Format("2006-01-02")
+	rows, err := db.Query(`SELECT snapshot_date, total_books, total_authors, total_value, low_stock, out_of_stock, average_price
+		FROM stats_snapshots WHERE store_id = ? AND snapshot_date >= ? ORDER BY snapshot_date`, storeID(c), since)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	snapshots := []StatsSnapshot{}
+	for rows.Next() {
+		var s StatsSnapshot
+		if err := rows.Scan(&s.Date, &s.TotalBooks, &s.TotalAuthors, &s.TotalValue, &s.LowStock, &s.OutOfStock, &s.AveragePrice); err != nil {
+			internalError(c, err)
+			return
+		}
+		snapshots = append(snapshots, s)
+	}
+	c.JSON(http.StatusOK, gin.H{"days": days, "since": since, "snapshots": snapshots})
+}
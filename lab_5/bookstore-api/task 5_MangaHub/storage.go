@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ---------- Storage ----------
+
+// Dialect names the SQL flavour behind a Store. Values double as the
+// database/sql driver name.
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite3"
+	DialectMySQL    Dialect = "mysql"
+	DialectPostgres Dialect = "postgres"
+)
+
+// parseDialect accepts the usual spellings of each supported driver.
+func parseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	case "mysql", "mariadb":
+		return DialectMySQL, nil
+	case "postgres", "postgresql", "pg":
+		return DialectPostgres, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q (use sqlite, mysql or postgres)", name)
+}
+
+// Store wraps *sql.DB so handlers can keep writing SQLite-flavoured SQL with
+// ? placeholders; queries are rewritten for the configured dialect.
+type Store struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+func openStore(driver, dsn string) (*Store, error) {
+	dialect, err := parseDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	if dsn == "" && dialect == DialectSQLite {
+		dsn = "./bookstore.db"
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("DB_DSN is required for %s", dialect)
+	}
+	if dialect == DialectSQLite && !strings.Contains(dsn, "_busy_timeout") {
+		// wait for the write lock instead of failing concurrent writers with SQLITE_BUSY
+		dsn = appendDSNParam(dsn, "_busy_timeout=5000")
+	}
+	if dialect == DialectMySQL && !strings.Contains(dsn, "parseTime") {
+		// created_at/deleted_at columns are scanned into strings
+		dsn = appendDSNParam(dsn, "parseTime=false")
+	}
+	if dialect == DialectMySQL && !strings.Contains(dsn, "clientFoundRows") {
+		// RowsAffected counts matched rows, as on SQLite and Postgres, so an
+		// UPDATE that changes nothing is not mistaken for a missing row
+		dsn = appendDSNParam(dsn, "clientFoundRows=true")
+	}
+	conn, err := sql.Open(string(dialect), dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Store{DB: conn, Dialect: dialect}, nil
+}
+
+func appendDSNParam(dsn, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
+
+// rebind converts ? placeholders to $1, $2, ... for Postgres.
+func rebind(dialect Dialect, query string) string {
+	if dialect != DialectPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var sb strings.Builder
+	n := 0
+	inQuote := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == '?' && !inQuote:
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// needsReturning reports whether an INSERT must be rewritten so Postgres can
+// report the generated id (lib/pq does not implement LastInsertId).
+func needsReturning(dialect Dialect, query string) bool {
+	if dialect != DialectPostgres {
+		return false
+	}
+	q := strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(q, "INSERT") && !strings.Contains(q, "RETURNING")
+}
+
+// returningResult implements sql.Result from the rows of INSERT ... RETURNING *.
+// The first column of every table is its id.
+type returningResult struct {
+	id       int64
+	affected int64
+}
+
+func (r returningResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r returningResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+func scanReturning(rows *sql.Rows) (sql.Result, error) {
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var res returningResult
+	for rows.Next() {
+		values := make([]any, len(cols))
+		for i := range values {
+			values[i] = new(any)
+		}
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		if res.affected == 0 {
+			if id, ok := (*values[0].(*any)).(int64); ok {
+				res.id = id
+			}
+		}
+		res.affected++
+	}
+	return res, rows.Err()
+}
+
+// queryer is the subset of *sql.DB / *sql.Tx used to implement the wrappers.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func execDialect(ctx context.Context, q queryer, dialect Dialect, query string, args ...any) (sql.Result, error) {
+	if needsReturning(dialect, query) {
+		rows, err := q.QueryContext(ctx, rebind(dialect, query)+" RETURNING *", args...)
+		if err != nil {
+			return nil, err
+		}
+		return scanReturning(rows)
+	}
+	return q.ExecContext(ctx, rebind(dialect, query), args...)
+}
+
+func (s *Store) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execDialect(ctx, s.DB, s.Dialect, query, args...)
+}
+
+func (s *Store) Exec(query string, args ...any) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s *Store) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return s.DB.QueryContext(ctx, rebind(s.Dialect, query), args...)
+}
+
+func (s *Store) Query(query string, args ...any) (*sql.Rows, error) {
+	return s.QueryContext(context.Background(), query, args...)
+}
+
+func (s *Store) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return s.DB.QueryRowContext(ctx, rebind(s.Dialect, query), args...)
+}
+
+func (s *Store) QueryRow(query string, args ...any) *sql.Row {
+	return s.QueryRowContext(context.Background(), query, args...)
+}
+
+func (s *Store) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := s.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, Dialect: s.Dialect}, nil
+}
+
+func (s *Store) Begin() (*Tx, error) {
+	return s.BeginTx(context.Background(), nil)
+}
+
+// Tx is the transactional counterpart of Store.
+type Tx struct {
+	*sql.Tx
+	Dialect Dialect
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execDialect(ctx, t.Tx, t.Dialect, query, args...)
+}
+
+func (t *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.Tx.QueryContext(ctx, rebind(t.Dialect, query), args...)
+}
+
+func (t *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.Tx.QueryRowContext(ctx, rebind(t.Dialect, query), args...)
+}
+
+func (t *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+// Prepare returns a statement whose Exec behaves like Tx.Exec for the dialect.
+func (t *Tx) Prepare(query string) (*Stmt, error) {
+	returning := needsReturning(t.Dialect, query)
+	q := rebind(t.Dialect, query)
+	if returning {
+		q += " RETURNING *"
+	}
+	stmt, err := t.Tx.Prepare(q)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{Stmt: stmt, returning: returning}, nil
+}
+
+type Stmt struct {
+	*sql.Stmt
+	returning bool
+}
+
+func (s *Stmt) Exec(args ...any) (sql.Result, error) {
+	if s.returning {
+		rows, err := s.Stmt.Query(args...)
+		if err != nil {
+			return nil, err
+		}
+		return scanReturning(rows)
+	}
+	return s.Stmt.Exec(args...)
+}
+
+// ExecDDL runs a script of ;-separated schema statements written for SQLite,
+// translating column types and syntax for the other dialects.
+func (s *Store) ExecDDL(script string) error {
+	for _, stmt := range splitSQL(script) {
+		stmt = translateDDL(s.Dialect, stmt)
+		if _, err := s.DB.Exec(stmt); err != nil {
+			// MySQL has no CREATE INDEX IF NOT EXISTS; an existing index is fine.
+			if s.Dialect == DialectMySQL && strings.Contains(err.Error(), "Duplicate key name") {
+				continue
+			}
+			return fmt.Errorf("%w\n%s", err, stmt)
+		}
+	}
+	return nil
+}
+
+func translateDDL(dialect Dialect, stmt string) string {
+	switch dialect {
+	case DialectPostgres:
+		r := strings.NewReplacer(
+			"INTEGER PRIMARY KEY AUTOINCREMENT", "SERIAL PRIMARY KEY",
+			"DATETIME", "TIMESTAMP",
+			"REAL", "DOUBLE PRECISION",
+		)
+		return r.Replace(stmt)
+	case DialectMySQL:
+		r := strings.NewReplacer(
+			"INTEGER PRIMARY KEY AUTOINCREMENT", "INTEGER PRIMARY KEY AUTO_INCREMENT",
+			"TEXT NOT NULL UNIQUE", "VARCHAR(255) NOT NULL UNIQUE",
+			"CREATE INDEX IF NOT EXISTS", "CREATE INDEX",
+			"CREATE UNIQUE INDEX IF NOT EXISTS", "CREATE UNIQUE INDEX",
+		)
+		return r.Replace(stmt)
+	}
+	return stmt
+}
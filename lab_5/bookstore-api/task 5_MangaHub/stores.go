@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Stores (multi-tenancy) ----------
+
+// Every author and book belongs to one store. Requests pick the store with
+// the X-Store-ID header or a /stores/:store_id/... path prefix; without
+// either they use the default store, which holds catalogs created before
+// stores existed.
+const (
+	DefaultStoreID = 1
+	storeHeader    = "X-Store-ID"
+)
+
+// Tenant is a row of the stores table (Store is taken by the database
+// wrapper).
+type Tenant struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name" binding:"required,min=2,max=100"`
+	CreatedAt string `json:"created_at"`
+}
+
+type storeCtxKey struct{}
+
+// storeFrom returns the store a request was scoped to by storeScope.
+func storeFrom(ctx context.Context) int {
+	if id, ok := ctx.Value(storeCtxKey{}).(int); ok {
+		return id
+	}
+	return DefaultStoreID
+}
+
+func storeID(c *gin.Context) int {
+	return storeFrom(c.Request.Context())
+}
+
+// storePathPrefix rewrites /stores/2/books/7 to /books/7 with X-Store-ID: 2
+// before Gin routes the request, so every catalog route is reachable under
+// either form without registering it twice. /stores and /stores/2 themselves
+// are left alone.
+func storePathPrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest, ok := strings.CutPrefix(r.URL.Path, "/stores/"); ok {
+			id, tail, found := strings.Cut(rest, "/")
+			if _, err := strconv.Atoi(id); err == nil && found && tail != "" {
+				r = r.Clone(r.Context())
+				r.URL.Path = "/" + tail
+				r.URL.RawPath = ""
+				r.Header.Set(storeHeader, id)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// storeScope resolves X-Store-ID and stores the id in the request context
+// for handlers and repositories. Unknown stores answer 404.
+func storeScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := DefaultStoreID
+		if raw := c.GetHeader(storeHeader); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				respondError(c, http.StatusNotFound, "Store not found")
+				return
+			}
+			id = n
+		}
+		if id != DefaultStoreID {
+			var exists bool
+			if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM stores WHERE id = ?)", id).Scan(&exists); err != nil {
+				internalError(c, err)
+				return
+			}
+			if !exists {
+				respondError(c, http.StatusNotFound, "Store not found")
+				return
+			}
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), storeCtxKey{}, id))
+		c.Next()
+	}
+}
+
+func getStores(c *gin.Context) {
+	rows, err := db.Query("SELECT id, name, created_at FROM stores ORDER BY id")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	stores := []Tenant{}
+	for rows.Next() {
+		var t Tenant
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			internalError(c, err)
+			return
+		}
+		stores = append(stores, t)
+	}
+	c.JSON(http.StatusOK, stores)
+}
+
+func getStore(c *gin.Context) {
+	var t Tenant
+	err := db.QueryRow("SELECT id, name, created_at FROM stores WHERE id = ?", c.Param("id")).Scan(&t.ID, &t.Name, &t.CreatedAt)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Store not found")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func createStore(c *gin.Context) {
+	var t Tenant
+	if err := c.ShouldBindJSON(&t); err != nil {
+		bindError(c, err)
+		return
+	}
+	res, err := db.Exec("INSERT INTO stores (name) VALUES (?)", t.Name)
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	db.QueryRow("SELECT id, name, created_at FROM stores WHERE id = ?", id).Scan(&t.ID, &t.Name, &t.CreatedAt)
+	recordAudit(c, "create", "store", t.ID, nil, &t)
+	c.JSON(http.StatusCreated, t)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Suggestions ----------
+
+const maxSuggestions = 10
+
+type Suggestion struct {
+	Type       string `json:"type"`
+	ID         int    `json:"id"`
+	Label      string `json:"label"`
+	AuthorName string `json:"author_name,omitempty"`
+}
+
+// prefixCondition matches column values starting with q in a way the
+// 0009_suggest_indexes indexes can serve. SQLite and Postgres cannot use an
+// index for LIKE on an expression, so they get a range on LOWER(column);
+// MySQL compares case-insensitively and uses its index for LIKE 'prefix%'.
+func prefixCondition(column, q string) (string, []any) {
+	q = strings.ToLower(q)
+	if db.Dialect == DialectMySQL {
+		escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(q)
+		return column + " LIKE ? ESCAPE '!'", []any{escaped + "%"}
+	}
+	return "LOWER(" + column + ") >= ? AND LOWER(" + column + ") < ?", []any{q, q + "\U0010FFFF"}
+}
+
+// suggestBooks serves GET /books/suggest?q=cl: up to 10 books and authors
+// whose title or name starts with q, for typeahead inputs.
+func suggestBooks(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		respondError(c, http.StatusBadRequest, "q is required")
+		return
+	}
+	suggestions := []Suggestion{}
+
+	cond, args := prefixCondition("b.title", q)
+	rows, err := db.QueryContext(c.Request.Context(), `SELECT b.id, b.title, COALESCE(a.name, '')
+		FROM books b LEFT JOIN authors a ON b.author_id = a.id
+		WHERE b.store_id = ? AND b.deleted_at IS NULL AND `+cond+`
+		ORDER BY b.title LIMIT ?`, append(append([]any{storeID(c)}, args...), maxSuggestions)...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	for rows.Next() {
+		s := Suggestion{Type: "book"}
+		rows.Scan(&s.ID, &s.Label, &s.AuthorName)
+		suggestions = append(suggestions, s)
+	}
+	rows.Close()
+
+	cond, args = prefixCondition("name", q)
+	rows, err = db.QueryContext(c.Request.Context(), `SELECT id, name FROM authors
+		WHERE store_id = ? AND deleted_at IS NULL AND `+cond+`
+		ORDER BY name LIMIT ?`, append(append([]any{storeID(c)}, args...), maxSuggestions)...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	for rows.Next() {
+		s := Suggestion{Type: "author"}
+		rows.Scan(&s.ID, &s.Label)
+		suggestions = append(suggestions, s)
+	}
+	rows.Close()
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return strings.ToLower(suggestions[i].Label) < strings.ToLower(suggestions[j].Label)
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	c.JSON(http.StatusOK, gin.H{"query": q, "suggestions": suggestions})
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Suppliers & Purchase Orders ----------
+
+const (
+	POStatusPending   = "pending"
+	POStatusReceived  = "received"
+	POStatusCancelled = "cancelled"
+)
+
+type Supplier struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name" binding:"required,min=2,max=100"`
+	Email     string `json:"email" binding:"omitempty,email"`
+	Phone     string `json:"phone" binding:"omitempty,max=32"`
+	CreatedAt string `json:"created_at"`
+}
+
+type PurchaseOrder struct {
+	ID           int     `json:"id"`
+	SupplierID   int     `json:"supplier_id" binding:"required,gt=0"`
+	SupplierName string  `json:"supplier_name,omitempty"`
+	BookID       int     `json:"book_id" binding:"required,gt=0"`
+	BookTitle    string  `json:"book_title,omitempty"`
+	Quantity     int     `json:"quantity" binding:"required,gt=0"`
+	UnitCost     float64 `json:"unit_cost" binding:"gte=0"`
+	Status       string  `json:"status"`
+	Notes        string  `json:"notes" binding:"max=500"`
+	CreatedAt    string  `json:"created_at"`
+	ReceivedAt   *string `json:"received_at"`
+}
+
+const purchaseOrderSelect = `SELECT po.id, po.supplier_id, COALESCE(s.name, ''), po.book_id, COALESCE(b.title, ''),
+	po.quantity, po.unit_cost, po.status, COALESCE(po.notes, ''), po.created_at, po.received_at
+	FROM purchase_orders po
+	LEFT JOIN suppliers s ON s.id = po.supplier_id
+	LEFT JOIN books b ON b.id = po.book_id`
+
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (PurchaseOrder, error) {
+	var po PurchaseOrder
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.BookID, &po.BookTitle,
+		&po.Quantity, &po.UnitCost, &po.Status, &po.Notes, &po.CreatedAt, &po.ReceivedAt)
+	return po, err
+}
+
+func getSuppliers(c *gin.Context) {
+	rows, err := db.Query("SELECT id, name, COALESCE(email, ''), COALESCE(phone, ''), created_at FROM suppliers ORDER BY name")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	suppliers := []Supplier{}
+	for rows.Next() {
+		var s Supplier
+		rows.Scan(&s.ID, &s.Name, &s.Email, &s.Phone, &s.CreatedAt)
+		suppliers = append(suppliers, s)
+	}
+	c.JSON(http.StatusOK, suppliers)
+}
+
+func getSupplier(c *gin.Context) {
+	var s Supplier
+	err := db.QueryRow("SELECT id, name, COALESCE(email, ''), COALESCE(phone, ''), created_at FROM suppliers WHERE id = ?", c.Param("id")).
+		Scan(&s.ID, &s.Name, &s.Email, &s.Phone, &s.CreatedAt)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Supplier not found")
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+func createSupplier(c *gin.Context) {
+	var s Supplier
+	if err := c.ShouldBindJSON(&s); err != nil {
+		bindError(c, err)
+		return
+	}
+	res, err := db.Exec("INSERT INTO suppliers (name, email, phone) VALUES (?, ?, ?)", s.Name, s.Email, s.Phone)
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	s.ID = int(id)
+	recordAudit(c, "create", "supplier", s.ID, nil, &s)
+	c.JSON(http.StatusCreated, s)
+}
+
+// getPurchaseOrders serves GET /purchase-orders?status=pending|received|cancelled|all.
+// Without a status only outstanding (pending) orders are listed.
+func getPurchaseOrders(c *gin.Context) {
+	query, args := purchaseOrderSelect+" WHERE b.store_id = ?", []any{storeID(c)}
+	switch status := c.DefaultQuery("status", POStatusPending); status {
+	case "all":
+	case POStatusPending, POStatusReceived, POStatusCancelled:
+		query += " AND po.status = ?"
+		args = append(args, status)
+	default:
+		respondError(c, http.StatusBadRequest, "status must be pending, received, cancelled or all")
+		return
+	}
+	if supplier := c.Query("supplier_id"); supplier != "" {
+		query += " AND po.supplier_id = ?"
+		args = append(args, supplier)
+	}
+	if book := c.Query("book_id"); book != "" {
+		query += " AND po.book_id = ?"
+		args = append(args, book)
+	}
+	rows, err := db.Query(query+" ORDER BY po.id", args...)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	orders := []PurchaseOrder{}
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		orders = append(orders, po)
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+func getPurchaseOrder(c *gin.Context) {
+	po, err := scanPurchaseOrder(db.QueryRow(purchaseOrderSelect+" WHERE po.id = ? AND b.store_id = ?", c.Param("id"), storeID(c)))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Purchase order not found")
+		return
+	}
+	c.JSON(http.StatusOK, po)
+}
+
+func createPurchaseOrder(c *gin.Context) {
+	var po PurchaseOrder
+	if err := c.ShouldBindJSON(&po); err != nil {
+		bindError(c, err)
+		return
+	}
+	var exists bool
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM suppliers WHERE id = ?)", po.SupplierID).Scan(&exists)
+	if !exists {
+		validationError(c, ErrorDetail{Field: "supplier_id", Message: "supplier not found"})
+		return
+	}
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NULL)", po.BookID, storeID(c)).Scan(&exists)
+	if !exists {
+		validationError(c, ErrorDetail{Field: "book_id", Message: "book not found"})
+		return
+	}
+	res, err := db.Exec("INSERT INTO purchase_orders (supplier_id, book_id, quantity, unit_cost, status, notes) VALUES (?, ?, ?, ?, ?, ?)",
+		po.SupplierID, po.BookID, po.Quantity, po.UnitCost, POStatusPending, po.Notes)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	created, err := scanPurchaseOrder(db.QueryRow(purchaseOrderSelect+" WHERE po.id = ?", id))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	recordAudit(c, "create", "purchase_order", created.ID, nil, &created)
+	c.JSON(http.StatusCreated, created)
+}
+
+// receivePurchaseOrder marks a pending order received and adds its quantity
+// to the book's stock in the same transaction, so stock never moves without
+// a matching order.
+func receivePurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+	tx, err := db.Begin()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+
+	po, err := scanPurchaseOrder(tx.QueryRow(purchaseOrderSelect+" WHERE po.id = ? AND b.store_id = ?", id, storeID(c)))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, "Purchase order not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	res, err := tx.Exec("UPDATE purchase_orders SET status = ?, received_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?",
+		POStatusReceived, po.ID, POStatusPending)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusConflict, "Purchase order is "+po.Status+", not pending")
+		return
+	}
+	res, err = tx.Exec("UPDATE books SET stock = stock + ?, version = version + 1 WHERE id = ? AND store_id = ? AND deleted_at IS NULL", po.Quantity, po.BookID, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusConflict, "Book is no longer in the catalog")
+		return
+	}
+	after := snapshotBookFrom(tx, po.BookID)
+	if err := recordMovement(tx, c, po.BookID, po.Quantity, MovementRestock); err != nil {
+		internalError(c, err)
+		return
+	}
+	received, err := scanPurchaseOrder(tx.QueryRow(purchaseOrderSelect+" WHERE po.id = ?", po.ID))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	before := *after
+	before.Stock -= po.Quantity
+	recordAudit(c, "receive", "purchase_order", po.ID, &po, &received)
+	countBook(storeID(c), &before, after)
+	recordAudit(c, "restock", "book", after.ID, &before, after)
+	notifyBackInStock(after, before.Stock)
+	c.JSON(http.StatusOK, gin.H{"purchase_order": received, "stock": after.Stock})
+}
+
+func cancelPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+	before, err := scanPurchaseOrder(db.QueryRow(purchaseOrderSelect+" WHERE po.id = ? AND b.store_id = ?", id, storeID(c)))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Purchase order not found")
+		return
+	}
+	res, err := db.Exec("UPDATE purchase_orders SET status = ? WHERE id = ? AND status = ?", POStatusCancelled, before.ID, POStatusPending)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusConflict, "Purchase order is "+before.Status+", not pending")
+		return
+	}
+	after := before
+	after.Status = POStatusCancelled
+	recordAudit(c, "cancel", "purchase_order", before.ID, &before, &after)
+	c.JSON(http.StatusOK, after)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Tags ----------
+
+// Tags are free-form labels ("shonen", "ongoing") stored lowercased in
+// book_tags. GET /books?tags=a,b returns books carrying all of them, or any
+// of them with tag_mode=any.
+
+const maxTagsPerBook = 20
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9 _-]{0,39}$`)
+
+type TagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// normalizeTag lowercases a tag and collapses inner whitespace.
+func normalizeTag(raw string) (string, error) {
+	tag := strings.Join(strings.Fields(strings.ToLower(raw)), " ")
+	if !tagPattern.MatchString(tag) {
+		return "", fmt.Errorf("%q must be 1-40 letters, digits, spaces, '-' or '_'", raw)
+	}
+	return tag, nil
+}
+
+// bookTagFilter turns ?tags=&tag_mode= into a condition on b.id for the
+// book list queries. It returns an empty condition when tags is absent.
+func bookTagFilter(c *gin.Context) (string, []any, error) {
+	raw := c.Query("tags")
+	if raw == "" {
+		return "", nil, nil
+	}
+	seen := map[string]bool{}
+	var args []any
+	for _, part := range strings.Split(raw, ",") {
+		tag, err := normalizeTag(part)
+		if err != nil {
+			return "", nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			args = append(args, tag)
+		}
+	}
+	in := "SELECT book_id FROM book_tags WHERE tag IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ") + ")"
+	switch c.DefaultQuery("tag_mode", "all") {
+	case "all":
+		return fmt.Sprintf(" AND b.id IN (%s GROUP BY book_id HAVING COUNT(*) = %d)", in, len(args)), args, nil
+	case "any":
+		return " AND b.id IN (" + in + ")", args, nil
+	}
+	return "", nil, errors.New("tag_mode must be all or any")
+}
+
+func loadBookTags(bookID int) ([]string, error) {
+	rows, err := db.Query("SELECT tag FROM book_tags WHERE book_id = ? ORDER BY tag", bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// taggedBook resolves :id to a live book of the current store, answering 404
+// otherwise.
+func taggedBook(c *gin.Context) (int, bool) {
+	id, ok := paramID(c, "id")
+	if ok {
+		_, err := bookRepo.Get(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			internalError(c, err)
+			return 0, false
+		}
+		ok = err == nil
+	}
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+	}
+	return id, ok
+}
+
+func getBookTags(c *gin.Context) {
+	id, ok := taggedBook(c)
+	if !ok {
+		return
+	}
+	tags, err := loadBookTags(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"book_id": id, "tags": tags})
+}
+
+// addBookTags serves POST /books/:id/tags. Tags the book already has are
+// ignored, so the call can be repeated safely.
+func addBookTags(c *gin.Context) {
+	id, ok := taggedBook(c)
+	if !ok {
+		return
+	}
+	var req TagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	before, err := loadBookTags(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	has := map[string]bool{}
+	for _, tag := range before {
+		has[tag] = true
+	}
+	var added []string
+	for i, raw := range req.Tags {
+		tag, err := normalizeTag(raw)
+		if err != nil {
+			validationError(c, ErrorDetail{Field: fmt.Sprintf("tags[%d]", i), Message: err.Error()})
+			return
+		}
+		if !has[tag] {
+			has[tag] = true
+			added = append(added, tag)
+		}
+	}
+	if len(before)+len(added) > maxTagsPerBook {
+		validationError(c, ErrorDetail{Field: "tags", Message: fmt.Sprintf("a book can have at most %d tags", maxTagsPerBook)})
+		return
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	for _, tag := range added {
+		if _, err := tx.Exec("INSERT INTO book_tags (book_id, tag) VALUES (?, ?)", id, tag); err != nil {
+			storeError(c, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		internalError(c, err)
+		return
+	}
+	after, _ := loadBookTags(id)
+	if len(added) > 0 {
+		recordAudit(c, "tag", "book", id, gin.H{"tags": before}, gin.H{"tags": after})
+	}
+	c.JSON(http.StatusOK, gin.H{"book_id": id, "tags": after})
+}
+
+func removeBookTag(c *gin.Context) {
+	id, ok := taggedBook(c)
+	if !ok {
+		return
+	}
+	tag, err := normalizeTag(c.Param("tag"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Book has no such tag")
+		return
+	}
+	before, _ := loadBookTags(id)
+	res, err := db.Exec("DELETE FROM book_tags WHERE book_id = ? AND tag = ?", id, tag)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book has no such tag")
+		return
+	}
+	after, _ := loadBookTags(id)
+	recordAudit(c, "untag", "book", id, gin.H{"tags": before}, gin.H{"tags": after})
+	c.JSON(http.StatusOK, gin.H{"book_id": id, "tags": after})
+}
+
+// getTags serves GET /tags: every tag in use in the store with the number of
+// live books carrying it, most used first.
+func getTags(c *gin.Context) {
+	rows, err := db.Query(`SELECT t.tag, COUNT(*) FROM book_tags t
+		JOIN books b ON b.id = t.book_id
+		WHERE b.store_id = ? AND b.deleted_at IS NULL
+		GROUP BY t.tag ORDER BY COUNT(*) DESC, t.tag`, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	tags := []TagCount{}
+	for rows.Next() {
+		var t TagCount
+		if err := rows.Scan(&t.Tag, &t.Count); err != nil {
+			internalError(c, err)
+			return
+		}
+		tags = append(tags, t)
+	}
+	c.JSON(http.StatusOK, tags)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Trash (soft delete) ----------
+
+type TrashedBook struct {
+	BookWithAuthor
+	DeletedAt string `json:"deleted_at"`
+}
+
+type TrashedAuthor struct {
+	Author
+	DeletedAt string `json:"deleted_at"`
+}
+
+func deleteBook(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	ctx := c.Request.Context()
+	before, err := bookRepo.Get(ctx, id)
+	if err == nil {
+		err = bookRepo.Delete(ctx, id)
+	}
+	if errors.Is(err, ErrNotFound) {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	countBook(storeID(c), &before.Book, nil)
+	recordAudit(c, "delete", "book", id, &before.Book, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Book moved to trash"})
+}
+
+func getTrashedBooks(c *gin.Context) {
+	rows, err := db.Query(`
+	SELECT b.id, b.title, COALESCE(b.author_id, 0), COALESCE(a.name, ''), b.isbn, b.price, b.stock,
+		COALESCE(b.published_year, 0), COALESCE(b.description, ''), b.version, b.deleted_at
+	FROM books b LEFT JOIN authors a ON b.author_id = a.id
+	WHERE b.store_id = ? AND b.deleted_at IS NOT NULL
+	ORDER BY b.deleted_at DESC`, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	books := []TrashedBook{}
+	for rows.Next() {
+		var b TrashedBook
+		rows.Scan(&b.ID, &b.Title, &b.AuthorID, &b.AuthorName, &b.ISBN, &b.Price, &b.Stock, &b.PublishedYear, &b.Description, &b.Version, &b.DeletedAt)
+		books = append(books, b)
+	}
+	c.JSON(http.StatusOK, gin.H{"books": books, "count": len(books)})
+}
+
+func getTrashedAuthors(c *gin.Context) {
+	rows, err := db.Query(`
+	SELECT id, name, COALESCE(bio, ''), COALESCE(birth_year, 0), COALESCE(country, ''), created_at, deleted_at
+	FROM authors WHERE store_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC`, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	authors := []TrashedAuthor{}
+	for rows.Next() {
+		var a TrashedAuthor
+		rows.Scan(&a.ID, &a.Name, &a.Bio, &a.BirthYear, &a.Country, &a.CreatedAt, &a.DeletedAt)
+		authors = append(authors, a)
+	}
+	c.JSON(http.StatusOK, gin.H{"authors": authors, "count": len(authors)})
+}
+
+func restoreBook(c *gin.Context) {
+	id := c.Param("id")
+	before := snapshotBook(id)
+	res, err := db.Exec("UPDATE books SET deleted_at = NULL WHERE id = ? AND store_id = ? AND deleted_at IS NOT NULL", id, storeID(c))
+	if err != nil {
+		// A live copy with the same ISBN exists; merge-duplicate is the way back.
+		storeError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book not found in trash")
+		return
+	}
+	after := snapshotBook(id)
+	countBook(storeID(c), nil, after)
+	recordAudit(c, "restore", "book", before.ID, before, after)
+	c.JSON(http.StatusOK, gin.H{"message": "Book restored"})
+}
+
+func restoreAuthor(c *gin.Context) {
+	id := c.Param("id")
+	before := snapshotAuthor(id)
+	res, err := db.Exec("UPDATE authors SET deleted_at = NULL WHERE id = ? AND store_id = ? AND deleted_at IS NOT NULL", id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Author not found in trash")
+		return
+	}
+	countAuthors(storeID(c), 1)
+	recordAudit(c, "restore", "author", before.ID, before, snapshotAuthor(id))
+	c.JSON(http.StatusOK, gin.H{"message": "Author restored"})
+}
+
+// purgeBook permanently removes a book that is already in the trash.
+func purgeBook(c *gin.Context) {
+	id := c.Param("id")
+	before := snapshotBook(id)
+	res, err := db.Exec("DELETE FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NOT NULL", id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book not found in trash")
+		return
+	}
+	recordAudit(c, "purge", "book", before.ID, before, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Book permanently deleted"})
+}
+
+// purgeAuthor permanently removes a trashed author. Books still pointing at the
+// author keep their row; the foreign key sets author_id to NULL.
+func purgeAuthor(c *gin.Context) {
+	id := c.Param("id")
+	before := snapshotAuthor(id)
+	res, err := db.Exec("DELETE FROM authors WHERE id = ? AND store_id = ? AND deleted_at IS NOT NULL", id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Author not found in trash")
+		return
+	}
+	recordAudit(c, "purge", "author", before.ID, before, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Author permanently deleted"})
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Webhooks ----------
+
+const (
+	EventBookCreated = "book.created"
+	EventBookSold    = "book.sold"
+	EventStockLow    = "stock.low"
+	EventAlertRaised = "alert.raised"
+	EventBackInStock = "wishlist.back_in_stock"
+
+	webhookMaxAttempts  = 4
+	webhookQueueSize    = 256
+	webhookTimeout      = 5 * time.Second
+	webhookInitialDelay = time.Second
+)
+
+var webhookEventTypes = []string{EventBookCreated, EventBookSold, EventStockLow, EventAlertRaised, EventBackInStock}
+
+type Webhook struct {
+	ID        int      `json:"id"`
+	URL       string   `json:"url" binding:"required,url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+type WebhookEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at"`
+	Data      any    `json:"data"`
+}
+
+var (
+	webhookQueue  = make(chan WebhookEvent, webhookQueueSize)
+	webhookClient = &http.Client{Timeout: webhookTimeout}
+)
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// publishEvent queues an event for delivery without blocking the request.
+func publishEvent(eventType string, data any) {
+	ev := WebhookEvent{
+		ID:        randomHex(8),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data:      data,
+	}
+	select {
+	case webhookQueue <- ev:
+	default:
+		log.Printf("webhooks: queue full, dropping %s event %s", ev.Type, ev.ID)
+	}
+}
+
+// startWebhookDispatcher fans queued events out to every subscribed webhook.
+func startWebhookDispatcher() {
+	go func() {
+		for ev := range webhookQueue {
+			hooks, err := webhooksFor(ev.Type)
+			if err != nil {
+				log.Printf("webhooks: load subscribers: %v", err)
+				continue
+			}
+			for _, h := range hooks {
+				go deliverWebhook(h, ev)
+			}
+		}
+	}()
+}
+
+func webhooksFor(eventType string) ([]Webhook, error) {
+	rows, err := db.Query("SELECT id, url, events, secret FROM webhooks")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hooks []Webhook
+	for rows.Next() {
+		var h Webhook
+		var events string
+		if err := rows.Scan(&h.ID, &h.URL, &events, &h.Secret); err != nil {
+			return nil, err
+		}
+		for _, e := range strings.Split(events, ",") {
+			if e == eventType || e == "*" {
+				hooks = append(hooks, h)
+				break
+			}
+		}
+	}
+	return hooks, rows.Err()
+}
+
+// signPayload returns the hex HMAC-SHA256 of the body, keyed by the webhook secret.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverWebhook POSTs the event, retrying with exponential backoff on
+// network errors and non-2xx responses.
+func deliverWebhook(h Webhook, ev WebhookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("webhooks: encode %s: %v", ev.ID, err)
+		return
+	}
+	delay := webhookInitialDelay
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		req, _ := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Event", ev.Type)
+		req.Header.Set("X-Webhook-Delivery", ev.ID)
+		req.Header.Set("X-Webhook-Signature", "sha256="+signPayload(h.Secret, body))
+
+		resp, err := webhookClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+		log.Printf("webhooks: deliver %s to %s (attempt %d/%d): %v", ev.ID, h.URL, attempt, webhookMaxAttempts, err)
+		if attempt < webhookMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// ---------- Webhook Endpoints ----------
+
+func createWebhook(c *gin.Context) {
+	var h Webhook
+	if err := c.ShouldBindJSON(&h); err != nil {
+		bindError(c, err)
+		return
+	}
+	if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		validationError(c, ErrorDetail{Field: "url", Message: "must be an http(s) URL"})
+		return
+	}
+	if len(h.Events) == 0 {
+		h.Events = []string{"*"}
+	}
+	for _, e := range h.Events {
+		valid := e == "*"
+		for _, known := range webhookEventTypes {
+			valid = valid || e == known
+		}
+		if !valid {
+			validationError(c, ErrorDetail{Field: "events", Message: fmt.Sprintf("unknown event %q (allowed: *, %s)", e, strings.Join(webhookEventTypes, ", "))})
+			return
+		}
+	}
+	if h.Secret == "" {
+		h.Secret = randomHex(16)
+	}
+	res, err := db.Exec("INSERT INTO webhooks (url, events, secret) VALUES (?, ?, ?)",
+		h.URL, strings.Join(h.Events, ","), h.Secret)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	h.ID = int(id)
+	// The secret is only ever returned here so receivers can verify signatures.
+	c.JSON(http.StatusCreated, h)
+}
+
+func getWebhooks(c *gin.Context) {
+	rows, err := db.Query("SELECT id, url, events, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	hooks := []Webhook{}
+	for rows.Next() {
+		var h Webhook
+		var events string
+		rows.Scan(&h.ID, &h.URL, &events, &h.CreatedAt)
+		h.Events = strings.Split(events, ",")
+		hooks = append(hooks, h)
+	}
+	c.JSON(http.StatusOK, hooks)
+}
+
+func deleteWebhook(c *gin.Context) {
+	res, err := db.Exec("DELETE FROM webhooks WHERE id = ?", c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Webhook not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ---------- Customers & Wishlists ----------
+
+type Customer struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name" binding:"required,min=2,max=100"`
+	Email     string `json:"email" binding:"required,email"`
+	CreatedAt string `json:"created_at"`
+}
+
+type WishlistItem struct {
+	BookID     int     `json:"book_id"`
+	Title      string  `json:"title"`
+	AuthorName string  `json:"author_name"`
+	Price      float64 `json:"price"`
+	Stock      int     `json:"stock"`
+	InStock    bool    `json:"in_stock"`
+	AddedAt    string  `json:"added_at"`
+}
+
+func customerExists(id any) bool {
+	var exists bool
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM customers WHERE id = ?)", id).Scan(&exists)
+	return exists
+}
+
+func createCustomer(c *gin.Context) {
+	var cu Customer
+	if err := c.ShouldBindJSON(&cu); err != nil {
+		bindError(c, err)
+		return
+	}
+	res, err := db.Exec("INSERT INTO customers (name, email) VALUES (?, ?)", cu.Name, cu.Email)
+	if err != nil {
+		storeError(c, err)
+		return
+	}
+	id, _ := res.LastInsertId()
+	cu.ID = int(id)
+	recordAudit(c, "create", "customer", cu.ID, nil, &cu)
+	c.JSON(http.StatusCreated, cu)
+}
+
+func getCustomer(c *gin.Context) {
+	var cu Customer
+	err := db.QueryRow("SELECT id, name, email, created_at FROM customers WHERE id = ?", c.Param("id")).
+		Scan(&cu.ID, &cu.Name, &cu.Email, &cu.CreatedAt)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+	c.JSON(http.StatusOK, cu)
+}
+
+func getWishlist(c *gin.Context) {
+	id := c.Param("id")
+	if !customerExists(id) {
+		respondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+	rows, err := db.Query(`SELECT b.id, b.title, COALESCE(a.name, ''), b.price, b.stock, w.created_at
+		FROM wishlist_items w
+		JOIN books b ON b.id = w.book_id
+		LEFT JOIN authors a ON a.id = b.author_id
+		WHERE w.customer_id = ? AND b.store_id = ? AND b.deleted_at IS NULL
+		ORDER BY w.created_at, b.id`, id, storeID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer rows.Close()
+	items := []WishlistItem{}
+	for rows.Next() {
+		var it WishlistItem
+		rows.Scan(&it.BookID, &it.Title, &it.AuthorName, &it.Price, &it.Stock, &it.AddedAt)
+		it.InStock = it.Stock > 0
+		items = append(items, it)
+	}
+	customerID, _ := strconv.Atoi(id)
+	c.JSON(http.StatusOK, gin.H{"customer_id": customerID, "count": len(items), "items": items})
+}
+
+// addToWishlist is idempotent: adding a book twice answers 200 instead of 201.
+func addToWishlist(c *gin.Context) {
+	id, bookID := c.Param("id"), c.Param("book_id")
+	if !customerExists(id) {
+		respondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+	var exists bool
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM books WHERE id = ? AND store_id = ? AND deleted_at IS NULL)", bookID, storeID(c)).Scan(&exists)
+	if !exists {
+		respondError(c, http.StatusNotFound, "Book not found")
+		return
+	}
+	db.QueryRow("SELECT EXISTS(SELECT 1 FROM wishlist_items WHERE customer_id = ? AND book_id = ?)", id, bookID).Scan(&exists)
+	if exists {
+		c.JSON(http.StatusOK, gin.H{"message": "Book already on wishlist"})
+		return
+	}
+	if _, err := db.Exec("INSERT INTO wishlist_items (customer_id, book_id) VALUES (?, ?)", id, bookID); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Book added to wishlist"})
+}
+
+func removeFromWishlist(c *gin.Context) {
+	res, err := db.Exec("DELETE FROM wishlist_items WHERE customer_id = ? AND book_id = ?", c.Param("id"), c.Param("book_id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if ra, _ := res.RowsAffected(); ra == 0 {
+		respondError(c, http.StatusNotFound, "Book is not on this wishlist")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Book removed from wishlist"})
+}
+
+// notifyBackInStock tells every customer wishing for book that it can be
+// bought again, via the wishlist.back_in_stock webhook and, when SMTP is
+// configured, an email, and queues its notify-me subscriptions. Call it
+// after stock rises from zero.
+func notifyBackInStock(book *Book, previousStock int) {
+	if book == nil || previousStock > 0 || book.Stock <= 0 {
+		return
+	}
+	queueStockNotifications(book.ID)
+	rows, err := db.Query(`SELECT cu.id, cu.name, cu.email FROM wishlist_items w
+		JOIN customers cu ON cu.id = w.customer_id
+		WHERE w.book_id = ?`, book.ID)
+	if err != nil {
+		log.Printf("wishlists: load subscribers for book %d: %v", book.ID, err)
+		return
+	}
+	var customers []Customer
+	for rows.Next() {
+		var cu Customer
+		rows.Scan(&cu.ID, &cu.Name, &cu.Email)
+		customers = append(customers, cu)
+	}
+	rows.Close()
+
+	for _, cu := range customers {
+		publishEvent(EventBackInStock, gin.H{
+			"customer_id": cu.ID, "email": cu.Email,
+			"book_id": book.ID, "title": book.Title, "stock": book.Stock,
+		})
+		body := fmt.Sprintf("Hi %s,\r\n\r\n%q from your wishlist is back in stock (%d available).\r\n", cu.Name, book.Title, book.Stock)
+		if err := sendMail([]string{cu.Email}, "[Bookstore] Back in stock: "+book.Title, body); err != nil {
+			log.Printf("wishlists: email customer %d: %v", cu.ID, err)
+		}
+	}
+	if len(customers) > 0 {
+		db.Exec("UPDATE wishlist_items SET notified_at = CURRENT_TIMESTAMP WHERE book_id = ?", book.ID)
+	}
+}
@@ -3,31 +3,72 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"strings"
 
+	"book-catalog-grpc/compression"
+	"book-catalog-grpc/interceptors"
+	"book-catalog-grpc/keepaliveconfig"
 	pb "book-catalog-grpc/proto"
+	"book-catalog-grpc/reflectionconfig"
+	"book-catalog-grpc/requestid"
+	"book-catalog-grpc/sqlitedb"
+	"book-catalog-grpc/tlsconfig"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	_ "modernc.org/sqlite"
 )
 
 type bookCatalogServer struct {
 	pb.UnimplementedBookCatalogServer
 	db *sql.DB
+
+	// Các query dùng nhiều nhất được prepare một lần lúc khởi động
+	getBookStmt    *sql.Stmt
+	listBooksStmt  *sql.Stmt // ListBooks với thứ tự mặc định
+	createBookStmt *sql.Stmt
 }
 
-func (s *bookCatalogServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.GetBookResponse, error) {
-	log.Printf("GetBook: id=%d", req.Id)
+const (
+	getBookQuery    = "SELECT id, title, author, isbn, price, stock, published_year FROM books WHERE id = ?"
+	listBooksQuery  = "SELECT id, title, author, isbn, price, stock, published_year FROM books ORDER BY id LIMIT ? OFFSET ?"
+	createBookQuery = "INSERT INTO books (title, author, isbn, price, stock, published_year) VALUES (?, ?, ?, ?, ?, ?)"
+)
 
+func newBookCatalogServer(db *sql.DB) (*bookCatalogServer, error) {
+	s := &bookCatalogServer{db: db}
+	var err error
+	if s.getBookStmt, err = db.Prepare(getBookQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare GetBook: %w", err)
+	}
+	if s.listBooksStmt, err = db.Prepare(listBooksQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare ListBooks: %w", err)
+	}
+	if s.createBookStmt, err = db.Prepare(createBookQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare CreateBook: %w", err)
+	}
+	return s, nil
+}
+
+// scanBook đọc một dòng "id, title, author, isbn, price, stock, published_year".
+// Server này lưu giá dạng REAL theo USD nên giá được đổi sang Money ở đây
+func scanBook(row interface{ Scan(...any) error }, book *pb.Book) error {
+	var price float64
+	if err := row.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &price, &book.Stock, &book.PublishedYear); err != nil {
+		return err
+	}
+	book.Price = pb.MoneyFromFloat(pb.DefaultCurrency, price)
+	return nil
+}
+
+func (s *bookCatalogServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.GetBookResponse, error) {
 	// Query book từ database
 	var book pb.Book
-	err := s.db.QueryRowContext(ctx,
-		"SELECT id, title, author, isbn, price, stock, published_year FROM books WHERE id = ?",
-		req.Id).Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear)
+	err := scanBook(s.getBookStmt.QueryRowContext(ctx, req.Id), &book)
 
 	if err == sql.ErrNoRows {
 		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
@@ -40,20 +81,19 @@ func (s *bookCatalogServer) GetBook(ctx context.Context, req *pb.GetBookRequest)
 }
 
 func (s *bookCatalogServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.CreateBookResponse, error) {
-	log.Printf("CreateBook: title=%s, author=%s", req.Title, req.Author)
-
-	// Validate input
-	if req.Title == "" || req.Author == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and author are required")
+	// Server này không lưu request_id; từ chối thay vì để retry tạo sách trùng
+	if req.RequestId != "" {
+		return nil, status.Error(codes.Unimplemented, "request_id is only supported by the Task5 book-service")
 	}
-	if req.Price < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+
+	// Cột price chỉ lưu số, không lưu tiền tệ; chỉ nhận giá theo USD
+	if req.Price.GetCurrencyCode() != pb.DefaultCurrency {
+		return nil, status.Error(codes.Unimplemented, "prices in currencies other than USD are only supported by the Task5 book-service")
 	}
 
 	// Insert vào database
-	result, err := s.db.ExecContext(ctx,
-		"INSERT INTO books (title, author, isbn, price, stock, published_year) VALUES (?, ?, ?, ?, ?, ?)",
-		req.Title, req.Author, req.Isbn, req.Price, req.Stock, req.PublishedYear)
+	result, err := s.createBookStmt.ExecContext(ctx,
+		req.Title, req.Author, req.Isbn, req.Price.Float(), req.Stock, req.PublishedYear)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to insert book: %v", err)
@@ -79,20 +119,27 @@ func (s *bookCatalogServer) CreateBook(ctx context.Context, req *pb.CreateBookRe
 }
 
 func (s *bookCatalogServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.UpdateBookResponse, error) {
-	log.Printf("UpdateBook: id=%d, title=%s", req.Id, req.Title)
+	// Server này luôn ghi đè mọi field; từ chối update_mask thay vì xoá mất
+	// các field client không gửi
+	if len(req.GetUpdateMask().GetPaths()) > 0 {
+		return nil, status.Error(codes.Unimplemented, "update_mask is only supported by the Task5 book-service")
+	}
 
-	// Validate input
-	if req.Title == "" || req.Author == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and author are required")
+	// Server này không có cột version; từ chối etag thay vì ghi đè mà client
+	// tưởng đã được kiểm tra
+	if req.Etag != "" {
+		return nil, status.Error(codes.Unimplemented, "etag is only supported by the Task5 book-service")
 	}
-	if req.Price < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+
+	// Cột price chỉ lưu số, không lưu tiền tệ; chỉ nhận giá theo USD
+	if req.Price.GetCurrencyCode() != pb.DefaultCurrency {
+		return nil, status.Error(codes.Unimplemented, "prices in currencies other than USD are only supported by the Task5 book-service")
 	}
 
 	// Update trong database
 	result, err := s.db.ExecContext(ctx,
 		"UPDATE books SET title=?, author=?, isbn=?, price=?, stock=?, published_year=? WHERE id=?",
-		req.Title, req.Author, req.Isbn, req.Price, req.Stock, req.PublishedYear, req.Id)
+		req.Title, req.Author, req.Isbn, req.Price.Float(), req.Stock, req.PublishedYear, req.Id)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update book: %v", err)
@@ -121,8 +168,8 @@ func (s *bookCatalogServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRe
 }
 
 func (s *bookCatalogServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.DeleteBookResponse, error) {
-	log.Printf("DeleteBook: id=%d", req.Id)
-
+	// Server này không có cột deleted nên không có xoá mềm: DeleteBook luôn
+	// xoá hẳn như trước, bỏ qua hard, để các client v1 cũ vẫn xoá được
 	// Delete từ database
 	result, err := s.db.ExecContext(ctx, "DELETE FROM books WHERE id = ?", req.Id)
 	if err != nil {
@@ -144,8 +191,49 @@ func (s *bookCatalogServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRe
 	}, nil
 }
 
+// orderColumns map các field order_by được sort theo sang cột của bảng books
+// của server này. Chỉ các tên này mới vào tới SQL.
+var orderColumns = map[string]string{
+	"id":             "id",
+	"title":          "title",
+	"author":         "author",
+	"isbn":           "isbn",
+	"price":          "price",
+	"stock":          "stock",
+	"published_year": "published_year",
+}
+
+// orderByClause trả về "ORDER BY ..." cho req.OrderBy (Validate đã kiểm tra),
+// luôn kết thúc bằng id để phân trang ổn định khi giá trị sort trùng nhau.
+func orderByClause(req *pb.ListBooksRequest) string {
+	keys, err := pb.ParseOrderBy(req.OrderBy)
+	if err != nil || len(keys) == 0 {
+		return "ORDER BY id"
+	}
+	var cols []string
+	for _, k := range keys {
+		col, ok := orderColumns[k.Field]
+		if !ok {
+			return "ORDER BY id"
+		}
+		if k.Desc {
+			col += " DESC"
+		} else {
+			col += " ASC"
+		}
+		cols = append(cols, col)
+	}
+	return "ORDER BY " + strings.Join(cols, ", ") + ", id"
+}
+
 func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
-	log.Printf("ListBooks: page=%d, page_size=%d", req.Page, req.PageSize)
+	// Server này chưa hỗ trợ lọc trong ListBooks; dùng FilterBooks
+	if req.Filter != nil {
+		return nil, status.Error(codes.Unimplemented, "filters in ListBooks are only supported by the Task5 book-service")
+	}
+	if req.UpdatedSince != nil {
+		return nil, status.Error(codes.Unimplemented, "updated_since is only supported by the Task5 book-service")
+	}
 
 	// Set default values cho pagination
 	page := req.Page
@@ -167,10 +255,16 @@ func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequ
 		return nil, status.Errorf(codes.Internal, "failed to count books: %v", err)
 	}
 
-	// Query sách với LIMIT và OFFSET
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, title, author, isbn, price, stock, published_year FROM books ORDER BY id LIMIT ? OFFSET ?",
-		pageSize, offset)
+	// Query sách với LIMIT và OFFSET, sắp xếp theo order_by (chỉ các field
+	// trong orderColumns, mặc định theo id). Thứ tự mặc định dùng statement
+	// đã prepare.
+	var rows *sql.Rows
+	query := "SELECT id, title, author, isbn, price, stock, published_year FROM books " + orderByClause(req) + " LIMIT ? OFFSET ?"
+	if query == listBooksQuery {
+		rows, err = s.listBooksStmt.QueryContext(ctx, pageSize, offset)
+	} else {
+		rows, err = s.db.QueryContext(ctx, query, pageSize, offset)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to query books: %v", err)
 	}
@@ -180,7 +274,7 @@ func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequ
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear)
+		err := scanBook(rows, &book)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
 		}
@@ -191,16 +285,69 @@ func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequ
 		return nil, status.Errorf(codes.Internal, "rows error: %v", err)
 	}
 
-	return &pb.ListBooksResponse{
+	resp := &pb.ListBooksResponse{
 		Books:    books,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	}, nil
+	}
+	return resp.SetPageInfo(), nil
+}
+
+// StreamBooks gửi từng sách ngay khi đọc được row, không gom cả catalog vào
+// memory. Send sẽ block khi flow-control window của client đầy, nên tốc độ
+// đọc database đi theo tốc độ client nhận.
+func (s *bookCatalogServer) StreamBooks(req *pb.ListBooksRequest, stream pb.BookCatalog_StreamBooksServer) error {
+	// Server này chưa hỗ trợ lọc trong ListBooks; dùng FilterBooks
+	if req.Filter != nil {
+		return status.Error(codes.Unimplemented, "filters in ListBooks are only supported by the Task5 book-service")
+	}
+	if req.UpdatedSince != nil {
+		return status.Error(codes.Unimplemented, "updated_since is only supported by the Task5 book-service")
+	}
+
+	// page_size = 0 nghĩa là stream toàn bộ sách
+	query := "SELECT id, title, author, isbn, price, stock, published_year FROM books " + orderByClause(req)
+	var args []interface{}
+	if req.PageSize > 0 {
+		page := req.Page
+		if page < 1 {
+			page = 1
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, req.PageSize, (page-1)*req.PageSize)
+	}
+
+	rows, err := s.db.QueryContext(stream.Context(), query, args...)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to query books: %v", err)
+	}
+	defer rows.Close()
+
+	sent := 0
+	for rows.Next() {
+		var book pb.Book
+		err := scanBook(rows, &book)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to scan book: %v", err)
+		}
+		// Lỗi khi client đã cancel hoặc ngắt kết nối
+		if err := stream.Send(&book); err != nil {
+			return err
+		}
+		sent++
+	}
+
+	if err = rows.Err(); err != nil {
+		return status.Errorf(codes.Internal, "rows error: %v", err)
+	}
+
+	log.Printf("StreamBooks: sent %d books", sent)
+	return nil
 }
 
 func initDB() (*sql.DB, error) {
-	db, err := sql.Open("sqlite", "./books.db")
+	db, err := sqlitedb.Open("./books.db")
 	if err != nil {
 		return nil, err
 	}
@@ -260,11 +407,9 @@ func initDB() (*sql.DB, error) {
 }
 
 func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooksRequest) (*pb.SearchBooksResponse, error) {
-	log.Printf("SearchBooks: query=%s, field=%s", req.Query, req.Field)
-
-	// Validate input
-	if req.Query == "" {
-		return nil, status.Error(codes.InvalidArgument, "search query required")
+	// Server này chỉ tìm bằng LIKE; báo rõ thay vì lặng lẽ bỏ qua fuzzy
+	if req.Fuzzy {
+		return nil, status.Error(codes.Unimplemented, "fuzzy search is only supported by the Task5 book-service")
 	}
 
 	// Build SQL query based on field
@@ -304,7 +449,7 @@ func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooks
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear)
+		err := scanBook(rows, &book)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
 		}
@@ -323,32 +468,32 @@ func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooks
 }
 
 func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooksRequest) (*pb.FilterBooksResponse, error) {
-	log.Printf("FilterBooks: price[%.2f-%.2f], year[%d-%d]",
-		req.MinPrice, req.MaxPrice, req.MinYear, req.MaxYear)
-
-	// Validate ranges
-	if req.MinPrice < 0 || req.MaxPrice < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price cannot be negative")
-	}
-	if req.MinPrice > 0 && req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
-		return nil, status.Error(codes.InvalidArgument, "min_price cannot be greater than max_price")
+	// Bảng books của server này không có cột category
+	if len(req.Categories) > 0 {
+		return nil, status.Error(codes.Unimplemented, "category filters are only supported by the Task5 book-service")
 	}
-	if req.MinYear > 0 && req.MaxYear > 0 && req.MinYear > req.MaxYear {
-		return nil, status.Error(codes.InvalidArgument, "min_year cannot be greater than max_year")
+	// Bảng books của server này cũng không có cột author_id
+	if req.AuthorId > 0 {
+		return nil, status.Error(codes.Unimplemented, "author filters are only supported by the Task5 book-service")
 	}
 
 	// Build dynamic query
 	query := "SELECT id, title, author, isbn, price, stock, published_year FROM books WHERE 1=1"
 	var args []interface{}
 
-	// Add price filters
-	if req.MinPrice > 0 {
+	// Add price filters; giá trong bảng là USD
+	for _, bound := range []*pb.Money{req.MinPrice, req.MaxPrice} {
+		if bound != nil && bound.CurrencyCode != pb.DefaultCurrency {
+			return nil, status.Error(codes.Unimplemented, "prices in currencies other than USD are only supported by the Task5 book-service")
+		}
+	}
+	if req.MinPrice != nil {
 		query += " AND price >= ?"
-		args = append(args, req.MinPrice)
+		args = append(args, req.MinPrice.Float())
 	}
-	if req.MaxPrice > 0 {
+	if req.MaxPrice != nil {
 		query += " AND price <= ?"
-		args = append(args, req.MaxPrice)
+		args = append(args, req.MaxPrice.Float())
 	}
 
 	// Add year filters
@@ -361,6 +506,19 @@ func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooks
 		args = append(args, req.MaxYear)
 	}
 
+	// Add stock filters
+	if req.InStockOnly {
+		query += " AND stock > 0"
+	}
+	if req.MinStock > 0 {
+		query += " AND stock >= ?"
+		args = append(args, req.MinStock)
+	}
+	if req.MaxStock > 0 {
+		query += " AND stock <= ?"
+		args = append(args, req.MaxStock)
+	}
+
 	// Execute query
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -372,7 +530,7 @@ func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooks
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear)
+		err := scanBook(rows, &book)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
 		}
@@ -390,8 +548,6 @@ func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooks
 }
 
 func (s *bookCatalogServer) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
-	log.Println("GetStats called")
-
 	var stats pb.GetStatsResponse
 
 	// Get total books
@@ -407,7 +563,7 @@ func (s *bookCatalogServer) GetStats(ctx context.Context, req *pb.GetStatsReques
 		return nil, status.Errorf(codes.Internal, "failed to calculate average price: %v", err)
 	}
 	if avgPrice.Valid {
-		stats.AveragePrice = float32(avgPrice.Float64)
+		stats.AveragePrices = []*pb.Money{pb.MoneyFromFloat(pb.DefaultCurrency, avgPrice.Float64)}
 	}
 
 	// Get total stock
@@ -433,6 +589,8 @@ func (s *bookCatalogServer) GetStats(ctx context.Context, req *pb.GetStatsReques
 }
 
 func main() {
+	flag.Parse()
+
 	// Khởi tạo database
 	db, err := initDB()
 	if err != nil {
@@ -449,10 +607,22 @@ func main() {
 	}
 
 	// Tạo gRPC server
-	grpcServer := grpc.NewServer()
+	creds, err := tlsconfig.ServerCredentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS credentials: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), keepaliveconfig.ServerParams(), keepaliveconfig.EnforcementPolicy(), compression.ServerOption(),
+		grpc.ChainUnaryInterceptor(interceptors.UnaryMetrics(), requestid.UnaryServerInterceptor(), interceptors.UnaryLogging(), interceptors.UnaryRecovery(), interceptors.UnaryValidation()),
+		grpc.ChainStreamInterceptor(interceptors.StreamMetrics(), requestid.StreamServerInterceptor(), interceptors.StreamLogging(), interceptors.StreamRecovery(), interceptors.StreamValidation()))
+	interceptors.ServeMetrics()
 
 	// Register service
-	pb.RegisterBookCatalogServer(grpcServer, &bookCatalogServer{db: db})
+	srv, err := newBookCatalogServer(db)
+	if err != nil {
+		log.Fatalf("Failed to prepare statements: %v", err)
+	}
+	pb.RegisterBookCatalogServer(grpcServer, srv)
+	reflectionconfig.Register(grpcServer)
 
 	log.Println("📚 BookCatalog gRPC server (Task4) listening on :50053")
 
@@ -2,72 +2,109 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net"
-
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"book-catalog-grpc/auth"
+	"book-catalog-grpc/breaker"
+	"book-catalog-grpc/cache"
+	"book-catalog-grpc/endpoints"
+	"book-catalog-grpc/healthcheck"
+	"book-catalog-grpc/interceptors"
+	"book-catalog-grpc/keepaliveconfig"
 	authorpb "book-catalog-grpc/proto"
 	bookpb "book-catalog-grpc/proto"
+	"book-catalog-grpc/reflectionconfig"
+	"book-catalog-grpc/requestid"
+	"book-catalog-grpc/retry"
+	"book-catalog-grpc/sqlitedb"
+	"book-catalog-grpc/tlsconfig"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
-	_ "modernc.org/sqlite"
+)
+
+// bookAddr is where Book service runs: one address, a comma-separated list
+// of replicas or a dns:/// target. Left empty, package endpoints finds it.
+var bookAddr = flag.String("book-addr", os.Getenv("BOOK_ADDR"), "Book service address(es) (default: endpoints file or 127.0.0.1:50051)")
+
+// The circuit breaker on the Book service client opens after
+// breakerThreshold failed calls in a row and tries again after
+// breakerCooldown.
+var (
+	breakerThreshold = flag.Int("breaker-threshold", 5, "consecutive Book service failures that open the circuit")
+	breakerCooldown  = flag.Duration("breaker-cooldown", 10*time.Second, "how long the circuit stays open before a trial call")
 )
 
 type authorCatalogServer struct {
 	authorpb.UnimplementedAuthorCatalogServer
 	db         *sql.DB
 	bookClient bookpb.BookCatalogClient // Client to Book service
+	// authors caches GetAuthor by id. Cached authors are shared between
+	// responses and must not be modified.
+	authors *cache.LRU[int32, *authorpb.Author]
 }
 
 func newServer(db *sql.DB, bookClient bookpb.BookCatalogClient) *authorCatalogServer {
 	return &authorCatalogServer{
 		db:         db,
 		bookClient: bookClient,
+		authors:    cache.New[int32, *authorpb.Author]("authors"),
 	}
 }
 
-func (s *authorCatalogServer) GetAuthor(ctx context.Context, req *authorpb.GetAuthorRequest) (*authorpb.GetAuthorResponse, error) {
-	log.Printf("GetAuthor: id=%d", req.Id)
-
-	if req.Id <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "author id must be positive")
+// scanAuthor scans the columns "id, name, bio, birth_year, country,
+// created_at, updated_at", which every query returning authors selects. The
+// times are Unix seconds.
+func scanAuthor(row interface{ Scan(...any) error }, author *authorpb.Author) error {
+	var created, updated int64
+	if err := row.Scan(&author.Id, &author.Name, &author.Bio, &author.BirthYear, &author.Country, &created, &updated); err != nil {
+		return err
 	}
+	author.CreatedAt, author.UpdatedAt = authorpb.UnixTime(created), authorpb.UnixTime(updated)
+	return nil
+}
 
-	var author authorpb.Author
-	err := s.db.QueryRowContext(ctx,
-		"SELECT id, name, bio, birth_year, country FROM authors WHERE id = ?",
-		req.Id,
-	).Scan(&author.Id, &author.Name, &author.Bio, &author.BirthYear, &author.Country)
+func (s *authorCatalogServer) GetAuthor(ctx context.Context, req *authorpb.GetAuthorRequest) (*authorpb.GetAuthorResponse, error) {
+	author, err := s.authors.Get(req.Id, func() (*authorpb.Author, error) {
+		var author authorpb.Author
+		err := scanAuthor(s.db.QueryRowContext(ctx,
+			"SELECT id, name, bio, birth_year, country, created_at, updated_at FROM authors WHERE id = ?",
+			req.Id,
+		), &author)
 
-	if err == sql.ErrNoRows {
-		return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.Id)
-	}
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.Id)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "database error: %v", err)
+		}
+		return &author, nil
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+		return nil, err
 	}
 
-	return &authorpb.GetAuthorResponse{Author: &author}, nil
+	return &authorpb.GetAuthorResponse{Author: author}, nil
 }
 
 func (s *authorCatalogServer) CreateAuthor(ctx context.Context, req *authorpb.CreateAuthorRequest) (*authorpb.CreateAuthorResponse, error) {
-	log.Printf("CreateAuthor: name=%s", req.Name)
-
-	// Validation
-	if req.Name == "" {
-		return nil, status.Error(codes.InvalidArgument, "author name is required")
-	}
-	if req.BirthYear < 1800 || req.BirthYear > 2100 {
-		return nil, status.Error(codes.InvalidArgument, "birth year must be between 1800 and 2100")
-	}
-
 	// Insert into database
+	now := time.Now().Unix()
 	result, err := s.db.ExecContext(ctx,
-		"INSERT INTO authors (name, bio, birth_year, country) VALUES (?, ?, ?, ?)",
-		req.Name, req.Bio, req.BirthYear, req.Country)
+		"INSERT INTO authors (name, bio, birth_year, country, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		req.Name, req.Bio, req.BirthYear, req.Country, now, now)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to insert author: %v", err)
@@ -84,14 +121,170 @@ func (s *authorCatalogServer) CreateAuthor(ctx context.Context, req *authorpb.Cr
 		Bio:       req.Bio,
 		BirthYear: req.BirthYear,
 		Country:   req.Country,
+		CreatedAt: authorpb.UnixTime(now),
+		UpdatedAt: authorpb.UnixTime(now),
 	}
 
 	return &authorpb.CreateAuthorResponse{Author: author}, nil
 }
 
-func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.ListAuthorsRequest) (*authorpb.ListAuthorsResponse, error) {
-	log.Printf("ListAuthors: page=%d, page_size=%d", req.Page, req.PageSize)
+// newRequestID returns a random CreateBook request_id.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
 
+// compensationTimeout bounds the undo steps of CreateAuthorWithBooks. They
+// run on a context of their own so they still happen when the client has
+// gone or its deadline has passed.
+const compensationTimeout = 5 * time.Second
+
+// CreateAuthorWithBooks is a saga across both services: each step is a local
+// transaction (the author here, each book on Book service) and a failed step
+// is undone by compensating actions in reverse order, since the two
+// databases cannot share one transaction.
+func (s *authorCatalogServer) CreateAuthorWithBooks(ctx context.Context, req *authorpb.CreateAuthorWithBooksRequest) (*authorpb.CreateAuthorWithBooksResponse, error) {
+	// Step 1: Create the author locally
+	authorResp, err := s.CreateAuthor(ctx, req.Author)
+	if err != nil {
+		return nil, err
+	}
+	author := authorResp.Author
+
+	// Step 2: Create the books one by one on Book service. Each gets its own
+	// request_id, so a retried CreateBook cannot leave a second copy that
+	// compensation would not know to delete.
+	var created []*authorpb.BookSummary
+	for i, b := range req.Books {
+		bookResp, err := s.bookClient.CreateBook(ctx, &bookpb.CreateBookRequest{
+			Title:         b.Title,
+			Author:        author.Name,
+			AuthorId:      author.Id,
+			Isbn:          b.Isbn,
+			Price:         b.Price,
+			Stock:         b.Stock,
+			PublishedYear: b.PublishedYear,
+			RequestId:     newRequestID(),
+		})
+		if err != nil {
+			requestid.Printf(ctx, "⚠️ Saga failed at book %d/%d for author_id=%d: %v", i+1, len(req.Books), author.Id, err)
+			// Step 3 (on failure): undo everything done so far
+			msg := s.compensate(ctx, author.Id, created)
+			st := status.Convert(err)
+			return nil, status.Errorf(st.Code(), "books[%d] (%q) failed: %s; %s", i, b.Title, st.Message(), msg)
+		}
+		created = append(created, &authorpb.BookSummary{
+			Id:            bookResp.Book.Id,
+			Title:         bookResp.Book.Title,
+			Price:         bookResp.Book.Price,
+			PublishedYear: bookResp.Book.PublishedYear,
+		})
+	}
+
+	requestid.Printf(ctx, "✅ Saga completed: author %s with %d books", author.Name, len(created))
+	return &authorpb.CreateAuthorWithBooksResponse{
+		Author: author,
+		Books:  created,
+	}, nil
+}
+
+// compensate hard-deletes the given books, which should never have existed,
+// and then the author, and describes the outcome for the error returned to
+// the client. A failed undo step is logged and reported but does not stop
+// the others.
+func (s *authorCatalogServer) compensate(ctx context.Context, authorID int32, books []*authorpb.BookSummary) string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	var failed []string
+	for i := len(books) - 1; i >= 0; i-- {
+		if _, err := s.bookClient.DeleteBook(ctx, &bookpb.DeleteBookRequest{Id: books[i].Id, Hard: true}); err != nil {
+			requestid.Printf(ctx, "❌ Compensation: failed to delete book %d: %v", books[i].Id, err)
+			failed = append(failed, fmt.Sprintf("book %d", books[i].Id))
+		}
+	}
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM authors WHERE id = ?", authorID); err != nil {
+		requestid.Printf(ctx, "❌ Compensation: failed to delete author %d: %v", authorID, err)
+		failed = append(failed, fmt.Sprintf("author %d", authorID))
+	}
+	s.authors.Invalidate(authorID)
+
+	if len(failed) > 0 {
+		return "rollback incomplete, left behind: " + strings.Join(failed, ", ")
+	}
+	requestid.Printf(ctx, "↩️ Compensation: removed author %d and %d books", authorID, len(books))
+	return fmt.Sprintf("rolled back author %d and %d created books", authorID, len(books))
+}
+
+func (s *authorCatalogServer) UpdateAuthor(ctx context.Context, req *authorpb.UpdateAuthorRequest) (*authorpb.UpdateAuthorResponse, error) {
+	// RETURNING hands back created_at, which the request does not carry.
+	var author authorpb.Author
+	err := scanAuthor(s.db.QueryRowContext(ctx,
+		`UPDATE authors SET name = ?, bio = ?, birth_year = ?, country = ?, updated_at = ? WHERE id = ?
+		RETURNING id, name, bio, birth_year, country, created_at, updated_at`,
+		req.Name, req.Bio, req.BirthYear, req.Country, time.Now().Unix(), req.Id), &author)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update author: %v", err)
+	}
+	s.authors.Invalidate(req.Id)
+
+	return &authorpb.UpdateAuthorResponse{Author: &author}, nil
+}
+
+// DeleteAuthor asks Book service first and refuses while any book still
+// points at the author, so no book is left with a dangling author_id. Unlike
+// GetAuthorBooks there is no graceful degradation: if Book service cannot
+// answer, nothing is deleted.
+func (s *authorCatalogServer) DeleteAuthor(ctx context.Context, req *authorpb.DeleteAuthorRequest) (*authorpb.DeleteAuthorResponse, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM authors WHERE id = ?)", req.Id).Scan(&exists)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+	}
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.Id)
+	}
+
+	// Soft-deleted books count too: UndeleteBook would bring them back
+	// pointing at an author that no longer exists.
+	requestid.Printf(ctx, "🔄 Checking Book service for books by author_id=%d", req.Id)
+	bookResp, err := s.bookClient.GetBooksByAuthor(ctx, &bookpb.GetBooksByAuthorRequest{
+		AuthorId:       req.Id,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot check books of author %d: %s", req.Id, status.Convert(err).Message())
+	}
+	if bookResp.Count > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "author %d still has %d books, soft-deleted ones included; hard-delete or reassign them first", req.Id, bookResp.Count)
+	}
+
+	// A book created for this author between the check and the delete is
+	// not caught; the two databases share no transaction.
+	result, err := s.db.ExecContext(ctx, "DELETE FROM authors WHERE id = ?", req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete author: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.Id)
+	}
+	s.authors.Invalidate(req.Id)
+
+	return &authorpb.DeleteAuthorResponse{
+		Success: true,
+		Message: fmt.Sprintf("Author with id %d deleted successfully", req.Id),
+	}, nil
+}
+
+func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.ListAuthorsRequest) (*authorpb.ListAuthorsResponse, error) {
 	// Default values
 	if req.Page < 1 {
 		req.Page = 1
@@ -102,10 +295,18 @@ func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.Lis
 
 	offset := (req.Page - 1) * req.PageSize
 
+	// updated_since lets a client fetch only what changed since its last sync
+	where := ""
+	var args []interface{}
+	if req.UpdatedSince != nil {
+		where = " WHERE updated_at >= ?"
+		args = append(args, req.UpdatedSince.Seconds)
+	}
+
 	// Query authors with pagination
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, name, bio, birth_year, country FROM authors LIMIT ? OFFSET ?",
-		req.PageSize, offset)
+		"SELECT id, name, bio, birth_year, country, created_at, updated_at FROM authors"+where+" ORDER BY id LIMIT ? OFFSET ?",
+		append(args, req.PageSize, offset)...)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to query authors: %v", err)
 	}
@@ -114,7 +315,7 @@ func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.Lis
 	var authors []*authorpb.Author
 	for rows.Next() {
 		var author authorpb.Author
-		if err := rows.Scan(&author.Id, &author.Name, &author.Bio, &author.BirthYear, &author.Country); err != nil {
+		if err := scanAuthor(rows, &author); err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to scan author: %v", err)
 		}
 		authors = append(authors, &author)
@@ -122,7 +323,7 @@ func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.Lis
 
 	// Get total count
 	var total int32
-	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM authors").Scan(&total)
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM authors"+where, args...).Scan(&total)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to count authors: %v", err)
 	}
@@ -133,20 +334,77 @@ func (s *authorCatalogServer) ListAuthors(ctx context.Context, req *authorpb.Lis
 	}, nil
 }
 
-// 🚀 KEY FEATURE: Service-to-Service Communication
-func (s *authorCatalogServer) GetAuthorBooks(ctx context.Context, req *authorpb.GetAuthorBooksRequest) (*authorpb.GetAuthorBooksResponse, error) {
-	log.Printf("GetAuthorBooks: author_id=%d", req.AuthorId)
+func (s *authorCatalogServer) SearchAuthors(ctx context.Context, req *authorpb.SearchAuthorsRequest) (*authorpb.SearchAuthorsResponse, error) {
+	// Default values
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
 
-	if req.AuthorId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "author_id must be positive")
+	where := " WHERE 1=1"
+	var args []interface{}
+	if req.Name != "" {
+		where += " AND name LIKE ?"
+		args = append(args, "%"+req.Name+"%")
 	}
+	if req.Country != "" {
+		where += " AND country = ? COLLATE NOCASE"
+		args = append(args, req.Country)
+	}
+	if req.MinBirthYear > 0 {
+		where += " AND birth_year >= ?"
+		args = append(args, req.MinBirthYear)
+	}
+	if req.MaxBirthYear > 0 {
+		where += " AND birth_year <= ?"
+		args = append(args, req.MaxBirthYear)
+	}
+
+	var total int32
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM authors"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count authors: %v", err)
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, name, bio, birth_year, country, created_at, updated_at FROM authors"+where+" ORDER BY id LIMIT ? OFFSET ?",
+		append(args, req.PageSize, offset)...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search authors: %v", err)
+	}
+	defer rows.Close()
+
+	var authors []*authorpb.Author
+	for rows.Next() {
+		var author authorpb.Author
+		if err := scanAuthor(rows, &author); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan author: %v", err)
+		}
+		authors = append(authors, &author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read authors: %v", err)
+	}
+
+	return &authorpb.SearchAuthorsResponse{
+		Authors:  authors,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
 
+// 🚀 KEY FEATURE: Service-to-Service Communication
+func (s *authorCatalogServer) GetAuthorBooks(ctx context.Context, req *authorpb.GetAuthorBooksRequest) (*authorpb.GetAuthorBooksResponse, error) {
 	// Step 1: Get author from local database
 	var author authorpb.Author
-	err := s.db.QueryRowContext(ctx,
-		"SELECT id, name, bio, birth_year, country FROM authors WHERE id = ?",
+	err := scanAuthor(s.db.QueryRowContext(ctx,
+		"SELECT id, name, bio, birth_year, country, created_at, updated_at FROM authors WHERE id = ?",
 		req.AuthorId,
-	).Scan(&author.Id, &author.Name, &author.Bio, &author.BirthYear, &author.Country)
+	), &author)
 
 	if err == sql.ErrNoRows {
 		return nil, status.Errorf(codes.NotFound, "author not found: id=%d", req.AuthorId)
@@ -157,18 +415,20 @@ func (s *authorCatalogServer) GetAuthorBooks(ctx context.Context, req *authorpb.
 
 	// Step 2: Call Book service to get books by this author
 	// This demonstrates MICROSERVICE COMMUNICATION!
-	log.Printf("🔄 Calling Book service for author_id=%d", req.AuthorId)
+	requestid.Printf(ctx, "🔄 Calling Book service for author_id=%d", req.AuthorId)
 	bookResp, err := s.bookClient.GetBooksByAuthor(ctx, &bookpb.GetBooksByAuthorRequest{
 		AuthorId: req.AuthorId,
 	})
 
 	if err != nil {
-		log.Printf("⚠️ Failed to get books from Book service: %v", err)
-		// Continue even if book service fails (graceful degradation)
+		requestid.Printf(ctx, "⚠️ Failed to get books from Book service: %v", err)
+		// Continue even if book service fails (graceful degradation), but
+		// say so, since no books and unknown books look the same otherwise
 		return &authorpb.GetAuthorBooksResponse{
-			Author:    &author,
-			Books:     nil,
-			BookCount: 0,
+			Author:            &author,
+			Books:             nil,
+			BookCount:         0,
+			BookServiceStatus: bookServiceStatus(err),
 		}, nil
 	}
 
@@ -183,7 +443,7 @@ func (s *authorCatalogServer) GetAuthorBooks(ctx context.Context, req *authorpb.
 		})
 	}
 
-	log.Printf("✅ Successfully retrieved %d books for author %s", len(bookSummaries), author.Name)
+	requestid.Printf(ctx, "✅ Successfully retrieved %d books for author %s", len(bookSummaries), author.Name)
 
 	return &authorpb.GetAuthorBooksResponse{
 		Author:    &author,
@@ -192,11 +452,109 @@ func (s *authorCatalogServer) GetAuthorBooks(ctx context.Context, req *authorpb.
 	}, nil
 }
 
+// bookServiceStatus tells a client why a call to Book service failed.
+func bookServiceStatus(err error) authorpb.GetAuthorBooksResponse_BookServiceStatus {
+	if errors.Is(err, breaker.ErrOpen) {
+		return authorpb.GetAuthorBooksResponse_CIRCUIT_OPEN
+	}
+	return authorpb.GetAuthorBooksResponse_UNAVAILABLE
+}
+
+// statsConcurrency is how many GetBooksByAuthor calls AuthorStats has in
+// flight at once, so a large page does not flood Book service.
+const statsConcurrency = 4
+
+// AuthorStats reads a page of authors locally, then asks Book service for
+// each author's books, statsConcurrency calls at a time. As in
+// GetAuthorBooks, an author whose call fails is still returned, with zero
+// stats and a book_service_status saying why.
+func (s *authorCatalogServer) AuthorStats(ctx context.Context, req *authorpb.AuthorStatsRequest) (*authorpb.AuthorStatsResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, name, bio, birth_year, country, created_at, updated_at FROM authors ORDER BY id LIMIT ? OFFSET ?",
+		req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query authors: %v", err)
+	}
+	defer rows.Close()
+
+	var authors []*authorpb.Author
+	for rows.Next() {
+		var author authorpb.Author
+		if err := scanAuthor(rows, &author); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan author: %v", err)
+		}
+		authors = append(authors, &author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read authors: %v", err)
+	}
+
+	var total int32
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM authors").Scan(&total); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count authors: %v", err)
+	}
+
+	// Each goroutine fills its own slot, so stats keep the authors' order.
+	stats := make([]*authorpb.AuthorStat, len(authors))
+	sem := make(chan struct{}, statsConcurrency)
+	var wg sync.WaitGroup
+	for i, author := range authors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			stats[i] = s.authorStat(ctx, author)
+		}()
+	}
+	wg.Wait()
+
+	requestid.Printf(ctx, "📊 Aggregated stats for %d authors from Book service", len(stats))
+	return &authorpb.AuthorStatsResponse{Stats: stats, Total: total}, nil
+}
+
+// authorStat sums up the books Book service has for author.
+func (s *authorCatalogServer) authorStat(ctx context.Context, author *authorpb.Author) *authorpb.AuthorStat {
+	stat := &authorpb.AuthorStat{Author: author}
+	bookResp, err := s.bookClient.GetBooksByAuthor(ctx, &bookpb.GetBooksByAuthorRequest{
+		AuthorId: author.Id,
+	})
+	if err != nil {
+		requestid.Printf(ctx, "⚠️ Failed to get books of author_id=%d from Book service: %v", author.Id, err)
+		stat.BookServiceStatus = bookServiceStatus(err)
+		return stat
+	}
+
+	inventory := bookpb.Totals{}
+	for _, book := range bookResp.Books {
+		stat.BookCount++
+		inventory.Add(book.Price.Times(int64(book.Stock)))
+		stat.NewestYear = max(stat.NewestYear, book.PublishedYear)
+	}
+	stat.InventoryValues = inventory.Sorted()
+	return stat
+}
+
 func connectToBookService() (bookpb.BookCatalogClient, error) {
-	log.Println("🔗 Connecting to Book service on localhost:50051...")
+	target := endpoints.Target("books", *bookAddr)
+	log.Printf("🔗 Connecting to Book service on %s...", target)
 
-	conn, err := grpc.Dial("127.0.0.1:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := tlsconfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+	}
+	cb := breaker.New("bookservice", *breakerThreshold, *breakerCooldown)
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds), keepaliveconfig.DialOption(), auth.DialOption(),
+		retry.FailFastDialOption(endpoints.ServiceConfig(bookpb.BookCatalog_ServiceDesc.ServiceName)),
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor(), cb.UnaryClientInterceptor()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Book service: %w", err)
 	}
@@ -206,7 +564,7 @@ func connectToBookService() (bookpb.BookCatalogClient, error) {
 }
 
 func initDB() (*sql.DB, error) {
-	db, err := sql.Open("sqlite", "./authors.db")
+	db, err := sqlitedb.Open("./authors.db")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -218,7 +576,9 @@ func initDB() (*sql.DB, error) {
 		name TEXT NOT NULL,
 		bio TEXT,
 		birth_year INTEGER,
-		country TEXT
+		country TEXT,
+		created_at INTEGER NOT NULL DEFAULT 0,
+		updated_at INTEGER NOT NULL DEFAULT 0
 	);`
 
 	_, err = db.Exec(createTableSQL)
@@ -226,6 +586,23 @@ func initDB() (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// CreateAuthor and UpdateAuthor set the times; authors stored before the
+	// columns existed keep 0 (unset).
+	var hasTimes bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('authors') WHERE name = 'created_at')").Scan(&hasTimes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect authors table: %w", err)
+	}
+	if !hasTimes {
+		_, err := db.Exec(`
+		ALTER TABLE authors ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE authors ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add time columns: %w", err)
+		}
+		log.Println("Added created_at and updated_at columns to authors")
+	}
+
 	// Seed sample authors
 	var count int
 	err = db.QueryRow("SELECT COUNT(*) FROM authors").Scan(&count)
@@ -247,10 +624,11 @@ func initDB() (*sql.DB, error) {
 			{"Hunt & Thomas", "Authors of The Pragmatic Programmer", "USA", 1965},
 		}
 
+		now := time.Now().Unix()
 		for _, author := range sampleAuthors {
 			_, err := db.Exec(
-				"INSERT INTO authors (name, bio, birth_year, country) VALUES (?, ?, ?, ?)",
-				author.name, author.bio, author.birthYear, author.country)
+				"INSERT INTO authors (name, bio, birth_year, country, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+				author.name, author.bio, author.birthYear, author.country, now, now)
 			if err != nil {
 				return nil, fmt.Errorf("failed to seed data: %w", err)
 			}
@@ -263,6 +641,8 @@ func initDB() (*sql.DB, error) {
 }
 
 func main() {
+	flag.Parse()
+
 	// Step 1: Initialize database
 	db, err := initDB()
 	if err != nil {
@@ -283,13 +663,31 @@ func main() {
 	}
 
 	// Step 4: Create gRPC server
-	grpcServer := grpc.NewServer()
+	creds, err := tlsconfig.ServerCredentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS credentials: %v", err)
+	}
+	authn, err := auth.New(
+		authorpb.AuthorCatalog_CreateAuthor_FullMethodName,
+		authorpb.AuthorCatalog_CreateAuthorWithBooks_FullMethodName,
+		authorpb.AuthorCatalog_UpdateAuthor_FullMethodName,
+		authorpb.AuthorCatalog_DeleteAuthor_FullMethodName,
+	)
+	if err != nil {
+		log.Fatalf("Failed to configure auth: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), keepaliveconfig.ServerParams(), keepaliveconfig.EnforcementPolicy(),
+		grpc.ChainUnaryInterceptor(interceptors.UnaryMetrics(), requestid.UnaryServerInterceptor(), authn.UnaryInterceptor(), interceptors.UnaryLogging(), interceptors.UnaryRecovery(), interceptors.UnaryValidation()),
+		grpc.ChainStreamInterceptor(interceptors.StreamMetrics(), requestid.StreamServerInterceptor(), authn.StreamInterceptor(), interceptors.StreamLogging(), interceptors.StreamRecovery(), interceptors.StreamValidation()))
+	interceptors.ServeMetrics()
 
 	// Step 5: Register service with book client for cross-service calls
 	authorpb.RegisterAuthorCatalogServer(grpcServer, newServer(db, bookClient))
+	reflectionconfig.Register(grpcServer)
+	healthcheck.Register(grpcServer, db, authorpb.AuthorCatalog_ServiceDesc.ServiceName)
 
 	log.Println("🚀 Author Catalog gRPC server listening on :50052")
-	log.Println("📚 Connected to Book Catalog service on :50051")
+	log.Println("📚 Connected to Book Catalog service")
 	log.Println("✨ Service-to-service communication enabled!")
 
 	// Step 6: Start serving
@@ -1,145 +1,602 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
-
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"book-catalog-grpc/auth"
+	"book-catalog-grpc/cache"
+	"book-catalog-grpc/compression"
+	"book-catalog-grpc/endpoints"
+	"book-catalog-grpc/events"
+	"book-catalog-grpc/fuzzy"
+	"book-catalog-grpc/healthcheck"
+	"book-catalog-grpc/interceptors"
+	"book-catalog-grpc/keepaliveconfig"
+	authorpb "book-catalog-grpc/proto"
 	pb "book-catalog-grpc/proto"
-
+	pbv2 "book-catalog-grpc/proto/bookcatalog/v2"
+	"book-catalog-grpc/reflectionconfig"
+	"book-catalog-grpc/requestid"
+	"book-catalog-grpc/retry"
+	"book-catalog-grpc/sqlitedb"
+	"book-catalog-grpc/tlsconfig"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	_ "modernc.org/sqlite"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// listenAddr lets several replicas run side by side, e.g. -addr :50061, for
+// clients that balance over them.
+var listenAddr = flag.String("addr", "0.0.0.0:50051", "address to listen on")
+
+// maxDeadline bounds how long any unary RPC may run on this server.
+var maxDeadline = flag.Duration("max-deadline", 10*time.Second, "longest a unary RPC may run; sooner client deadlines still apply")
+
+// natsURL selects the NATS broker for lifecycle events; empty keeps them in
+// this process.
+var natsURL = flag.String("nats-url", os.Getenv("NATS_URL"), "NATS server for lifecycle events, e.g. nats://localhost:4222 (needs -tags nats)")
+
+// With validateAuthors (-validate-authors, VALIDATE_AUTHORS=true),
+// CreateBook and UpdateBook ask Author service, at authorAddr, whether the
+// author_id they write exists. With authorDetails (-author-details,
+// AUTHOR_DETAILS=true), GetBook adds the author's name and country, which
+// are cached for -cache-ttl. authorAddr takes the same forms as the
+// clients' address flags; left empty, package endpoints finds the service.
+var (
+	validateAuthors = flag.Bool("validate-authors", os.Getenv("VALIDATE_AUTHORS") == "true", "check author_id against Author service on CreateBook and UpdateBook")
+	authorDetails   = flag.Bool("author-details", os.Getenv("AUTHOR_DETAILS") == "true", "add the author's name and country from Author service to GetBook")
+	authorAddr      = flag.String("author-addr", os.Getenv("AUTHOR_ADDR"), "Author service address(es) for -validate-authors (default: endpoints file or 127.0.0.1:50052)")
 )
 
 type bookCatalogServer struct {
 	pb.UnimplementedBookCatalogServer
-	db *sql.DB
+	db     *sql.DB
+	bus    *eventBus
+	events events.Broker
+	// authorClient reaches Author service; nil unless -validate-authors or
+	// -author-details. checkAuthors is -validate-authors.
+	authorClient authorpb.AuthorCatalogClient
+	checkAuthors bool
+	// authors caches Author service's answers for GetBook, nil for an
+	// author_id it does not have; nil unless -author-details. Author service
+	// cannot invalidate it, so the TTL bounds how stale it gets.
+	authors *cache.LRU[int32, *authorpb.Author]
+	// books caches GetBook by id. Cached books are shared between
+	// responses and must not be modified.
+	books *cache.LRU[int32, *pb.Book]
+
+	// The hottest queries, prepared once at startup.
+	getBookStmt    *sql.Stmt
+	listBooksStmt  *sql.Stmt // ListBooks in the default order
+	createBookStmt *sql.Stmt
+	searchStmt     *sql.Stmt // nil when the books_fts index is unavailable
+}
+
+const (
+	getBookQuery    = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE id = ? AND deleted = 0"
+	listBooksQuery  = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 ORDER BY id LIMIT ? OFFSET ?"
+	createBookQuery = "INSERT INTO books (title, author, isbn, price, currency, stock, published_year, author_id, category) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	searchQuery     = `SELECT b.id, b.title, b.author, b.isbn, b.price, b.currency, b.stock, b.published_year, b.author_id, b.category, b.created_at, b.updated_at, b.version,
+		-bm25(books_fts), snippet(books_fts, -1, '[', ']', '…', 10)
+		FROM books_fts JOIN books b ON b.id = books_fts.rowid
+		WHERE books_fts MATCH ? AND b.deleted = 0 ORDER BY bm25(books_fts), b.id`
+)
+
+func newBookCatalogServer(db *sql.DB, broker events.Broker, authorClient authorpb.AuthorCatalogClient) (*bookCatalogServer, error) {
+	s := &bookCatalogServer{
+		db:           db,
+		bus:          newEventBus(),
+		events:       broker,
+		authorClient: authorClient,
+		checkAuthors: authorClient != nil && *validateAuthors,
+		books:        cache.New[int32, *pb.Book]("books"),
+	}
+	if authorClient != nil && *authorDetails {
+		s.authors = cache.New[int32, *authorpb.Author]("authors")
+	}
+	var err error
+	if s.getBookStmt, err = db.Prepare(getBookQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare GetBook: %w", err)
+	}
+	if s.listBooksStmt, err = db.Prepare(listBooksQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare ListBooks: %w", err)
+	}
+	if s.createBookStmt, err = db.Prepare(createBookQuery); err != nil {
+		return nil, fmt.Errorf("failed to prepare CreateBook: %w", err)
+	}
+	if s.searchStmt, err = db.Prepare(searchQuery); err != nil {
+		log.Printf("⚠️ Full-text search unavailable, SearchBooks falls back to LIKE: %v", err)
+		s.searchStmt = nil
+	}
+	return s, nil
+}
+
+// scanBook scans the columns "id, title, author, isbn, price, currency,
+// stock, published_year, author_id, category, created_at, updated_at,
+// version", which every query returning books selects first, and then
+// extra. price holds nanos of currency; the times are Unix seconds; version
+// becomes the etag.
+func scanBook(row interface{ Scan(...any) error }, book *pb.Book, extra ...any) error {
+	var nanos, created, updated, version int64
+	var currency string
+	dest := []any{&book.Id, &book.Title, &book.Author, &book.Isbn, &nanos, &currency, &book.Stock, &book.PublishedYear, &book.AuthorId, &book.Category, &created, &updated, &version}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	book.Price = pb.MoneyFromNanos(currency, nanos)
+	book.CreatedAt, book.UpdatedAt = pb.UnixTime(created), pb.UnixTime(updated)
+	book.Etag = pb.ETag(version)
+	return nil
+}
+
+// subscriberBuffer is how many unread lifecycle events a SubscribeEvents
+// stream may fall behind before events for it are dropped.
+const subscriberBuffer = 64
+
+// emit publishes a lifecycle event. The write it reports has already been
+// committed, so a failure is only logged.
+func (s *bookCatalogServer) emit(typ pb.LifecycleEvent_Type, id int32, book *pb.Book, oldStock int32) {
+	ev := &pb.LifecycleEvent{Type: typ, BookId: id, Book: book, OldStock: oldStock, Timestamp: time.Now().Unix()}
+	if err := s.events.Publish(ev); err != nil {
+		log.Printf("⚠️ Failed to publish %s for book %d: %v", typ, id, err)
+	}
+}
+
+// eventBus fans book changes out to the open WatchBooks streams.
+type eventBus struct {
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+}
+
+// watcher is one WatchBooks stream. events is buffered so a slow client
+// cannot hold up UpdateBook; when it is full, events for that client are
+// dropped.
+type watcher struct {
+	mu     sync.Mutex
+	ids    map[int32]bool
+	events chan *pb.BookEvent
+}
+
+const watchBuffer = 64
+
+func newEventBus() *eventBus {
+	return &eventBus{watchers: make(map[*watcher]struct{})}
+}
+
+func (b *eventBus) subscribe() *watcher {
+	w := &watcher{ids: make(map[int32]bool), events: make(chan *pb.BookEvent, watchBuffer)}
+	b.mu.Lock()
+	b.watchers[w] = struct{}{}
+	b.mu.Unlock()
+	return w
+}
+
+func (b *eventBus) unsubscribe(w *watcher) {
+	b.mu.Lock()
+	delete(b.watchers, w)
+	b.mu.Unlock()
+}
+
+func (b *eventBus) publish(ev *pb.BookEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for w := range b.watchers {
+		if w.follows(ev.BookId) {
+			w.push(ev)
+		}
+	}
+}
+
+func (w *watcher) follows(id int32) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ids[id]
+}
+
+func (w *watcher) push(ev *pb.BookEvent) {
+	select {
+	case w.events <- ev:
+	default:
+		log.Printf("WatchBooks: buffer full, dropped %s event for book %d", ev.Type, ev.BookId)
+	}
+}
+
+// publishChange emits one event per changed field between before and after.
+func (b *eventBus) publishChange(before, after *pb.Book) {
+	now := time.Now().Unix()
+	if !proto.Equal(before.Price, after.Price) {
+		b.publish(&pb.BookEvent{Type: pb.BookEvent_PRICE_CHANGED, BookId: after.Id, Book: after,
+			OldPrice: before.Price, OldStock: before.Stock, Timestamp: now})
+	}
+	if before.Stock != after.Stock {
+		b.publish(&pb.BookEvent{Type: pb.BookEvent_STOCK_CHANGED, BookId: after.Id, Book: after,
+			OldPrice: before.Price, OldStock: before.Stock, Timestamp: now})
+	}
+}
+
+// alive returns DeadlineExceeded or Canceled once ctx is done, so handlers
+// that run several queries stop working for a client that has gone.
+func alive(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// errorDomain is the google.rpc.ErrorInfo domain of book-service errors.
+const errorDomain = "book-service.book-catalog-grpc"
+
+// busyRetryDelay is how long a client should wait after DATABASE_BUSY:
+// about as long as a writer holds the lock.
+const busyRetryDelay = time.Second
+
+// dbError reports a failed database call as Internal, unless it failed
+// because ctx ended, which is reported as DeadlineExceeded or Canceled. An
+// ErrorInfo names the failed operation. A database busy past busy_timeout
+// is transient, so it is reported as Unavailable with a RetryInfo instead,
+// which the clients' retry policy retries.
+func dbError(ctx context.Context, msg string, err error) error {
+	if err := alive(ctx); err != nil {
+		return err
+	}
+	info := &errdetails.ErrorInfo{Reason: "DATABASE_ERROR", Domain: errorDomain, Metadata: map[string]string{"operation": msg}}
+	if sqlitedb.IsBusy(err) {
+		info.Reason = "DATABASE_BUSY"
+		return withDetails(status.Newf(codes.Unavailable, "%s: database busy", msg),
+			info, &errdetails.RetryInfo{RetryDelay: durationpb.New(busyRetryDelay)})
+	}
+	return withDetails(status.Newf(codes.Internal, "%s: %v", msg, err), info)
+}
+
+// withDetails attaches details to st, or leaves them out if they cannot be
+// marshalled, since the status itself is what matters.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
 }
 
 func (s *bookCatalogServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.GetBookResponse, error) {
-	log.Printf("GetBook: id=%d", req.Id)
+	book, err := s.books.Get(req.Id, func() (*pb.Book, error) {
+		var book pb.Book
+		err := scanBook(s.getBookStmt.QueryRowContext(ctx, req.Id), &book)
 
-	var book pb.Book
-	err := s.db.QueryRowContext(ctx,
-		"SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE id = ?",
-		req.Id).Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear, &book.AuthorId)
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
+		}
+		if err != nil {
+			return nil, dbError(ctx, "database error", err)
+		}
+		return &book, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
+	resp := &pb.GetBookResponse{Book: book}
+	// Ratings change with every review, so they are never cached.
+	if req.IncludeRating {
+		if resp.Rating, err = bookRating(ctx, s.db, req.Id); err != nil {
+			return nil, err
+		}
+	}
+	if author := s.author(ctx, book.AuthorId); author != nil {
+		resp.AuthorName, resp.AuthorCountry = author.Name, author.Country
+	}
+	return resp, nil
+}
+
+// author returns author id as Author service has it, through the authors
+// cache, or nil if there is no such author. Without -author-details, or when Author
+// service fails, it returns nil too: the book is still worth returning.
+func (s *bookCatalogServer) author(ctx context.Context, id int32) *authorpb.Author {
+	if s.authors == nil || id == 0 {
+		return nil
 	}
+	author, err := s.authors.Get(id, func() (*authorpb.Author, error) {
+		resp, err := s.authorClient.GetAuthor(ctx, &authorpb.GetAuthorRequest{Id: id})
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.Author, nil
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "database error: %v", err)
+		requestid.Printf(ctx, "⚠️ Failed to get author_id=%d from Author service: %v", id, err)
+		return nil
 	}
+	return author
+}
 
-	return &pb.GetBookResponse{Book: &book}, nil
+// checkAuthor returns FailedPrecondition, with a PreconditionFailure naming
+// the author, if Author service has no author id. It passes id 0, which
+// means the book has no author, and everything when -validate-authors is
+// off. It runs before the write transaction begins, so the database is not
+// locked while Author service answers; an author deleted in between goes
+// unnoticed, as with any check across two services.
+func (s *bookCatalogServer) checkAuthor(ctx context.Context, id int32) error {
+	if !s.checkAuthors || id == 0 {
+		return nil
+	}
+	_, err := s.authorClient.GetAuthor(ctx, &authorpb.GetAuthorRequest{Id: id})
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return withDetails(status.Newf(codes.FailedPrecondition, "author_id %d does not exist in Author service", id),
+			&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        "AUTHOR_EXISTS",
+				Subject:     fmt.Sprintf("authors/%d", id),
+				Description: fmt.Sprintf("author %d not found", id),
+			}}})
+	}
+	if err := alive(ctx); err != nil {
+		return err
+	}
+	requestid.Printf(ctx, "⚠️ Failed to check author_id=%d with Author service: %v", id, err)
+	return withDetails(status.Newf(codes.Unavailable, "cannot check author_id %d: Author service: %s", id, status.Convert(err).Message()),
+		&errdetails.ErrorInfo{Reason: "AUTHOR_SERVICE_UNAVAILABLE", Domain: errorDomain})
 }
 
+// requestIDTTL is how long CreateBook remembers a request_id. A client
+// retrying later than this creates a new book.
+const requestIDTTL = 24 * time.Hour
+
+// CreateBook creates a book, or, for a request_id it has seen, returns the
+// book created the first time. The check and the insert share a
+// transaction, which takes the write lock up front, so two concurrent calls
+// with the same request_id cannot both insert.
 func (s *bookCatalogServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.CreateBookResponse, error) {
-	log.Printf("CreateBook: title=%s, author=%s, author_id=%d", req.Title, req.Author, req.AuthorId)
+	if err := s.checkAuthor(ctx, req.AuthorId); err != nil {
+		return nil, err
+	}
 
-	if req.Title == "" || req.Author == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and author are required")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(ctx, "failed to begin transaction", err)
 	}
-	if req.Price < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+	defer tx.Rollback()
+
+	if req.RequestId != "" {
+		var bookID int32
+		err := tx.QueryRowContext(ctx, "SELECT book_id FROM create_requests WHERE request_id = ? AND created_at >= ?",
+			req.RequestId, time.Now().Add(-requestIDTTL).Unix()).Scan(&bookID)
+		if err == nil {
+			return s.createdBefore(ctx, tx, req.RequestId, bookID)
+		}
+		if err != sql.ErrNoRows {
+			return nil, dbError(ctx, "failed to look up request_id", err)
+		}
 	}
 
-	result, err := s.db.ExecContext(ctx,
-		"INSERT INTO books (title, author, isbn, price, stock, published_year, author_id) VALUES (?, ?, ?, ?, ?, ?, ?)",
-		req.Title, req.Author, req.Isbn, req.Price, req.Stock, req.PublishedYear, req.AuthorId)
+	result, err := tx.StmtContext(ctx, s.createBookStmt).ExecContext(ctx,
+		req.Title, req.Author, req.Isbn, req.Price.TotalNanos(), req.Price.GetCurrencyCode(), req.Stock, req.PublishedYear, req.AuthorId, req.Category)
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to insert book: %v", err)
+		return nil, dbError(ctx, "failed to insert book", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get insert id: %v", err)
+		return nil, dbError(ctx, "failed to get insert id", err)
 	}
 
-	book := &pb.Book{
-		Id:            int32(id),
-		Title:         req.Title,
-		Author:        req.Author,
-		Isbn:          req.Isbn,
-		Price:         req.Price,
-		Stock:         req.Stock,
-		PublishedYear: req.PublishedYear,
-		AuthorId:      req.AuthorId,
+	if req.RequestId != "" {
+		// Expired ids are dropped here, so the table only holds those of the
+		// last requestIDTTL.
+		now := time.Now()
+		if _, err := tx.ExecContext(ctx, "DELETE FROM create_requests WHERE created_at < ?", now.Add(-requestIDTTL).Unix()); err != nil {
+			return nil, dbError(ctx, "failed to expire request ids", err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO create_requests (request_id, book_id, created_at) VALUES (?, ?, ?)",
+			req.RequestId, id, now.Unix())
+		if err != nil {
+			return nil, dbError(ctx, "failed to record request_id", err)
+		}
+	}
+	// Read the book back for the times the books_created trigger set.
+	var book pb.Book
+	if err := scanBook(tx.StmtContext(ctx, s.getBookStmt).QueryRowContext(ctx, id), &book); err != nil {
+		return nil, dbError(ctx, "failed to read created book", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit book", err)
+	}
+	s.emit(pb.LifecycleEvent_BOOK_CREATED, book.Id, &book, 0)
 
-	return &pb.CreateBookResponse{Book: book}, nil
+	return &pb.CreateBookResponse{Book: &book}, nil
 }
 
-func (s *bookCatalogServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.UpdateBookResponse, error) {
-	log.Printf("UpdateBook: id=%d, title=%s", req.Id, req.Title)
-
-	if req.Title == "" || req.Author == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and author are required")
+// createdBefore answers a repeated CreateBook with the book the first call
+// created, as it is now. Nothing is written, so no event is emitted.
+func (s *bookCatalogServer) createdBefore(ctx context.Context, tx *sql.Tx, requestID string, id int32) (*pb.CreateBookResponse, error) {
+	var book pb.Book
+	err := scanBook(tx.StmtContext(ctx, s.getBookStmt).QueryRowContext(ctx, id), &book)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "book %d created by request_id %q has since been deleted", id, requestID)
 	}
-	if req.Price < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+	if err != nil {
+		return nil, dbError(ctx, "database error", err)
 	}
+	requestid.Printf(ctx, "CreateBook: request_id %q already created book %d", requestID, id)
+	return &pb.CreateBookResponse{Book: &book}, nil
+}
 
-	result, err := s.db.ExecContext(ctx,
-		"UPDATE books SET title=?, author=?, isbn=?, price=?, stock=?, published_year=?, author_id=? WHERE id=?",
-		req.Title, req.Author, req.Isbn, req.Price, req.Stock, req.PublishedYear, req.AuthorId, req.Id)
+// UpdateBook applies the update only if the book is still at the version
+// req.Etag names: the etag is compared and the version bumped in the same
+// transaction, so of two clients updating from the same read, the second
+// gets Aborted instead of overwriting the first.
+func (s *bookCatalogServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.UpdateBookResponse, error) {
+	if req.Etag == "" {
+		return nil, withDetails(status.New(codes.InvalidArgument, "etag is required: send the etag of the book as last read"),
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       "etag",
+				Description: "is required",
+			}}})
+	}
 
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to update book: %v", err)
+	// Without an update_mask every field, author_id included, is written.
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 || slices.Contains(paths, "author_id") {
+		if err := s.checkAuthor(ctx, req.AuthorId); err != nil {
+			return nil, err
+		}
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// Read the old price and stock in the same transaction as the update so
+	// watchers see exactly what changed.
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get rows affected: %v", err)
+		return nil, dbError(ctx, "failed to begin transaction", err)
 	}
-	if rowsAffected == 0 {
+	defer tx.Rollback()
+
+	before := &pb.Book{Id: req.Id}
+	var nanos, version int64
+	var currency string
+	err = tx.QueryRowContext(ctx, "SELECT price, currency, stock, version FROM books WHERE id = ? AND deleted = 0", req.Id).Scan(&nanos, &currency, &before.Stock, &version)
+	if err == sql.ErrNoRows {
 		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
 	}
+	if err != nil {
+		return nil, dbError(ctx, "database error", err)
+	}
+	if etag := pb.ETag(version); req.Etag != etag {
+		requestid.Printf(ctx, "UpdateBook: book %d is at etag %q, client sent %q", req.Id, etag, req.Etag)
+		return nil, withDetails(status.Newf(codes.Aborted, "book %d has changed since etag %q was read; read it again and retry", req.Id, req.Etag),
+			&errdetails.ErrorInfo{Reason: "ETAG_MISMATCH", Domain: errorDomain})
+	}
+	before.Price = pb.MoneyFromNanos(currency, nanos)
+
+	// With an update_mask only the named columns are written, so a client can
+	// change the price without resending (or wiping) everything else.
+	cols, args := maskedColumns(req)
+	if cols == nil {
+		cols = []string{"title", "author", "isbn", "price", "currency", "stock", "published_year", "author_id", "category"}
+		args = []any{req.Title, req.Author, req.Isbn, req.Price.TotalNanos(), req.Price.GetCurrencyCode(), req.Stock, req.PublishedYear, req.AuthorId, req.Category}
+	}
+	query := "UPDATE books SET " + strings.Join(cols, " = ?, ") + " = ?, version = version + 1 WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, append(args, req.Id)...); err != nil {
+		return nil, dbError(ctx, "failed to update book", err)
+	}
 
-	book := &pb.Book{
-		Id:            req.Id,
-		Title:         req.Title,
-		Author:        req.Author,
-		Isbn:          req.Isbn,
-		Price:         req.Price,
-		Stock:         req.Stock,
-		PublishedYear: req.PublishedYear,
-		AuthorId:      req.AuthorId,
+	var book pb.Book
+	err = scanBook(tx.QueryRowContext(ctx,
+		"SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE id = ?",
+		req.Id), &book)
+	if err != nil {
+		return nil, dbError(ctx, "failed to read updated book", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit update", err)
+	}
+	s.books.Invalidate(req.Id)
+	s.bus.publishChange(before, &book)
+	s.emit(pb.LifecycleEvent_BOOK_UPDATED, book.Id, &book, 0)
+	if before.Stock != book.Stock {
+		s.emit(pb.LifecycleEvent_STOCK_CHANGED, book.Id, &book, before.Stock)
 	}
 
-	return &pb.UpdateBookResponse{Book: book}, nil
+	return &pb.UpdateBookResponse{Book: &book}, nil
 }
 
+// DeleteBook marks the book deleted, or with hard removes its row, which
+// also purges a book already marked. Either way it is gone for watchers and
+// subscribers.
 func (s *bookCatalogServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.DeleteBookResponse, error) {
-	log.Printf("DeleteBook: id=%d", req.Id)
-
-	result, err := s.db.ExecContext(ctx, "DELETE FROM books WHERE id = ?", req.Id)
+	query, done := "UPDATE books SET deleted = 1 WHERE id = ? AND deleted = 0", "deleted"
+	if req.Hard {
+		query, done = "DELETE FROM books WHERE id = ?", "permanently deleted"
+	}
+	result, err := s.db.ExecContext(ctx, query, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to delete book: %v", err)
+		return nil, dbError(ctx, "failed to delete book", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get rows affected: %v", err)
+		return nil, dbError(ctx, "failed to get rows affected", err)
 	}
 	if rowsAffected == 0 {
 		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
 	}
+	s.books.Invalidate(req.Id)
+	s.bus.publish(&pb.BookEvent{Type: pb.BookEvent_DELETED, BookId: req.Id, Timestamp: time.Now().Unix()})
+	s.emit(pb.LifecycleEvent_BOOK_DELETED, req.Id, nil, 0)
 
 	return &pb.DeleteBookResponse{
 		Success: true,
-		Message: fmt.Sprintf("Book with id %d deleted successfully", req.Id),
+		Message: fmt.Sprintf("Book with id %d %s successfully", req.Id, done),
 	}, nil
 }
 
-func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
-	log.Printf("ListBooks: page=%d, page_size=%d", req.Page, req.PageSize)
+// UndeleteBook clears the deleted mark and reads the book back in one
+// transaction. Watchers of the book get it as a SNAPSHOT, as if they had
+// just subscribed.
+func (s *bookCatalogServer) UndeleteBook(ctx context.Context, req *pb.UndeleteBookRequest) (*pb.UndeleteBookResponse, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(ctx, "failed to begin transaction", err)
+	}
+	defer tx.Rollback()
 
+	result, err := tx.ExecContext(ctx, "UPDATE books SET deleted = 0 WHERE id = ? AND deleted = 1", req.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to undelete book", err)
+	}
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return nil, dbError(ctx, "failed to get rows affected", err)
+	}
+	var book pb.Book
+	err = scanBook(tx.StmtContext(ctx, s.getBookStmt).QueryRowContext(ctx, req.Id), &book)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.Id)
+	}
+	if err != nil {
+		return nil, dbError(ctx, "database error", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit undelete", err)
+	}
+	if restored == 0 {
+		// Not deleted to begin with: nothing changed, so nothing to announce.
+		return &pb.UndeleteBookResponse{Book: &book}, nil
+	}
+	s.books.Invalidate(req.Id)
+	s.bus.publish(&pb.BookEvent{Type: pb.BookEvent_SNAPSHOT, BookId: req.Id, Book: &book, Timestamp: time.Now().Unix()})
+	s.emit(pb.LifecycleEvent_BOOK_UNDELETED, book.Id, &book, 0)
+
+	return &pb.UndeleteBookResponse{Book: &book}, nil
+}
+
+func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -147,44 +604,572 @@ func (s *bookCatalogServer) ListBooks(ctx context.Context, req *pb.ListBooksRequ
 		req.PageSize = 10
 	}
 
-	offset := (req.Page - 1) * req.PageSize
+	books, total, err := s.listBooks(ctx, req, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books LIMIT ? OFFSET ?",
-		req.PageSize, offset)
+	resp := &pb.ListBooksResponse{
+		Books:    books,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	return resp.SetPageInfo(), nil
+}
+
+// listBooks returns req.PageSize books matching req.Filter in req's order
+// starting after offset, and how many books match in all. Both API versions
+// page through it.
+func (s *bookCatalogServer) listBooks(ctx context.Context, req *pb.ListBooksRequest, offset int32) ([]*pb.Book, int32, error) {
+	var rows *sql.Rows
+	var err error
+	where, args := listWhere(req)
+	query := "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books" + where + " " + orderByClause(req) + " LIMIT ? OFFSET ?"
+	if query == listBooksQuery {
+		rows, err = s.listBooksStmt.QueryContext(ctx, req.PageSize, offset)
+	} else {
+		rows, err = s.db.QueryContext(ctx, query, append(args, req.PageSize, offset)...)
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to query books: %v", err)
+		return nil, 0, dbError(ctx, "failed to query books", err)
 	}
 	defer rows.Close()
 
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		if err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear, &book.AuthorId); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
+		if err := scanBook(rows, &book); err != nil {
+			return nil, 0, dbError(ctx, "failed to scan book", err)
 		}
 		books = append(books, &book)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, dbError(ctx, "failed to read books", err)
+	}
 
 	var total int32
-	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books").Scan(&total)
+	if err := alive(ctx); err != nil {
+		return nil, 0, err
+	}
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books"+where, args...).Scan(&total)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to count books: %v", err)
+		return nil, 0, dbError(ctx, "failed to count books", err)
 	}
+	return books, total, nil
+}
 
-	return &pb.ListBooksResponse{
-		Books:    books,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
-	}, nil
+// StreamBooks sends each book as soon as its row is scanned, so neither side
+// holds the whole catalog. Send blocks while the client's flow-control
+// window is full, which paces the scan to the reader.
+func (s *bookCatalogServer) StreamBooks(req *pb.ListBooksRequest, stream pb.BookCatalog_StreamBooksServer) error {
+	ctx := stream.Context()
+	where, args := listWhere(req)
+	query := "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books" + where + " " + orderByClause(req)
+	if req.PageSize > 0 {
+		if req.Page < 1 {
+			req.Page = 1
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return dbError(ctx, "failed to query books", err)
+	}
+	defer rows.Close()
+
+	sent := 0
+	for rows.Next() {
+		var book pb.Book
+		if err := scanBook(rows, &book); err != nil {
+			return dbError(ctx, "failed to scan book", err)
+		}
+		// Fails once the client cancels or disconnects.
+		if err := stream.Send(&book); err != nil {
+			return err
+		}
+		sent++
+	}
+	if err := rows.Err(); err != nil {
+		return dbError(ctx, "rows error", err)
+	}
+
+	requestid.Printf(ctx, "StreamBooks: sent %d books", sent)
+	return nil
 }
 
-func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooksRequest) (*pb.SearchBooksResponse, error) {
-	log.Printf("SearchBooks: query=%s, field=%s", req.Query, req.Field)
+// WatchBooks reads subscribe/unsubscribe requests on one goroutine and sends
+// events on this one, since a stream's Send must not be called concurrently.
+// The watch ends when the client closes its side or cancels.
+func (s *bookCatalogServer) WatchBooks(stream pb.BookCatalog_WatchBooksServer) error {
+	w := s.bus.subscribe()
+	defer s.bus.unsubscribe(w)
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			requestid.Printf(stream.Context(), "WatchBooks: %s %v", req.Action, req.BookIds)
+			for _, id := range req.BookIds {
+				w.mu.Lock()
+				if req.Action == pb.WatchRequest_UNSUBSCRIBE {
+					delete(w.ids, id)
+				} else {
+					w.ids[id] = true
+				}
+				w.mu.Unlock()
+				if req.Action == pb.WatchRequest_SUBSCRIBE {
+					s.sendSnapshot(stream.Context(), w, id)
+				}
+			}
+		}
+	}()
 
-	if req.Query == "" {
-		return nil, status.Error(codes.InvalidArgument, "search query is required")
+	for {
+		select {
+		case ev := <-w.events:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// Flush what was already queued before ending the stream.
+			for {
+				select {
+				case ev := <-w.events:
+					if err := stream.Send(ev); err != nil {
+						return err
+					}
+				default:
+					requestid.Printf(stream.Context(), "WatchBooks: watcher closed")
+					return nil
+				}
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// sendSnapshot queues the current state of a book for a new subscriber, or
+// a DELETED event if it does not exist.
+func (s *bookCatalogServer) sendSnapshot(ctx context.Context, w *watcher, id int32) {
+	ev := &pb.BookEvent{Type: pb.BookEvent_SNAPSHOT, BookId: id, Timestamp: time.Now().Unix()}
+	resp, err := s.GetBook(ctx, &pb.GetBookRequest{Id: id})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		ev.Type = pb.BookEvent_DELETED
+	case err != nil:
+		requestid.Printf(ctx, "WatchBooks: snapshot of book %d: %v", id, err)
+		return
+	default:
+		ev.Book = resp.Book
+	}
+	w.push(ev)
+}
+
+// SubscribeEvents forwards lifecycle events of the requested types until the
+// client cancels or the broker closes the subscription.
+func (s *bookCatalogServer) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.BookCatalog_SubscribeEventsServer) error {
+	want := make(map[pb.LifecycleEvent_Type]bool, len(req.Types))
+	for _, t := range req.Types {
+		want[t] = true
+	}
+
+	sub, err := s.events.Subscribe(subscriberBuffer)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to subscribe: %v", err)
+	}
+	defer sub.Close()
+	// Headers tell the client it is subscribed: any write it makes from now
+	// on will reach it.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream closed")
+			}
+			if len(want) > 0 && !want[ev.Type] {
+				continue
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WatchLowStock follows the STOCK_CHANGED lifecycle events that UpdateBook
+// publishes, so stock is never polled. Only a change from threshold or more
+// to below it alerts; further drops of a book already below do not.
+func (s *bookCatalogServer) WatchLowStock(req *pb.WatchLowStockRequest, stream pb.BookCatalog_WatchLowStockServer) error {
+	sub, err := s.events.Subscribe(subscriberBuffer)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to subscribe: %v", err)
+	}
+	defer sub.Close()
+	// As in SubscribeEvents, headers mean the watch is in place.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream closed")
+			}
+			if ev.Type != pb.LifecycleEvent_STOCK_CHANGED || ev.OldStock < req.Threshold || ev.Book.GetStock() >= req.Threshold {
+				continue
+			}
+			alert := &pb.LowStockAlert{
+				BookId:    ev.BookId,
+				Book:      ev.Book,
+				OldStock:  ev.OldStock,
+				Threshold: req.Threshold,
+				Timestamp: ev.Timestamp,
+			}
+			if err := stream.Send(alert); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// importBatchSize is how many CSV rows ImportBooks reads before it commits
+// the valid ones and reports progress.
+const importBatchSize = 100
+
+// importColumns are the CSV columns ImportBooks understands. An id column,
+// as written by ExportBooks, is accepted too and ignored.
+var importColumns = []string{"title", "author", "isbn", "price", "currency", "stock", "published_year", "author_id", "category"}
+
+// chunkReader turns an ImportBooks stream into one io.Reader, so csv.Reader
+// does not care where the client split the file.
+type chunkReader struct {
+	stream pb.BookCatalog_ImportBooksServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// ImportBooks parses the CSV while it arrives and commits every
+// importBatchSize rows in one transaction, so a large file is neither held
+// in memory nor written row by row. A bad row is reported and skipped; a
+// database error ends the import, keeping the batches already committed.
+func (s *bookCatalogServer) ImportBooks(stream pb.BookCatalog_ImportBooksServer) error {
+	ctx := stream.Context()
+	r := csv.NewReader(&chunkReader{stream: stream})
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "empty CSV: a header row is required")
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return status.Errorf(codes.InvalidArgument, "bad CSV header: %v", err)
+	}
+	if err != nil {
+		return err
+	}
+	cols, err := importHeader(header)
+	if err != nil {
+		return err
+	}
+
+	progress := &pb.ImportProgress{}
+	var batch []*pb.CreateBookRequest
+	flush := func(done bool) error {
+		books, err := s.insertBooks(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, book := range books {
+			s.emit(pb.LifecycleEvent_BOOK_CREATED, book.Id, book, 0)
+		}
+		progress.RowsImported += int32(len(books))
+		progress.Done = done
+		if err := stream.Send(progress); err != nil {
+			return err
+		}
+		batch, progress.Errors = nil, nil
+		return nil
+	}
+	reject := func(line int, msg string) {
+		progress.RowsFailed++
+		progress.Errors = append(progress.Errors, &pb.ImportError{Line: int32(line), Message: msg})
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		switch {
+		case errors.As(err, &parseErr):
+			reject(parseErr.StartLine, parseErr.Err.Error())
+		case err != nil:
+			// The stream failed or the client cancelled.
+			return err
+		default:
+			line, _ := r.FieldPos(0)
+			req, err := bookFromCSV(cols, record)
+			if err == nil {
+				err = req.Validate()
+			}
+			if err != nil {
+				reject(line, err.Error())
+			} else {
+				batch = append(batch, req)
+			}
+		}
+		progress.RowsProcessed++
+		if progress.RowsProcessed%importBatchSize == 0 {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(true); err != nil {
+		return err
+	}
+
+	requestid.Printf(ctx, "ImportBooks: %d rows, %d imported, %d failed", progress.RowsProcessed, progress.RowsImported, progress.RowsFailed)
+	return nil
+}
+
+// importHeader maps each known column to its position in the CSV header.
+func importHeader(header []string) (map[string]int, error) {
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // byte order mark from spreadsheet exports
+		}
+		if _, dup := cols[name]; dup {
+			return nil, status.Errorf(codes.InvalidArgument, "CSV header: duplicate column %q", name)
+		}
+		known := name == "id"
+		for _, c := range importColumns {
+			known = known || c == name
+		}
+		if !known {
+			return nil, status.Errorf(codes.InvalidArgument, "CSV header: unknown column %q, expected %s", name, strings.Join(importColumns, ", "))
+		}
+		cols[name] = i
+	}
+	for _, name := range []string{"title", "author"} {
+		if _, ok := cols[name]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "CSV header: missing column %q", name)
+		}
+	}
+	return cols, nil
+}
+
+// bookFromCSV reads one CSV record into a CreateBookRequest. Missing
+// optional columns and empty cells are left as zero; validation is up to
+// the caller.
+func bookFromCSV(cols map[string]int, record []string) (*pb.CreateBookRequest, error) {
+	cell := func(name string) string {
+		if i, ok := cols[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(name string) (int32, error) {
+		if cell(name) == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(cell(name), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %q is not a whole number", name, cell(name))
+		}
+		return int32(n), nil
+	}
+
+	req := &pb.CreateBookRequest{Title: cell("title"), Author: cell("author"), Isbn: cell("isbn")}
+	// Without a currency column prices are in DefaultCurrency; a blank price
+	// is 0, as before prices had a currency.
+	currency := pb.DefaultCurrency
+	if c := cell("currency"); c != "" {
+		currency = strings.ToUpper(c)
+	}
+	req.Price = &pb.Money{CurrencyCode: currency}
+	if p := cell("price"); p != "" {
+		price, err := pb.ParseMoney(currency, p)
+		if err != nil {
+			return nil, fmt.Errorf("price: %v", err)
+		}
+		req.Price = price
+	}
+	var err error
+	if req.Stock, err = number("stock"); err != nil {
+		return nil, err
+	}
+	if req.PublishedYear, err = number("published_year"); err != nil {
+		return nil, err
+	}
+	if req.AuthorId, err = number("author_id"); err != nil {
+		return nil, err
+	}
+	if c := cell("category"); c != "" {
+		category, ok := pb.BookCategory_value[strings.ToUpper(c)]
+		if !ok {
+			return nil, fmt.Errorf("category: %q is not a known category", c)
+		}
+		req.Category = pb.BookCategory(category)
+	}
+	return req, nil
+}
+
+// insertBooks inserts a batch of books in one transaction and returns them
+// with their new ids.
+func (s *bookCatalogServer) insertBooks(ctx context.Context, reqs []*pb.CreateBookRequest) ([]*pb.Book, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(ctx, "failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	stmt := tx.StmtContext(ctx, s.createBookStmt)
+	get := tx.StmtContext(ctx, s.getBookStmt)
+	books := make([]*pb.Book, 0, len(reqs))
+	for _, req := range reqs {
+		result, err := stmt.ExecContext(ctx,
+			req.Title, req.Author, req.Isbn, req.Price.TotalNanos(), req.Price.GetCurrencyCode(), req.Stock, req.PublishedYear, req.AuthorId, req.Category)
+		if err != nil {
+			return nil, dbError(ctx, "failed to insert book", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, dbError(ctx, "failed to get insert id", err)
+		}
+		// Read each book back, as CreateBook does, for its times and etag.
+		var book pb.Book
+		if err := scanBook(get.QueryRowContext(ctx, id), &book); err != nil {
+			return nil, dbError(ctx, "failed to read imported book", err)
+		}
+		books = append(books, &book)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit import", err)
+	}
+	return books, nil
+}
+
+// exportChunkSize is about how many bytes ExportBooks puts in one message.
+const exportChunkSize = 32 * 1024
+
+// ExportBooks encodes rows into a buffer and sends it each time it passes
+// exportChunkSize. Send blocks while the client's flow-control window is
+// full, so a slow reader slows the scan down instead of piling the catalog
+// up in server memory.
+func (s *bookCatalogServer) ExportBooks(req *pb.ExportRequest, stream pb.BookCatalog_ExportBooksServer) error {
+	ctx := stream.Context()
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 ORDER BY id")
+	if err != nil {
+		return dbError(ctx, "failed to query books", err)
+	}
+	defer rows.Close()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	jsonl := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	send := func() error {
+		w.Flush()
+		if buf.Len() == 0 {
+			return nil
+		}
+		// The message is encoded before Send returns, so buf can be reused.
+		if err := stream.Send(&pb.ExportChunk{Data: buf.Bytes()}); err != nil {
+			return err
+		}
+		buf.Reset()
+		return nil
+	}
+	if req.Format == pb.ExportRequest_CSV {
+		w.Write(append([]string{"id"}, importColumns...))
+	}
+
+	exported := 0
+	for rows.Next() {
+		var book pb.Book
+		if err := scanBook(rows, &book); err != nil {
+			return dbError(ctx, "failed to scan book", err)
+		}
+		switch req.Format {
+		case pb.ExportRequest_CSV:
+			w.Write([]string{
+				strconv.Itoa(int(book.Id)), book.Title, book.Author, book.Isbn,
+				book.Price.Decimal(), book.Price.GetCurrencyCode(),
+				strconv.Itoa(int(book.Stock)), strconv.Itoa(int(book.PublishedYear)), strconv.Itoa(int(book.AuthorId)), book.Category.String(),
+			})
+		default:
+			line, err := jsonl.Marshal(&book)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to encode book %d: %v", book.Id, err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		exported++
+		if buf.Len() >= exportChunkSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return dbError(ctx, "failed to read books", err)
+	}
+	if err := send(); err != nil {
+		return err
+	}
+
+	requestid.Printf(ctx, "ExportBooks: sent %d books as %s", exported, req.Format)
+	return nil
+}
+
+// SearchBooks goes through the books_fts index, ranked by relevance, and
+// falls back to LIKE when the index is missing or the query has no words.
+// Fuzzy searches are scored in Go instead.
+func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooksRequest) (*pb.SearchBooksResponse, error) {
+	if req.Fuzzy {
+		return s.searchFuzzy(ctx, req)
+	}
+	if expr := req.MatchExpr(); s.searchStmt != nil && expr != "" {
+		return s.searchFTS(ctx, req, expr)
 	}
 
 	var query string
@@ -192,16 +1177,16 @@ func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooks
 
 	switch req.Field {
 	case "title":
-		query = "SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE title LIKE ?"
+		query = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 AND title LIKE ?"
 		args = append(args, "%"+req.Query+"%")
 	case "author":
-		query = "SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE author LIKE ?"
+		query = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 AND author LIKE ?"
 		args = append(args, "%"+req.Query+"%")
 	case "isbn":
-		query = "SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE isbn = ?"
+		query = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 AND isbn = ?"
 		args = append(args, req.Query)
-	case "all":
-		query = "SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE title LIKE ? OR author LIKE ? OR isbn LIKE ?"
+	case "all", "":
+		query = "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 AND (title LIKE ? OR author LIKE ? OR isbn LIKE ?)"
 		args = append(args, "%"+req.Query+"%", "%"+req.Query+"%", "%"+req.Query+"%")
 	default:
 		return nil, status.Error(codes.InvalidArgument, "invalid field, must be title, author, isbn, or all")
@@ -209,18 +1194,21 @@ func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooks
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to search books: %v", err)
+		return nil, dbError(ctx, "failed to search books", err)
 	}
 	defer rows.Close()
 
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		if err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear, &book.AuthorId); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
+		if err := scanBook(rows, &book); err != nil {
+			return nil, dbError(ctx, "failed to scan book", err)
 		}
 		books = append(books, &book)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to read books", err)
+	}
 
 	return &pb.SearchBooksResponse{
 		Books: books,
@@ -229,53 +1217,114 @@ func (s *bookCatalogServer) SearchBooks(ctx context.Context, req *pb.SearchBooks
 	}, nil
 }
 
-func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooksRequest) (*pb.FilterBooksResponse, error) {
-	log.Printf("FilterBooks: price[%.2f-%.2f], year[%d-%d]", req.MinPrice, req.MaxPrice, req.MinYear, req.MaxYear)
-
-	if req.MinPrice < 0 || req.MaxPrice < 0 {
-		return nil, status.Error(codes.InvalidArgument, "price cannot be negative")
+func (s *bookCatalogServer) searchFTS(ctx context.Context, req *pb.SearchBooksRequest, expr string) (*pb.SearchBooksResponse, error) {
+	rows, err := s.searchStmt.QueryContext(ctx, expr)
+	if err != nil {
+		return nil, dbError(ctx, "failed to search books", err)
 	}
-	if req.MinPrice > 0 && req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
-		return nil, status.Error(codes.InvalidArgument, "min_price cannot be greater than max_price")
+	defer rows.Close()
+
+	var books []*pb.Book
+	var hits []*pb.SearchHit
+	for rows.Next() {
+		var book pb.Book
+		var hit pb.SearchHit
+		if err := scanBook(rows, &book,
+			&hit.Relevance, &hit.Snippet); err != nil {
+			return nil, dbError(ctx, "failed to scan book", err)
+		}
+		hit.BookId = book.Id
+		books = append(books, &book)
+		hits = append(hits, &hit)
 	}
-	if req.MinYear > 0 && req.MaxYear > 0 && req.MinYear > req.MaxYear {
-		return nil, status.Error(codes.InvalidArgument, "min_year cannot be greater than max_year")
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to read books", err)
 	}
 
-	query := "SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE 1=1"
-	var args []interface{}
+	return &pb.SearchBooksResponse{
+		Books: books,
+		Count: int32(len(books)),
+		Query: req.Query,
+		Hits:  hits,
+	}, nil
+}
 
-	if req.MinPrice > 0 {
-		query += " AND price >= ?"
-		args = append(args, req.MinPrice)
+// fuzzyThreshold is the lowest fuzzy.Score a fuzzy search returns. One typo
+// in each word of "Pragmtic Programer" still scores about 0.89.
+const fuzzyThreshold = 0.7
+
+// searchFuzzy scores the title and author of every book against the query
+// and keeps the better of the two. No index can answer this, so it reads
+// the whole table.
+func (s *bookCatalogServer) searchFuzzy(ctx context.Context, req *pb.SearchBooksRequest) (*pb.SearchBooksResponse, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE deleted = 0 ORDER BY id")
+	if err != nil {
+		return nil, dbError(ctx, "failed to search books", err)
 	}
-	if req.MaxPrice > 0 {
-		query += " AND price <= ?"
-		args = append(args, req.MaxPrice)
+	defer rows.Close()
+
+	type match struct {
+		book *pb.Book
+		hit  *pb.SearchHit
 	}
-	if req.MinYear > 0 {
-		query += " AND published_year >= ?"
-		args = append(args, req.MinYear)
+	var matches []match
+	for rows.Next() {
+		var book pb.Book
+		if err := scanBook(rows, &book); err != nil {
+			return nil, dbError(ctx, "failed to scan book", err)
+		}
+		hit := &pb.SearchHit{BookId: book.Id}
+		for _, f := range []struct{ name, text string }{{"title", book.Title}, {"author", book.Author}} {
+			if req.Field == "title" || req.Field == "author" {
+				if f.name != req.Field {
+					continue
+				}
+			}
+			if score := fuzzy.Score(req.Query, f.text); score > hit.Similarity {
+				hit.Similarity, hit.Snippet = score, f.text
+			}
+		}
+		if hit.Similarity >= fuzzyThreshold {
+			matches = append(matches, match{&book, hit})
+		}
 	}
-	if req.MaxYear > 0 {
-		query += " AND published_year <= ?"
-		args = append(args, req.MaxYear)
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to read books", err)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	// Stable, so equal scores stay in id order.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].hit.Similarity > matches[j].hit.Similarity
+	})
+	resp := &pb.SearchBooksResponse{Count: int32(len(matches)), Query: req.Query}
+	for _, m := range matches {
+		resp.Books = append(resp.Books, m.book)
+		resp.Hits = append(resp.Hits, m.hit)
+	}
+	return resp, nil
+}
+
+// FilterBooks builds its WHERE like ListBooks and StreamBooks, in
+// filterWhere.
+func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooksRequest) (*pb.FilterBooksResponse, error) {
+	where, args := filterWhere(req)
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books"+where, args...)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to filter books: %v", err)
+		return nil, dbError(ctx, "failed to filter books", err)
 	}
 	defer rows.Close()
 
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		if err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear, &book.AuthorId); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
+		if err := scanBook(rows, &book); err != nil {
+			return nil, dbError(ctx, "failed to scan book", err)
 		}
 		books = append(books, &book)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to read books", err)
+	}
 
 	return &pb.FilterBooksResponse{
 		Books: books,
@@ -283,42 +1332,63 @@ func (s *bookCatalogServer) FilterBooks(ctx context.Context, req *pb.FilterBooks
 	}, nil
 }
 
+// GetStats runs four queries; it stops between them once the client has
+// gone or the deadline has passed.
 func (s *bookCatalogServer) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
-	log.Printf("GetStats")
-
 	var totalBooks int32
-	var avgPrice sql.NullFloat64
 	var totalStock int32
 	var minYear, maxYear sql.NullInt32
 
-	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books").Scan(&totalBooks)
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books WHERE deleted = 0").Scan(&totalBooks)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to count books: %v", err)
+		return nil, dbError(ctx, "failed to count books", err)
 	}
 
-	err = s.db.QueryRowContext(ctx, "SELECT AVG(price) FROM books").Scan(&avgPrice)
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+	// One average per currency, from the exact sum of nanos.
+	var averages []*pb.Money
+	rows, err := s.db.QueryContext(ctx, "SELECT currency, SUM(price), COUNT(*) FROM books WHERE deleted = 0 GROUP BY currency ORDER BY currency")
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to calculate average price: %v", err)
+		return nil, dbError(ctx, "failed to calculate average prices", err)
+	}
+	for rows.Next() {
+		var currency string
+		var sum, count int64
+		if err := rows.Scan(&currency, &sum, &count); err != nil {
+			rows.Close()
+			return nil, dbError(ctx, "failed to scan average price", err)
+		}
+		averages = append(averages, pb.MoneyFromNanos(currency, sum/count))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to calculate average prices", err)
 	}
 
-	err = s.db.QueryRowContext(ctx, "SELECT SUM(stock) FROM books").Scan(&totalStock)
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+	err = s.db.QueryRowContext(ctx, "SELECT SUM(stock) FROM books WHERE deleted = 0").Scan(&totalStock)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to sum stock: %v", err)
+		return nil, dbError(ctx, "failed to sum stock", err)
 	}
 
-	err = s.db.QueryRowContext(ctx, "SELECT MIN(published_year), MAX(published_year) FROM books").Scan(&minYear, &maxYear)
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+	err = s.db.QueryRowContext(ctx, "SELECT MIN(published_year), MAX(published_year) FROM books WHERE deleted = 0").Scan(&minYear, &maxYear)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get year range: %v", err)
+		return nil, dbError(ctx, "failed to get year range", err)
 	}
 
 	resp := &pb.GetStatsResponse{
-		TotalBooks: totalBooks,
-		TotalStock: totalStock,
+		TotalBooks:    totalBooks,
+		AveragePrices: averages,
+		TotalStock:    totalStock,
 	}
 
-	if avgPrice.Valid {
-		resp.AveragePrice = float32(avgPrice.Float64)
-	}
 	if minYear.Valid {
 		resp.EarliestYear = minYear.Int32
 	}
@@ -331,28 +1401,27 @@ func (s *bookCatalogServer) GetStats(ctx context.Context, req *pb.GetStatsReques
 
 // NEW: Get books by author_id - for service-to-service communication
 func (s *bookCatalogServer) GetBooksByAuthor(ctx context.Context, req *pb.GetBooksByAuthorRequest) (*pb.GetBooksByAuthorResponse, error) {
-	log.Printf("GetBooksByAuthor: author_id=%d", req.AuthorId)
-
-	if req.AuthorId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "author_id must be positive")
+	query := "SELECT id, title, author, isbn, price, currency, stock, published_year, author_id, category, created_at, updated_at, version FROM books WHERE author_id = ?"
+	if !req.IncludeDeleted {
+		query += " AND deleted = 0"
 	}
-
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, title, author, isbn, price, stock, published_year, author_id FROM books WHERE author_id = ?",
-		req.AuthorId)
+	rows, err := s.db.QueryContext(ctx, query, req.AuthorId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to query books: %v", err)
+		return nil, dbError(ctx, "failed to query books", err)
 	}
 	defer rows.Close()
 
 	var books []*pb.Book
 	for rows.Next() {
 		var book pb.Book
-		if err := rows.Scan(&book.Id, &book.Title, &book.Author, &book.Isbn, &book.Price, &book.Stock, &book.PublishedYear, &book.AuthorId); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to scan book: %v", err)
+		if err := scanBook(rows, &book); err != nil {
+			return nil, dbError(ctx, "failed to scan book", err)
 		}
 		books = append(books, &book)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to read books", err)
+	}
 
 	return &pb.GetBooksByAuthorResponse{
 		Books: books,
@@ -360,8 +1429,172 @@ func (s *bookCatalogServer) GetBooksByAuthor(ctx context.Context, req *pb.GetBoo
 	}, nil
 }
 
+// bookCatalogV2Server serves bookcatalog.v2.BookCatalog by translating each
+// call to the v1 handler, so the versions cannot drift apart: a v2 write
+// still invalidates the cache and reaches WatchBooks and SubscribeEvents.
+type bookCatalogV2Server struct {
+	pbv2.UnimplementedBookCatalogServer
+	v1 *bookCatalogServer
+}
+
+func (s *bookCatalogV2Server) GetBook(ctx context.Context, req *pbv2.GetBookRequest) (*pbv2.Book, error) {
+	resp, err := s.v1.GetBook(ctx, req.V1())
+	if err != nil {
+		return nil, err
+	}
+	return pbv2.FromV1(resp.Book), nil
+}
+
+// ListBooks pages by offset, carried in the page token, so a book created
+// or deleted between pages shifts the later ones, as with v1 page numbers.
+func (s *bookCatalogV2Server) ListBooks(ctx context.Context, req *pbv2.ListBooksRequest) (*pbv2.ListBooksResponse, error) {
+	offset, err := req.Offset()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "page_token: %v", err)
+	}
+	v1 := req.V1()
+	if v1.PageSize < 1 {
+		v1.PageSize = 10
+	}
+
+	books, total, err := s.v1.listBooks(ctx, v1, offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pbv2.ListBooksResponse{TotalSize: total}
+	for _, book := range books {
+		resp.Books = append(resp.Books, pbv2.FromV1(book))
+	}
+	if next := offset + int32(len(books)); len(books) > 0 && next < total {
+		resp.NextPageToken = pbv2.PageToken(next)
+	}
+	return resp, nil
+}
+
+func (s *bookCatalogV2Server) CreateBook(ctx context.Context, req *pbv2.CreateBookRequest) (*pbv2.Book, error) {
+	resp, err := s.v1.CreateBook(ctx, req.V1())
+	if err != nil {
+		return nil, err
+	}
+	return pbv2.FromV1(resp.Book), nil
+}
+
+func (s *bookCatalogV2Server) UpdateBook(ctx context.Context, req *pbv2.UpdateBookRequest) (*pbv2.Book, error) {
+	resp, err := s.v1.UpdateBook(ctx, req.V1())
+	if err != nil {
+		return nil, err
+	}
+	return pbv2.FromV1(resp.Book), nil
+}
+
+func (s *bookCatalogV2Server) DeleteBook(ctx context.Context, req *pbv2.DeleteBookRequest) (*emptypb.Empty, error) {
+	if _, err := s.v1.DeleteBook(ctx, req.V1()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// reviewCatalogServer serves ReviewCatalog from the books database, so a
+// review can only be added to a book that exists.
+type reviewCatalogServer struct {
+	pb.UnimplementedReviewCatalogServer
+	db *sql.DB
+}
+
+// bookRatingQuery has no row when the book does not exist, and a count of 0
+// when it has no reviews.
+const bookRatingQuery = `SELECT COUNT(r.id), COALESCE(AVG(r.rating), 0)
+	FROM books b LEFT JOIN reviews r ON r.book_id = b.id
+	WHERE b.id = ? AND b.deleted = 0 GROUP BY b.id`
+
+func bookRating(ctx context.Context, db *sql.DB, bookID int32) (*pb.BookRating, error) {
+	rating := &pb.BookRating{BookId: bookID}
+	err := db.QueryRowContext(ctx, bookRatingQuery, bookID).Scan(&rating.ReviewCount, &rating.Average)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "book with id %d not found", bookID)
+	}
+	if err != nil {
+		return nil, dbError(ctx, "failed to compute rating", err)
+	}
+	return rating, nil
+}
+
+// AddReview checks that the book exists in the insert itself, so a book
+// deleted meanwhile cannot be left with a review.
+func (s *reviewCatalogServer) AddReview(ctx context.Context, req *pb.AddReviewRequest) (*pb.AddReviewResponse, error) {
+	review := &pb.Review{
+		BookId:    req.BookId,
+		Reviewer:  req.Reviewer,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+		CreatedAt: time.Now().Unix(),
+	}
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO reviews (book_id, reviewer, rating, comment, created_at)
+		SELECT ?, ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM books WHERE id = ? AND deleted = 0)`,
+		review.BookId, review.Reviewer, review.Rating, review.Comment, review.CreatedAt, req.BookId)
+	if err != nil {
+		return nil, dbError(ctx, "failed to insert review", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, dbError(ctx, "failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "book with id %d not found", req.BookId)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, dbError(ctx, "failed to get insert id", err)
+	}
+	review.Id = int32(id)
+
+	return &pb.AddReviewResponse{Review: review}, nil
+}
+
+func (s *reviewCatalogServer) ListReviews(req *pb.ListReviewsRequest, stream pb.ReviewCatalog_ListReviewsServer) error {
+	ctx := stream.Context()
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM books WHERE id = ? AND deleted = 0)", req.BookId).Scan(&exists); err != nil {
+		return dbError(ctx, "database error", err)
+	}
+	if !exists {
+		return status.Errorf(codes.NotFound, "book with id %d not found", req.BookId)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, book_id, reviewer, rating, comment, created_at FROM reviews WHERE book_id = ? ORDER BY id",
+		req.BookId)
+	if err != nil {
+		return dbError(ctx, "failed to query reviews", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review pb.Review
+		if err := rows.Scan(&review.Id, &review.BookId, &review.Reviewer, &review.Rating, &review.Comment, &review.CreatedAt); err != nil {
+			return dbError(ctx, "failed to scan review", err)
+		}
+		if err := stream.Send(&review); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return dbError(ctx, "failed to read reviews", err)
+	}
+	return nil
+}
+
+func (s *reviewCatalogServer) GetBookRating(ctx context.Context, req *pb.GetBookRatingRequest) (*pb.GetBookRatingResponse, error) {
+	rating, err := bookRating(ctx, s.db, req.BookId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetBookRatingResponse{Rating: rating}, nil
+}
+
 func initDB() (*sql.DB, error) {
-	db, err := sql.Open("sqlite", "./books_task5.db")
+	db, err := sqlitedb.Open("./books_task5.db")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -373,10 +1606,16 @@ func initDB() (*sql.DB, error) {
 		title TEXT NOT NULL,
 		author TEXT NOT NULL,
 		isbn TEXT,
-		price REAL,
+		price INTEGER NOT NULL DEFAULT 0, -- nanos of currency
+		currency TEXT NOT NULL DEFAULT 'USD',
 		stock INTEGER,
 		published_year INTEGER,
-		author_id INTEGER DEFAULT 0
+		author_id INTEGER DEFAULT 0,
+		category INTEGER NOT NULL DEFAULT 0,
+		deleted INTEGER NOT NULL DEFAULT 0, -- 1 once soft-deleted
+		created_at INTEGER NOT NULL DEFAULT 0,
+		updated_at INTEGER NOT NULL DEFAULT 0,
+		version INTEGER NOT NULL DEFAULT 1 -- bumped by UpdateBook; the etag
 	);`
 
 	_, err = db.Exec(createTableSQL)
@@ -384,6 +1623,123 @@ func initDB() (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Databases from before books had a category get the column, with every
+	// existing book UNKNOWN.
+	var hasCategory bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('books') WHERE name = 'category')").Scan(&hasCategory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect books table: %w", err)
+	}
+	if !hasCategory {
+		if _, err := db.Exec("ALTER TABLE books ADD COLUMN category INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return nil, fmt.Errorf("failed to add category column: %w", err)
+		}
+		log.Println("Added category column to books")
+	}
+
+	// bookcatalog.v2 reports when a book was created and last updated. The
+	// times are kept by triggers, like the search index, so every write path
+	// records them; books stored before the columns existed keep 0 (unset).
+	var hasTimes bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('books') WHERE name = 'created_at')").Scan(&hasTimes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect books table: %w", err)
+	}
+	if !hasTimes {
+		_, err := db.Exec(`
+		ALTER TABLE books ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE books ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add time columns: %w", err)
+		}
+		log.Println("Added created_at and updated_at columns to books")
+	}
+
+	// UpdateBook checks the etag, derived from version, so concurrent
+	// updates cannot overwrite each other. Existing books start at 1.
+	var hasVersion bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('books') WHERE name = 'version')").Scan(&hasVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect books table: %w", err)
+	}
+	if !hasVersion {
+		if _, err := db.Exec("ALTER TABLE books ADD COLUMN version INTEGER NOT NULL DEFAULT 1"); err != nil {
+			return nil, fmt.Errorf("failed to add version column: %w", err)
+		}
+		log.Println("Added version column to books")
+	}
+
+	// DeleteBook only marks a book deleted unless asked to remove it, so
+	// UndeleteBook can bring it back. Every read skips marked rows.
+	var hasDeleted bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('books') WHERE name = 'deleted')").Scan(&hasDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect books table: %w", err)
+	}
+	if !hasDeleted {
+		if _, err := db.Exec("ALTER TABLE books ADD COLUMN deleted INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return nil, fmt.Errorf("failed to add deleted column: %w", err)
+		}
+		log.Println("Added deleted column to books")
+	}
+
+	// Prices used to be REAL. They are now whole nanos of a currency, so sums
+	// and comparisons are exact; old prices are rounded to the cent and taken
+	// to be in DefaultCurrency.
+	var hasCurrency bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('books') WHERE name = 'currency')").Scan(&hasCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect books table: %w", err)
+	}
+	if !hasCurrency {
+		if err := migratePrices(db); err != nil {
+			return nil, fmt.Errorf("failed to migrate prices: %w", err)
+		}
+		log.Println("Converted book prices to nanos and added currency column")
+	}
+	_, err = db.Exec(`
+	CREATE TRIGGER IF NOT EXISTS books_created AFTER INSERT ON books BEGIN
+		UPDATE books SET created_at = unixepoch(), updated_at = unixepoch() WHERE id = new.id;
+	END;
+	CREATE TRIGGER IF NOT EXISTS books_updated AFTER UPDATE OF title, author, isbn, price, currency, stock, published_year, author_id, category ON books BEGIN
+		UPDATE books SET updated_at = unixepoch() WHERE id = new.id;
+	END;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time triggers: %w", err)
+	}
+
+	// request_ids CreateBook has processed, kept for requestIDTTL.
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS create_requests (
+		request_id TEXT PRIMARY KEY,
+		book_id INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	);`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create create_requests table: %w", err)
+	}
+
+	// Reviews belong to ReviewCatalog and go away with their book.
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS reviews (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		book_id INTEGER NOT NULL,
+		reviewer TEXT NOT NULL,
+		rating INTEGER NOT NULL,
+		comment TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS reviews_book_id ON reviews(book_id);
+	CREATE TRIGGER IF NOT EXISTS reviews_book_delete AFTER DELETE ON books BEGIN
+		DELETE FROM reviews WHERE book_id = old.id;
+	END;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reviews table: %w", err)
+	}
+	if err := initSearchIndex(db); err != nil {
+		log.Printf("⚠️ Failed to set up full-text index: %v", err)
+	}
+
 	// Seed sample data
 	var count int
 	err = db.QueryRow("SELECT COUNT(*) FROM books").Scan(&count)
@@ -395,21 +1751,26 @@ func initDB() (*sql.DB, error) {
 		log.Println("Seeding sample data...")
 		sampleBooks := []struct {
 			title, author, isbn            string
-			price                          float32
+			price                          string
 			stock, publishedYear, authorId int32
 		}{
-			{"The Go Programming Language", "Alan Donovan", "978-0134190440", 44.99, 20, 2015, 1},
-			{"Clean Code", "Robert C. Martin", "978-0132350884", 39.95, 15, 2008, 2},
-			{"Design Patterns", "Gang of Four", "978-0201633610", 54.99, 10, 1994, 3},
-			{"Effective Java", "Joshua Bloch", "978-0134685991", 42.50, 25, 2017, 4},
-			{"Go Programming Blueprints", "Mat Ryer", "978-1783988020", 29.99, 18, 2015, 5},
-			{"The Pragmatic Programmer", "Hunt & Thomas", "978-0135957059", 45.00, 22, 2019, 6},
+			{"The Go Programming Language", "Alan Donovan", "978-0134190440", "44.99", 20, 2015, 1},
+			{"Clean Code", "Robert C. Martin", "978-0132350884", "39.95", 15, 2008, 2},
+			{"Design Patterns", "Gang of Four", "978-0201633610", "54.99", 10, 1994, 3},
+			{"Effective Java", "Joshua Bloch", "978-0134685991", "42.50", 25, 2017, 4},
+			{"Go Programming Blueprints", "Mat Ryer", "978-1783988020", "29.99", 18, 2015, 5},
+			{"The Pragmatic Programmer", "Hunt & Thomas", "978-0135957059", "45.00", 22, 2019, 6},
 		}
 
 		for _, book := range sampleBooks {
-			_, err := db.Exec(
-				"INSERT INTO books (title, author, isbn, price, stock, published_year, author_id) VALUES (?, ?, ?, ?, ?, ?, ?)",
-				book.title, book.author, book.isbn, book.price, book.stock, book.publishedYear, book.authorId)
+			price, err := pb.ParseMoney(pb.DefaultCurrency, book.price)
+			if err != nil {
+				return nil, fmt.Errorf("failed to seed data: %w", err)
+			}
+			// Every sample book is about programming.
+			_, err = db.Exec(
+				"INSERT INTO books (title, author, isbn, price, currency, stock, published_year, author_id, category) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				book.title, book.author, book.isbn, price.TotalNanos(), price.CurrencyCode, book.stock, book.publishedYear, book.authorId, pb.BookCategory_NONFICTION)
 			if err != nil {
 				return nil, fmt.Errorf("failed to seed data: %w", err)
 			}
@@ -421,7 +1782,93 @@ func initDB() (*sql.DB, error) {
 	return db, nil
 }
 
+// migratePrices rewrites the REAL price column of an old database as nanos
+// and adds the currency column, in one transaction. The books_updated
+// trigger names the price column, so it is dropped first and recreated by
+// initDB.
+func migratePrices(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`
+	DROP TRIGGER IF EXISTS books_updated;
+	ALTER TABLE books ADD COLUMN price_nanos INTEGER NOT NULL DEFAULT 0;
+	UPDATE books SET price_nanos = CAST(ROUND(COALESCE(price, 0) * 100) AS INTEGER) * 10000000;
+	ALTER TABLE books DROP COLUMN price;
+	ALTER TABLE books RENAME COLUMN price_nanos TO price;
+	ALTER TABLE books ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';`)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// initSearchIndex creates the FTS5 index behind SearchBooks. It is an
+// external-content table over books, kept in sync by triggers, so every
+// write path (CreateBook, ImportBooks, UpdateBook, DeleteBook) updates it
+// in the same transaction. A new index is filled from the existing rows.
+func initSearchIndex(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'books_fts')").Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+	CREATE VIRTUAL TABLE books_fts USING fts5(title, author, isbn, content='books', content_rowid='id');
+	CREATE TRIGGER books_fts_insert AFTER INSERT ON books BEGIN
+		INSERT INTO books_fts(rowid, title, author, isbn) VALUES (new.id, new.title, new.author, new.isbn);
+	END;
+	CREATE TRIGGER books_fts_delete AFTER DELETE ON books BEGIN
+		INSERT INTO books_fts(books_fts, rowid, title, author, isbn) VALUES ('delete', old.id, old.title, old.author, old.isbn);
+	END;
+	CREATE TRIGGER books_fts_update AFTER UPDATE OF title, author, isbn ON books BEGIN
+		INSERT INTO books_fts(books_fts, rowid, title, author, isbn) VALUES ('delete', old.id, old.title, old.author, old.isbn);
+		INSERT INTO books_fts(rowid, title, author, isbn) VALUES (new.id, new.title, new.author, new.isbn);
+	END;
+	INSERT INTO books_fts(books_fts) VALUES ('rebuild');`)
+	if err != nil {
+		return err
+	}
+	log.Println("Full-text index created")
+	return tx.Commit()
+}
+
+// connectToAuthorService dials Author service for checkAuthor and author.
+// Calls fail fast, without waiting for a server that is down, so a write is
+// refused at once rather than when its deadline runs out.
+func connectToAuthorService() (authorpb.AuthorCatalogClient, error) {
+	target := endpoints.Target("authors", *authorAddr)
+	log.Printf("🔗 Connecting to Author service on %s...", target)
+
+	creds, err := tlsconfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+	}
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds), keepaliveconfig.DialOption(), auth.DialOption(),
+		retry.FailFastDialOption(endpoints.ServiceConfig(authorpb.AuthorCatalog_ServiceDesc.ServiceName)),
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Author service: %w", err)
+	}
+	return authorpb.NewAuthorCatalogClient(conn), nil
+}
+
 func main() {
+	flag.Parse()
+
 	// Initialize database
 	db, err := initDB()
 	if err != nil {
@@ -429,18 +1876,68 @@ func main() {
 	}
 	defer db.Close()
 
-	// Create listener on port 50051 (Book service)
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	// Lifecycle events stay in this process unless -nats-url is set
+	broker, err := events.New(*natsURL)
+	if err != nil {
+		log.Fatalf("Failed to set up event broker: %v", err)
+	}
+	defer broker.Close()
+
+	// Create listener, port 50051 by default (Book service)
+	lis, err := net.Listen("tcp", *listenAddr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer()
-	pb.RegisterBookCatalogServer(grpcServer, &bookCatalogServer{db: db})
+	creds, err := tlsconfig.ServerCredentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS credentials: %v", err)
+	}
+	authn, err := auth.New(
+		pb.BookCatalog_CreateBook_FullMethodName,
+		pb.BookCatalog_UpdateBook_FullMethodName,
+		pb.BookCatalog_DeleteBook_FullMethodName,
+		pb.BookCatalog_UndeleteBook_FullMethodName,
+		pb.BookCatalog_ImportBooks_FullMethodName,
+		pb.ReviewCatalog_AddReview_FullMethodName,
+		pbv2.BookCatalog_CreateBook_FullMethodName,
+		pbv2.BookCatalog_UpdateBook_FullMethodName,
+		pbv2.BookCatalog_DeleteBook_FullMethodName,
+	)
+	if err != nil {
+		log.Fatalf("Failed to configure auth: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), keepaliveconfig.ServerParams(), keepaliveconfig.EnforcementPolicy(), compression.ServerOption(),
+		grpc.ChainUnaryInterceptor(interceptors.UnaryMetrics(), requestid.UnaryServerInterceptor(), authn.UnaryInterceptor(), interceptors.UnaryLogging(), interceptors.UnaryRecovery(), interceptors.UnaryValidation(), interceptors.UnaryDeadline(*maxDeadline)),
+		grpc.ChainStreamInterceptor(interceptors.StreamMetrics(), requestid.StreamServerInterceptor(), authn.StreamInterceptor(), interceptors.StreamLogging(), interceptors.StreamRecovery(), interceptors.StreamValidation()))
+	interceptors.ServeMetrics()
+	var authorClient authorpb.AuthorCatalogClient
+	if *validateAuthors || *authorDetails {
+		if authorClient, err = connectToAuthorService(); err != nil {
+			log.Fatalf("Failed to connect to Author service: %v", err)
+		}
+	}
+	srv, err := newBookCatalogServer(db, broker, authorClient)
+	if err != nil {
+		log.Fatalf("Failed to prepare statements: %v", err)
+	}
+	pb.RegisterBookCatalogServer(grpcServer, srv)
+	pb.RegisterReviewCatalogServer(grpcServer, &reviewCatalogServer{db: db})
+	pbv2.RegisterBookCatalogServer(grpcServer, &bookCatalogV2Server{v1: srv})
+	reflectionconfig.Register(grpcServer)
+	healthcheck.Register(grpcServer, db, pb.BookCatalog_ServiceDesc.ServiceName, pb.ReviewCatalog_ServiceDesc.ServiceName, pbv2.BookCatalog_ServiceDesc.ServiceName)
 
-	log.Println("📚 BookCatalog gRPC server (Task5) listening on :50051")
+	log.Printf("📚 BookCatalog gRPC server (Task5) listening on %s", *listenAddr)
 	log.Println("✨ Supports service-to-service communication with Author service")
+	if *validateAuthors {
+		log.Println("🔗 author_id checked against Author service")
+	}
+	if *authorDetails {
+		log.Println("👤 GetBook adds author details from Author service")
+	}
+	log.Println("⭐ ReviewCatalog served on the same port")
+	log.Println("🆕 bookcatalog.v2.BookCatalog served next to v1")
 
 	// Start serving
 	if err := grpcServer.Serve(lis); err != nil {
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+
+	pb "book-catalog-grpc/proto"
+)
+
+// The SQL the handlers build from requests. Every name here is a column of
+// this server's books table; Validate has already checked each request.
+
+// filterWhere returns the " WHERE ..." selecting the books r matches, with a
+// leading space so it can follow "FROM books", and its arguments.
+// Soft-deleted books never match, so even a nil or empty filter yields a
+// clause. FilterBooks and ListBooks/StreamBooks share it, so both filter
+// alike.
+func filterWhere(r *pb.FilterBooksRequest) (string, []any) {
+	conds := []string{"deleted = 0"}
+	var args []any
+	where := func(cond string, condArgs ...any) {
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+
+	// A price bound also limits the books to its currency: amounts in
+	// different currencies are not comparable.
+	if m := r.GetMinPrice(); m != nil {
+		where("currency = ? AND price >= ?", m.CurrencyCode, m.TotalNanos())
+	}
+	if m := r.GetMaxPrice(); m != nil {
+		where("currency = ? AND price <= ?", m.CurrencyCode, m.TotalNanos())
+	}
+	if r.GetMinYear() > 0 {
+		where("published_year >= ?", r.MinYear)
+	}
+	if r.GetMaxYear() > 0 {
+		where("published_year <= ?", r.MaxYear)
+	}
+	if r.GetInStockOnly() {
+		where("stock > 0")
+	}
+	if r.GetMinStock() > 0 {
+		where("stock >= ?", r.MinStock)
+	}
+	if r.GetMaxStock() > 0 {
+		where("stock <= ?", r.MaxStock)
+	}
+	if r.GetAuthorId() > 0 {
+		where("author_id = ?", r.AuthorId)
+	}
+	if n := len(r.GetCategories()); n > 0 {
+		var categories []any
+		for _, c := range r.Categories {
+			categories = append(categories, c)
+		}
+		where("category IN (?"+strings.Repeat(", ?", n-1)+")", categories...)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// listWhere extends the clause of r.Filter with updated_since, for ListBooks
+// and StreamBooks.
+func listWhere(r *pb.ListBooksRequest) (string, []any) {
+	where, args := filterWhere(r.GetFilter())
+	if t := r.GetUpdatedSince(); t != nil {
+		where += " AND updated_at >= ?"
+		args = append(args, t.Seconds)
+	}
+	return where, args
+}
+
+// orderColumns maps the fields ListBooksRequest.order_by may sort on to
+// columns. Only these names ever reach the SQL.
+var orderColumns = map[string]string{
+	"id":             "id",
+	"title":          "title",
+	"author":         "author",
+	"isbn":           "isbn",
+	"price":          "price",
+	"stock":          "stock",
+	"published_year": "published_year",
+}
+
+// orderByClause returns the "ORDER BY ..." for r.OrderBy. id is always the
+// last key so pages stay stable when sort values tie.
+func orderByClause(r *pb.ListBooksRequest) string {
+	keys, err := pb.ParseOrderBy(r.OrderBy)
+	if err != nil || len(keys) == 0 {
+		return "ORDER BY id"
+	}
+	var cols []string
+	for _, k := range keys {
+		col, ok := orderColumns[k.Field]
+		if !ok {
+			return "ORDER BY id"
+		}
+		if k.Desc {
+			col += " DESC"
+		} else {
+			col += " ASC"
+		}
+		cols = append(cols, col)
+	}
+	return "ORDER BY " + strings.Join(cols, ", ") + ", id"
+}
+
+// bookColumns maps the update_mask paths of UpdateBookRequest to columns.
+// price is stored as nanos plus a currency.
+var bookColumns = map[string][]string{
+	"title":          {"title"},
+	"author":         {"author"},
+	"isbn":           {"isbn"},
+	"price":          {"price", "currency"},
+	"stock":          {"stock"},
+	"published_year": {"published_year"},
+	"author_id":      {"author_id"},
+	"category":       {"category"},
+}
+
+// maskedColumns returns the columns and new values named in update_mask, in
+// mask order, for building "UPDATE books SET col = ?, ...". It returns nil
+// when there is no mask.
+func maskedColumns(r *pb.UpdateBookRequest) ([]string, []any) {
+	paths := r.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	values := map[string][]any{
+		"title":          {r.Title},
+		"author":         {r.Author},
+		"isbn":           {r.Isbn},
+		"price":          {r.Price.TotalNanos(), r.Price.GetCurrencyCode()},
+		"stock":          {r.Stock},
+		"published_year": {r.PublishedYear},
+		"author_id":      {r.AuthorId},
+		"category":       {r.Category},
+	}
+	var cols []string
+	var args []any
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		pathCols, ok := bookColumns[p]
+		if !ok || seen[p] {
+			continue
+		}
+		seen[p] = true
+		cols = append(cols, pathCols...)
+		args = append(args, values[p]...)
+	}
+	return cols, args
+}
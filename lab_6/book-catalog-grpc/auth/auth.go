@@ -0,0 +1,145 @@
+// Package auth checks bearer tokens sent in the "authorization" metadata.
+// Servers list the tokens they accept with -auth-tokens (AUTH_TOKENS) as
+// comma-separated token=caller pairs, e.g. "s3cret=alice,svc=author-service".
+// Read RPCs stay open to anonymous callers; write RPCs need a valid token.
+// A token that is sent but unknown is rejected on every RPC. With no tokens
+// configured the check is off and every caller is "anonymous".
+//
+// Clients send their token with -auth-token (AUTH_TOKEN) via DialOption.
+package auth
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serverTokens = flag.String("auth-tokens", os.Getenv("AUTH_TOKENS"), "accepted bearer tokens as token=caller,... (server)")
+	clientToken  = flag.String("auth-token", os.Getenv("AUTH_TOKEN"), "bearer token sent with every RPC (client)")
+)
+
+// Anonymous is the caller name of requests without a token.
+const Anonymous = "anonymous"
+
+type callerKey struct{}
+
+// Caller returns who made the request, for logging.
+func Caller(ctx context.Context) string {
+	if c, ok := ctx.Value(callerKey{}).(string); ok {
+		return c
+	}
+	return Anonymous
+}
+
+// Authenticator holds the accepted tokens and which methods write.
+type Authenticator struct {
+	tokens map[string]string
+	writes map[string]bool
+}
+
+// New reads the accepted tokens from -auth-tokens; writeMethods are full
+// method names such as "/bookservice.BookCatalog/CreateBook". Call
+// flag.Parse first.
+func New(writeMethods ...string) (*Authenticator, error) {
+	a := &Authenticator{tokens: make(map[string]string), writes: make(map[string]bool)}
+	for _, m := range writeMethods {
+		a.writes[m] = true
+	}
+	for _, pair := range strings.Split(*serverTokens, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		token, caller, ok := strings.Cut(pair, "=")
+		token, caller = strings.TrimSpace(token), strings.TrimSpace(caller)
+		if !ok || token == "" || caller == "" {
+			return nil, fmt.Errorf("malformed -auth-tokens entry %q, want token=caller", pair)
+		}
+		a.tokens[token] = caller
+	}
+	if len(a.tokens) == 0 {
+		log.Println("⚠️  Auth disabled: no -auth-tokens configured")
+	} else {
+		log.Printf("🔑 Auth enabled: %d token(s), %d write RPC(s) protected", len(a.tokens), len(a.writes))
+	}
+	return a, nil
+}
+
+// authenticate returns ctx carrying the caller, or an Unauthenticated error.
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if len(a.tokens) == 0 {
+		return ctx, nil
+	}
+	caller := Anonymous
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		token, found := strings.CutPrefix(md.Get("authorization")[0], "Bearer ")
+		name, known := a.tokens[token]
+		if !found || !known {
+			log.Printf("🚫 %s: invalid token", method)
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+		caller = name
+	}
+	if caller == Anonymous && a.writes[method] {
+		log.Printf("🚫 %s: anonymous write rejected", method)
+		return nil, status.Errorf(codes.Unauthenticated, "%s requires a bearer token", method)
+	}
+	return context.WithValue(ctx, callerKey{}, caller), nil
+}
+
+// UnaryInterceptor checks unary RPCs.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor checks streaming RPCs when the stream opens.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &callerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// callerStream hands the authenticated context to stream handlers.
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context { return s.ctx }
+
+// tokenCredentials attaches a bearer token to every RPC.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false so the lab also works over plaintext;
+// use TLS (see tlsconfig) anywhere the token matters.
+func (t tokenCredentials) RequireTransportSecurity() bool { return false }
+
+// DialOption sends the -auth-token with every RPC, if one is set.
+func DialOption() grpc.DialOption {
+	if *clientToken == "" {
+		return grpc.EmptyDialOption{}
+	}
+	return grpc.WithPerRPCCredentials(tokenCredentials(*clientToken))
+}
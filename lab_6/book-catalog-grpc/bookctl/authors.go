@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	authorpb "book-catalog-grpc/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuthorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "author",
+		Short: "Call Author service",
+	}
+	cmd.AddCommand(newAuthorGetCmd(), newAuthorListCmd(), newAuthorSearchCmd(), newAuthorCreateCmd(),
+		newAuthorDeleteCmd(), newAuthorBooksCmd(), newAuthorStatsCmd())
+	return cmd
+}
+
+func newAuthorGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show one author",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetAuthor(cmd.Context(), &authorpb.GetAuthorRequest{Id: id})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeAuthors(w, resp.Author)
+				if resp.Author.Bio != "" {
+					fmt.Fprintf(w, "\n%s\n", resp.Author.Bio)
+				}
+			})
+		},
+	}
+}
+
+func newAuthorListCmd() *cobra.Command {
+	var page, pageSize int32
+	var updatedSince string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List authors a page at a time",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, err := parseSince(updatedSince)
+			if err != nil {
+				return err
+			}
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.ListAuthors(cmd.Context(), &authorpb.ListAuthorsRequest{Page: page, PageSize: pageSize, UpdatedSince: since})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeAuthors(w, resp.Authors...)
+				fmt.Fprintf(w, "\n%d authors in all\n", resp.Total)
+			})
+		},
+	}
+	cmd.Flags().Int32Var(&page, "page", 1, "page number, from 1")
+	cmd.Flags().Int32Var(&pageSize, "page-size", 10, "authors per page")
+	cmd.Flags().StringVar(&updatedSince, "updated-since", "", "only authors created or updated since this RFC 3339 time, or this long ago, e.g. 24h")
+	return cmd
+}
+
+func newAuthorSearchCmd() *cobra.Command {
+	var req authorpb.SearchAuthorsRequest
+	cmd := &cobra.Command{
+		Use:     "search",
+		Short:   "Find authors by name, country and birth year",
+		Example: "  bookctl author search --name mar --country uk",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.SearchAuthors(cmd.Context(), &req)
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeAuthors(w, resp.Authors...)
+				fmt.Fprintf(w, "\n%d authors match\n", resp.Total)
+			})
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&req.Name, "name", "", "part of the name, any case")
+	fs.StringVar(&req.Country, "country", "", "country, any case")
+	fs.Int32Var(&req.MinBirthYear, "min-birth-year", 0, "born in or after this year")
+	fs.Int32Var(&req.MaxBirthYear, "max-birth-year", 0, "born in or before this year")
+	fs.Int32Var(&req.Page, "page", 1, "page number, from 1")
+	fs.Int32Var(&req.PageSize, "page-size", 10, "authors per page")
+	return cmd
+}
+
+func newAuthorCreateCmd() *cobra.Command {
+	var req authorpb.CreateAuthorRequest
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Add an author",
+		Example: `  bookctl author create --name "Rob Pike" --country Canada --birth-year 1956`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreateAuthor(cmd.Context(), &req)
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { writeAuthors(w, resp.Author) })
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&req.Name, "name", "", "name")
+	fs.StringVar(&req.Bio, "bio", "", "short biography")
+	fs.Int32Var(&req.BirthYear, "birth-year", 0, "year of birth")
+	fs.StringVar(&req.Country, "country", "", "country")
+	return cmd
+}
+
+func newAuthorDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete an author",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.DeleteAuthor(cmd.Context(), &authorpb.DeleteAuthorRequest{Id: id})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { fmt.Fprintln(w, resp.Message) })
+		},
+	}
+}
+
+func newAuthorBooksCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "books ID",
+		Short: "Show an author with their books, fetched from Book service",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetAuthorBooks(cmd.Context(), &authorpb.GetAuthorBooksRequest{AuthorId: id})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				fmt.Fprintf(w, "%s, %d books\n\n", resp.Author.Name, resp.BookCount)
+				fmt.Fprintln(w, "ID\tTITLE\tYEAR\tPRICE")
+				for _, b := range resp.Books {
+					fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", b.Id, b.Title, b.PublishedYear, b.Price.Format())
+				}
+				if resp.BookServiceStatus != authorpb.GetAuthorBooksResponse_OK {
+					fmt.Fprintf(w, "\n⚠️ Book list may be incomplete: Book service %s\n", resp.BookServiceStatus)
+				}
+			})
+		},
+	}
+}
+
+func newAuthorStatsCmd() *cobra.Command {
+	var page, pageSize int32
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show each author's book count and inventory value",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := authorClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.AuthorStats(cmd.Context(), &authorpb.AuthorStatsRequest{Page: page, PageSize: pageSize})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tNAME\tBOOKS\tNEWEST\tINVENTORY VALUE")
+				for _, s := range resp.Stats {
+					value := formatAmounts(s.InventoryValues)
+					if s.BookServiceStatus != authorpb.GetAuthorBooksResponse_OK {
+						value = "? (Book service " + s.BookServiceStatus.String() + ")"
+					}
+					fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\n", s.Author.Id, s.Author.Name, s.BookCount, s.NewestYear, value)
+				}
+				fmt.Fprintf(w, "\n%d authors in all\n", resp.Total)
+			})
+		},
+	}
+	cmd.Flags().Int32Var(&page, "page", 1, "page number, from 1")
+	cmd.Flags().Int32Var(&pageSize, "page-size", 10, "authors per page")
+	return cmd
+}
+
+// writeAuthors writes authors as a table under a header row.
+func writeAuthors(w io.Writer, authors ...*authorpb.Author) {
+	fmt.Fprintln(w, "ID\tNAME\tBORN\tCOUNTRY")
+	for _, a := range authors {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", a.Id, a.Name, a.BirthYear, a.Country)
+	}
+}
@@ -0,0 +1,596 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	bookpb "book-catalog-grpc/proto"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// bookFlags are the Book fields create and update take.
+type bookFlags struct {
+	title, author, isbn string
+	price, currency     string
+	stock, year         int32
+	authorID            int32
+	category            string
+}
+
+func (f *bookFlags) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.title, "title", "", "title")
+	fs.StringVar(&f.author, "author", "", "author name")
+	fs.StringVar(&f.isbn, "isbn", "", "ISBN-10 or ISBN-13")
+	fs.StringVar(&f.price, "price", "", "price as a decimal, e.g. 44.99")
+	fs.StringVar(&f.currency, "currency", bookpb.DefaultCurrency, "ISO 4217 currency of --price")
+	fs.Int32Var(&f.stock, "stock", 0, "copies in stock")
+	fs.Int32Var(&f.year, "year", 0, "published year")
+	fs.Int32Var(&f.authorID, "author-id", 0, "id of the book's Author")
+	fs.StringVar(&f.category, "category", "", "category, e.g. FICTION or scifi")
+}
+
+// maskPaths maps the flags of bookFlags to update_mask paths.
+var maskPaths = map[string]string{
+	"title":     "title",
+	"author":    "author",
+	"isbn":      "isbn",
+	"price":     "price",
+	"currency":  "price",
+	"stock":     "stock",
+	"year":      "published_year",
+	"author-id": "author_id",
+	"category":  "category",
+}
+
+// money returns --price in --currency, or nil without --price.
+func (f *bookFlags) money() (*bookpb.Money, error) {
+	if f.price == "" {
+		return nil, nil
+	}
+	return bookpb.ParseMoney(strings.ToUpper(f.currency), f.price)
+}
+
+func parseCategory(s string) (bookpb.BookCategory, error) {
+	if s == "" {
+		return bookpb.BookCategory_UNKNOWN, nil
+	}
+	c, ok := bookpb.BookCategory_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown category %q", s)
+	}
+	return bookpb.BookCategory(c), nil
+}
+
+func newGetCmd() *cobra.Command {
+	var rating bool
+	cmd := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show one book",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetBook(cmd.Context(), &bookpb.GetBookRequest{Id: id, IncludeRating: rating})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeBooks(w, resp.Book)
+				if resp.AuthorName != "" {
+					fmt.Fprintf(w, "\nAuthor: %s (%s)\n", resp.AuthorName, resp.AuthorCountry)
+				}
+				if r := resp.Rating; r != nil {
+					fmt.Fprintf(w, "\nRating: %.1f/5 from %d reviews\n", r.Average, r.ReviewCount)
+				}
+				if b := resp.Book; b.CreatedAt != nil {
+					fmt.Fprintf(w, "\nCreated %s, updated %s\n",
+						b.CreatedAt.AsTime().Local().Format(time.DateTime), b.UpdatedAt.AsTime().Local().Format(time.DateTime))
+				}
+				if resp.Book.Etag != "" {
+					fmt.Fprintf(w, "ETag: %s\n", resp.Book.Etag)
+				}
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&rating, "rating", false, "also show the average review rating")
+	return cmd
+}
+
+func newCreateCmd() *cobra.Command {
+	var f bookFlags
+	var requestID string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Add a book",
+		Example: `  bookctl create --title "Learning Go" --author "Jon Bodner" --isbn 978-1492077213 \
+      --price 44.99 --stock 30 --year 2021 --category nonfiction`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			price, err := f.money()
+			if err != nil {
+				return err
+			}
+			category, err := parseCategory(f.category)
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreateBook(cmd.Context(), &bookpb.CreateBookRequest{
+				Title:         f.title,
+				Author:        f.author,
+				Isbn:          f.isbn,
+				Price:         price,
+				Stock:         f.stock,
+				PublishedYear: f.year,
+				AuthorId:      f.authorID,
+				Category:      category,
+				RequestId:     requestID,
+			})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { writeBooks(w, resp.Book) })
+		},
+	}
+	f.register(cmd.Flags())
+	cmd.Flags().StringVar(&requestID, "request-id", "", "idempotency key: retrying with the same key creates the book once")
+	return cmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	var f bookFlags
+	var replace bool
+	var etag string
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the given fields of a book",
+		Long: `update writes only the fields whose flags are given, using an
+update_mask. With --replace it sends every field without a mask, so fields
+not given are cleared; use that against the Task3/Task4 servers, which do not
+support update_mask.
+
+The update is refused with Aborted if the book has changed since the etag
+was read. Without --etag, update reads the book first and sends its current
+etag; pass the etag "get" printed to be sure nothing changed in between.`,
+		Example: "  bookctl update 1 --price 39.99 --stock 20",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			price, err := f.money()
+			if err != nil {
+				return err
+			}
+			category, err := parseCategory(f.category)
+			if err != nil {
+				return err
+			}
+			req := &bookpb.UpdateBookRequest{
+				Id:            id,
+				Title:         f.title,
+				Author:        f.author,
+				Isbn:          f.isbn,
+				Price:         price,
+				Stock:         f.stock,
+				PublishedYear: f.year,
+				AuthorId:      f.authorID,
+				Category:      category,
+			}
+			if !replace {
+				mask := &fieldmaskpb.FieldMask{}
+				seen := map[string]bool{}
+				cmd.Flags().Visit(func(fl *pflag.Flag) {
+					if path, ok := maskPaths[fl.Name]; ok && !seen[path] {
+						seen[path] = true
+						mask.Paths = append(mask.Paths, path)
+					}
+				})
+				if len(mask.Paths) == 0 {
+					return fmt.Errorf("nothing to update: give at least one field flag")
+				}
+				if seen["price"] && f.price == "" {
+					return fmt.Errorf("--currency needs --price")
+				}
+				req.UpdateMask = mask
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			req.Etag = etag
+			if etag == "" {
+				current, err := client.GetBook(cmd.Context(), &bookpb.GetBookRequest{Id: id})
+				if err != nil {
+					return err
+				}
+				req.Etag = current.Book.Etag
+			}
+			resp, err := client.UpdateBook(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { writeBooks(w, resp.Book) })
+		},
+	}
+	f.register(cmd.Flags())
+	cmd.Flags().BoolVar(&replace, "replace", false, "send every field without an update_mask")
+	cmd.Flags().StringVar(&etag, "etag", "", "etag of the book as last read (default: the current one)")
+	return cmd
+}
+
+func newDeleteCmd() *cobra.Command {
+	var hard bool
+	cmd := &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a book, so that undelete can restore it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.DeleteBook(cmd.Context(), &bookpb.DeleteBookRequest{Id: id, Hard: hard})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { fmt.Fprintln(w, resp.Message) })
+		},
+	}
+	cmd.Flags().BoolVar(&hard, "hard", false, "remove the book for good; the Task3 and Task4 servers need it")
+	return cmd
+}
+
+func newUndeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "undelete ID",
+		Short: "Restore a book deleted without --hard",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.UndeleteBook(cmd.Context(), &bookpb.UndeleteBookRequest{Id: id})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) { writeBooks(w, resp.Book) })
+		},
+	}
+}
+
+// filterFlags are the FilterBooksRequest fields filter and list take.
+type filterFlags struct {
+	minPrice, maxPrice, currency string
+	minYear, maxYear             int32
+	categories                   []string
+	inStock                      bool
+	minStock, maxStock           int32
+	authorID                     int32
+}
+
+func (f *filterFlags) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.minPrice, "min-price", "", "lowest price, e.g. 20")
+	fs.StringVar(&f.maxPrice, "max-price", "", "highest price, e.g. 50")
+	fs.StringVar(&f.currency, "currency", bookpb.DefaultCurrency, "currency of --min-price and --max-price")
+	fs.Int32Var(&f.minYear, "min-year", 0, "published in or after this year")
+	fs.Int32Var(&f.maxYear, "max-year", 0, "published in or before this year")
+	fs.StringSliceVar(&f.categories, "category", nil, "categories to keep; repeat or separate with commas")
+	fs.BoolVar(&f.inStock, "in-stock", false, "only books with stock")
+	fs.Int32Var(&f.minStock, "min-stock", 0, "at least this many in stock")
+	fs.Int32Var(&f.maxStock, "max-stock", 0, "at most this many in stock")
+	fs.Int32Var(&f.authorID, "author-id", 0, "only books by this Author")
+}
+
+// request returns the filter, or nil when no filter flag is given, so that
+// list works against servers without filters.
+func (f *filterFlags) request() (*bookpb.FilterBooksRequest, error) {
+	r := &bookpb.FilterBooksRequest{
+		MinYear:     f.minYear,
+		MaxYear:     f.maxYear,
+		InStockOnly: f.inStock,
+		MinStock:    f.minStock,
+		MaxStock:    f.maxStock,
+		AuthorId:    f.authorID,
+	}
+	currency := strings.ToUpper(f.currency)
+	var err error
+	if f.minPrice != "" {
+		if r.MinPrice, err = bookpb.ParseMoney(currency, f.minPrice); err != nil {
+			return nil, err
+		}
+	}
+	if f.maxPrice != "" {
+		if r.MaxPrice, err = bookpb.ParseMoney(currency, f.maxPrice); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range f.categories {
+		c, err := parseCategory(s)
+		if err != nil {
+			return nil, err
+		}
+		r.Categories = append(r.Categories, c)
+	}
+	if proto.Equal(r, &bookpb.FilterBooksRequest{}) {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func newListCmd() *cobra.Command {
+	var f filterFlags
+	var page, pageSize int32
+	var orderBy, updatedSince string
+	var stream bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List books a page at a time, or stream them all",
+		Example: `  bookctl list --page 2 --page-size 5 --order-by "price desc"
+  bookctl list --stream --category scifi --in-stock
+  bookctl list --updated-since 1h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, err := f.request()
+			if err != nil {
+				return err
+			}
+			since, err := parseSince(updatedSince)
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			req := &bookpb.ListBooksRequest{Page: page, PageSize: pageSize, OrderBy: orderBy, Filter: filter, UpdatedSince: since}
+			if !stream {
+				resp, err := client.ListBooks(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				return show(resp, func(w io.Writer) {
+					writeBooks(w, resp.Books...)
+					fmt.Fprintf(w, "\nPage %d of %d, %d per page, %d books in all\n", resp.Page, resp.TotalPages, resp.PageSize, resp.Total)
+					if resp.HasNext {
+						fmt.Fprintf(w, "More with --page %d\n", resp.Page+1)
+					}
+				})
+			}
+
+			books, err := client.StreamBooks(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			w := newTable()
+			if output == "table" {
+				fmt.Fprintln(w, bookHeader)
+			}
+			for {
+				book, err := books.Recv()
+				if err == io.EOF {
+					return w.Flush()
+				}
+				if err != nil {
+					w.Flush()
+					return err
+				}
+				// A stream prints a JSON object per line, as it arrives.
+				if output == "json" {
+					if err := printJSON(book, false); err != nil {
+						return err
+					}
+					continue
+				}
+				writeBookRow(w, book)
+			}
+		},
+	}
+	fs := cmd.Flags()
+	fs.Int32Var(&page, "page", 1, "page number, from 1")
+	fs.Int32Var(&pageSize, "page-size", 10, "books per page")
+	fs.StringVar(&orderBy, "order-by", "", `sort order, e.g. "price desc, title"`)
+	fs.BoolVar(&stream, "stream", false, "stream every matching book with StreamBooks instead of paging")
+	fs.StringVar(&updatedSince, "updated-since", "", "only books created or updated since this RFC 3339 time, or this long ago, e.g. 1h")
+	f.register(fs)
+	return cmd
+}
+
+func newSearchCmd() *cobra.Command {
+	var field string
+	var fuzzy bool
+	cmd := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "Search books by title, author or ISBN",
+		Example: `  bookctl search "go prog"
+  bookctl search --fuzzy --field author "Donovan"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.SearchBooks(cmd.Context(), &bookpb.SearchBooksRequest{Query: args[0], Field: field, Fuzzy: fuzzy})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeBooks(w, resp.Books...)
+				fmt.Fprintf(w, "\n%d books match %q\n", resp.Count, resp.Query)
+				for _, hit := range resp.Hits {
+					if hit.Snippet != "" {
+						fmt.Fprintf(w, "  %d: %s\n", hit.BookId, hit.Snippet)
+					}
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&field, "field", "all", "field to search: title, author, isbn or all")
+	cmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "tolerate typos")
+	return cmd
+}
+
+func newFilterCmd() *cobra.Command {
+	var f filterFlags
+	cmd := &cobra.Command{
+		Use:     "filter",
+		Short:   "List the books matching every given filter",
+		Example: "  bookctl filter --min-price 20 --max-price 50 --category fiction,scifi --in-stock",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := f.request()
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.FilterBooks(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				writeBooks(w, resp.Books...)
+				fmt.Fprintf(w, "\n%d books\n", resp.Count)
+			})
+		},
+	}
+	f.register(cmd.Flags())
+	return cmd
+}
+
+func newStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show catalog statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetStats(cmd.Context(), &bookpb.GetStatsRequest{})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				fmt.Fprintf(w, "Books:\t%d\n", resp.TotalBooks)
+				fmt.Fprintf(w, "Copies in stock:\t%d\n", resp.TotalStock)
+				fmt.Fprintf(w, "Average price:\t%s\n", formatAmounts(resp.AveragePrices))
+				fmt.Fprintf(w, "Years:\t%d-%d\n", resp.EarliestYear, resp.LatestYear)
+			})
+		},
+	}
+}
+
+// importChunkSize splits a file into chunks well below gRPC's 4 MB message
+// limit.
+const importChunkSize = 32 * 1024
+
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import FILE",
+		Short: "Add the books of a CSV file with ImportBooks",
+		Long: `import streams FILE ("-" for standard input) to ImportBooks. Its
+header row names the columns: title, author, isbn, price, currency, stock,
+published_year, category, as export --format csv writes them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			return importCSV(cmd.Context(), client, string(data), importChunkSize)
+		},
+	}
+}
+
+func newExportCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Write the whole catalog to standard output with ExportBooks",
+		Example: "  bookctl export --format csv > books.csv",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, ok := bookpb.ExportRequest_Format_value[strings.ToUpper(format)]
+			if !ok {
+				return fmt.Errorf("--format must be csv or jsonl, got %q", format)
+			}
+			client, err := bookClient()
+			if err != nil {
+				return err
+			}
+			stream, err := client.ExportBooks(cmd.Context(), &bookpb.ExportRequest{Format: bookpb.ExportRequest_Format(f)})
+			if err != nil {
+				return err
+			}
+			for {
+				chunk, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if _, err := os.Stdout.Write(chunk.Data); err != nil {
+					return err
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "csv", "csv or jsonl")
+	return cmd
+}
+
+const bookHeader = "ID\tTITLE\tAUTHOR\tPRICE\tSTOCK\tYEAR\tCATEGORY"
+
+// writeBooks writes books as a table under a header row.
+func writeBooks(w io.Writer, books ...*bookpb.Book) {
+	fmt.Fprintln(w, bookHeader)
+	for _, b := range books {
+		writeBookRow(w, b)
+	}
+}
+
+func writeBookRow(w io.Writer, b *bookpb.Book) {
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\n",
+		b.Id, b.Title, b.Author, b.Price.Format(), b.Stock, b.PublishedYear, b.Category)
+}
@@ -0,0 +1,772 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	authorpb "book-catalog-grpc/proto"
+	bookpb "book-catalog-grpc/proto"
+	reviewpb "book-catalog-grpc/proto"
+	bookv2pb "book-catalog-grpc/proto/bookcatalog/v2"
+	"book-catalog-grpc/requestid"
+	"book-catalog-grpc/rpcerr"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func newDemoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "demo",
+		Short: "Walk through every Task5 feature against Book and Author service",
+		Long: `demo runs the Task5 microservice walk-through: an author saga,
+cross-service calls, streams, import/export, search, reviews, API v2 and
+more, printing each step. It writes to both services' databases.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bookConn, err := dialBooks()
+			if err != nil {
+				return err
+			}
+			authorConn, err := dialAuthors()
+			if err != nil {
+				return err
+			}
+			runDemo(cmd.Context(), bookConn, authorConn)
+			return nil
+		},
+	}
+}
+
+func runDemo(ctx context.Context, bookConn, authorConn *grpc.ClientConn) {
+	// CRUD and listing go through bookcatalog.v2; the RPCs v2 does not have
+	// yet (streams, search, import/export) stay on v1, served on the same
+	// connection.
+	bookClient := bookpb.NewBookCatalogClient(bookConn)
+	catalog := bookv2pb.NewBookCatalogClient(bookConn)
+	authorClient := authorpb.NewAuthorCatalogClient(authorConn)
+
+	fmt.Println("=== Microservice Demo ===")
+	fmt.Println()
+
+	// Collect Book service lifecycle events in the background for step 22
+	eventsCtx, stopEvents := context.WithCancel(ctx)
+	defer stopEvents()
+	received, err := collectEvents(eventsCtx, bookClient)
+	if err != nil {
+		log.Printf("Failed to subscribe to book events: %v", err)
+	}
+
+	// 1-2. Create the author and their books in one saga on Author service;
+	// if any book fails, Author service rolls the whole thing back.
+	fmt.Println("1. Creating author with books (saga)...")
+	authorResp, err := authorClient.CreateAuthorWithBooks(ctx, &authorpb.CreateAuthorWithBooksRequest{
+		Author: &authorpb.CreateAuthorRequest{
+			Name:      "Martin Fowler",
+			Bio:       "Software development expert",
+			BirthYear: 1963,
+			Country:   "UK",
+		},
+		Books: []*authorpb.NewBook{
+			{
+				Title:         "Refactoring",
+				Isbn:          "978-0134757599",
+				Price:         &bookpb.Money{CurrencyCode: "USD", Units: 49, Nanos: 990000000},
+				Stock:         15,
+				PublishedYear: 2018,
+			},
+			{
+				Title:         "Patterns of Enterprise Application Architecture",
+				Isbn:          "978-0321127426",
+				Price:         &bookpb.Money{CurrencyCode: "EUR", Units: 54, Nanos: 990000000},
+				Stock:         8,
+				PublishedYear: 2002,
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create author with books: %v", err)
+	} else {
+		fmt.Printf("✓ Created author: %s (ID: %d)\n\n",
+			authorResp.Author.Name, authorResp.Author.Id)
+
+		fmt.Println("2. Books created for author...")
+		for _, book := range authorResp.Books {
+			fmt.Printf("✓ Created book: %s\n", book.Title)
+		}
+		fmt.Println()
+
+		// 3. Get author's books (cross-service call)
+		fmt.Println("3. Fetching author's books (cross-service call)...")
+		// Author service logs its own line and the Book service call under
+		// the request id it returns in the headers.
+		var header metadata.MD
+		booksResp, err := authorClient.GetAuthorBooks(ctx, &authorpb.GetAuthorBooksRequest{
+			AuthorId: authorResp.Author.Id,
+		}, grpc.Header(&header))
+		if err != nil {
+			log.Printf("Failed: %v", err)
+		} else {
+			if ids := header.Get(requestid.Header); len(ids) > 0 {
+				fmt.Printf("✓ Request id: %s (look for req=%s in both services' logs)\n", ids[0], ids[0])
+			}
+			fmt.Printf("✓ Author: %s\n", booksResp.Author.Name)
+			fmt.Printf("✓ Books written: %d\n", booksResp.BookCount)
+			if booksResp.BookServiceStatus != authorpb.GetAuthorBooksResponse_OK {
+				fmt.Printf("⚠️ Book list may be incomplete: Book service %s\n", booksResp.BookServiceStatus)
+			}
+			for i, book := range booksResp.Books {
+				fmt.Printf("  %d. %s (%d) - %s\n", i+1, book.Title, book.PublishedYear, book.Price.Format())
+			}
+		}
+	}
+
+	// 4. List all authors
+	fmt.Println("\n4. Listing all authors...")
+	authorsResp, err := authorClient.ListAuthors(ctx, &authorpb.ListAuthorsRequest{
+		Page:     1,
+		PageSize: 10,
+	})
+	if err != nil {
+		log.Printf("Failed to list authors: %v", err)
+	} else {
+		fmt.Printf("✓ Total authors: %d\n", authorsResp.Total)
+		for i, author := range authorsResp.Authors {
+			fmt.Printf("  %d. %s (%d, %s)\n", i+1, author.Name, author.BirthYear, author.Country)
+		}
+	}
+
+	// 5. Get statistics from Book service
+	fmt.Println("\n5. Getting book statistics...")
+	statsResp, err := bookClient.GetStats(ctx, &bookpb.GetStatsRequest{})
+	if err != nil {
+		log.Printf("Failed to get stats: %v", err)
+	} else {
+		fmt.Printf("✓ Total books: %d\n", statsResp.TotalBooks)
+		fmt.Printf("✓ Average price: %s\n", formatAmounts(statsResp.AveragePrices))
+		fmt.Printf("✓ Total stock: %d\n", statsResp.TotalStock)
+		fmt.Printf("✓ Year range: %d - %d\n", statsResp.EarliestYear, statsResp.LatestYear)
+	}
+
+	// 6. Stream the whole catalog from Book service
+	fmt.Println("\n6. Streaming the catalog...")
+	var watchID int32
+	stream, err := bookClient.StreamBooks(ctx, &bookpb.ListBooksRequest{})
+	if err != nil {
+		log.Printf("Failed to stream books: %v", err)
+	} else {
+		count := 0
+		for {
+			book, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("Stream interrupted: %v", err)
+				break
+			}
+			count++
+			if watchID == 0 {
+				watchID = book.Id
+			}
+			fmt.Printf("  %d. %s - %s\n", count, book.Title, book.Price.Format())
+		}
+		fmt.Printf("✓ Streamed %d books\n", count)
+	}
+
+	// 7. Watch a book while its price changes
+	if watchID != 0 {
+		fmt.Println("\n7. Watching price changes...")
+		if err := watchPriceChange(ctx, bookClient, catalog, watchID); err != nil {
+			log.Printf("Watch failed: %v", err)
+		}
+	}
+
+	// 8. Probe both services with the standard health check
+	fmt.Println("\n8. Checking service health...")
+	for _, probe := range []struct {
+		conn    *grpc.ClientConn
+		service string
+	}{
+		{bookConn, bookpb.BookCatalog_ServiceDesc.ServiceName},
+		{authorConn, authorpb.AuthorCatalog_ServiceDesc.ServiceName},
+	} {
+		resp, err := healthpb.NewHealthClient(probe.conn).Check(ctx, &healthpb.HealthCheckRequest{Service: probe.service})
+		if err != nil {
+			log.Printf("Health check of %s failed: %v", probe.service, err)
+			continue
+		}
+		fmt.Printf("✓ %s: %s\n", probe.service, resp.Status)
+	}
+
+	// 9. Update and delete an author; one with books cannot be deleted
+	fmt.Println("\n9. Updating and deleting authors...")
+	tempResp, err := authorClient.CreateAuthor(ctx, &authorpb.CreateAuthorRequest{
+		Name:      "Temp Author",
+		BirthYear: 1990,
+		Country:   "VN",
+	})
+	if err != nil {
+		log.Printf("Failed to create author: %v", err)
+	} else {
+		updated, err := authorClient.UpdateAuthor(ctx, &authorpb.UpdateAuthorRequest{
+			Id:        tempResp.Author.Id,
+			Name:      "Temp Author",
+			Bio:       "Created to be deleted",
+			BirthYear: 1990,
+			Country:   "US",
+		})
+		if err != nil {
+			log.Printf("Failed to update author: %v", err)
+		} else {
+			fmt.Printf("✓ Updated author %d: country %s\n", updated.Author.Id, updated.Author.Country)
+		}
+		deleted, err := authorClient.DeleteAuthor(ctx, &authorpb.DeleteAuthorRequest{Id: tempResp.Author.Id})
+		if err != nil {
+			log.Printf("Failed to delete author: %v", err)
+		} else {
+			fmt.Printf("✓ %s\n", deleted.Message)
+		}
+	}
+	if authorResp != nil {
+		_, err := authorClient.DeleteAuthor(ctx, &authorpb.DeleteAuthorRequest{Id: authorResp.Author.Id})
+		if status.Code(err) == codes.FailedPrecondition {
+			fmt.Printf("✓ Expected error: %s\n", rpcerr.Describe(err))
+		} else {
+			log.Printf("Deleting %s: expected FailedPrecondition, got %v", authorResp.Author.Name, err)
+		}
+	}
+
+	// 10. Search authors by country and birth year
+	fmt.Println("\n10. Searching authors from the UK born after 1950...")
+	searchResp, err := authorClient.SearchAuthors(ctx, &authorpb.SearchAuthorsRequest{
+		Country:      "UK",
+		MinBirthYear: 1950,
+		PageSize:     5,
+	})
+	if err != nil {
+		log.Printf("Failed to search authors: %v", err)
+	} else {
+		fmt.Printf("✓ Found %d authors\n", searchResp.Total)
+		for i, author := range searchResp.Authors {
+			fmt.Printf("  %d. %s (%d, %s)\n", i+1, author.Name, author.BirthYear, author.Country)
+		}
+	}
+
+	// 11. Import books from CSV; the bad row is reported, the rest imported
+	fmt.Println("\n11. Importing books from CSV...")
+	if err := importCSV(ctx, bookClient, sampleCSV, demoChunkSize); err != nil {
+		log.Printf("Import failed: %v", err)
+	}
+	fmt.Println("Filtering NONFICTION books from 2015 on...")
+	filtered, err := bookClient.FilterBooks(ctx, &bookpb.FilterBooksRequest{
+		MinYear:    2015,
+		Categories: []bookpb.BookCategory{bookpb.BookCategory_NONFICTION},
+	})
+	if err != nil {
+		log.Printf("Failed to filter books: %v", err)
+	} else {
+		for i, book := range filtered.Books {
+			fmt.Printf("  %d. %s (%d, %s)\n", i+1, book.Title, book.PublishedYear, book.Category)
+		}
+	}
+	// ListBooks takes the same filter, and pages and sorts what it matches
+	fmt.Println("Listing books with at least 15 in stock, most stocked first...")
+	stocked, err := bookClient.ListBooks(ctx, &bookpb.ListBooksRequest{
+		PageSize: 3,
+		OrderBy:  "stock desc",
+		Filter:   &bookpb.FilterBooksRequest{InStockOnly: true, MinStock: 15},
+	})
+	if err != nil {
+		log.Printf("Failed to list books: %v", err)
+	} else {
+		for i, book := range stocked.Books {
+			fmt.Printf("  %d. %s (%d in stock)\n", i+1, book.Title, book.Stock)
+		}
+		fmt.Printf("✓ %d of %d matching books, page 1 of %d\n", len(stocked.Books), stocked.Total, stocked.TotalPages)
+	}
+
+	// 12. Export the catalog as CSV
+	fmt.Println("\n12. Exporting the catalog as CSV...")
+	if err := exportCSV(ctx, bookClient); err != nil {
+		log.Printf("Export failed: %v", err)
+	}
+
+	// 13. Full-text search, best match first
+	fmt.Println("\n13. Searching books for \"go prog\"...")
+	found, err := bookClient.SearchBooks(ctx, &bookpb.SearchBooksRequest{Query: "go prog"})
+	if err != nil {
+		log.Printf("Failed to search books: %v", err)
+	} else {
+		fmt.Printf("✓ Found %d books\n", found.Count)
+		for i, hit := range found.Hits {
+			fmt.Printf("  %d. %s (relevance %.2f)\n", i+1, hit.Snippet, hit.Relevance)
+		}
+	}
+	fmt.Println("Fuzzy search for \"Pragmtic Programer\"...")
+	found, err = bookClient.SearchBooks(ctx, &bookpb.SearchBooksRequest{Query: "Pragmtic Programer", Fuzzy: true})
+	if err != nil {
+		log.Printf("Failed to search books: %v", err)
+	} else {
+		for i, hit := range found.Hits {
+			fmt.Printf("  %d. %s (similarity %.2f)\n", i+1, hit.Snippet, hit.Similarity)
+		}
+	}
+
+	// 14. Get an alert when a book's stock drops below 5
+	if watchID != 0 {
+		fmt.Println("\n14. Watching for low stock...")
+		if err := watchLowStock(ctx, bookClient, catalog, watchID, 5); err != nil {
+			log.Printf("Low-stock watch failed: %v", err)
+		}
+	}
+
+	// 15. Review a book on Review service, which shares Book service's port
+	if watchID != 0 {
+		fmt.Println("\n15. Reviewing a book...")
+		if err := reviewBook(ctx, bookClient, reviewpb.NewReviewCatalogClient(bookConn), watchID); err != nil {
+			log.Printf("Review failed: %v", err)
+		}
+	}
+
+	// 16. Per-author stats, aggregated by Author service from Book service
+	fmt.Println("\n16. Author statistics (cross-service aggregation)...")
+	statsPage, err := authorClient.AuthorStats(ctx, &authorpb.AuthorStatsRequest{PageSize: 10})
+	if err != nil {
+		log.Printf("Failed to get author stats: %v", err)
+	} else {
+		for i, stat := range statsPage.Stats {
+			if stat.BookServiceStatus != authorpb.GetAuthorBooksResponse_OK {
+				fmt.Printf("  %d. %s: ⚠️ Book service %s\n", i+1, stat.Author.Name, stat.BookServiceStatus)
+				continue
+			}
+			fmt.Printf("  %d. %s: %d books, inventory %s, newest %d\n",
+				i+1, stat.Author.Name, stat.BookCount, formatAmounts(stat.InventoryValues), stat.NewestYear)
+		}
+	}
+
+	// 17. Page through the catalog with the v2 API's page tokens
+	fmt.Println("\n17. Paging through the catalog (bookcatalog.v2)...")
+	if err := pageBooks(ctx, catalog, 5); err != nil {
+		log.Printf("Paging failed: %v", err)
+	}
+
+	// 18. Send the same CreateBook twice, as a retry after a lost response
+	// would; the request_id makes the second call return the first book
+	fmt.Println("\n18. Creating a book twice with one request_id...")
+	deletedID, err := createTwice(ctx, catalog)
+	if err != nil {
+		log.Printf("Idempotent create failed: %v", err)
+	}
+
+	// 19. DeleteBook only marks the book deleted, so it can be brought back
+	if deletedID != 0 {
+		fmt.Println("\n19. Restoring the deleted book...")
+		if err := undelete(ctx, bookClient, deletedID); err != nil {
+			log.Printf("Undelete failed: %v", err)
+		}
+	}
+
+	// 20. Two clients update the same book from one read: the etag makes
+	// the second one fail instead of undoing the first
+	if watchID != 0 {
+		fmt.Println("\n20. Updating a book with a stale etag...")
+		if err := staleUpdate(ctx, catalog, watchID); err != nil {
+			log.Printf("Stale update failed: %v", err)
+		}
+	}
+
+	// 21. A bad request comes back with a google.rpc.BadRequest naming
+	// every field that failed, not just a message
+	fmt.Println("\n21. Creating an invalid book...")
+	_, err = catalog.CreateBook(ctx, &bookv2pb.CreateBookRequest{
+		Book: &bookv2pb.Book{Title: "Untitled", Isbn: "123", Price: &bookpb.Money{CurrencyCode: "USD", Units: -5}, PublishedYear: 1200},
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		fmt.Printf("✓ Expected error: %s\n", rpcerr.Describe(err))
+	} else {
+		log.Printf("Creating an invalid book: expected InvalidArgument, got %v", err)
+	}
+
+	// 22. Show the lifecycle events Book service published during the demo
+	if received != nil {
+		fmt.Println("\n22. Book lifecycle events received...")
+		stopEvents()
+		for _, ev := range <-received {
+			fmt.Printf("  %s book %d\n", ev.Type, ev.BookId)
+		}
+	}
+
+	fmt.Println("\n✅ Microservice demo completed successfully!")
+	fmt.Println("📊 Demonstrated:")
+	fmt.Println("   - Service-to-service communication (Author → Book)")
+	fmt.Println("   - CRUD operations across multiple services")
+	fmt.Println("   - Cross-service data aggregation")
+	fmt.Println("   - A third service (Review) sharing Book service's database")
+	fmt.Println("   - API v1 and v2 served side by side")
+}
+
+// collectEvents subscribes to Book service lifecycle events. Once ctx is
+// cancelled, the returned channel yields everything received.
+func collectEvents(ctx context.Context, client bookpb.BookCatalogClient) (<-chan []*bookpb.LifecycleEvent, error) {
+	stream, err := client.SubscribeEvents(ctx, &bookpb.SubscribeEventsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	// The server sends headers once it has subscribed, so no write made
+	// after this returns is missed.
+	if _, err := stream.Header(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan []*bookpb.LifecycleEvent, 1)
+	go func() {
+		var got []*bookpb.LifecycleEvent
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				done <- got
+				return
+			}
+			got = append(got, ev)
+		}
+	}()
+	return done, nil
+}
+
+// watchPriceChange subscribes to one book, raises its price by a dollar with
+// a partial update and prints the event the server pushes back, then
+// restores the price.
+func watchPriceChange(ctx context.Context, client bookpb.BookCatalogClient, catalog bookv2pb.BookCatalogClient, id int32) error {
+	watch, err := client.WatchBooks(ctx)
+	if err != nil {
+		return err
+	}
+	err = watch.Send(&bookpb.WatchRequest{Action: bookpb.WatchRequest_SUBSCRIBE, BookIds: []int32{id}})
+	if err != nil {
+		return err
+	}
+	snapshot, err := watch.Recv()
+	if err != nil {
+		return err
+	}
+	book := snapshot.Book
+	fmt.Printf("✓ Subscribed to %q at %s\n", book.Title, book.Price.Format())
+
+	// Only price is in the mask, so the other fields can be left empty.
+	// Each update returns the etag the next one must send.
+	etag := book.Etag
+	update := func(price *bookpb.Money) error {
+		updated, err := catalog.UpdateBook(ctx, &bookv2pb.UpdateBookRequest{
+			Book:       &bookv2pb.Book{Id: book.Id, Price: price, Etag: etag},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"price"}},
+		})
+		if err != nil {
+			return err
+		}
+		etag = updated.Etag
+		return nil
+	}
+	if err := update(book.Price.Plus(&bookpb.Money{CurrencyCode: book.Price.CurrencyCode, Units: 1})); err != nil {
+		return err
+	}
+	ev, err := watch.Recv()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %s: %s → %s\n", ev.Type, ev.OldPrice.Format(), ev.Book.Price.Format())
+
+	if err := update(book.Price); err != nil {
+		return err
+	}
+	watch.CloseSend()
+	for {
+		ev, err := watch.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s: %s → %s\n", ev.Type, ev.OldPrice.Format(), ev.Book.Price.Format())
+	}
+}
+
+// watchLowStock subscribes to low-stock alerts, sells off the book's stock
+// down to 2 with a partial update, prints the alert and restores the stock.
+func watchLowStock(ctx context.Context, client bookpb.BookCatalogClient, catalog bookv2pb.BookCatalogClient, id, threshold int32) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	alerts, err := client.WatchLowStock(ctx, &bookpb.WatchLowStockRequest{Threshold: threshold})
+	if err != nil {
+		return err
+	}
+	if _, err := alerts.Header(); err != nil {
+		return err
+	}
+
+	current, err := catalog.GetBook(ctx, &bookv2pb.GetBookRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	etag := current.Etag
+	setStock := func(stock int32) (*bookv2pb.Book, error) {
+		updated, err := catalog.UpdateBook(ctx, &bookv2pb.UpdateBookRequest{
+			Book:       &bookv2pb.Book{Id: id, Stock: stock, Etag: etag},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"stock"}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		etag = updated.Etag
+		return updated, nil
+	}
+	if _, err := setStock(2); err != nil {
+		return err
+	}
+	defer setStock(current.Stock)
+
+	alert, err := alerts.Recv()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %q is low on stock: %d → %d (threshold %d)\n",
+		alert.Book.Title, alert.OldStock, alert.Book.Stock, alert.Threshold)
+	return nil
+}
+
+// reviewBook adds two reviews to a book, lists its reviews and reads the
+// book back with its average rating embedded.
+func reviewBook(ctx context.Context, books bookpb.BookCatalogClient, reviews reviewpb.ReviewCatalogClient, id int32) error {
+	for _, r := range []*reviewpb.AddReviewRequest{
+		{BookId: id, Reviewer: "alice", Rating: 5, Comment: "A classic"},
+		{BookId: id, Reviewer: "bob", Rating: 4, Comment: "Dense but worth it"},
+	} {
+		if _, err := reviews.AddReview(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	stream, err := reviews.ListReviews(ctx, &reviewpb.ListReviewsRequest{BookId: id})
+	if err != nil {
+		return err
+	}
+	for {
+		review, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s: %d★ %q\n", review.Reviewer, review.Rating, review.Comment)
+	}
+
+	resp, err := books.GetBook(ctx, &bookpb.GetBookRequest{Id: id, IncludeRating: true})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %q: %.1f★ from %d reviews\n", resp.Book.Title, resp.Rating.Average, resp.Rating.ReviewCount)
+	return nil
+}
+
+// pageBooks lists the catalog pageSize books at a time, following
+// next_page_token until the last page.
+func pageBooks(ctx context.Context, catalog bookv2pb.BookCatalogClient, pageSize int32) error {
+	req := &bookv2pb.ListBooksRequest{PageSize: pageSize, OrderBy: "published_year desc"}
+	for page := 1; ; page++ {
+		resp, err := catalog.ListBooks(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Page %d of %d books:\n", page, resp.TotalSize)
+		for _, book := range resp.Books {
+			updated := "unknown"
+			if book.UpdateTime != nil {
+				updated = book.UpdateTime.AsTime().Local().Format(time.DateTime)
+			}
+			fmt.Printf("  %d. %s (%d) - updated %s\n", book.Id, book.Title, book.PublishedYear, updated)
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// createTwice creates a book with a fresh request_id, repeats the exact
+// request and checks both calls name the same book, then deletes it and
+// returns its id.
+func createTwice(ctx context.Context, catalog bookv2pb.BookCatalogClient) (int32, error) {
+	req := &bookv2pb.CreateBookRequest{
+		Book: &bookv2pb.Book{
+			Title:         "Domain-Driven Design",
+			Author:        "Eric Evans",
+			Isbn:          "978-0321125217",
+			Price:         &bookpb.Money{CurrencyCode: "USD", Units: 59, Nanos: 990000000},
+			Stock:         3,
+			PublishedYear: 2003,
+			Category:      bookpb.BookCategory_NONFICTION,
+		},
+		RequestId: fmt.Sprintf("demo-%d", time.Now().UnixNano()),
+	}
+	first, err := catalog.CreateBook(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	again, err := catalog.CreateBook(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if again.Id != first.Id {
+		return 0, fmt.Errorf("request_id %s created books %d and %d", req.RequestId, first.Id, again.Id)
+	}
+	fmt.Printf("✓ Both calls returned book %d (%s)\n", first.Id, first.Title)
+	if _, err := catalog.DeleteBook(ctx, &bookv2pb.DeleteBookRequest{Id: first.Id}); err != nil {
+		return 0, err
+	}
+	return first.Id, nil
+}
+
+// undelete checks that a soft-deleted book reads as NotFound, restores it,
+// and then deletes it for good so that the demo leaves no trace.
+func undelete(ctx context.Context, client bookpb.BookCatalogClient, id int32) error {
+	if _, err := client.GetBook(ctx, &bookpb.GetBookRequest{Id: id}); status.Code(err) != codes.NotFound {
+		return fmt.Errorf("deleted book %d: expected NotFound, got %v", id, err)
+	}
+	resp, err := client.UndeleteBook(ctx, &bookpb.UndeleteBookRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Book %d (%s) is back\n", resp.Book.Id, resp.Book.Title)
+	if _, err := client.DeleteBook(ctx, &bookpb.DeleteBookRequest{Id: id, Hard: true}); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Book %d deleted for good\n", id)
+	return nil
+}
+
+// staleUpdate reads a book once and updates its stock twice with the etag
+// of that read, as two clients would. The second update must be Aborted;
+// the stock is then restored with the current etag.
+func staleUpdate(ctx context.Context, catalog bookv2pb.BookCatalogClient, id int32) error {
+	read, err := catalog.GetBook(ctx, &bookv2pb.GetBookRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	setStock := func(stock int32, etag string) (*bookv2pb.Book, error) {
+		return catalog.UpdateBook(ctx, &bookv2pb.UpdateBookRequest{
+			Book:       &bookv2pb.Book{Id: id, Stock: stock, Etag: etag},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"stock"}},
+		})
+	}
+	first, err := setStock(read.Stock+1, read.Etag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ First update: stock %d → %d, etag %s → %s\n", read.Stock, first.Stock, read.Etag, first.Etag)
+	_, err = setStock(read.Stock+2, read.Etag)
+	if status.Code(err) != codes.Aborted {
+		return fmt.Errorf("second update with etag %s: expected Aborted, got %v", read.Etag, err)
+	}
+	fmt.Printf("✓ Expected error: %s\n", rpcerr.Describe(err))
+	_, err = setStock(read.Stock, first.Etag)
+	return err
+}
+
+// formatAmounts prints amounts in several currencies, e.g. "44.99 USD,
+// 12.50 EUR".
+func formatAmounts(amounts []*bookpb.Money) string {
+	if len(amounts) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(amounts))
+	for i, m := range amounts {
+		parts[i] = m.Format()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sampleCSV has one row that fails validation.
+const sampleCSV = `title,author,isbn,price,currency,stock,published_year,category
+Concurrency in Go,Katherine Cox-Buday,978-1491941195,39.99,USD,12,2017,NONFICTION
+"Go in Action",William Kennedy,978-1617291784,34.99,eur,9,2015,nonfiction
+Untitled Draft,,not-an-isbn,-5,,1,2016,
+`
+
+// demoChunkSize is deliberately small so rows are split across chunks.
+const demoChunkSize = 32
+
+// importCSV uploads data to ImportBooks in chunks of chunkSize bytes and
+// prints each progress update.
+func importCSV(ctx context.Context, client bookpb.BookCatalogClient, data string, chunkSize int) error {
+	stream, err := client.ImportBooks(ctx)
+	if err != nil {
+		return err
+	}
+	// Send on another goroutine: progress may come back before the upload
+	// is finished.
+	go func() {
+		for len(data) > 0 {
+			n := min(chunkSize, len(data))
+			if err := stream.Send(&bookpb.ImportChunk{Data: []byte(data[:n])}); err != nil {
+				return // Recv reports why the stream ended
+			}
+			data = data[n:]
+		}
+		stream.CloseSend()
+	}()
+
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, e := range progress.Errors {
+			fmt.Printf("  ✗ line %d: %s\n", e.Line, e.Message)
+		}
+		fmt.Printf("✓ %d rows read, %d imported, %d failed\n",
+			progress.RowsProcessed, progress.RowsImported, progress.RowsFailed)
+	}
+}
+
+// exportCSV downloads the catalog from ExportBooks and prints its first
+// lines.
+func exportCSV(ctx context.Context, client bookpb.BookCatalogClient) error {
+	stream, err := client.ExportBooks(ctx, &bookpb.ExportRequest{Format: bookpb.ExportRequest_CSV})
+	if err != nil {
+		return err
+	}
+	var data []byte
+	chunks := 0
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data = append(data, chunk.Data...)
+		chunks++
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for _, line := range lines[:min(4, len(lines))] {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Printf("✓ Exported %d books (%d bytes in %d chunks)\n", len(lines)-1, len(data), chunks)
+	return nil
+}
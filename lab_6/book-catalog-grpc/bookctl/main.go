@@ -0,0 +1,241 @@
+// Command bookctl calls the lab's gRPC services from the command line, one
+// subcommand per RPC, so any of them can be tried without writing a client:
+//
+//	bookctl list --order-by "price desc" --in-stock
+//	bookctl create --title "Learning Go" --author "Jon Bodner" --price 44.99 --stock 30
+//	bookctl update 1 --price 39.99
+//	bookctl author books 1 -o json
+//	bookctl demo
+//	bookctl repl
+//
+// Book commands speak bookservice.BookCatalog v1, which the Task3, Task4 and
+// Task5 servers all serve; pick one with --endpoint task3-books or
+// task4-books, or with --addr. Features a server
+// lacks come back as Unimplemented. The client flags of the shared packages
+// (TLS, auth, keepalive, compression, endpoints) work here too, with two
+// dashes, e.g. --tls-ca ca.crt --auth-token demo.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"book-catalog-grpc/auth"
+	"book-catalog-grpc/compression"
+	"book-catalog-grpc/endpoints"
+	"book-catalog-grpc/keepaliveconfig"
+	authorpb "book-catalog-grpc/proto"
+	bookpb "book-catalog-grpc/proto"
+	"book-catalog-grpc/retry"
+	"book-catalog-grpc/rpcerr"
+	"book-catalog-grpc/tlsconfig"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Values of the global flags. Each address flag takes one address, a
+// comma-separated list of replicas or a dns:/// target; left empty, package
+// endpoints finds the service.
+var (
+	bookAddr   = os.Getenv("BOOK_ADDR")
+	authorAddr = os.Getenv("AUTHOR_ADDR")
+	bookName   = "books"
+	output     = "table"
+	timeout    = 10 * time.Second
+)
+
+// conns are the connections dialled so far, by service and target, so that the lines of
+// a REPL share them; they are closed when bookctl exits. stop ends the
+// --timeout of the command.
+var (
+	conns = map[string]*grpc.ClientConn{}
+	stop  = func() {}
+)
+
+// clientFlags are the flags the shared packages register on the standard
+// flag set that concern a client. Their server-only flags, such as
+// -auth-tokens or -tls-client-auth, stay out of bookctl.
+var clientFlags = []string{
+	"tls", "tls-ca", "tls-cert", "tls-key", "tls-server-name",
+	"auth-token",
+	"keepalive-time", "keepalive-timeout",
+	"compress",
+	"endpoints-file", "lb-policy",
+}
+
+// noTimeout marks commands that run until the user ends them; --timeout
+// applies to each of their steps instead.
+const noTimeout = "no-timeout"
+
+func main() {
+	err := newRootCmd().ExecuteContext(context.Background())
+	stop()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+}
+
+// printError prints err with the google.rpc details of its status.
+func printError(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", rpcerr.Describe(err))
+}
+
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "bookctl",
+		Short: "Call the book catalog's gRPC services from the command line",
+		// Errors are printed once, by main, with their google.rpc details.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--output must be table or json, got %q", output)
+			}
+			if timeout > 0 && cmd.Annotations[noTimeout] == "" {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(ctx)
+				stop = cancel
+			}
+			return nil
+		},
+	}
+
+	// The current values are the defaults, so that the lines of a REPL
+	// start from the flags bookctl repl was given.
+	pf := root.PersistentFlags()
+	pf.StringVar(&bookAddr, "addr", bookAddr, "Book service address(es) (default: endpoints file or 127.0.0.1:50051)")
+	pf.StringVar(&bookName, "endpoint", bookName, "endpoints name of the book server: books (Task5), task3-books or task4-books")
+	pf.StringVar(&authorAddr, "author-addr", authorAddr, "Author service address(es) (default: endpoints file or 127.0.0.1:50052)")
+	pf.StringVarP(&output, "output", "o", output, "output format: table or json")
+	pf.DurationVar(&timeout, "timeout", timeout, "deadline for the whole command; 0 for none")
+	for _, name := range clientFlags {
+		pf.AddGoFlag(flag.CommandLine.Lookup(name))
+	}
+
+	root.AddCommand(
+		newGetCmd(), newCreateCmd(), newUpdateCmd(), newDeleteCmd(), newUndeleteCmd(),
+		newListCmd(), newSearchCmd(), newFilterCmd(), newStatsCmd(),
+		newImportCmd(), newExportCmd(),
+		newAuthorCmd(), newReviewCmd(), newDemoCmd(), newReplCmd(),
+	)
+	return root
+}
+
+// dialBooks connects to Book service, which also serves ReviewCatalog and
+// bookcatalog.v2 on the same port.
+func dialBooks() (*grpc.ClientConn, error) {
+	return dial(bookName, bookAddr, bookpb.BookCatalog_ServiceDesc.ServiceName)
+}
+
+func dialAuthors() (*grpc.ClientConn, error) {
+	return dial("authors", authorAddr, authorpb.AuthorCatalog_ServiceDesc.ServiceName)
+}
+
+func dial(name, addr, service string) (*grpc.ClientConn, error) {
+	// The service is part of the key: its retry policy is in the connection.
+	target := endpoints.Target(name, addr)
+	key := service + " " + target
+	if conn, ok := conns[key]; ok {
+		return conn, nil
+	}
+	creds, err := tlsconfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+	}
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds), keepaliveconfig.DialOption(), auth.DialOption(), compression.DialOption(),
+		grpc.WithChainUnaryInterceptor(compression.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(compression.StreamClientInterceptor()),
+		retry.DialOption(endpoints.ServiceConfig(service)))
+	if err != nil {
+		return nil, err
+	}
+	conns[key] = conn
+	return conn, nil
+}
+
+func bookClient() (bookpb.BookCatalogClient, error) {
+	conn, err := dialBooks()
+	if err != nil {
+		return nil, err
+	}
+	return bookpb.NewBookCatalogClient(conn), nil
+}
+
+func authorClient() (authorpb.AuthorCatalogClient, error) {
+	conn, err := dialAuthors()
+	if err != nil {
+		return nil, err
+	}
+	return authorpb.NewAuthorCatalogClient(conn), nil
+}
+
+// show prints resp as JSON with --output json, and otherwise as the table
+// that table writes.
+func show(resp proto.Message, table func(w io.Writer)) error {
+	if output == "json" {
+		return printJSON(resp, true)
+	}
+	w := newTable()
+	table(w)
+	return w.Flush()
+}
+
+// newTable aligns the tab-separated cells written to it on standard output.
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+}
+
+// printJSON prints m with the field names of the .proto files, indented, or
+// on one line for streams, which print a message per line.
+func printJSON(m proto.Message, indent bool) error {
+	opts := protojson.MarshalOptions{}
+	if indent {
+		opts.Multiline, opts.Indent = true, "  "
+	}
+	b, err := opts.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// parseID reads the ID argument of a command.
+func parseID(arg string) (int32, error) {
+	id, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an id", arg)
+	}
+	return int32(id), nil
+}
+
+// parseSince reads an --updated-since value: an RFC 3339 time, or a
+// duration meaning that long ago. "" gives nil, which lists everything.
+func parseSince(s string) (*timestamppb.Timestamp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return timestamppb.New(time.Now().Add(-d)), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("--updated-since: %q is neither an RFC 3339 time nor a duration", s)
+	}
+	return timestamppb.New(t), nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newReplCmd() *cobra.Command {
+	var historyFile string
+	cmd := &cobra.Command{
+		Use:   "repl",
+		Short: "Run bookctl commands interactively over one connection",
+		Long: `repl reads bookctl commands line by line, e.g. "list --in-stock" or
+"author books 1 -o json", and runs them over connections that stay open
+between lines. Tab completes command and flag names, the arrow keys and
+Ctrl-R walk the history, which is kept in --history-file across sessions.
+Ctrl-C cancels the running command, Ctrl-D or "exit" leaves.
+
+The global flags given to repl, e.g. --addr or -o json, apply to every
+line; a line may override them for itself. --timeout applies to each line.
+Connection flags such as --tls-ca or --auth-token take effect when a
+connection is first made, so give them to repl.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{noTimeout: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rl, err := readline.NewEx(&readline.Config{
+				Prompt:            "bookctl> ",
+				HistoryFile:       historyFile,
+				HistorySearchFold: true,
+				AutoComplete:      readline.NewPrefixCompleter(completions(cmd.Root())...),
+				InterruptPrompt:   "^C",
+				EOFPrompt:         "exit",
+			})
+			if err != nil {
+				return err
+			}
+			defer rl.Close()
+			fmt.Println(`Type a command such as "list" or "get 1", "help" for the list, "exit" to leave.`)
+
+			session := saveGlobals()
+			for {
+				line, err := rl.Readline()
+				if errors.Is(err, readline.ErrInterrupt) {
+					continue
+				}
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				args, err := splitLine(line)
+				if err != nil {
+					printError(err)
+					continue
+				}
+				if len(args) == 0 {
+					continue
+				}
+				switch args[0] {
+				case "exit", "quit":
+					return nil
+				case "repl":
+					printError(errors.New("already in the REPL"))
+					continue
+				}
+				session.restore()
+				if err := runLine(cmd.Context(), args); err != nil {
+					printError(err)
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&historyFile, "history-file", defaultHistoryFile(), "file keeping the REPL history; empty keeps none")
+	return cmd
+}
+
+// runLine runs one line of the REPL as if it were bookctl's command line.
+// Ctrl-C cancels it rather than ending the REPL.
+func runLine(ctx context.Context, args []string) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+	// A fresh command tree, so that flags of the previous line are unset.
+	root := newRootCmd()
+	root.SetArgs(args)
+	defer func() {
+		stop()
+		stop = func() {}
+	}()
+	return root.ExecuteContext(ctx)
+}
+
+// globals are the values of the global flags, saved when the REPL starts
+// so that each line starts from them again.
+type globals struct {
+	bookAddr, authorAddr, bookName, output string
+	timeout                                time.Duration
+}
+
+func saveGlobals() globals {
+	return globals{bookAddr, authorAddr, bookName, output, timeout}
+}
+
+func (g globals) restore() {
+	bookAddr, authorAddr, bookName, output, timeout = g.bookAddr, g.authorAddr, g.bookName, g.output, g.timeout
+}
+
+// completions lists the subcommands of cmd for tab completion, each followed
+// by its own subcommands or its flags.
+func completions(cmd *cobra.Command) []readline.PrefixCompleterInterface {
+	var items []readline.PrefixCompleterInterface
+	for _, c := range cmd.Commands() {
+		if c.Hidden || c.Name() == "repl" || c.Name() == "completion" {
+			continue
+		}
+		children := completions(c)
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			children = append(children, readline.PcItem("--"+f.Name))
+		})
+		items = append(items, readline.PcItem(c.Name(), children...))
+	}
+	if cmd.Parent() == nil {
+		items = append(items, readline.PcItem("help"), readline.PcItem("exit"))
+	}
+	return items
+}
+
+// splitLine splits a line into arguments like a shell does for the simple
+// cases: on spaces, except inside single or double quotes, and with a
+// backslash escaping the next character outside single quotes.
+func splitLine(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// defaultHistoryFile is ~/.bookctl_history, or none without a home.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".bookctl_history")
+}
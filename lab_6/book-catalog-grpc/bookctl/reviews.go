@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	reviewpb "book-catalog-grpc/proto"
+
+	"github.com/spf13/cobra"
+)
+
+// Reviews are served by the Task5 book-service, on the port of Book service.
+
+func reviewClient() (reviewpb.ReviewCatalogClient, error) {
+	conn, err := dialBooks()
+	if err != nil {
+		return nil, err
+	}
+	return reviewpb.NewReviewCatalogClient(conn), nil
+}
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Read and write book reviews",
+	}
+	cmd.AddCommand(newReviewListCmd(), newReviewAddCmd(), newReviewRatingCmd())
+	return cmd
+}
+
+func newReviewListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list BOOK_ID",
+		Short: "List a book's reviews",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := reviewClient()
+			if err != nil {
+				return err
+			}
+			stream, err := client.ListReviews(cmd.Context(), &reviewpb.ListReviewsRequest{BookId: id})
+			if err != nil {
+				return err
+			}
+			w := newTable()
+			if output == "table" {
+				fmt.Fprintln(w, "ID\tSTARS\tREVIEWER\tDATE\tCOMMENT")
+			}
+			for {
+				r, err := stream.Recv()
+				if err == io.EOF {
+					return w.Flush()
+				}
+				if err != nil {
+					w.Flush()
+					return err
+				}
+				if output == "json" {
+					if err := printJSON(r, false); err != nil {
+						return err
+					}
+					continue
+				}
+				fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", r.Id, r.Rating, r.Reviewer,
+					time.Unix(r.CreatedAt, 0).Local().Format(time.DateOnly), r.Comment)
+			}
+		},
+	}
+}
+
+func newReviewAddCmd() *cobra.Command {
+	var req reviewpb.AddReviewRequest
+	cmd := &cobra.Command{
+		Use:     "add BOOK_ID",
+		Short:   "Review a book",
+		Example: `  bookctl review add 1 --reviewer alice --rating 5 --comment "A classic"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			req.BookId = id
+			client, err := reviewClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.AddReview(cmd.Context(), &req)
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				fmt.Fprintf(w, "Review %d: %d stars for book %d by %s\n",
+					resp.Review.Id, resp.Review.Rating, resp.Review.BookId, resp.Review.Reviewer)
+			})
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&req.Reviewer, "reviewer", "", "who is reviewing")
+	fs.Int32Var(&req.Rating, "rating", 0, "1 to 5 stars")
+	fs.StringVar(&req.Comment, "comment", "", "optional comment")
+	return cmd
+}
+
+func newReviewRatingCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rating BOOK_ID",
+		Short: "Show a book's average rating",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := reviewClient()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetBookRating(cmd.Context(), &reviewpb.GetBookRatingRequest{BookId: id})
+			if err != nil {
+				return err
+			}
+			return show(resp, func(w io.Writer) {
+				fmt.Fprintf(w, "Book %d: %.1f/5 from %d reviews\n", resp.Rating.BookId, resp.Rating.Average, resp.Rating.ReviewCount)
+			})
+		},
+	}
+}
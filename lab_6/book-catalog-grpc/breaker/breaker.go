@@ -0,0 +1,183 @@
+// Package breaker is a circuit breaker for gRPC client connections. After
+// threshold consecutive failed calls the circuit opens and calls fail at once
+// with ErrOpen instead of waiting on a server that is down. After the
+// cooldown one trial call is let through (half-open): if it succeeds the
+// circuit closes again, otherwise it stays open for another cooldown.
+//
+// State and rejections are exported as Prometheus metrics, served with the
+// rest on -metrics-addr.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// State is where a Breaker is in its cycle.
+type State int
+
+const (
+	Closed State = iota
+	HalfOpen
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "open"
+}
+
+// ErrOpen is returned for calls the breaker rejects. It carries the
+// Unavailable code, so it can be returned to a gRPC caller as is; use
+// errors.Is to tell it apart from a real Unavailable.
+var ErrOpen error = openError{}
+
+type openError struct{}
+
+func (openError) Error() string { return "circuit breaker is open" }
+
+func (openError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, "circuit breaker is open")
+}
+
+var (
+	stateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_client_circuit_state",
+		Help: "Circuit breaker state by target: 0 closed, 1 half-open, 2 open.",
+	}, []string{"target"})
+
+	rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_circuit_rejected_total",
+		Help: "Calls failed fast because the circuit was open.",
+	}, []string{"target"})
+)
+
+// Breaker guards the calls to one target.
+type Breaker struct {
+	target    string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // a half-open trial call is in flight
+}
+
+// New returns a closed Breaker for target, the name used in logs and
+// metrics.
+func New(target string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	b := &Breaker{target: target, threshold: threshold, cooldown: cooldown}
+	stateGauge.WithLabelValues(target).Set(float64(Closed))
+	return b
+}
+
+// State reports the current state; an open circuit whose cooldown has passed
+// reports HalfOpen.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && time.Since(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// UnaryClientInterceptor runs every unary call on the connection through
+// the breaker.
+func (b *Breaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			rejectedTotal.WithLabelValues(b.target).Inc()
+			return ErrOpen
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(isFailure(err))
+		return err
+	}
+}
+
+// isFailure reports whether err says the server is unhealthy. Errors about
+// the request itself (NotFound, InvalidArgument, ...) and calls the caller
+// cancelled do not count.
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return !errors.Is(err, ErrOpen)
+	}
+	return false
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Closed:
+		return true
+	case Open:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(HalfOpen)
+	}
+	// Half-open: only one trial call at a time.
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen {
+		b.probing = false
+		if failed {
+			b.trip()
+		} else {
+			b.failures = 0
+			b.setState(Closed)
+		}
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == Closed && b.failures >= b.threshold {
+		b.trip()
+	}
+}
+
+func (b *Breaker) trip() {
+	b.openedAt = time.Now()
+	b.setState(Open)
+}
+
+func (b *Breaker) setState(s State) {
+	if b.state != s {
+		log.Printf("🔌 Circuit breaker for %s: %s → %s", b.target, b.state, s)
+	}
+	b.state = s
+	stateGauge.WithLabelValues(b.target).Set(float64(s))
+}
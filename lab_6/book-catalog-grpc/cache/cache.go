@@ -0,0 +1,207 @@
+// Package cache is a small in-memory read-through LRU cache with a TTL, for
+// the lookups the demo clients repeat (GetBook, GetAuthor). Size and TTL
+// come from flags that default to environment variables:
+//
+//	-cache-size CACHE_SIZE entries kept per cache (default 256; 0 disables caching)
+//	-cache-ttl  CACHE_TTL  how long an entry may be served (default 30s)
+//
+// Concurrent misses on one key share a single load (singleflight), so a
+// burst of lookups for a cold key reaches the database or the remote
+// service once. Writers must Invalidate what they change. The TTL bounds how stale an
+// entry can get when the data is changed by someone else, e.g. another
+// book-service replica sharing the database.
+//
+// Hits and misses are exported as Prometheus metrics, served with the rest
+// on -metrics-addr. Call flag.Parse before New.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	size = flag.Int("cache-size", envInt("CACHE_SIZE", 256), "entries kept per cache; 0 disables caching")
+	ttl  = flag.Duration("cache-ttl", envDuration("CACHE_TTL", 30*time.Second), "how long a cached entry may be served")
+)
+
+var (
+	hitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "Lookups served from the cache or by a load already in flight.",
+	}, []string{"cache"})
+
+	missesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "Lookups that had to load the value.",
+	}, []string{"cache"})
+)
+
+// LRU caches up to -cache-size values for -cache-ttl each, evicting the
+// least recently used one when full. It is safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	name string
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[K]*list.Element
+	// gen counts invalidations, so a value loaded while its key was
+	// invalidated is not stored.
+	gen uint64
+	// loading holds the loads in flight, which other misses on their key
+	// wait for.
+	loading map[K]*call[V]
+}
+
+// call is one load, shared by every Get that missed while it ran.
+type call[V any] struct {
+	done  chan struct{} // closed once value and err are set
+	value V
+	err   error
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// New returns an empty cache; name labels its metrics.
+func New[K comparable, V any](name string) *LRU[K, V] {
+	if *size > 0 {
+		log.Printf("🗃️ Cache %s: %d entries, TTL %v", name, *size, *ttl)
+	}
+	return &LRU[K, V]{
+		name:    name,
+		size:    *size,
+		ttl:     *ttl,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		loading: make(map[K]*call[V]),
+	}
+}
+
+// Get returns the cached value for key, or calls load and caches what it
+// returns. While load runs, other Gets for key wait for its result instead
+// of loading too. Errors are not cached, but are returned to every Get
+// that waited.
+func (c *LRU[K, V]) Get(key K, load func() (V, error)) (V, error) {
+	if c.size <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[K, V])
+		if time.Now().Before(e.expires) {
+			c.order.MoveToFront(el)
+			c.mu.Unlock()
+			hitsTotal.WithLabelValues(c.name).Inc()
+			return e.value, nil
+		}
+		c.remove(el)
+	}
+	if cl, ok := c.loading[key]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		hitsTotal.WithLabelValues(c.name).Inc()
+		return cl.value, cl.err
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	c.loading[key] = cl
+	gen := c.gen
+	c.mu.Unlock()
+	missesTotal.WithLabelValues(c.name).Inc()
+
+	v, err := c.load(key, cl, load)
+	if err != nil {
+		return v, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		// Invalidated while loading; v may already be out of date.
+		return v, nil
+	}
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: v, expires: time.Now().Add(c.ttl)})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return v, nil
+}
+
+// load runs the load of cl and then releases the Gets waiting for it, even
+// if it panics.
+func (c *LRU[K, V]) load(key K, cl *call[V], load func() (V, error)) (V, error) {
+	defer func() {
+		c.mu.Lock()
+		if c.loading[key] == cl {
+			delete(c.loading, key)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.err = errLoadPanicked
+	cl.value, cl.err = load()
+	return cl.value, cl.err
+}
+
+var errLoadPanicked = errors.New("cache: load panicked")
+
+// Invalidate drops key, after its value has been changed or deleted.
+func (c *LRU[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	// Gets from now on load afresh rather than wait for a load that may
+	// have read the old value.
+	delete(c.loading, key)
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *LRU[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[K, V]).key)
+}
+
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️ Ignoring %s=%q: %v", name, v, err)
+		return def
+	}
+	return n
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️ Ignoring %s=%q: %v", name, v, err)
+		return def
+	}
+	return d
+}
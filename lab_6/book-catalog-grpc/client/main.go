@@ -2,20 +2,34 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"book-catalog-grpc/endpoints"
+	"book-catalog-grpc/keepaliveconfig"
 	pb "book-catalog-grpc/proto"
+	"book-catalog-grpc/tlsconfig"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
+// calculatorAddr is left empty to let package endpoints find the server.
+var calculatorAddr = flag.String("calculator-addr", os.Getenv("CALCULATOR_ADDR"), "Calculator server address(es) (default: endpoints file or 127.0.0.1:50051)")
+
 func main() {
-	conn, err := grpc.Dial("127.0.0.1:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	flag.Parse()
+
+	creds, err := tlsconfig.ClientCredentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS credentials: %v", err)
+	}
+
+	conn, err := grpc.Dial(endpoints.Target("calculator", *calculatorAddr),
+		grpc.WithTransportCredentials(creds), keepaliveconfig.DialOption())
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
@@ -0,0 +1,150 @@
+// Package compression gzips the lab's large gRPC responses and logs how
+// many bytes that saves, for the performance comparison exercise.
+//
+// Importing the package registers the gzip codec, so a server can read a
+// gzipped request; grpc-go then compresses the response with the codec the
+// client used. Clients ask for it with UnaryClientInterceptor and
+// StreamClientInterceptor, which gzip only the bulk RPCs in bulkMethods and
+// honour a flag that defaults to an environment variable:
+//
+//	-compress COMPRESS gzip ListBooks and ExportBooks (default true)
+//
+// Other RPCs, such as the small messages of StreamBooks, stay uncompressed:
+// gzip makes them bigger.
+//
+// Run a client once with -compress=false and once without, and compare the
+// "📦" lines that ServerOption and DialOption log for every RPC moving at
+// least 512 bytes: the payload size next to the bytes that crossed the wire.
+//
+// Call flag.Parse before the first RPC.
+package compression
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/stats"
+)
+
+// minLogBytes keeps the small RPCs, which compression does not help, out of
+// the log.
+const minLogBytes = 512
+
+var enabled = flag.Bool("compress", envBool("COMPRESS", true), "gzip large responses such as ListBooks and ExportBooks (client)")
+
+// bulkMethods are the RPCs whose responses are large enough for gzip to
+// pay off.
+var bulkMethods = map[string]bool{
+	"/bookservice.BookCatalog/ListBooks":    true,
+	"/bookservice.BookCatalog/ExportBooks":  true,
+	"/bookcatalog.v2.BookCatalog/ListBooks": true,
+}
+
+// callOptions adds gzip to the options of a call to method if it is a bulk
+// RPC, unless -compress=false.
+func callOptions(method string, opts []grpc.CallOption) []grpc.CallOption {
+	if !*enabled || !bulkMethods[method] {
+		return opts
+	}
+	return append(opts, grpc.UseCompressor(gzip.Name))
+}
+
+// UnaryClientInterceptor gzips the unary bulk RPCs, such as ListBooks.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, callOptions(method, opts)...)
+	}
+}
+
+// StreamClientInterceptor gzips the streaming bulk RPCs, such as
+// ExportBooks.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, callOptions(method, opts)...)
+	}
+}
+
+// ServerOption logs the bytes of each RPC a server handles.
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(byteLogger{})
+}
+
+// DialOption logs the bytes of each RPC a client makes.
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(byteLogger{})
+}
+
+// byteLogger adds up the payloads of each RPC, before and after
+// compression, and logs them when the RPC ends.
+type byteLogger struct{}
+
+type rpcKey struct{}
+
+// rpcBytes is updated from both directions of a bidirectional stream, which
+// may run on different goroutines.
+type rpcBytes struct {
+	mu                       sync.Mutex
+	method                   string
+	inCodec, outCodec        string
+	in, inWire, out, outWire int
+}
+
+func (byteLogger) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcKey{}, &rpcBytes{method: info.FullMethodName})
+}
+
+func (byteLogger) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	b, ok := ctx.Value(rpcKey{}).(*rpcBytes)
+	if !ok {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch s := s.(type) {
+	case *stats.InHeader:
+		b.inCodec = s.Compression
+	case *stats.OutHeader:
+		b.outCodec = s.Compression
+	case *stats.InPayload:
+		b.in += s.Length
+		b.inWire += s.CompressedLength
+	case *stats.OutPayload:
+		b.out += s.Length
+		b.outWire += s.CompressedLength
+	case *stats.End:
+		logBytes(b.method, "received", b.in, b.inWire, b.inCodec)
+		logBytes(b.method, "sent", b.out, b.outWire, b.outCodec)
+	}
+}
+
+func (byteLogger) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (byteLogger) HandleConn(context.Context, stats.ConnStats)                       {}
+
+func logBytes(method, verb string, size, wire int, codec string) {
+	if size < minLogBytes {
+		return
+	}
+	if codec == "" {
+		codec = "identity"
+	}
+	log.Printf("📦 %s: %s %d bytes, %d on the wire (%s, %d%%)", method, verb, size, wire, codec, wire*100/size)
+}
+
+func envBool(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("⚠️ Ignoring %s=%q: %v", name, v, err)
+		return def
+	}
+	return b
+}
@@ -0,0 +1,113 @@
+// Package endpoints is where the lab clients find their servers. Each
+// server has a name (see defaults) and its address is taken from, in order:
+//
+//  1. the client's address flag or its environment variable, e.g.
+//     -book-addr or BOOK_ADDR;
+//  2. the -endpoints-file (ENDPOINTS_FILE) JSON file, which maps names to
+//     address lists and is watched, so replicas can be added or removed
+//     while clients run;
+//  3. the built-in default, the port each lab server listens on.
+//
+// An address flag takes any of:
+//
+//	127.0.0.1:50051                  one server
+//	127.0.0.1:50051,127.0.0.1:50061  a fixed list of replicas
+//	dns:///books.local:50051         every address the name resolves to
+//
+// How calls are spread is set with -lb-policy (LB_POLICY): round_robin (the
+// default) sends each call to the next ready replica, pick_first sticks to
+// the first replica that connects and moves on only when it fails. With
+// round_robin, replicas whose grpc.health.v1 status is not SERVING are
+// skipped.
+package endpoints
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "google.golang.org/grpc/health" // client-side health checking
+	"google.golang.org/grpc/resolver"
+)
+
+var lbPolicy = flag.String("lb-policy", envOr("LB_POLICY", "round_robin"), "how calls are spread over replicas: round_robin or pick_first")
+
+// defaults are the addresses the lab servers listen on out of the box.
+var defaults = map[string]string{
+	"calculator":  "127.0.0.1:50051", // server
+	"task3-books": "127.0.0.1:50052", // Task3/server
+	"task4-books": "127.0.0.1:50053", // Task4/server
+	"books":       "127.0.0.1:50051", // Task5/book-service
+	"authors":     "127.0.0.1:50052", // Task5/author-service
+}
+
+// scheme is the resolver for comma-separated address lists.
+const scheme = "static"
+
+func init() {
+	resolver.Register(staticBuilder{})
+	resolver.Register(fileBuilder{})
+}
+
+// Target returns the dial target for the server called name, given the
+// value of its address flag, which may be empty. Call flag.Parse first.
+func Target(name, addrs string) string {
+	addrs = strings.TrimSpace(addrs)
+	switch {
+	case strings.Contains(addrs, ","):
+		return scheme + ":///" + addrs
+	case addrs != "":
+		return addrs
+	case inFile(name):
+		return fileScheme + ":///" + name
+	}
+	if addr, ok := defaults[name]; ok {
+		return addr
+	}
+	panic("endpoints: no default address for " + name)
+}
+
+// ServiceConfig returns the load balancing fields of the default service
+// config for a client of service, e.g. "bookservice.BookCatalog", for
+// retry.DialOption to merge. Call flag.Parse first.
+func ServiceConfig(service string) map[string]any {
+	return map[string]any{
+		"loadBalancingConfig": []any{map[string]any{*lbPolicy: map[string]any{}}},
+		"healthCheckConfig":   map[string]any{"serviceName": service},
+	}
+}
+
+// staticBuilder resolves "static:///a:1,b:2" to the listed addresses once;
+// the list never changes.
+type staticBuilder struct{}
+
+func (staticBuilder) Scheme() string { return scheme }
+
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	var eps []resolver.Endpoint
+	for _, addr := range strings.Split(target.Endpoint(), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			eps = append(eps, resolver.Endpoint{Addresses: []resolver.Address{{Addr: addr}}})
+		}
+	}
+	if len(eps) == 0 {
+		return nil, fmt.Errorf("endpoints: no addresses in %q", target.Endpoint())
+	}
+	if err := cc.UpdateState(resolver.State{Endpoints: eps}); err != nil {
+		return nil, err
+	}
+	return staticResolver{}, nil
+}
+
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (staticResolver) Close()                                {}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,165 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// The endpoints file maps server names to address lists:
+//
+//	{
+//	  "books":   ["127.0.0.1:50051", "127.0.0.1:50061"],
+//	  "authors": ["127.0.0.1:50052"]
+//	}
+var endpointsFile = flag.String("endpoints-file", os.Getenv("ENDPOINTS_FILE"), "JSON file mapping server names to addresses; re-read when it changes")
+
+// fileScheme is the resolver for names looked up in the endpoints file.
+const fileScheme = "endpoints"
+
+// pollInterval is how often the file is checked for changes.
+const pollInterval = 2 * time.Second
+
+func readFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string][]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// inFile reports whether the endpoints file, if any, lists name.
+func inFile(name string) bool {
+	if *endpointsFile == "" {
+		return false
+	}
+	m, err := readFile(*endpointsFile)
+	if err != nil {
+		log.Printf("⚠️ Ignoring endpoints file: %v", err)
+		return false
+	}
+	return len(m[name]) > 0
+}
+
+// fileBuilder resolves "endpoints:///books" from the endpoints file.
+type fileBuilder struct{}
+
+func (fileBuilder) Scheme() string { return fileScheme }
+
+func (fileBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &fileResolver{
+		path: *endpointsFile,
+		name: target.Endpoint(),
+		cc:   cc,
+		now:  make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	r.update()
+	go r.watch()
+	return r, nil
+}
+
+// fileResolver pushes the addresses listed for name to the channel, and
+// pushes them again whenever the file changes. If the file turns unreadable
+// or drops the name, the last good list is kept.
+type fileResolver struct {
+	path string
+	name string
+	cc   resolver.ClientConn
+	now  chan struct{} // ResolveNow asks for a re-read
+	done chan struct{}
+
+	once    sync.Once
+	modTime time.Time
+	addrs   []string
+	lastErr string // logged once, not on every poll
+}
+
+func (r *fileResolver) watch() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+		r.update()
+	}
+}
+
+// update re-reads the file if it changed since the last read. Only the
+// watch goroutine calls it once Build has returned.
+func (r *fileResolver) update() {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	if info.ModTime().Equal(r.modTime) {
+		return
+	}
+	r.modTime = info.ModTime()
+	m, err := readFile(r.path)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	addrs := m[r.name]
+	if len(addrs) == 0 {
+		r.fail(fmt.Errorf("%s lists no addresses for %q", r.path, r.name))
+		return
+	}
+	r.lastErr = ""
+	if slices.Equal(addrs, r.addrs) {
+		return
+	}
+	if r.addrs != nil {
+		log.Printf("🔄 Endpoints for %s changed: %v → %v", r.name, r.addrs, addrs)
+	}
+	r.addrs = addrs
+
+	eps := make([]resolver.Endpoint, len(addrs))
+	for i, addr := range addrs {
+		eps[i] = resolver.Endpoint{Addresses: []resolver.Address{{Addr: addr}}}
+	}
+	if err := r.cc.UpdateState(resolver.State{Endpoints: eps}); err != nil {
+		log.Printf("⚠️ Endpoints for %s rejected: %v", r.name, err)
+	}
+}
+
+// fail keeps the addresses already in use; only a resolver that never had
+// any reports the error to the channel.
+func (r *fileResolver) fail(err error) {
+	if r.addrs == nil {
+		r.cc.ReportError(err)
+		return
+	}
+	if err.Error() != r.lastErr {
+		r.lastErr = err.Error()
+		log.Printf("⚠️ Keeping endpoints for %s: %v", r.name, err)
+	}
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fileResolver) Close() {
+	r.once.Do(func() { close(r.done) })
+}
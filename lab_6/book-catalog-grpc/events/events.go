@@ -0,0 +1,130 @@
+// Package events carries book lifecycle events from book-service to anyone
+// who wants to react to them. Broker hides the transport: Memory keeps
+// everything inside one process, and a NATS broker (built with -tags nats)
+// lets several book-service replicas and other processes share one stream.
+package events
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	pb "book-catalog-grpc/proto"
+)
+
+// Broker publishes lifecycle events and hands them to subscribers.
+type Broker interface {
+	// Publish sends ev to every current subscriber. It does not wait for
+	// them to read it.
+	Publish(ev *pb.LifecycleEvent) error
+	// Subscribe starts a subscription with room for buffer unread events;
+	// events that arrive while it is full are dropped for that subscriber.
+	Subscribe(buffer int) (Subscription, error)
+	Close() error
+}
+
+// Subscription is one subscriber's view of a Broker.
+type Subscription interface {
+	// Events is closed once the subscription is.
+	Events() <-chan *pb.LifecycleEvent
+	Close()
+}
+
+// New returns a NATS broker when natsURL is set and a Memory broker
+// otherwise.
+func New(natsURL string) (Broker, error) {
+	if natsURL == "" {
+		return NewMemory(), nil
+	}
+	if dialNATS == nil {
+		return nil, errors.New("NATS support not built in; rebuild with -tags nats")
+	}
+	return dialNATS(natsURL)
+}
+
+// dialNATS is set by nats.go when the binary is built with -tags nats.
+var dialNATS func(url string) (Broker, error)
+
+// Memory is an in-process Broker.
+type Memory struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+func NewMemory() *Memory {
+	return &Memory{subs: make(map[*subscription]struct{})}
+}
+
+func (m *Memory) Publish(ev *pb.LifecycleEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for sub := range m.subs {
+		sub.push(ev)
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(buffer int) (Subscription, error) {
+	sub := newSubscription(buffer, func(sub *subscription) {
+		m.mu.Lock()
+		delete(m.subs, sub)
+		m.mu.Unlock()
+	})
+	m.mu.Lock()
+	m.subs[sub] = struct{}{}
+	m.mu.Unlock()
+	return sub, nil
+}
+
+// Close ends every open subscription.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	subs := m.subs
+	m.subs = make(map[*subscription]struct{})
+	m.mu.Unlock()
+	for sub := range subs {
+		sub.end()
+	}
+	return nil
+}
+
+// subscription buffers events for one subscriber. Both brokers use it.
+type subscription struct {
+	mu     sync.Mutex
+	closed bool
+	events chan *pb.LifecycleEvent
+	detach func(*subscription)
+}
+
+func newSubscription(buffer int, detach func(*subscription)) *subscription {
+	return &subscription{events: make(chan *pb.LifecycleEvent, buffer), detach: detach}
+}
+
+func (s *subscription) Events() <-chan *pb.LifecycleEvent { return s.events }
+
+func (s *subscription) Close() {
+	s.detach(s)
+	s.end()
+}
+
+func (s *subscription) push(ev *pb.LifecycleEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- ev:
+	default:
+		log.Printf("events: subscriber buffer full, dropped %s for book %d", ev.Type, ev.BookId)
+	}
+}
+
+func (s *subscription) end() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build nats
+
+package events
+
+import (
+	"fmt"
+	"log"
+
+	pb "book-catalog-grpc/proto"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+// subject is where book-service publishes; every subscriber gets every
+// event.
+const subject = "bookcatalog.events"
+
+func init() {
+	dialNATS = func(url string) (Broker, error) {
+		nc, err := nats.Connect(url, nats.Name("book-catalog"), nats.MaxReconnects(-1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to NATS at %s: %w", url, err)
+		}
+		return &natsBroker{nc: nc}, nil
+	}
+}
+
+// natsBroker publishes events as protobuf messages on a NATS subject.
+type natsBroker struct {
+	nc *nats.Conn
+}
+
+func (b *natsBroker) Publish(ev *pb.LifecycleEvent) error {
+	data, err := proto.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return b.nc.Publish(subject, data)
+}
+
+func (b *natsBroker) Subscribe(buffer int) (Subscription, error) {
+	var ns *nats.Subscription
+	sub := newSubscription(buffer, func(*subscription) {
+		ns.Unsubscribe()
+	})
+	ns, err := b.nc.Subscribe(subject, func(m *nats.Msg) {
+		ev := new(pb.LifecycleEvent)
+		if err := proto.Unmarshal(m.Data, ev); err != nil {
+			log.Printf("events: dropped malformed message on %s: %v", subject, err)
+			return
+		}
+		sub.push(ev)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	return sub, nil
+}
+
+func (b *natsBroker) Close() error {
+	return b.nc.Drain()
+}
@@ -0,0 +1,73 @@
+// Package fuzzy scores how closely a search query matches a piece of text
+// while tolerating typos, so "Pragmtic Programer" still finds "The
+// Pragmatic Programmer".
+//
+// Both sides are split into words and every query word is compared with its
+// closest word in the text by edit distance. There is no index behind it:
+// callers score each candidate, which is fine for catalogs of a few
+// thousand books.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Score is the average, over the words of query, of the similarity to the
+// closest word in text: 1 when every query word appears in text, falling
+// towards 0 as more edits are needed. Case is ignored. A query without words
+// scores 0.
+func Score(query, text string) float64 {
+	qs, ts := words(query), words(text)
+	if len(qs) == 0 || len(ts) == 0 {
+		return 0
+	}
+	var total float64
+	for _, q := range qs {
+		best := 0.0
+		for _, t := range ts {
+			best = max(best, Similarity(q, t))
+		}
+		total += best
+	}
+	return total / float64(len(qs))
+}
+
+// Similarity is 1 minus the Levenshtein distance between a and b divided by
+// the length of the longer one, so one typo costs a long word less than a
+// short one.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(distance(ra, rb))/float64(longest)
+}
+
+// distance is the Levenshtein distance, keeping one row of the table.
+func distance(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			diag, row[j] = row[j], min(row[j]+1, row[j-1]+1, diag+cost)
+		}
+	}
+	return row[len(b)]
+}
+
+// words lowercases s and splits it on anything but letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
@@ -1,4 +1,4 @@
 @echo off
 set PATH=%PATH%;C:\Users\hung1\go\bin
-protoc --go_out=. --go-grpc_out=. --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative proto\book_service.proto
+protoc --go_out=. --go-grpc_out=. --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative proto\book.proto proto\book_service.proto proto\review_service.proto proto\author_service.proto proto\calculator.proto proto\bookcatalog\v2\book_catalog.proto
 echo Proto files generated successfully!
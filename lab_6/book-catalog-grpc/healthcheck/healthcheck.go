@@ -0,0 +1,55 @@
+// Package healthcheck serves grpc.health.v1.Health for a service backed by
+// a database. A background ping keeps the status current: SERVING while the
+// database answers, NOT_SERVING once a ping fails.
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	pingInterval = 5 * time.Second
+	pingTimeout  = 2 * time.Second
+)
+
+// Register adds the health service to s and starts pinging db. services are
+// the full service names to report on, e.g. "bookservice.BookCatalog"; the
+// empty name, for the server as a whole, is always reported.
+func Register(s *grpc.Server, db *sql.DB, services ...string) *health.Server {
+	h := health.NewServer()
+	healthpb.RegisterHealthServer(s, h)
+	names := append([]string{""}, services...)
+
+	check := func(last healthpb.HealthCheckResponse_ServingStatus) healthpb.HealthCheckResponse_ServingStatus {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := db.PingContext(ctx); err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if last != status {
+				log.Printf("💔 Database ping failed, reporting NOT_SERVING: %v", err)
+			}
+		} else if last == healthpb.HealthCheckResponse_NOT_SERVING {
+			log.Println("💚 Database reachable again, reporting SERVING")
+		}
+		for _, name := range names {
+			h.SetServingStatus(name, status)
+		}
+		return status
+	}
+
+	last := check(healthpb.HealthCheckResponse_UNKNOWN)
+	go func() {
+		for range time.Tick(pingInterval) {
+			last = check(last)
+		}
+	}()
+	return h
+}
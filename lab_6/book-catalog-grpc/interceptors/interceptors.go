@@ -0,0 +1,262 @@
+// Package interceptors holds the logging, metrics, recovery and validation
+// interceptors every lab_6 server installs, so handlers neither log their
+// own calls nor repeat input checks, and one bad request cannot crash a
+// server others are using.
+//
+// Metrics are served in the Prometheus text format on -metrics-addr
+// (METRICS_ADDR), e.g. ":9090"; leave it empty to not serve them.
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"book-catalog-grpc/auth"
+	"book-catalog-grpc/requestid"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var metricsAddr = flag.String("metrics-addr", os.Getenv("METRICS_ADDR"), "address to serve Prometheus /metrics on (empty: off)")
+
+var (
+	handledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed on the server, by method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_type", "grpc_code"})
+
+	handlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time taken to complete RPCs on the server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_type"})
+)
+
+// ServeMetrics starts the /metrics endpoint in the background when
+// -metrics-addr is set. Call flag.Parse first.
+func ServeMetrics() {
+	if *metricsAddr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		log.Printf("📈 Metrics on http://%s/metrics", *metricsAddr)
+		if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+			log.Printf("⚠️ Metrics server stopped: %v", err)
+		}
+	}()
+}
+
+// UnaryMetrics records every unary RPC, including ones later interceptors
+// reject, so install it first.
+func UnaryMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, "unary", start, err)
+		return resp, err
+	}
+}
+
+// StreamMetrics is UnaryMetrics for streaming RPCs; the time covers the
+// whole stream.
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, streamType(info), start, err)
+		return err
+	}
+}
+
+// UnaryLogging logs one line per unary RPC once it completes. Install it
+// after auth's and requestid's interceptors so the caller and request id
+// are known.
+func UnaryLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamLogging logs one line per streaming RPC once the stream ends.
+func StreamLogging() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryRecovery turns a panic in the rest of the chain into an Internal
+// error, logging the RPC and the stack, instead of letting it kill the
+// process. Install it after UnaryLogging so the failed call is logged and
+// counted.
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery is UnaryRecovery for streaming RPCs. A panic in a
+// goroutine the handler started is not caught.
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, fullMethod string, r any) error {
+	requestid.Printf(ctx, "💥 Panic in %s: %v\n%s", fullMethod, r, debug.Stack())
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// UnaryDeadline caps every unary RPC at max. A client deadline that is
+// sooner still applies; one that is later, or none at all, is cut to max so
+// a forgotten deadline cannot keep a handler busy forever. Streams are left
+// alone since WatchBooks is meant to stay open.
+func UnaryDeadline(max time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if d, ok := ctx.Deadline(); !ok || time.Until(d) > max {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, max)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// validator is implemented by the request messages in package proto.
+type validator interface {
+	Validate() error
+}
+
+// badRequest is implemented by proto.ValidationError, which knows the field
+// of every broken rule.
+type badRequest interface {
+	BadRequest() *errdetails.BadRequest
+}
+
+// validate attaches the broken rules to the status as a
+// google.rpc.BadRequest, so clients can tell which field failed without
+// parsing the message.
+func validate(req any) error {
+	v, ok := req.(validator)
+	if !ok {
+		return nil
+	}
+	err := v.Validate()
+	if err == nil {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	var br badRequest
+	if errors.As(err, &br) {
+		if detailed, derr := st.WithDetails(br.BadRequest()); derr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// UnaryValidation rejects requests that break their message's rules with
+// InvalidArgument before the handler runs.
+func UnaryValidation() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validate(req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamValidation checks every message the client sends on a stream; a
+// bad one fails the handler's RecvMsg, and so the stream.
+func StreamValidation() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ss})
+	}
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validate(m)
+}
+
+func observe(fullMethod, rpcType string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	handledTotal.WithLabelValues(service, method, rpcType, status.Code(err).String()).Inc()
+	handlingSeconds.WithLabelValues(service, method, rpcType).Observe(time.Since(start).Seconds())
+}
+
+func logCall(ctx context.Context, fullMethod string, start time.Time, err error) {
+	addr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	line := "%s | %s | %v | peer=%s | caller=%s"
+	args := []any{fullMethod, status.Code(err), time.Since(start).Round(time.Microsecond), addr, auth.Caller(ctx)}
+	if id := requestid.FromContext(ctx); id != "" {
+		line += " | req=%s"
+		args = append(args, id)
+	}
+	if err != nil {
+		line += " | %s"
+		args = append(args, status.Convert(err).Message())
+	}
+	log.Printf(line, args...)
+}
+
+// splitMethod turns "/bookservice.BookCatalog/GetBook" into its service and
+// method names.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", fullMethod
+	}
+	return service, method
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	}
+	return "server_stream"
+}
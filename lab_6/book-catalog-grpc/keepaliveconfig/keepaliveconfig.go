@@ -0,0 +1,70 @@
+// Package keepaliveconfig sets up HTTP/2 keepalive pings for the lab's gRPC
+// servers and clients, so an idle connection, such as a WatchBooks stream
+// waiting for the next price change, is not silently dropped by a NAT or
+// firewall in between. Each flag defaults to an environment variable:
+//
+//	-keepalive-time     KEEPALIVE_TIME     ping a connection idle this long (default 30s)
+//	-keepalive-timeout  KEEPALIVE_TIMEOUT  close it if the ping is not answered in time (default 10s)
+//	-keepalive-min-time KEEPALIVE_MIN_TIME servers reject pings more frequent than this (default 20s)
+//
+// A server closes the connection of a client that pings more often than
+// -keepalive-min-time, so keep the clients' -keepalive-time at or above it.
+//
+// Call flag.Parse before any of the options.
+package keepaliveconfig
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+var (
+	pingTime    = flag.Duration("keepalive-time", envDuration("KEEPALIVE_TIME", 30*time.Second), "ping a connection after it has been idle this long")
+	pingTimeout = flag.Duration("keepalive-timeout", envDuration("KEEPALIVE_TIMEOUT", 10*time.Second), "close the connection if a ping is not answered within this time")
+	minTime     = flag.Duration("keepalive-min-time", envDuration("KEEPALIVE_MIN_TIME", 20*time.Second), "shortest ping interval a server accepts from clients (server)")
+)
+
+// ServerParams makes a server ping idle clients itself.
+func ServerParams() grpc.ServerOption {
+	return grpc.KeepaliveParams(keepalive.ServerParameters{
+		Time:    *pingTime,
+		Timeout: *pingTimeout,
+	})
+}
+
+// EnforcementPolicy lets clients ping as often as -keepalive-min-time, even
+// with no RPC in flight; grpc-go's default of 5 minutes would cut off the
+// clients' pings with a GOAWAY.
+func EnforcementPolicy() grpc.ServerOption {
+	return grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime:             *minTime,
+		PermitWithoutStream: true,
+	})
+}
+
+// DialOption makes a client ping the server on an idle connection.
+func DialOption() grpc.DialOption {
+	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                *pingTime,
+		Timeout:             *pingTimeout,
+		PermitWithoutStream: true,
+	})
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️ Ignoring %s=%q: %v", name, v, err)
+		return def
+	}
+	return d
+}
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,13 +22,69 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Whether books came from Book service. Anything but OK means books is
+// empty because Book service could not be asked, not because the author
+// has no books.
+type GetAuthorBooksResponse_BookServiceStatus int32
+
+const (
+	GetAuthorBooksResponse_OK           GetAuthorBooksResponse_BookServiceStatus = 0
+	GetAuthorBooksResponse_UNAVAILABLE  GetAuthorBooksResponse_BookServiceStatus = 1 // The call to Book service failed
+	GetAuthorBooksResponse_CIRCUIT_OPEN GetAuthorBooksResponse_BookServiceStatus = 2 // Not called: Book service failed repeatedly of late
+)
+
+// Enum value maps for GetAuthorBooksResponse_BookServiceStatus.
+var (
+	GetAuthorBooksResponse_BookServiceStatus_name = map[int32]string{
+		0: "OK",
+		1: "UNAVAILABLE",
+		2: "CIRCUIT_OPEN",
+	}
+	GetAuthorBooksResponse_BookServiceStatus_value = map[string]int32{
+		"OK":           0,
+		"UNAVAILABLE":  1,
+		"CIRCUIT_OPEN": 2,
+	}
+)
+
+func (x GetAuthorBooksResponse_BookServiceStatus) Enum() *GetAuthorBooksResponse_BookServiceStatus {
+	p := new(GetAuthorBooksResponse_BookServiceStatus)
+	*p = x
+	return p
+}
+
+func (x GetAuthorBooksResponse_BookServiceStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetAuthorBooksResponse_BookServiceStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_author_service_proto_enumTypes[0].Descriptor()
+}
+
+func (GetAuthorBooksResponse_BookServiceStatus) Type() protoreflect.EnumType {
+	return &file_proto_author_service_proto_enumTypes[0]
+}
+
+func (x GetAuthorBooksResponse_BookServiceStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetAuthorBooksResponse_BookServiceStatus.Descriptor instead.
+func (GetAuthorBooksResponse_BookServiceStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{21, 0}
+}
+
 type Author struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Bio           string                 `protobuf:"bytes,3,opt,name=bio,proto3" json:"bio,omitempty"`
-	BirthYear     int32                  `protobuf:"varint,4,opt,name=birth_year,json=birthYear,proto3" json:"birth_year,omitempty"`
-	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Bio       string                 `protobuf:"bytes,3,opt,name=bio,proto3" json:"bio,omitempty"`
+	BirthYear int32                  `protobuf:"varint,4,opt,name=birth_year,json=birthYear,proto3" json:"birth_year,omitempty"`
+	Country   string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	// Set by author-service on create and update; unset for authors stored
+	// before it recorded them.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,6 +154,20 @@ func (x *Author) GetCountry() string {
 	return ""
 }
 
+func (x *Author) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Author) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 type GetAuthorRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -297,28 +368,33 @@ func (x *CreateAuthorResponse) GetAuthor() *Author {
 	return nil
 }
 
-type ListAuthorsRequest struct {
+// A book to create for the author in CreateAuthorWithBooks; the author and
+// author_id are filled in by author-service.
+type NewBook struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Isbn          string                 `protobuf:"bytes,2,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Stock         int32                  `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	PublishedYear int32                  `protobuf:"varint,5,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
+	Price         *Money                 `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAuthorsRequest) Reset() {
-	*x = ListAuthorsRequest{}
+func (x *NewBook) Reset() {
+	*x = NewBook{}
 	mi := &file_proto_author_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAuthorsRequest) String() string {
+func (x *NewBook) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAuthorsRequest) ProtoMessage() {}
+func (*NewBook) ProtoMessage() {}
 
-func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
+func (x *NewBook) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_author_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -330,47 +406,68 @@ func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAuthorsRequest.ProtoReflect.Descriptor instead.
-func (*ListAuthorsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NewBook.ProtoReflect.Descriptor instead.
+func (*NewBook) Descriptor() ([]byte, []int) {
 	return file_proto_author_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ListAuthorsRequest) GetPage() int32 {
+func (x *NewBook) GetTitle() string {
 	if x != nil {
-		return x.Page
+		return x.Title
+	}
+	return ""
+}
+
+func (x *NewBook) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *NewBook) GetStock() int32 {
+	if x != nil {
+		return x.Stock
 	}
 	return 0
 }
 
-func (x *ListAuthorsRequest) GetPageSize() int32 {
+func (x *NewBook) GetPublishedYear() int32 {
 	if x != nil {
-		return x.PageSize
+		return x.PublishedYear
 	}
 	return 0
 }
 
-type ListAuthorsResponse struct {
+func (x *NewBook) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+type CreateAuthorWithBooksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Author        *CreateAuthorRequest   `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Books         []*NewBook             `protobuf:"bytes,2,rep,name=books,proto3" json:"books,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAuthorsResponse) Reset() {
-	*x = ListAuthorsResponse{}
+func (x *CreateAuthorWithBooksRequest) Reset() {
+	*x = CreateAuthorWithBooksRequest{}
 	mi := &file_proto_author_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAuthorsResponse) String() string {
+func (x *CreateAuthorWithBooksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAuthorsResponse) ProtoMessage() {}
+func (*CreateAuthorWithBooksRequest) ProtoMessage() {}
 
-func (x *ListAuthorsResponse) ProtoReflect() protoreflect.Message {
+func (x *CreateAuthorWithBooksRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_author_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -382,46 +479,47 @@ func (x *ListAuthorsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAuthorsResponse.ProtoReflect.Descriptor instead.
-func (*ListAuthorsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateAuthorWithBooksRequest.ProtoReflect.Descriptor instead.
+func (*CreateAuthorWithBooksRequest) Descriptor() ([]byte, []int) {
 	return file_proto_author_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListAuthorsResponse) GetAuthors() []*Author {
+func (x *CreateAuthorWithBooksRequest) GetAuthor() *CreateAuthorRequest {
 	if x != nil {
-		return x.Authors
+		return x.Author
 	}
 	return nil
 }
 
-func (x *ListAuthorsResponse) GetTotal() int32 {
+func (x *CreateAuthorWithBooksRequest) GetBooks() []*NewBook {
 	if x != nil {
-		return x.Total
+		return x.Books
 	}
-	return 0
+	return nil
 }
 
-type GetAuthorBooksRequest struct {
+type CreateAuthorWithBooksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AuthorId      int32                  `protobuf:"varint,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Books         []*BookSummary         `protobuf:"bytes,2,rep,name=books,proto3" json:"books,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAuthorBooksRequest) Reset() {
-	*x = GetAuthorBooksRequest{}
+func (x *CreateAuthorWithBooksResponse) Reset() {
+	*x = CreateAuthorWithBooksResponse{}
 	mi := &file_proto_author_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAuthorBooksRequest) String() string {
+func (x *CreateAuthorWithBooksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAuthorBooksRequest) ProtoMessage() {}
+func (*CreateAuthorWithBooksResponse) ProtoMessage() {}
 
-func (x *GetAuthorBooksRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateAuthorWithBooksResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_author_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -433,43 +531,50 @@ func (x *GetAuthorBooksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAuthorBooksRequest.ProtoReflect.Descriptor instead.
-func (*GetAuthorBooksRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateAuthorWithBooksResponse.ProtoReflect.Descriptor instead.
+func (*CreateAuthorWithBooksResponse) Descriptor() ([]byte, []int) {
 	return file_proto_author_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetAuthorBooksRequest) GetAuthorId() int32 {
+func (x *CreateAuthorWithBooksResponse) GetAuthor() *Author {
 	if x != nil {
-		return x.AuthorId
+		return x.Author
 	}
-	return 0
+	return nil
 }
 
-// Reference to Book from book_service
-type BookSummary struct {
+func (x *CreateAuthorWithBooksResponse) GetBooks() []*BookSummary {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+type UpdateAuthorRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Price         float32                `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"`
-	PublishedYear int32                  `protobuf:"varint,4,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Bio           string                 `protobuf:"bytes,3,opt,name=bio,proto3" json:"bio,omitempty"`
+	BirthYear     int32                  `protobuf:"varint,4,opt,name=birth_year,json=birthYear,proto3" json:"birth_year,omitempty"`
+	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BookSummary) Reset() {
-	*x = BookSummary{}
+func (x *UpdateAuthorRequest) Reset() {
+	*x = UpdateAuthorRequest{}
 	mi := &file_proto_author_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BookSummary) String() string {
+func (x *UpdateAuthorRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BookSummary) ProtoMessage() {}
+func (*UpdateAuthorRequest) ProtoMessage() {}
 
-func (x *BookSummary) ProtoReflect() protoreflect.Message {
+func (x *UpdateAuthorRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_author_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -481,62 +586,67 @@ func (x *BookSummary) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BookSummary.ProtoReflect.Descriptor instead.
-func (*BookSummary) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAuthorRequest) Descriptor() ([]byte, []int) {
 	return file_proto_author_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *BookSummary) GetId() int32 {
+func (x *UpdateAuthorRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *BookSummary) GetTitle() string {
+func (x *UpdateAuthorRequest) GetName() string {
 	if x != nil {
-		return x.Title
+		return x.Name
 	}
 	return ""
 }
 
-func (x *BookSummary) GetPrice() float32 {
+func (x *UpdateAuthorRequest) GetBio() string {
 	if x != nil {
-		return x.Price
+		return x.Bio
 	}
-	return 0
+	return ""
 }
 
-func (x *BookSummary) GetPublishedYear() int32 {
+func (x *UpdateAuthorRequest) GetBirthYear() int32 {
 	if x != nil {
-		return x.PublishedYear
+		return x.BirthYear
 	}
 	return 0
 }
 
-type GetAuthorBooksResponse struct {
+func (x *UpdateAuthorRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type UpdateAuthorResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
-	Books         []*BookSummary         `protobuf:"bytes,2,rep,name=books,proto3" json:"books,omitempty"`
-	BookCount     int32                  `protobuf:"varint,3,opt,name=book_count,json=bookCount,proto3" json:"book_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAuthorBooksResponse) Reset() {
-	*x = GetAuthorBooksResponse{}
+func (x *UpdateAuthorResponse) Reset() {
+	*x = UpdateAuthorResponse{}
 	mi := &file_proto_author_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAuthorBooksResponse) String() string {
+func (x *UpdateAuthorResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAuthorBooksResponse) ProtoMessage() {}
+func (*UpdateAuthorResponse) ProtoMessage() {}
 
-func (x *GetAuthorBooksResponse) ProtoReflect() protoreflect.Message {
+func (x *UpdateAuthorResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_author_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -548,79 +658,863 @@ func (x *GetAuthorBooksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAuthorBooksResponse.ProtoReflect.Descriptor instead.
-func (*GetAuthorBooksResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateAuthorResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAuthorResponse) Descriptor() ([]byte, []int) {
 	return file_proto_author_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GetAuthorBooksResponse) GetAuthor() *Author {
+func (x *UpdateAuthorResponse) GetAuthor() *Author {
 	if x != nil {
 		return x.Author
 	}
 	return nil
 }
 
-func (x *GetAuthorBooksResponse) GetBooks() []*BookSummary {
+type DeleteAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAuthorRequest) Reset() {
+	*x = DeleteAuthorRequest{}
+	mi := &file_proto_author_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAuthorRequest) ProtoMessage() {}
+
+func (x *DeleteAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[10]
 	if x != nil {
-		return x.Books
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GetAuthorBooksResponse) GetBookCount() int32 {
+// Deprecated: Use DeleteAuthorRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteAuthorRequest) GetId() int32 {
 	if x != nil {
-		return x.BookCount
+		return x.Id
 	}
 	return 0
 }
 
-var File_proto_author_service_proto protoreflect.FileDescriptor
+type DeleteAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_author_service_proto_rawDesc = "" +
-	"\n" +
-	"\x1aproto/author_service.proto\x12\rauthorservice\"w\n" +
-	"\x06Author\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
-	"\x03bio\x18\x03 \x01(\tR\x03bio\x12\x1d\n" +
-	"\n" +
-	"birth_year\x18\x04 \x01(\x05R\tbirthYear\x12\x18\n" +
-	"\acountry\x18\x05 \x01(\tR\acountry\"\"\n" +
-	"\x10GetAuthorRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"B\n" +
-	"\x11GetAuthorResponse\x12-\n" +
-	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\"t\n" +
-	"\x13CreateAuthorRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
-	"\x03bio\x18\x02 \x01(\tR\x03bio\x12\x1d\n" +
-	"\n" +
-	"birth_year\x18\x03 \x01(\x05R\tbirthYear\x12\x18\n" +
-	"\acountry\x18\x04 \x01(\tR\acountry\"E\n" +
-	"\x14CreateAuthorResponse\x12-\n" +
-	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\"E\n" +
-	"\x12ListAuthorsRequest\x12\x12\n" +
-	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"\\\n" +
-	"\x13ListAuthorsResponse\x12/\n" +
-	"\aauthors\x18\x01 \x03(\v2\x15.authorservice.AuthorR\aauthors\x12\x14\n" +
+func (x *DeleteAuthorResponse) Reset() {
+	*x = DeleteAuthorResponse{}
+	mi := &file_proto_author_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAuthorResponse) ProtoMessage() {}
+
+func (x *DeleteAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAuthorResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteAuthorResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteAuthorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListAuthorsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Page     int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Optional; only authors created or updated at or after this time, for
+	// clients that sync incrementally. Deletions are not reported.
+	UpdatedSince  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsRequest) Reset() {
+	*x = ListAuthorsRequest{}
+	mi := &file_proto_author_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsRequest) ProtoMessage() {}
+
+func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAuthorsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAuthorsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuthorsRequest) GetUpdatedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return nil
+}
+
+type ListAuthorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsResponse) Reset() {
+	*x = ListAuthorsResponse{}
+	mi := &file_proto_author_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsResponse) ProtoMessage() {}
+
+func (x *ListAuthorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthorsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAuthorsResponse) GetAuthors() []*Author {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *ListAuthorsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Every filter is optional: an empty string or 0 leaves it out.
+type SearchAuthorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`       // substring of the name, case-insensitive
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"` // exact country, case-insensitive
+	MinBirthYear  int32                  `protobuf:"varint,3,opt,name=min_birth_year,json=minBirthYear,proto3" json:"min_birth_year,omitempty"`
+	MaxBirthYear  int32                  `protobuf:"varint,4,opt,name=max_birth_year,json=maxBirthYear,proto3" json:"max_birth_year,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchAuthorsRequest) Reset() {
+	*x = SearchAuthorsRequest{}
+	mi := &file_proto_author_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchAuthorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAuthorsRequest) ProtoMessage() {}
+
+func (x *SearchAuthorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAuthorsRequest.ProtoReflect.Descriptor instead.
+func (*SearchAuthorsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchAuthorsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SearchAuthorsRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *SearchAuthorsRequest) GetMinBirthYear() int32 {
+	if x != nil {
+		return x.MinBirthYear
+	}
+	return 0
+}
+
+func (x *SearchAuthorsRequest) GetMaxBirthYear() int32 {
+	if x != nil {
+		return x.MaxBirthYear
+	}
+	return 0
+}
+
+func (x *SearchAuthorsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchAuthorsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type SearchAuthorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // matches across all pages
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchAuthorsResponse) Reset() {
+	*x = SearchAuthorsResponse{}
+	mi := &file_proto_author_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchAuthorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAuthorsResponse) ProtoMessage() {}
+
+func (x *SearchAuthorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAuthorsResponse.ProtoReflect.Descriptor instead.
+func (*SearchAuthorsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SearchAuthorsResponse) GetAuthors() []*Author {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *SearchAuthorsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchAuthorsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchAuthorsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// Paging works as in ListAuthors.
+type AuthorStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorStatsRequest) Reset() {
+	*x = AuthorStatsRequest{}
+	mi := &file_proto_author_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorStatsRequest) ProtoMessage() {}
+
+func (x *AuthorStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorStatsRequest.ProtoReflect.Descriptor instead.
+func (*AuthorStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AuthorStatsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *AuthorStatsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// AuthorStat sums up one author's books on Book service.
+type AuthorStat struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Author     *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	BookCount  int32                  `protobuf:"varint,2,opt,name=book_count,json=bookCount,proto3" json:"book_count,omitempty"`
+	NewestYear int32                  `protobuf:"varint,4,opt,name=newest_year,json=newestYear,proto3" json:"newest_year,omitempty"` // Latest published_year; 0 without books
+	// Anything but OK means the numbers are zero because Book service could
+	// not be asked for this author.
+	BookServiceStatus GetAuthorBooksResponse_BookServiceStatus `protobuf:"varint,5,opt,name=book_service_status,json=bookServiceStatus,proto3,enum=authorservice.GetAuthorBooksResponse_BookServiceStatus" json:"book_service_status,omitempty"`
+	// Sum of price × stock over the books, one per currency, by currency
+	// code.
+	InventoryValues []*Money `protobuf:"bytes,6,rep,name=inventory_values,json=inventoryValues,proto3" json:"inventory_values,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AuthorStat) Reset() {
+	*x = AuthorStat{}
+	mi := &file_proto_author_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorStat) ProtoMessage() {}
+
+func (x *AuthorStat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorStat.ProtoReflect.Descriptor instead.
+func (*AuthorStat) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AuthorStat) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *AuthorStat) GetBookCount() int32 {
+	if x != nil {
+		return x.BookCount
+	}
+	return 0
+}
+
+func (x *AuthorStat) GetNewestYear() int32 {
+	if x != nil {
+		return x.NewestYear
+	}
+	return 0
+}
+
+func (x *AuthorStat) GetBookServiceStatus() GetAuthorBooksResponse_BookServiceStatus {
+	if x != nil {
+		return x.BookServiceStatus
+	}
+	return GetAuthorBooksResponse_OK
+}
+
+func (x *AuthorStat) GetInventoryValues() []*Money {
+	if x != nil {
+		return x.InventoryValues
+	}
+	return nil
+}
+
+type AuthorStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*AuthorStat          `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // Authors across all pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorStatsResponse) Reset() {
+	*x = AuthorStatsResponse{}
+	mi := &file_proto_author_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorStatsResponse) ProtoMessage() {}
+
+func (x *AuthorStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorStatsResponse.ProtoReflect.Descriptor instead.
+func (*AuthorStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AuthorStatsResponse) GetStats() []*AuthorStat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *AuthorStatsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetAuthorBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId      int32                  `protobuf:"varint,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorBooksRequest) Reset() {
+	*x = GetAuthorBooksRequest{}
+	mi := &file_proto_author_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorBooksRequest) ProtoMessage() {}
+
+func (x *GetAuthorBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorBooksRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthorBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetAuthorBooksRequest) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+// Reference to Book from book_service
+type BookSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	PublishedYear int32                  `protobuf:"varint,4,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
+	Price         *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookSummary) Reset() {
+	*x = BookSummary{}
+	mi := &file_proto_author_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookSummary) ProtoMessage() {}
+
+func (x *BookSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookSummary.ProtoReflect.Descriptor instead.
+func (*BookSummary) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BookSummary) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BookSummary) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *BookSummary) GetPublishedYear() int32 {
+	if x != nil {
+		return x.PublishedYear
+	}
+	return 0
+}
+
+func (x *BookSummary) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+type GetAuthorBooksResponse struct {
+	state             protoimpl.MessageState                   `protogen:"open.v1"`
+	Author            *Author                                  `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Books             []*BookSummary                           `protobuf:"bytes,2,rep,name=books,proto3" json:"books,omitempty"`
+	BookCount         int32                                    `protobuf:"varint,3,opt,name=book_count,json=bookCount,proto3" json:"book_count,omitempty"`
+	BookServiceStatus GetAuthorBooksResponse_BookServiceStatus `protobuf:"varint,4,opt,name=book_service_status,json=bookServiceStatus,proto3,enum=authorservice.GetAuthorBooksResponse_BookServiceStatus" json:"book_service_status,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetAuthorBooksResponse) Reset() {
+	*x = GetAuthorBooksResponse{}
+	mi := &file_proto_author_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorBooksResponse) ProtoMessage() {}
+
+func (x *GetAuthorBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_author_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorBooksResponse.ProtoReflect.Descriptor instead.
+func (*GetAuthorBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_author_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetAuthorBooksResponse) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *GetAuthorBooksResponse) GetBooks() []*BookSummary {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *GetAuthorBooksResponse) GetBookCount() int32 {
+	if x != nil {
+		return x.BookCount
+	}
+	return 0
+}
+
+func (x *GetAuthorBooksResponse) GetBookServiceStatus() GetAuthorBooksResponse_BookServiceStatus {
+	if x != nil {
+		return x.BookServiceStatus
+	}
+	return GetAuthorBooksResponse_OK
+}
+
+var File_proto_author_service_proto protoreflect.FileDescriptor
+
+const file_proto_author_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/author_service.proto\x12\rauthorservice\x1a\x10proto/book.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xed\x01\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03bio\x18\x03 \x01(\tR\x03bio\x12\x1d\n" +
+	"\n" +
+	"birth_year\x18\x04 \x01(\x05R\tbirthYear\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\"\n" +
+	"\x10GetAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"B\n" +
+	"\x11GetAuthorResponse\x12-\n" +
+	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\"t\n" +
+	"\x13CreateAuthorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03bio\x18\x02 \x01(\tR\x03bio\x12\x1d\n" +
+	"\n" +
+	"birth_year\x18\x03 \x01(\x05R\tbirthYear\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\"E\n" +
+	"\x14CreateAuthorResponse\x12-\n" +
+	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\"\x9e\x01\n" +
+	"\aNewBook\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04isbn\x18\x02 \x01(\tR\x04isbn\x12\x14\n" +
+	"\x05stock\x18\x04 \x01(\x05R\x05stock\x12%\n" +
+	"\x0epublished_year\x18\x05 \x01(\x05R\rpublishedYear\x12&\n" +
+	"\x05price\x18\x06 \x01(\v2\x10.bookstore.MoneyR\x05priceJ\x04\b\x03\x10\x04\"\x88\x01\n" +
+	"\x1cCreateAuthorWithBooksRequest\x12:\n" +
+	"\x06author\x18\x01 \x01(\v2\".authorservice.CreateAuthorRequestR\x06author\x12,\n" +
+	"\x05books\x18\x02 \x03(\v2\x16.authorservice.NewBookR\x05books\"\x80\x01\n" +
+	"\x1dCreateAuthorWithBooksResponse\x12-\n" +
+	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\x120\n" +
+	"\x05books\x18\x02 \x03(\v2\x1a.authorservice.BookSummaryR\x05books\"\x84\x01\n" +
+	"\x13UpdateAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03bio\x18\x03 \x01(\tR\x03bio\x12\x1d\n" +
+	"\n" +
+	"birth_year\x18\x04 \x01(\x05R\tbirthYear\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\"E\n" +
+	"\x14UpdateAuthorResponse\x12-\n" +
+	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\"%\n" +
+	"\x13DeleteAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"J\n" +
+	"\x14DeleteAuthorResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x86\x01\n" +
+	"\x12ListAuthorsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12?\n" +
+	"\rupdated_since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedSince\"\\\n" +
+	"\x13ListAuthorsResponse\x12/\n" +
+	"\aauthors\x18\x01 \x03(\v2\x15.authorservice.AuthorR\aauthors\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xc1\x01\n" +
+	"\x14SearchAuthorsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12$\n" +
+	"\x0emin_birth_year\x18\x03 \x01(\x05R\fminBirthYear\x12$\n" +
+	"\x0emax_birth_year\x18\x04 \x01(\x05R\fmaxBirthYear\x12\x12\n" +
+	"\x04page\x18\x05 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"\x8f\x01\n" +
+	"\x15SearchAuthorsResponse\x12/\n" +
+	"\aauthors\x18\x01 \x03(\v2\x15.authorservice.AuthorR\aauthors\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"E\n" +
+	"\x12AuthorStatsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"\xa7\x02\n" +
+	"\n" +
+	"AuthorStat\x12-\n" +
+	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\x12\x1d\n" +
+	"\n" +
+	"book_count\x18\x02 \x01(\x05R\tbookCount\x12\x1f\n" +
+	"\vnewest_year\x18\x04 \x01(\x05R\n" +
+	"newestYear\x12g\n" +
+	"\x13book_service_status\x18\x05 \x01(\x0e27.authorservice.GetAuthorBooksResponse.BookServiceStatusR\x11bookServiceStatus\x12;\n" +
+	"\x10inventory_values\x18\x06 \x03(\v2\x10.bookstore.MoneyR\x0finventoryValuesJ\x04\b\x03\x10\x04\"\\\n" +
+	"\x13AuthorStatsResponse\x12/\n" +
+	"\x05stats\x18\x01 \x03(\v2\x19.authorservice.AuthorStatR\x05stats\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\"4\n" +
 	"\x15GetAuthorBooksRequest\x12\x1b\n" +
-	"\tauthor_id\x18\x01 \x01(\x05R\bauthorId\"p\n" +
+	"\tauthor_id\x18\x01 \x01(\x05R\bauthorId\"\x88\x01\n" +
 	"\vBookSummary\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x02R\x05price\x12%\n" +
-	"\x0epublished_year\x18\x04 \x01(\x05R\rpublishedYear\"\x98\x01\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\x0epublished_year\x18\x04 \x01(\x05R\rpublishedYear\x12&\n" +
+	"\x05price\x18\x05 \x01(\v2\x10.bookstore.MoneyR\x05priceJ\x04\b\x03\x10\x04\"\xc1\x02\n" +
 	"\x16GetAuthorBooksResponse\x12-\n" +
 	"\x06author\x18\x01 \x01(\v2\x15.authorservice.AuthorR\x06author\x120\n" +
 	"\x05books\x18\x02 \x03(\v2\x1a.authorservice.BookSummaryR\x05books\x12\x1d\n" +
 	"\n" +
-	"book_count\x18\x03 \x01(\x05R\tbookCount2\xed\x02\n" +
+	"book_count\x18\x03 \x01(\x05R\tbookCount\x12g\n" +
+	"\x13book_service_status\x18\x04 \x01(\x0e27.authorservice.GetAuthorBooksResponse.BookServiceStatusR\x11bookServiceStatus\">\n" +
+	"\x11BookServiceStatus\x12\x06\n" +
+	"\x02OK\x10\x00\x12\x0f\n" +
+	"\vUNAVAILABLE\x10\x01\x12\x10\n" +
+	"\fCIRCUIT_OPEN\x10\x022\xc5\x06\n" +
 	"\rAuthorCatalog\x12N\n" +
 	"\tGetAuthor\x12\x1f.authorservice.GetAuthorRequest\x1a .authorservice.GetAuthorResponse\x12W\n" +
-	"\fCreateAuthor\x12\".authorservice.CreateAuthorRequest\x1a#.authorservice.CreateAuthorResponse\x12T\n" +
-	"\vListAuthors\x12!.authorservice.ListAuthorsRequest\x1a\".authorservice.ListAuthorsResponse\x12]\n" +
-	"\x0eGetAuthorBooks\x12$.authorservice.GetAuthorBooksRequest\x1a%.authorservice.GetAuthorBooksResponseB\x19Z\x17book-catalog-grpc/protob\x06proto3"
+	"\fCreateAuthor\x12\".authorservice.CreateAuthorRequest\x1a#.authorservice.CreateAuthorResponse\x12r\n" +
+	"\x15CreateAuthorWithBooks\x12+.authorservice.CreateAuthorWithBooksRequest\x1a,.authorservice.CreateAuthorWithBooksResponse\x12W\n" +
+	"\fUpdateAuthor\x12\".authorservice.UpdateAuthorRequest\x1a#.authorservice.UpdateAuthorResponse\x12W\n" +
+	"\fDeleteAuthor\x12\".authorservice.DeleteAuthorRequest\x1a#.authorservice.DeleteAuthorResponse\x12T\n" +
+	"\vListAuthors\x12!.authorservice.ListAuthorsRequest\x1a\".authorservice.ListAuthorsResponse\x12Z\n" +
+	"\rSearchAuthors\x12#.authorservice.SearchAuthorsRequest\x1a$.authorservice.SearchAuthorsResponse\x12]\n" +
+	"\x0eGetAuthorBooks\x12$.authorservice.GetAuthorBooksRequest\x1a%.authorservice.GetAuthorBooksResponse\x12T\n" +
+	"\vAuthorStats\x12!.authorservice.AuthorStatsRequest\x1a\".authorservice.AuthorStatsResponseB\x19Z\x17book-catalog-grpc/protob\x06proto3"
 
 var (
 	file_proto_author_service_proto_rawDescOnce sync.Once
@@ -634,38 +1528,80 @@ func file_proto_author_service_proto_rawDescGZIP() []byte {
 	return file_proto_author_service_proto_rawDescData
 }
 
-var file_proto_author_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_author_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_author_service_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_proto_author_service_proto_goTypes = []any{
-	(*Author)(nil),                 // 0: authorservice.Author
-	(*GetAuthorRequest)(nil),       // 1: authorservice.GetAuthorRequest
-	(*GetAuthorResponse)(nil),      // 2: authorservice.GetAuthorResponse
-	(*CreateAuthorRequest)(nil),    // 3: authorservice.CreateAuthorRequest
-	(*CreateAuthorResponse)(nil),   // 4: authorservice.CreateAuthorResponse
-	(*ListAuthorsRequest)(nil),     // 5: authorservice.ListAuthorsRequest
-	(*ListAuthorsResponse)(nil),    // 6: authorservice.ListAuthorsResponse
-	(*GetAuthorBooksRequest)(nil),  // 7: authorservice.GetAuthorBooksRequest
-	(*BookSummary)(nil),            // 8: authorservice.BookSummary
-	(*GetAuthorBooksResponse)(nil), // 9: authorservice.GetAuthorBooksResponse
+	(GetAuthorBooksResponse_BookServiceStatus)(0), // 0: authorservice.GetAuthorBooksResponse.BookServiceStatus
+	(*Author)(nil),                        // 1: authorservice.Author
+	(*GetAuthorRequest)(nil),              // 2: authorservice.GetAuthorRequest
+	(*GetAuthorResponse)(nil),             // 3: authorservice.GetAuthorResponse
+	(*CreateAuthorRequest)(nil),           // 4: authorservice.CreateAuthorRequest
+	(*CreateAuthorResponse)(nil),          // 5: authorservice.CreateAuthorResponse
+	(*NewBook)(nil),                       // 6: authorservice.NewBook
+	(*CreateAuthorWithBooksRequest)(nil),  // 7: authorservice.CreateAuthorWithBooksRequest
+	(*CreateAuthorWithBooksResponse)(nil), // 8: authorservice.CreateAuthorWithBooksResponse
+	(*UpdateAuthorRequest)(nil),           // 9: authorservice.UpdateAuthorRequest
+	(*UpdateAuthorResponse)(nil),          // 10: authorservice.UpdateAuthorResponse
+	(*DeleteAuthorRequest)(nil),           // 11: authorservice.DeleteAuthorRequest
+	(*DeleteAuthorResponse)(nil),          // 12: authorservice.DeleteAuthorResponse
+	(*ListAuthorsRequest)(nil),            // 13: authorservice.ListAuthorsRequest
+	(*ListAuthorsResponse)(nil),           // 14: authorservice.ListAuthorsResponse
+	(*SearchAuthorsRequest)(nil),          // 15: authorservice.SearchAuthorsRequest
+	(*SearchAuthorsResponse)(nil),         // 16: authorservice.SearchAuthorsResponse
+	(*AuthorStatsRequest)(nil),            // 17: authorservice.AuthorStatsRequest
+	(*AuthorStat)(nil),                    // 18: authorservice.AuthorStat
+	(*AuthorStatsResponse)(nil),           // 19: authorservice.AuthorStatsResponse
+	(*GetAuthorBooksRequest)(nil),         // 20: authorservice.GetAuthorBooksRequest
+	(*BookSummary)(nil),                   // 21: authorservice.BookSummary
+	(*GetAuthorBooksResponse)(nil),        // 22: authorservice.GetAuthorBooksResponse
+	(*timestamppb.Timestamp)(nil),         // 23: google.protobuf.Timestamp
+	(*Money)(nil),                         // 24: bookstore.Money
 }
 var file_proto_author_service_proto_depIdxs = []int32{
-	0, // 0: authorservice.GetAuthorResponse.author:type_name -> authorservice.Author
-	0, // 1: authorservice.CreateAuthorResponse.author:type_name -> authorservice.Author
-	0, // 2: authorservice.ListAuthorsResponse.authors:type_name -> authorservice.Author
-	0, // 3: authorservice.GetAuthorBooksResponse.author:type_name -> authorservice.Author
-	8, // 4: authorservice.GetAuthorBooksResponse.books:type_name -> authorservice.BookSummary
-	1, // 5: authorservice.AuthorCatalog.GetAuthor:input_type -> authorservice.GetAuthorRequest
-	3, // 6: authorservice.AuthorCatalog.CreateAuthor:input_type -> authorservice.CreateAuthorRequest
-	5, // 7: authorservice.AuthorCatalog.ListAuthors:input_type -> authorservice.ListAuthorsRequest
-	7, // 8: authorservice.AuthorCatalog.GetAuthorBooks:input_type -> authorservice.GetAuthorBooksRequest
-	2, // 9: authorservice.AuthorCatalog.GetAuthor:output_type -> authorservice.GetAuthorResponse
-	4, // 10: authorservice.AuthorCatalog.CreateAuthor:output_type -> authorservice.CreateAuthorResponse
-	6, // 11: authorservice.AuthorCatalog.ListAuthors:output_type -> authorservice.ListAuthorsResponse
-	9, // 12: authorservice.AuthorCatalog.GetAuthorBooks:output_type -> authorservice.GetAuthorBooksResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	23, // 0: authorservice.Author.created_at:type_name -> google.protobuf.Timestamp
+	23, // 1: authorservice.Author.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: authorservice.GetAuthorResponse.author:type_name -> authorservice.Author
+	1,  // 3: authorservice.CreateAuthorResponse.author:type_name -> authorservice.Author
+	24, // 4: authorservice.NewBook.price:type_name -> bookstore.Money
+	4,  // 5: authorservice.CreateAuthorWithBooksRequest.author:type_name -> authorservice.CreateAuthorRequest
+	6,  // 6: authorservice.CreateAuthorWithBooksRequest.books:type_name -> authorservice.NewBook
+	1,  // 7: authorservice.CreateAuthorWithBooksResponse.author:type_name -> authorservice.Author
+	21, // 8: authorservice.CreateAuthorWithBooksResponse.books:type_name -> authorservice.BookSummary
+	1,  // 9: authorservice.UpdateAuthorResponse.author:type_name -> authorservice.Author
+	23, // 10: authorservice.ListAuthorsRequest.updated_since:type_name -> google.protobuf.Timestamp
+	1,  // 11: authorservice.ListAuthorsResponse.authors:type_name -> authorservice.Author
+	1,  // 12: authorservice.SearchAuthorsResponse.authors:type_name -> authorservice.Author
+	1,  // 13: authorservice.AuthorStat.author:type_name -> authorservice.Author
+	0,  // 14: authorservice.AuthorStat.book_service_status:type_name -> authorservice.GetAuthorBooksResponse.BookServiceStatus
+	24, // 15: authorservice.AuthorStat.inventory_values:type_name -> bookstore.Money
+	18, // 16: authorservice.AuthorStatsResponse.stats:type_name -> authorservice.AuthorStat
+	24, // 17: authorservice.BookSummary.price:type_name -> bookstore.Money
+	1,  // 18: authorservice.GetAuthorBooksResponse.author:type_name -> authorservice.Author
+	21, // 19: authorservice.GetAuthorBooksResponse.books:type_name -> authorservice.BookSummary
+	0,  // 20: authorservice.GetAuthorBooksResponse.book_service_status:type_name -> authorservice.GetAuthorBooksResponse.BookServiceStatus
+	2,  // 21: authorservice.AuthorCatalog.GetAuthor:input_type -> authorservice.GetAuthorRequest
+	4,  // 22: authorservice.AuthorCatalog.CreateAuthor:input_type -> authorservice.CreateAuthorRequest
+	7,  // 23: authorservice.AuthorCatalog.CreateAuthorWithBooks:input_type -> authorservice.CreateAuthorWithBooksRequest
+	9,  // 24: authorservice.AuthorCatalog.UpdateAuthor:input_type -> authorservice.UpdateAuthorRequest
+	11, // 25: authorservice.AuthorCatalog.DeleteAuthor:input_type -> authorservice.DeleteAuthorRequest
+	13, // 26: authorservice.AuthorCatalog.ListAuthors:input_type -> authorservice.ListAuthorsRequest
+	15, // 27: authorservice.AuthorCatalog.SearchAuthors:input_type -> authorservice.SearchAuthorsRequest
+	20, // 28: authorservice.AuthorCatalog.GetAuthorBooks:input_type -> authorservice.GetAuthorBooksRequest
+	17, // 29: authorservice.AuthorCatalog.AuthorStats:input_type -> authorservice.AuthorStatsRequest
+	3,  // 30: authorservice.AuthorCatalog.GetAuthor:output_type -> authorservice.GetAuthorResponse
+	5,  // 31: authorservice.AuthorCatalog.CreateAuthor:output_type -> authorservice.CreateAuthorResponse
+	8,  // 32: authorservice.AuthorCatalog.CreateAuthorWithBooks:output_type -> authorservice.CreateAuthorWithBooksResponse
+	10, // 33: authorservice.AuthorCatalog.UpdateAuthor:output_type -> authorservice.UpdateAuthorResponse
+	12, // 34: authorservice.AuthorCatalog.DeleteAuthor:output_type -> authorservice.DeleteAuthorResponse
+	14, // 35: authorservice.AuthorCatalog.ListAuthors:output_type -> authorservice.ListAuthorsResponse
+	16, // 36: authorservice.AuthorCatalog.SearchAuthors:output_type -> authorservice.SearchAuthorsResponse
+	22, // 37: authorservice.AuthorCatalog.GetAuthorBooks:output_type -> authorservice.GetAuthorBooksResponse
+	19, // 38: authorservice.AuthorCatalog.AuthorStats:output_type -> authorservice.AuthorStatsResponse
+	30, // [30:39] is the sub-list for method output_type
+	21, // [21:30] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_proto_author_service_proto_init() }
@@ -673,18 +1609,20 @@ func file_proto_author_service_proto_init() {
 	if File_proto_author_service_proto != nil {
 		return
 	}
+	file_proto_book_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_author_service_proto_rawDesc), len(file_proto_author_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      1,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_author_service_proto_goTypes,
 		DependencyIndexes: file_proto_author_service_proto_depIdxs,
+		EnumInfos:         file_proto_author_service_proto_enumTypes,
 		MessageInfos:      file_proto_author_service_proto_msgTypes,
 	}.Build()
 	File_proto_author_service_proto = out.File
@@ -19,10 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuthorCatalog_GetAuthor_FullMethodName      = "/authorservice.AuthorCatalog/GetAuthor"
-	AuthorCatalog_CreateAuthor_FullMethodName   = "/authorservice.AuthorCatalog/CreateAuthor"
-	AuthorCatalog_ListAuthors_FullMethodName    = "/authorservice.AuthorCatalog/ListAuthors"
-	AuthorCatalog_GetAuthorBooks_FullMethodName = "/authorservice.AuthorCatalog/GetAuthorBooks"
+	AuthorCatalog_GetAuthor_FullMethodName             = "/authorservice.AuthorCatalog/GetAuthor"
+	AuthorCatalog_CreateAuthor_FullMethodName          = "/authorservice.AuthorCatalog/CreateAuthor"
+	AuthorCatalog_CreateAuthorWithBooks_FullMethodName = "/authorservice.AuthorCatalog/CreateAuthorWithBooks"
+	AuthorCatalog_UpdateAuthor_FullMethodName          = "/authorservice.AuthorCatalog/UpdateAuthor"
+	AuthorCatalog_DeleteAuthor_FullMethodName          = "/authorservice.AuthorCatalog/DeleteAuthor"
+	AuthorCatalog_ListAuthors_FullMethodName           = "/authorservice.AuthorCatalog/ListAuthors"
+	AuthorCatalog_SearchAuthors_FullMethodName         = "/authorservice.AuthorCatalog/SearchAuthors"
+	AuthorCatalog_GetAuthorBooks_FullMethodName        = "/authorservice.AuthorCatalog/GetAuthorBooks"
+	AuthorCatalog_AuthorStats_FullMethodName           = "/authorservice.AuthorCatalog/AuthorStats"
 )
 
 // AuthorCatalogClient is the client API for AuthorCatalog service.
@@ -31,8 +36,21 @@ const (
 type AuthorCatalogClient interface {
 	GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*GetAuthorResponse, error)
 	CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*CreateAuthorResponse, error)
+	// CreateAuthorWithBooks creates the author, then each book on book-service.
+	// If a book fails, the books already created and the author are deleted
+	// again and the book's error is returned.
+	CreateAuthorWithBooks(ctx context.Context, in *CreateAuthorWithBooksRequest, opts ...grpc.CallOption) (*CreateAuthorWithBooksResponse, error)
+	UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*UpdateAuthorResponse, error)
+	// DeleteAuthor fails with FAILED_PRECONDITION while book-service still has
+	// books by the author.
+	DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteAuthorResponse, error)
 	ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (*ListAuthorsResponse, error)
+	SearchAuthors(ctx context.Context, in *SearchAuthorsRequest, opts ...grpc.CallOption) (*SearchAuthorsResponse, error)
 	GetAuthorBooks(ctx context.Context, in *GetAuthorBooksRequest, opts ...grpc.CallOption) (*GetAuthorBooksResponse, error)
+	// AuthorStats returns a page of authors with book count, inventory value
+	// and newest publication year, from one GetBooksByAuthor call per author
+	// on book-service.
+	AuthorStats(ctx context.Context, in *AuthorStatsRequest, opts ...grpc.CallOption) (*AuthorStatsResponse, error)
 }
 
 type authorCatalogClient struct {
@@ -63,6 +81,36 @@ func (c *authorCatalogClient) CreateAuthor(ctx context.Context, in *CreateAuthor
 	return out, nil
 }
 
+func (c *authorCatalogClient) CreateAuthorWithBooks(ctx context.Context, in *CreateAuthorWithBooksRequest, opts ...grpc.CallOption) (*CreateAuthorWithBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAuthorWithBooksResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalog_CreateAuthorWithBooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogClient) UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*UpdateAuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalog_UpdateAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogClient) DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteAuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalog_DeleteAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authorCatalogClient) ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (*ListAuthorsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListAuthorsResponse)
@@ -73,6 +121,16 @@ func (c *authorCatalogClient) ListAuthors(ctx context.Context, in *ListAuthorsRe
 	return out, nil
 }
 
+func (c *authorCatalogClient) SearchAuthors(ctx context.Context, in *SearchAuthorsRequest, opts ...grpc.CallOption) (*SearchAuthorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalog_SearchAuthors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authorCatalogClient) GetAuthorBooks(ctx context.Context, in *GetAuthorBooksRequest, opts ...grpc.CallOption) (*GetAuthorBooksResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAuthorBooksResponse)
@@ -83,14 +141,37 @@ func (c *authorCatalogClient) GetAuthorBooks(ctx context.Context, in *GetAuthorB
 	return out, nil
 }
 
+func (c *authorCatalogClient) AuthorStats(ctx context.Context, in *AuthorStatsRequest, opts ...grpc.CallOption) (*AuthorStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorStatsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalog_AuthorStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthorCatalogServer is the server API for AuthorCatalog service.
 // All implementations must embed UnimplementedAuthorCatalogServer
 // for forward compatibility.
 type AuthorCatalogServer interface {
 	GetAuthor(context.Context, *GetAuthorRequest) (*GetAuthorResponse, error)
 	CreateAuthor(context.Context, *CreateAuthorRequest) (*CreateAuthorResponse, error)
+	// CreateAuthorWithBooks creates the author, then each book on book-service.
+	// If a book fails, the books already created and the author are deleted
+	// again and the book's error is returned.
+	CreateAuthorWithBooks(context.Context, *CreateAuthorWithBooksRequest) (*CreateAuthorWithBooksResponse, error)
+	UpdateAuthor(context.Context, *UpdateAuthorRequest) (*UpdateAuthorResponse, error)
+	// DeleteAuthor fails with FAILED_PRECONDITION while book-service still has
+	// books by the author.
+	DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteAuthorResponse, error)
 	ListAuthors(context.Context, *ListAuthorsRequest) (*ListAuthorsResponse, error)
+	SearchAuthors(context.Context, *SearchAuthorsRequest) (*SearchAuthorsResponse, error)
 	GetAuthorBooks(context.Context, *GetAuthorBooksRequest) (*GetAuthorBooksResponse, error)
+	// AuthorStats returns a page of authors with book count, inventory value
+	// and newest publication year, from one GetBooksByAuthor call per author
+	// on book-service.
+	AuthorStats(context.Context, *AuthorStatsRequest) (*AuthorStatsResponse, error)
 	mustEmbedUnimplementedAuthorCatalogServer()
 }
 
@@ -107,12 +188,27 @@ func (UnimplementedAuthorCatalogServer) GetAuthor(context.Context, *GetAuthorReq
 func (UnimplementedAuthorCatalogServer) CreateAuthor(context.Context, *CreateAuthorRequest) (*CreateAuthorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthor not implemented")
 }
+func (UnimplementedAuthorCatalogServer) CreateAuthorWithBooks(context.Context, *CreateAuthorWithBooksRequest) (*CreateAuthorWithBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthorWithBooks not implemented")
+}
+func (UnimplementedAuthorCatalogServer) UpdateAuthor(context.Context, *UpdateAuthorRequest) (*UpdateAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServer) DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthor not implemented")
+}
 func (UnimplementedAuthorCatalogServer) ListAuthors(context.Context, *ListAuthorsRequest) (*ListAuthorsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAuthors not implemented")
 }
+func (UnimplementedAuthorCatalogServer) SearchAuthors(context.Context, *SearchAuthorsRequest) (*SearchAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAuthors not implemented")
+}
 func (UnimplementedAuthorCatalogServer) GetAuthorBooks(context.Context, *GetAuthorBooksRequest) (*GetAuthorBooksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorBooks not implemented")
 }
+func (UnimplementedAuthorCatalogServer) AuthorStats(context.Context, *AuthorStatsRequest) (*AuthorStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorStats not implemented")
+}
 func (UnimplementedAuthorCatalogServer) mustEmbedUnimplementedAuthorCatalogServer() {}
 func (UnimplementedAuthorCatalogServer) testEmbeddedByValue()                       {}
 
@@ -170,6 +266,60 @@ func _AuthorCatalog_CreateAuthor_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthorCatalog_CreateAuthorWithBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuthorWithBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServer).CreateAuthorWithBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalog_CreateAuthorWithBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServer).CreateAuthorWithBooks(ctx, req.(*CreateAuthorWithBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalog_UpdateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServer).UpdateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalog_UpdateAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServer).UpdateAuthor(ctx, req.(*UpdateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalog_DeleteAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServer).DeleteAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalog_DeleteAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServer).DeleteAuthor(ctx, req.(*DeleteAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthorCatalog_ListAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAuthorsRequest)
 	if err := dec(in); err != nil {
@@ -188,6 +338,24 @@ func _AuthorCatalog_ListAuthors_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthorCatalog_SearchAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServer).SearchAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalog_SearchAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServer).SearchAuthors(ctx, req.(*SearchAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthorCatalog_GetAuthorBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAuthorBooksRequest)
 	if err := dec(in); err != nil {
@@ -206,6 +374,24 @@ func _AuthorCatalog_GetAuthorBooks_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthorCatalog_AuthorStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServer).AuthorStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalog_AuthorStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServer).AuthorStats(ctx, req.(*AuthorStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthorCatalog_ServiceDesc is the grpc.ServiceDesc for AuthorCatalog service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -221,14 +407,34 @@ var AuthorCatalog_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateAuthor",
 			Handler:    _AuthorCatalog_CreateAuthor_Handler,
 		},
+		{
+			MethodName: "CreateAuthorWithBooks",
+			Handler:    _AuthorCatalog_CreateAuthorWithBooks_Handler,
+		},
+		{
+			MethodName: "UpdateAuthor",
+			Handler:    _AuthorCatalog_UpdateAuthor_Handler,
+		},
+		{
+			MethodName: "DeleteAuthor",
+			Handler:    _AuthorCatalog_DeleteAuthor_Handler,
+		},
 		{
 			MethodName: "ListAuthors",
 			Handler:    _AuthorCatalog_ListAuthors_Handler,
 		},
+		{
+			MethodName: "SearchAuthors",
+			Handler:    _AuthorCatalog_SearchAuthors_Handler,
+		},
 		{
 			MethodName: "GetAuthorBooks",
 			Handler:    _AuthorCatalog_GetAuthorBooks_Handler,
 		},
+		{
+			MethodName: "AuthorStats",
+			Handler:    _AuthorCatalog_AuthorStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/author_service.proto",
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -88,10 +89,18 @@ type Book struct {
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
 	Isbn          string                 `protobuf:"bytes,4,opt,name=isbn,proto3" json:"isbn,omitempty"`
-	Price         float32                `protobuf:"fixed32,5,opt,name=price,proto3" json:"price,omitempty"`
 	Stock         int32                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
 	PublishedYear int32                  `protobuf:"varint,7,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
 	AuthorId      int32                  `protobuf:"varint,8,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // Foreign key to Author service
+	Category      BookCategory           `protobuf:"varint,9,opt,name=category,proto3,enum=bookstore.BookCategory" json:"category,omitempty"`
+	Price         *Money                 `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	// Kept by the Task5 book-service; unset from the Task3/Task4 servers and
+	// for books stored before it recorded them.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Opaque; changes with every update. UpdateBook on the Task5 book-service
+	// must send the etag of the book as last read. Empty from Task3/Task4.
+	Etag          string `protobuf:"bytes,13,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -154,13 +163,6 @@ func (x *Book) GetIsbn() string {
 	return ""
 }
 
-func (x *Book) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
 func (x *Book) GetStock() int32 {
 	if x != nil {
 		return x.Stock
@@ -182,6 +184,105 @@ func (x *Book) GetAuthorId() int32 {
 	return 0
 }
 
+func (x *Book) GetCategory() BookCategory {
+	if x != nil {
+		return x.Category
+	}
+	return BookCategory_UNKNOWN
+}
+
+func (x *Book) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Book) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Book) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Book) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+// Money is an amount in one currency, laid out like google.type.Money:
+// whole units plus nanos (billionths of a unit) of the same sign, so
+// prices add and compare exactly instead of as floats. 44.99 USD is
+// {currency_code: "USD", units: 44, nanos: 990000000}.
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyCode  string                 `protobuf:"bytes,1,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"` // ISO 4217, e.g. "USD"
+	Units         int64                  `protobuf:"varint,2,opt,name=units,proto3" json:"units,omitempty"`
+	Nanos         int32                  `protobuf:"varint,3,opt,name=nanos,proto3" json:"nanos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_proto_book_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Money) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *Money) GetUnits() int64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *Money) GetNanos() int32 {
+	if x != nil {
+		return x.Nanos
+	}
+	return 0
+}
+
 type DetailedBook struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
@@ -195,7 +296,7 @@ type DetailedBook struct {
 
 func (x *DetailedBook) Reset() {
 	*x = DetailedBook{}
-	mi := &file_proto_book_proto_msgTypes[1]
+	mi := &file_proto_book_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -207,7 +308,7 @@ func (x *DetailedBook) String() string {
 func (*DetailedBook) ProtoMessage() {}
 
 func (x *DetailedBook) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[1]
+	mi := &file_proto_book_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -220,7 +321,7 @@ func (x *DetailedBook) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DetailedBook.ProtoReflect.Descriptor instead.
 func (*DetailedBook) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{1}
+	return file_proto_book_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *DetailedBook) GetBook() *Book {
@@ -262,16 +363,27 @@ var File_proto_book_proto protoreflect.FileDescriptor
 
 const file_proto_book_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/book.proto\x12\tbookstore\"\xc8\x01\n" +
+	"\x10proto/book.proto\x12\tbookstore\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9f\x03\n" +
 	"\x04Book\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
 	"\x06author\x18\x03 \x01(\tR\x06author\x12\x12\n" +
 	"\x04isbn\x18\x04 \x01(\tR\x04isbn\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x02R\x05price\x12\x14\n" +
 	"\x05stock\x18\x06 \x01(\x05R\x05stock\x12%\n" +
 	"\x0epublished_year\x18\a \x01(\x05R\rpublishedYear\x12\x1b\n" +
-	"\tauthor_id\x18\b \x01(\x05R\bauthorId\"\xb6\x01\n" +
+	"\tauthor_id\x18\b \x01(\x05R\bauthorId\x123\n" +
+	"\bcategory\x18\t \x01(\x0e2\x17.bookstore.BookCategoryR\bcategory\x12&\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\x10.bookstore.MoneyR\x05price\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04etag\x18\r \x01(\tR\x04etagJ\x04\b\x05\x10\x06\"X\n" +
+	"\x05Money\x12#\n" +
+	"\rcurrency_code\x18\x01 \x01(\tR\fcurrencyCode\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\x03R\x05units\x12\x14\n" +
+	"\x05nanos\x18\x03 \x01(\x05R\x05nanos\"\xb6\x01\n" +
 	"\fDetailedBook\x12#\n" +
 	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\x123\n" +
 	"\bcategory\x18\x02 \x01(\x0e2\x17.bookstore.BookCategoryR\bcategory\x12 \n" +
@@ -301,20 +413,26 @@ func file_proto_book_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_book_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_proto_book_proto_goTypes = []any{
-	(BookCategory)(0),    // 0: bookstore.BookCategory
-	(*Book)(nil),         // 1: bookstore.Book
-	(*DetailedBook)(nil), // 2: bookstore.DetailedBook
+	(BookCategory)(0),             // 0: bookstore.BookCategory
+	(*Book)(nil),                  // 1: bookstore.Book
+	(*Money)(nil),                 // 2: bookstore.Money
+	(*DetailedBook)(nil),          // 3: bookstore.DetailedBook
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
 }
 var file_proto_book_proto_depIdxs = []int32{
-	1, // 0: bookstore.DetailedBook.book:type_name -> bookstore.Book
-	0, // 1: bookstore.DetailedBook.category:type_name -> bookstore.BookCategory
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0, // 0: bookstore.Book.category:type_name -> bookstore.BookCategory
+	2, // 1: bookstore.Book.price:type_name -> bookstore.Money
+	4, // 2: bookstore.Book.created_at:type_name -> google.protobuf.Timestamp
+	4, // 3: bookstore.Book.updated_at:type_name -> google.protobuf.Timestamp
+	1, // 4: bookstore.DetailedBook.book:type_name -> bookstore.Book
+	0, // 5: bookstore.DetailedBook.category:type_name -> bookstore.BookCategory
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_proto_book_proto_init() }
@@ -328,7 +446,7 @@ func file_proto_book_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_book_proto_rawDesc), len(file_proto_book_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -9,6 +9,8 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,9 +23,209 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type WatchRequest_Action int32
+
+const (
+	WatchRequest_SUBSCRIBE   WatchRequest_Action = 0
+	WatchRequest_UNSUBSCRIBE WatchRequest_Action = 1
+)
+
+// Enum value maps for WatchRequest_Action.
+var (
+	WatchRequest_Action_name = map[int32]string{
+		0: "SUBSCRIBE",
+		1: "UNSUBSCRIBE",
+	}
+	WatchRequest_Action_value = map[string]int32{
+		"SUBSCRIBE":   0,
+		"UNSUBSCRIBE": 1,
+	}
+)
+
+func (x WatchRequest_Action) Enum() *WatchRequest_Action {
+	p := new(WatchRequest_Action)
+	*p = x
+	return p
+}
+
+func (x WatchRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_book_service_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchRequest_Action) Type() protoreflect.EnumType {
+	return &file_proto_book_service_proto_enumTypes[0]
+}
+
+func (x WatchRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchRequest_Action.Descriptor instead.
+func (WatchRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{21, 0}
+}
+
+type BookEvent_Type int32
+
+const (
+	BookEvent_SNAPSHOT      BookEvent_Type = 0
+	BookEvent_PRICE_CHANGED BookEvent_Type = 1
+	BookEvent_STOCK_CHANGED BookEvent_Type = 2
+	BookEvent_DELETED       BookEvent_Type = 3
+)
+
+// Enum value maps for BookEvent_Type.
+var (
+	BookEvent_Type_name = map[int32]string{
+		0: "SNAPSHOT",
+		1: "PRICE_CHANGED",
+		2: "STOCK_CHANGED",
+		3: "DELETED",
+	}
+	BookEvent_Type_value = map[string]int32{
+		"SNAPSHOT":      0,
+		"PRICE_CHANGED": 1,
+		"STOCK_CHANGED": 2,
+		"DELETED":       3,
+	}
+)
+
+func (x BookEvent_Type) Enum() *BookEvent_Type {
+	p := new(BookEvent_Type)
+	*p = x
+	return p
+}
+
+func (x BookEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BookEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_book_service_proto_enumTypes[1].Descriptor()
+}
+
+func (BookEvent_Type) Type() protoreflect.EnumType {
+	return &file_proto_book_service_proto_enumTypes[1]
+}
+
+func (x BookEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BookEvent_Type.Descriptor instead.
+func (BookEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{22, 0}
+}
+
+type LifecycleEvent_Type int32
+
+const (
+	LifecycleEvent_BOOK_CREATED   LifecycleEvent_Type = 0
+	LifecycleEvent_BOOK_UPDATED   LifecycleEvent_Type = 1
+	LifecycleEvent_BOOK_DELETED   LifecycleEvent_Type = 2
+	LifecycleEvent_STOCK_CHANGED  LifecycleEvent_Type = 3
+	LifecycleEvent_BOOK_UNDELETED LifecycleEvent_Type = 4
+)
+
+// Enum value maps for LifecycleEvent_Type.
+var (
+	LifecycleEvent_Type_name = map[int32]string{
+		0: "BOOK_CREATED",
+		1: "BOOK_UPDATED",
+		2: "BOOK_DELETED",
+		3: "STOCK_CHANGED",
+		4: "BOOK_UNDELETED",
+	}
+	LifecycleEvent_Type_value = map[string]int32{
+		"BOOK_CREATED":   0,
+		"BOOK_UPDATED":   1,
+		"BOOK_DELETED":   2,
+		"STOCK_CHANGED":  3,
+		"BOOK_UNDELETED": 4,
+	}
+)
+
+func (x LifecycleEvent_Type) Enum() *LifecycleEvent_Type {
+	p := new(LifecycleEvent_Type)
+	*p = x
+	return p
+}
+
+func (x LifecycleEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LifecycleEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_book_service_proto_enumTypes[2].Descriptor()
+}
+
+func (LifecycleEvent_Type) Type() protoreflect.EnumType {
+	return &file_proto_book_service_proto_enumTypes[2]
+}
+
+func (x LifecycleEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LifecycleEvent_Type.Descriptor instead.
+func (LifecycleEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{23, 0}
+}
+
+type ExportRequest_Format int32
+
+const (
+	ExportRequest_JSONL ExportRequest_Format = 0 // One JSON object per line, fields named as in book.proto
+	ExportRequest_CSV   ExportRequest_Format = 1 // Header row first; the columns ImportBooks reads, plus id
+)
+
+// Enum value maps for ExportRequest_Format.
+var (
+	ExportRequest_Format_name = map[int32]string{
+		0: "JSONL",
+		1: "CSV",
+	}
+	ExportRequest_Format_value = map[string]int32{
+		"JSONL": 0,
+		"CSV":   1,
+	}
+)
+
+func (x ExportRequest_Format) Enum() *ExportRequest_Format {
+	p := new(ExportRequest_Format)
+	*p = x
+	return p
+}
+
+func (x ExportRequest_Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportRequest_Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_book_service_proto_enumTypes[3].Descriptor()
+}
+
+func (ExportRequest_Format) Type() protoreflect.EnumType {
+	return &file_proto_book_service_proto_enumTypes[3]
+}
+
+func (x ExportRequest_Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportRequest_Format.Descriptor instead.
+func (ExportRequest_Format) EnumDescriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{30, 0}
+}
+
 type GetBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeRating bool                   `protobuf:"varint,2,opt,name=include_rating,json=includeRating,proto3" json:"include_rating,omitempty"` // Also return the book's average review rating
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,9 +267,22 @@ func (x *GetBookRequest) GetId() int32 {
 	return 0
 }
 
+func (x *GetBookRequest) GetIncludeRating() bool {
+	if x != nil {
+		return x.IncludeRating
+	}
+	return false
+}
+
 type GetBookResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Book   *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	Rating *BookRating            `protobuf:"bytes,2,opt,name=rating,proto3" json:"rating,omitempty"` // Set only with include_rating
+	// The book's author as Author service knows them, so a client can show
+	// both without a second call. Set only when book-service runs with
+	// -author-details and Author service has book.author_id.
+	AuthorName    string `protobuf:"bytes,3,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"`
+	AuthorCountry string `protobuf:"bytes,4,opt,name=author_country,json=authorCountry,proto3" json:"author_country,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,15 +324,41 @@ func (x *GetBookResponse) GetBook() *Book {
 	return nil
 }
 
+func (x *GetBookResponse) GetRating() *BookRating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+func (x *GetBookResponse) GetAuthorName() string {
+	if x != nil {
+		return x.AuthorName
+	}
+	return ""
+}
+
+func (x *GetBookResponse) GetAuthorCountry() string {
+	if x != nil {
+		return x.AuthorCountry
+	}
+	return ""
+}
+
 type CreateBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Author        string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
 	Isbn          string                 `protobuf:"bytes,3,opt,name=isbn,proto3" json:"isbn,omitempty"`
-	Price         float32                `protobuf:"fixed32,4,opt,name=price,proto3" json:"price,omitempty"`
 	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
 	PublishedYear int32                  `protobuf:"varint,6,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
 	AuthorId      int32                  `protobuf:"varint,7,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // Foreign key to Author
+	Category      BookCategory           `protobuf:"varint,8,opt,name=category,proto3,enum=bookstore.BookCategory" json:"category,omitempty"`
+	// Optional idempotency key, unique per book to create, e.g. a random UUID.
+	// A retry with a request_id seen in the last 24 hours returns the book the
+	// first call created instead of creating another.
+	RequestId     string `protobuf:"bytes,9,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Price         *Money `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -173,13 +414,6 @@ func (x *CreateBookRequest) GetIsbn() string {
 	return ""
 }
 
-func (x *CreateBookRequest) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
 func (x *CreateBookRequest) GetStock() int32 {
 	if x != nil {
 		return x.Stock
@@ -201,6 +435,27 @@ func (x *CreateBookRequest) GetAuthorId() int32 {
 	return 0
 }
 
+func (x *CreateBookRequest) GetCategory() BookCategory {
+	if x != nil {
+		return x.Category
+	}
+	return BookCategory_UNKNOWN
+}
+
+func (x *CreateBookRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
 type CreateBookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
@@ -251,10 +506,18 @@ type UpdateBookRequest struct {
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
 	Isbn          string                 `protobuf:"bytes,4,opt,name=isbn,proto3" json:"isbn,omitempty"`
-	Price         float32                `protobuf:"fixed32,5,opt,name=price,proto3" json:"price,omitempty"`
 	Stock         int32                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
 	PublishedYear int32                  `protobuf:"varint,7,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
 	AuthorId      int32                  `protobuf:"varint,8,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // Foreign key to Author
+	// Fields to change, e.g. paths: ["price", "stock"]. Fields not listed keep
+	// their stored value. Empty replaces every field, as before.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,9,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Category   BookCategory           `protobuf:"varint,10,opt,name=category,proto3,enum=bookstore.BookCategory" json:"category,omitempty"`
+	Price      *Money                 `protobuf:"bytes,11,opt,name=price,proto3" json:"price,omitempty"`
+	// The etag of the book as last read. Required by the Task5 book-service,
+	// which answers ABORTED if the book has changed since, so that one
+	// client's update cannot silently undo another's.
+	Etag          string `protobuf:"bytes,12,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -317,13 +580,6 @@ func (x *UpdateBookRequest) GetIsbn() string {
 	return ""
 }
 
-func (x *UpdateBookRequest) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
 func (x *UpdateBookRequest) GetStock() int32 {
 	if x != nil {
 		return x.Stock
@@ -345,6 +601,34 @@ func (x *UpdateBookRequest) GetAuthorId() int32 {
 	return 0
 }
 
+func (x *UpdateBookRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetCategory() BookCategory {
+	if x != nil {
+		return x.Category
+	}
+	return BookCategory_UNKNOWN
+}
+
+func (x *UpdateBookRequest) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type UpdateBookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
@@ -390,8 +674,11 @@ func (x *UpdateBookResponse) GetBook() *Book {
 }
 
 type DeleteBookRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// hard removes the row for good. Otherwise the book is only marked
+	// deleted: it disappears from every read but UndeleteBook brings it back.
+	Hard          bool `protobuf:"varint,2,opt,name=hard,proto3" json:"hard,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -433,6 +720,13 @@ func (x *DeleteBookRequest) GetId() int32 {
 	return 0
 }
 
+func (x *DeleteBookRequest) GetHard() bool {
+	if x != nil {
+		return x.Hard
+	}
+	return false
+}
+
 type DeleteBookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -485,28 +779,27 @@ func (x *DeleteBookResponse) GetMessage() string {
 	return ""
 }
 
-type ListBooksRequest struct {
+type UndeleteBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListBooksRequest) Reset() {
-	*x = ListBooksRequest{}
+func (x *UndeleteBookRequest) Reset() {
+	*x = UndeleteBookRequest{}
 	mi := &file_proto_book_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListBooksRequest) String() string {
+func (x *UndeleteBookRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBooksRequest) ProtoMessage() {}
+func (*UndeleteBookRequest) ProtoMessage() {}
 
-func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+func (x *UndeleteBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_book_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -518,49 +811,39 @@ func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
-func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UndeleteBookRequest.ProtoReflect.Descriptor instead.
+func (*UndeleteBookRequest) Descriptor() ([]byte, []int) {
 	return file_proto_book_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListBooksRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListBooksRequest) GetPageSize() int32 {
+func (x *UndeleteBookRequest) GetId() int32 {
 	if x != nil {
-		return x.PageSize
+		return x.Id
 	}
 	return 0
 }
 
-type ListBooksResponse struct {
+type UndeleteBookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListBooksResponse) Reset() {
-	*x = ListBooksResponse{}
+func (x *UndeleteBookResponse) Reset() {
+	*x = UndeleteBookResponse{}
 	mi := &file_proto_book_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListBooksResponse) String() string {
+func (x *UndeleteBookResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBooksResponse) ProtoMessage() {}
+func (*UndeleteBookResponse) ProtoMessage() {}
 
-func (x *ListBooksResponse) ProtoReflect() protoreflect.Message {
+func (x *UndeleteBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_book_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -572,61 +855,48 @@ func (x *ListBooksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBooksResponse.ProtoReflect.Descriptor instead.
-func (*ListBooksResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UndeleteBookResponse.ProtoReflect.Descriptor instead.
+func (*UndeleteBookResponse) Descriptor() ([]byte, []int) {
 	return file_proto_book_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ListBooksResponse) GetBooks() []*Book {
+func (x *UndeleteBookResponse) GetBook() *Book {
 	if x != nil {
-		return x.Books
+		return x.Book
 	}
 	return nil
 }
 
-func (x *ListBooksResponse) GetTotal() int32 {
-	if x != nil {
-		return x.Total
-	}
-	return 0
-}
-
-func (x *ListBooksResponse) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListBooksResponse) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
-	}
-	return 0
-}
-
-type SearchBooksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"` // "title", "author", "isbn", or "all"
+type ListBooksRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Page     int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Comma-separated sort keys, each a field optionally followed by asc or
+	// desc, e.g. "price desc" or "published_year asc, title". Empty sorts by id.
+	OrderBy string `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Optional; only books matching it are listed and counted in total.
+	Filter *FilterBooksRequest `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Optional; only books created or updated at or after this time, for
+	// clients that sync incrementally. Deletions are not reported.
+	UpdatedSince  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchBooksRequest) Reset() {
-	*x = SearchBooksRequest{}
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
 	mi := &file_proto_book_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchBooksRequest) String() string {
+func (x *ListBooksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchBooksRequest) ProtoMessage() {}
+func (*ListBooksRequest) ProtoMessage() {}
 
-func (x *SearchBooksRequest) ProtoReflect() protoreflect.Message {
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_book_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -638,49 +908,811 @@ func (x *SearchBooksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchBooksRequest.ProtoReflect.Descriptor instead.
-func (*SearchBooksRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
 	return file_proto_book_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *SearchBooksRequest) GetQuery() string {
+func (x *ListBooksRequest) GetPage() int32 {
 	if x != nil {
-		return x.Query
+		return x.Page
 	}
-	return ""
+	return 0
 }
 
-func (x *SearchBooksRequest) GetField() string {
+func (x *ListBooksRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Field
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBooksRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
 	}
 	return ""
 }
 
-type SearchBooksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
-	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
-	Query         string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ListBooksRequest) GetFilter() *FilterBooksRequest {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
 }
 
-func (x *SearchBooksResponse) Reset() {
-	*x = SearchBooksResponse{}
-	mi := &file_proto_book_service_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+func (x *ListBooksRequest) GetUpdatedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return nil
+}
+
+type ListBooksResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Books    []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Total    int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page     int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Worked out by the server from total, page and page_size, so clients
+	// need not. total_pages is 0 when nothing matches.
+	TotalPages    int32 `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	HasNext       bool  `protobuf:"varint,6,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	HasPrev       bool  `protobuf:"varint,7,opt,name=has_prev,json=hasPrev,proto3" json:"has_prev,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksResponse) Reset() {
+	*x = ListBooksResponse{}
+	mi := &file_proto_book_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksResponse) ProtoMessage() {}
+
+func (x *ListBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksResponse.ProtoReflect.Descriptor instead.
+func (*ListBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *ListBooksResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListBooksResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListBooksResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBooksResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *ListBooksResponse) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *ListBooksResponse) GetHasPrev() bool {
+	if x != nil {
+		return x.HasPrev
+	}
+	return false
+}
+
+type SearchBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Field string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"` // "title", "author", "isbn", or "all"
+	// fuzzy matches title and author by edit distance, so misspelt words
+	// still match; results are ranked by hits[i].similarity. Not valid with
+	// field "isbn".
+	Fuzzy         bool `protobuf:"varint,3,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchBooksRequest) Reset() {
+	*x = SearchBooksRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBooksRequest) ProtoMessage() {}
+
+func (x *SearchBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBooksRequest.ProtoReflect.Descriptor instead.
+func (*SearchBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchBooksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchBooksRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SearchBooksRequest) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+type SearchBooksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Books []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Count int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Query string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	// hits[i] describes books[i], best match first, when the search went
+	// through the full-text index or was fuzzy. Empty when the server fell
+	// back to LIKE.
+	Hits          []*SearchHit `protobuf:"bytes,4,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchBooksResponse) Reset() {
+	*x = SearchBooksResponse{}
+	mi := &file_proto_book_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBooksResponse) ProtoMessage() {}
+
+func (x *SearchBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBooksResponse.ProtoReflect.Descriptor instead.
+func (*SearchBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *SearchBooksResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SearchBooksResponse) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchBooksResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+type SearchHit struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookId    int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Relevance float64                `protobuf:"fixed64,2,opt,name=relevance,proto3" json:"relevance,omitempty"` // Negated FTS5 bm25(); higher is more relevant
+	// Best matching field. Matched words are in [brackets], except for fuzzy
+	// searches, which return the field as is.
+	Snippet       string  `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	Similarity    float64 `protobuf:"fixed64,4,opt,name=similarity,proto3" json:"similarity,omitempty"` // Fuzzy searches only: 0 to 1, 1 is an exact match
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_proto_book_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchHit) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *SearchHit) GetRelevance() float64 {
+	if x != nil {
+		return x.Relevance
+	}
+	return 0
+}
+
+func (x *SearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *SearchHit) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type FilterBooksRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	MinYear int32                  `protobuf:"varint,3,opt,name=min_year,json=minYear,proto3" json:"min_year,omitempty"`
+	MaxYear int32                  `protobuf:"varint,4,opt,name=max_year,json=maxYear,proto3" json:"max_year,omitempty"`
+	// Books in any of these categories; empty matches every category.
+	Categories []BookCategory `protobuf:"varint,5,rep,packed,name=categories,proto3,enum=bookstore.BookCategory" json:"categories,omitempty"`
+	// Price bounds, both optional. A bound only matches books priced in its
+	// currency; with both set they must be in the same currency.
+	MinPrice *Money `protobuf:"bytes,6,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice *Money `protobuf:"bytes,7,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	// Only books with stock left.
+	InStockOnly bool `protobuf:"varint,8,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	// Stock bounds; 0 means no bound, as for years.
+	MinStock int32 `protobuf:"varint,9,opt,name=min_stock,json=minStock,proto3" json:"min_stock,omitempty"`
+	MaxStock int32 `protobuf:"varint,10,opt,name=max_stock,json=maxStock,proto3" json:"max_stock,omitempty"`
+	// Only books by this author (Author service id); 0 matches every author.
+	AuthorId      int32 `protobuf:"varint,11,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterBooksRequest) Reset() {
+	*x = FilterBooksRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterBooksRequest) ProtoMessage() {}
+
+func (x *FilterBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterBooksRequest.ProtoReflect.Descriptor instead.
+func (*FilterBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FilterBooksRequest) GetMinYear() int32 {
+	if x != nil {
+		return x.MinYear
+	}
+	return 0
+}
+
+func (x *FilterBooksRequest) GetMaxYear() int32 {
+	if x != nil {
+		return x.MaxYear
+	}
+	return 0
+}
+
+func (x *FilterBooksRequest) GetCategories() []BookCategory {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *FilterBooksRequest) GetMinPrice() *Money {
+	if x != nil {
+		return x.MinPrice
+	}
+	return nil
+}
+
+func (x *FilterBooksRequest) GetMaxPrice() *Money {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+func (x *FilterBooksRequest) GetInStockOnly() bool {
+	if x != nil {
+		return x.InStockOnly
+	}
+	return false
+}
+
+func (x *FilterBooksRequest) GetMinStock() int32 {
+	if x != nil {
+		return x.MinStock
+	}
+	return 0
+}
+
+func (x *FilterBooksRequest) GetMaxStock() int32 {
+	if x != nil {
+		return x.MaxStock
+	}
+	return 0
+}
+
+func (x *FilterBooksRequest) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+type FilterBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterBooksResponse) Reset() {
+	*x = FilterBooksResponse{}
+	mi := &file_proto_book_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterBooksResponse) ProtoMessage() {}
+
+func (x *FilterBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterBooksResponse.ProtoReflect.Descriptor instead.
+func (*FilterBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FilterBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *FilterBooksResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{17}
+}
+
+type GetStatsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TotalBooks   int32                  `protobuf:"varint,1,opt,name=total_books,json=totalBooks,proto3" json:"total_books,omitempty"`
+	TotalStock   int32                  `protobuf:"varint,3,opt,name=total_stock,json=totalStock,proto3" json:"total_stock,omitempty"`
+	EarliestYear int32                  `protobuf:"varint,4,opt,name=earliest_year,json=earliestYear,proto3" json:"earliest_year,omitempty"`
+	LatestYear   int32                  `protobuf:"varint,5,opt,name=latest_year,json=latestYear,proto3" json:"latest_year,omitempty"`
+	// One average per currency in the catalog, by currency code, since
+	// prices in different currencies cannot be averaged together.
+	AveragePrices []*Money `protobuf:"bytes,6,rep,name=average_prices,json=averagePrices,proto3" json:"average_prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_proto_book_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetStatsResponse) GetTotalBooks() int32 {
+	if x != nil {
+		return x.TotalBooks
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetTotalStock() int32 {
+	if x != nil {
+		return x.TotalStock
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetEarliestYear() int32 {
+	if x != nil {
+		return x.EarliestYear
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetLatestYear() int32 {
+	if x != nil {
+		return x.LatestYear
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetAveragePrices() []*Money {
+	if x != nil {
+		return x.AveragePrices
+	}
+	return nil
+}
+
+type GetBooksByAuthorRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId int32                  `protobuf:"varint,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Also return soft-deleted books, which UndeleteBook can bring back. Author
+	// service sets it before deleting an author.
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetBooksByAuthorRequest) Reset() {
+	*x = GetBooksByAuthorRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBooksByAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBooksByAuthorRequest) ProtoMessage() {}
+
+func (x *GetBooksByAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBooksByAuthorRequest.ProtoReflect.Descriptor instead.
+func (*GetBooksByAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetBooksByAuthorRequest) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+func (x *GetBooksByAuthorRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type GetBooksByAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBooksByAuthorResponse) Reset() {
+	*x = GetBooksByAuthorResponse{}
+	mi := &file_proto_book_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBooksByAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBooksByAuthorResponse) ProtoMessage() {}
+
+func (x *GetBooksByAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBooksByAuthorResponse.ProtoReflect.Descriptor instead.
+func (*GetBooksByAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetBooksByAuthorResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *GetBooksByAuthorResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// WatchRequest changes which books a WatchBooks stream follows. Subscribing
+// to a book sends its current state back as a SNAPSHOT event.
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        WatchRequest_Action    `protobuf:"varint,1,opt,name=action,proto3,enum=bookservice.WatchRequest_Action" json:"action,omitempty"`
+	BookIds       []int32                `protobuf:"varint,2,rep,packed,name=book_ids,json=bookIds,proto3" json:"book_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchRequest) GetAction() WatchRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return WatchRequest_SUBSCRIBE
+}
+
+func (x *WatchRequest) GetBookIds() []int32 {
+	if x != nil {
+		return x.BookIds
+	}
+	return nil
+}
+
+type BookEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          BookEvent_Type         `protobuf:"varint,1,opt,name=type,proto3,enum=bookservice.BookEvent_Type" json:"type,omitempty"`
+	BookId        int32                  `protobuf:"varint,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Book          *Book                  `protobuf:"bytes,3,opt,name=book,proto3" json:"book,omitempty"` // State after the change; empty for DELETED
+	OldStock      int32                  `protobuf:"varint,5,opt,name=old_stock,json=oldStock,proto3" json:"old_stock,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix seconds
+	OldPrice      *Money                 `protobuf:"bytes,7,opt,name=old_price,json=oldPrice,proto3" json:"old_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookEvent) Reset() {
+	*x = BookEvent{}
+	mi := &file_proto_book_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchBooksResponse) String() string {
+func (x *BookEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchBooksResponse) ProtoMessage() {}
+func (*BookEvent) ProtoMessage() {}
 
-func (x *SearchBooksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[11]
+func (x *BookEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -691,57 +1723,82 @@ func (x *SearchBooksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchBooksResponse.ProtoReflect.Descriptor instead.
-func (*SearchBooksResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use BookEvent.ProtoReflect.Descriptor instead.
+func (*BookEvent) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *SearchBooksResponse) GetBooks() []*Book {
+func (x *BookEvent) GetType() BookEvent_Type {
 	if x != nil {
-		return x.Books
+		return x.Type
+	}
+	return BookEvent_SNAPSHOT
+}
+
+func (x *BookEvent) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *BookEvent) GetBook() *Book {
+	if x != nil {
+		return x.Book
 	}
 	return nil
 }
 
-func (x *SearchBooksResponse) GetCount() int32 {
+func (x *BookEvent) GetOldStock() int32 {
 	if x != nil {
-		return x.Count
+		return x.OldStock
 	}
 	return 0
 }
 
-func (x *SearchBooksResponse) GetQuery() string {
+func (x *BookEvent) GetTimestamp() int64 {
 	if x != nil {
-		return x.Query
+		return x.Timestamp
 	}
-	return ""
+	return 0
 }
 
-type FilterBooksRequest struct {
+func (x *BookEvent) GetOldPrice() *Money {
+	if x != nil {
+		return x.OldPrice
+	}
+	return nil
+}
+
+// LifecycleEvent is published by book-service after every successful
+// write. An UpdateBook that changes the stock publishes BOOK_UPDATED and then
+// STOCK_CHANGED.
+type LifecycleEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MinPrice      float32                `protobuf:"fixed32,1,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
-	MaxPrice      float32                `protobuf:"fixed32,2,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
-	MinYear       int32                  `protobuf:"varint,3,opt,name=min_year,json=minYear,proto3" json:"min_year,omitempty"`
-	MaxYear       int32                  `protobuf:"varint,4,opt,name=max_year,json=maxYear,proto3" json:"max_year,omitempty"`
+	Type          LifecycleEvent_Type    `protobuf:"varint,1,opt,name=type,proto3,enum=bookservice.LifecycleEvent_Type" json:"type,omitempty"`
+	BookId        int32                  `protobuf:"varint,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Book          *Book                  `protobuf:"bytes,3,opt,name=book,proto3" json:"book,omitempty"`                          // State after the change; empty for BOOK_DELETED
+	OldStock      int32                  `protobuf:"varint,4,opt,name=old_stock,json=oldStock,proto3" json:"old_stock,omitempty"` // Set for STOCK_CHANGED
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`               // Unix seconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FilterBooksRequest) Reset() {
-	*x = FilterBooksRequest{}
-	mi := &file_proto_book_service_proto_msgTypes[12]
+func (x *LifecycleEvent) Reset() {
+	*x = LifecycleEvent{}
+	mi := &file_proto_book_service_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FilterBooksRequest) String() string {
+func (x *LifecycleEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FilterBooksRequest) ProtoMessage() {}
+func (*LifecycleEvent) ProtoMessage() {}
 
-func (x *FilterBooksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[12]
+func (x *LifecycleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -752,62 +1809,162 @@ func (x *FilterBooksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FilterBooksRequest.ProtoReflect.Descriptor instead.
-func (*FilterBooksRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use LifecycleEvent.ProtoReflect.Descriptor instead.
+func (*LifecycleEvent) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *FilterBooksRequest) GetMinPrice() float32 {
+func (x *LifecycleEvent) GetType() LifecycleEvent_Type {
 	if x != nil {
-		return x.MinPrice
+		return x.Type
+	}
+	return LifecycleEvent_BOOK_CREATED
+}
+
+func (x *LifecycleEvent) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
 	}
 	return 0
 }
 
-func (x *FilterBooksRequest) GetMaxPrice() float32 {
+func (x *LifecycleEvent) GetBook() *Book {
 	if x != nil {
-		return x.MaxPrice
+		return x.Book
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetOldStock() int32 {
+	if x != nil {
+		return x.OldStock
 	}
 	return 0
 }
 
-func (x *FilterBooksRequest) GetMinYear() int32 {
+func (x *LifecycleEvent) GetTimestamp() int64 {
 	if x != nil {
-		return x.MinYear
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *FilterBooksRequest) GetMaxYear() int32 {
+type SubscribeEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Types         []LifecycleEvent_Type  `protobuf:"varint,1,rep,packed,name=types,proto3,enum=bookservice.LifecycleEvent_Type" json:"types,omitempty"` // Empty subscribes to every type
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeEventsRequest) Reset() {
+	*x = SubscribeEventsRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[24]
 	if x != nil {
-		return x.MaxYear
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SubscribeEventsRequest) GetTypes() []LifecycleEvent_Type {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type WatchLowStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Threshold     int32                  `protobuf:"varint,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLowStockRequest) Reset() {
+	*x = WatchLowStockRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLowStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLowStockRequest) ProtoMessage() {}
+
+func (x *WatchLowStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLowStockRequest.ProtoReflect.Descriptor instead.
+func (*WatchLowStockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WatchLowStockRequest) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
 	}
 	return 0
 }
 
-type FilterBooksResponse struct {
+// LowStockAlert reports a book whose stock fell from threshold or more to
+// below it.
+type LowStockAlert struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
-	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	BookId        int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Book          *Book                  `protobuf:"bytes,2,opt,name=book,proto3" json:"book,omitempty"` // State after the change
+	OldStock      int32                  `protobuf:"varint,3,opt,name=old_stock,json=oldStock,proto3" json:"old_stock,omitempty"`
+	Threshold     int32                  `protobuf:"varint,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix seconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FilterBooksResponse) Reset() {
-	*x = FilterBooksResponse{}
-	mi := &file_proto_book_service_proto_msgTypes[13]
+func (x *LowStockAlert) Reset() {
+	*x = LowStockAlert{}
+	mi := &file_proto_book_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FilterBooksResponse) String() string {
+func (x *LowStockAlert) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FilterBooksResponse) ProtoMessage() {}
+func (*LowStockAlert) ProtoMessage() {}
 
-func (x *FilterBooksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[13]
+func (x *LowStockAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -818,46 +1975,73 @@ func (x *FilterBooksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FilterBooksResponse.ProtoReflect.Descriptor instead.
-func (*FilterBooksResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use LowStockAlert.ProtoReflect.Descriptor instead.
+func (*LowStockAlert) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *FilterBooksResponse) GetBooks() []*Book {
+func (x *LowStockAlert) GetBookId() int32 {
 	if x != nil {
-		return x.Books
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *LowStockAlert) GetBook() *Book {
+	if x != nil {
+		return x.Book
 	}
 	return nil
 }
 
-func (x *FilterBooksResponse) GetCount() int32 {
+func (x *LowStockAlert) GetOldStock() int32 {
 	if x != nil {
-		return x.Count
+		return x.OldStock
 	}
 	return 0
 }
 
-type GetStatsRequest struct {
+func (x *LowStockAlert) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *LowStockAlert) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// ImportChunk carries the next bytes of a CSV file for ImportBooks; a row may
+// be split across chunks. The first row is the header naming the columns, in
+// any order: title and author are required, isbn, price, stock,
+// published_year and author_id are optional, and id is ignored so an
+// ExportBooks CSV can be imported again.
+type ImportChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
-	mi := &file_proto_book_service_proto_msgTypes[14]
+func (x *ImportChunk) Reset() {
+	*x = ImportChunk{}
+	mi := &file_proto_book_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *ImportChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*ImportChunk) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[14]
+func (x *ImportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,37 +2052,46 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ImportChunk.ProtoReflect.Descriptor instead.
+func (*ImportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{27}
 }
 
-type GetStatsResponse struct {
+func (x *ImportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ImportProgress is sent after each batch of rows is committed, and once
+// more with done set when the whole file has been read.
+type ImportProgress struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalBooks    int32                  `protobuf:"varint,1,opt,name=total_books,json=totalBooks,proto3" json:"total_books,omitempty"`
-	AveragePrice  float32                `protobuf:"fixed32,2,opt,name=average_price,json=averagePrice,proto3" json:"average_price,omitempty"`
-	TotalStock    int32                  `protobuf:"varint,3,opt,name=total_stock,json=totalStock,proto3" json:"total_stock,omitempty"`
-	EarliestYear  int32                  `protobuf:"varint,4,opt,name=earliest_year,json=earliestYear,proto3" json:"earliest_year,omitempty"`
-	LatestYear    int32                  `protobuf:"varint,5,opt,name=latest_year,json=latestYear,proto3" json:"latest_year,omitempty"`
+	RowsProcessed int32                  `protobuf:"varint,1,opt,name=rows_processed,json=rowsProcessed,proto3" json:"rows_processed,omitempty"` // Data rows read so far, header excluded
+	RowsImported  int32                  `protobuf:"varint,2,opt,name=rows_imported,json=rowsImported,proto3" json:"rows_imported,omitempty"`
+	RowsFailed    int32                  `protobuf:"varint,3,opt,name=rows_failed,json=rowsFailed,proto3" json:"rows_failed,omitempty"`
+	Errors        []*ImportError         `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"` // Rows rejected since the previous message
+	Done          bool                   `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsResponse) Reset() {
-	*x = GetStatsResponse{}
-	mi := &file_proto_book_service_proto_msgTypes[15]
+func (x *ImportProgress) Reset() {
+	*x = ImportProgress{}
+	mi := &file_proto_book_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsResponse) String() string {
+func (x *ImportProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsResponse) ProtoMessage() {}
+func (*ImportProgress) ProtoMessage() {}
 
-func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[15]
+func (x *ImportProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -909,68 +2102,69 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ImportProgress.ProtoReflect.Descriptor instead.
+func (*ImportProgress) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *GetStatsResponse) GetTotalBooks() int32 {
+func (x *ImportProgress) GetRowsProcessed() int32 {
 	if x != nil {
-		return x.TotalBooks
+		return x.RowsProcessed
 	}
 	return 0
 }
 
-func (x *GetStatsResponse) GetAveragePrice() float32 {
+func (x *ImportProgress) GetRowsImported() int32 {
 	if x != nil {
-		return x.AveragePrice
+		return x.RowsImported
 	}
 	return 0
 }
 
-func (x *GetStatsResponse) GetTotalStock() int32 {
+func (x *ImportProgress) GetRowsFailed() int32 {
 	if x != nil {
-		return x.TotalStock
+		return x.RowsFailed
 	}
 	return 0
 }
 
-func (x *GetStatsResponse) GetEarliestYear() int32 {
+func (x *ImportProgress) GetErrors() []*ImportError {
 	if x != nil {
-		return x.EarliestYear
+		return x.Errors
 	}
-	return 0
+	return nil
 }
 
-func (x *GetStatsResponse) GetLatestYear() int32 {
+func (x *ImportProgress) GetDone() bool {
 	if x != nil {
-		return x.LatestYear
+		return x.Done
 	}
-	return 0
+	return false
 }
 
-type GetBooksByAuthorRequest struct {
+type ImportError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AuthorId      int32                  `protobuf:"varint,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"` // Line in the CSV file; the header is line 1
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetBooksByAuthorRequest) Reset() {
-	*x = GetBooksByAuthorRequest{}
-	mi := &file_proto_book_service_proto_msgTypes[16]
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	mi := &file_proto_book_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetBooksByAuthorRequest) String() string {
+func (x *ImportError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetBooksByAuthorRequest) ProtoMessage() {}
+func (*ImportError) ProtoMessage() {}
 
-func (x *GetBooksByAuthorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[16]
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -981,41 +2175,47 @@ func (x *GetBooksByAuthorRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetBooksByAuthorRequest.ProtoReflect.Descriptor instead.
-func (*GetBooksByAuthorRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *GetBooksByAuthorRequest) GetAuthorId() int32 {
+func (x *ImportError) GetLine() int32 {
 	if x != nil {
-		return x.AuthorId
+		return x.Line
 	}
 	return 0
 }
 
-type GetBooksByAuthorResponse struct {
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExportRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
-	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Format        ExportRequest_Format   `protobuf:"varint,1,opt,name=format,proto3,enum=bookservice.ExportRequest_Format" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetBooksByAuthorResponse) Reset() {
-	*x = GetBooksByAuthorResponse{}
-	mi := &file_proto_book_service_proto_msgTypes[17]
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_proto_book_service_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetBooksByAuthorResponse) String() string {
+func (x *ExportRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetBooksByAuthorResponse) ProtoMessage() {}
+func (*ExportRequest) ProtoMessage() {}
 
-func (x *GetBooksByAuthorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_service_proto_msgTypes[17]
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,98 +2226,240 @@ func (x *GetBooksByAuthorResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetBooksByAuthorResponse.ProtoReflect.Descriptor instead.
-func (*GetBooksByAuthorResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_service_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *GetBooksByAuthorResponse) GetBooks() []*Book {
+func (x *ExportRequest) GetFormat() ExportRequest_Format {
 	if x != nil {
-		return x.Books
+		return x.Format
 	}
-	return nil
+	return ExportRequest_JSONL
 }
 
-func (x *GetBooksByAuthorResponse) GetCount() int32 {
+// ExportChunk holds the next bytes of the export. Chunks end on a row
+// boundary, but clients should simply concatenate them.
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_proto_book_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_service_proto_msgTypes[31]
 	if x != nil {
-		return x.Count
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_book_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
 }
 
 var File_proto_book_service_proto protoreflect.FileDescriptor
 
 const file_proto_book_service_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/book_service.proto\x12\vbookservice\x1a\x10proto/book.proto\" \n" +
+	"\x18proto/book_service.proto\x12\vbookservice\x1a\x10proto/book.proto\x1a\x1aproto/review_service.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"G\n" +
 	"\x0eGetBookRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"6\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12%\n" +
+	"\x0einclude_rating\x18\x02 \x01(\bR\rincludeRating\"\xb1\x01\n" +
 	"\x0fGetBookResponse\x12#\n" +
-	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"\xc5\x01\n" +
+	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\x121\n" +
+	"\x06rating\x18\x02 \x01(\v2\x19.reviewservice.BookRatingR\x06rating\x12\x1f\n" +
+	"\vauthor_name\x18\x03 \x01(\tR\n" +
+	"authorName\x12%\n" +
+	"\x0eauthor_country\x18\x04 \x01(\tR\rauthorCountry\"\xb1\x02\n" +
 	"\x11CreateBookRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x16\n" +
 	"\x06author\x18\x02 \x01(\tR\x06author\x12\x12\n" +
 	"\x04isbn\x18\x03 \x01(\tR\x04isbn\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x02R\x05price\x12\x14\n" +
 	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12%\n" +
 	"\x0epublished_year\x18\x06 \x01(\x05R\rpublishedYear\x12\x1b\n" +
-	"\tauthor_id\x18\a \x01(\x05R\bauthorId\"9\n" +
+	"\tauthor_id\x18\a \x01(\x05R\bauthorId\x123\n" +
+	"\bcategory\x18\b \x01(\x0e2\x17.bookstore.BookCategoryR\bcategory\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\t \x01(\tR\trequestId\x12&\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\x10.bookstore.MoneyR\x05priceJ\x04\b\x04\x10\x05\"9\n" +
 	"\x12CreateBookResponse\x12#\n" +
-	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"\xd5\x01\n" +
+	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"\xf3\x02\n" +
 	"\x11UpdateBookRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
 	"\x06author\x18\x03 \x01(\tR\x06author\x12\x12\n" +
 	"\x04isbn\x18\x04 \x01(\tR\x04isbn\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x02R\x05price\x12\x14\n" +
 	"\x05stock\x18\x06 \x01(\x05R\x05stock\x12%\n" +
 	"\x0epublished_year\x18\a \x01(\x05R\rpublishedYear\x12\x1b\n" +
-	"\tauthor_id\x18\b \x01(\x05R\bauthorId\"9\n" +
+	"\tauthor_id\x18\b \x01(\x05R\bauthorId\x12;\n" +
+	"\vupdate_mask\x18\t \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x123\n" +
+	"\bcategory\x18\n" +
+	" \x01(\x0e2\x17.bookstore.BookCategoryR\bcategory\x12&\n" +
+	"\x05price\x18\v \x01(\v2\x10.bookstore.MoneyR\x05price\x12\x12\n" +
+	"\x04etag\x18\f \x01(\tR\x04etagJ\x04\b\x05\x10\x06\"9\n" +
 	"\x12UpdateBookResponse\x12#\n" +
-	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"#\n" +
+	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"7\n" +
 	"\x11DeleteBookRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"H\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04hard\x18\x02 \x01(\bR\x04hard\"H\n" +
 	"\x12DeleteBookResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"C\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"%\n" +
+	"\x13UndeleteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\";\n" +
+	"\x14UndeleteBookResponse\x12#\n" +
+	"\x04book\x18\x01 \x01(\v2\x0f.bookstore.BookR\x04book\"\xd8\x01\n" +
 	"\x10ListBooksRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"\x81\x01\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\border_by\x18\x03 \x01(\tR\aorderBy\x127\n" +
+	"\x06filter\x18\x04 \x01(\v2\x1f.bookservice.FilterBooksRequestR\x06filter\x12?\n" +
+	"\rupdated_since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedSince\"\xd8\x01\n" +
 	"\x11ListBooksResponse\x12%\n" +
 	"\x05books\x18\x01 \x03(\v2\x0f.bookstore.BookR\x05books\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"@\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages\x12\x19\n" +
+	"\bhas_next\x18\x06 \x01(\bR\ahasNext\x12\x19\n" +
+	"\bhas_prev\x18\a \x01(\bR\ahasPrev\"V\n" +
 	"\x12SearchBooksRequest\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
-	"\x05field\x18\x02 \x01(\tR\x05field\"h\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x14\n" +
+	"\x05fuzzy\x18\x03 \x01(\bR\x05fuzzy\"\x94\x01\n" +
 	"\x13SearchBooksResponse\x12%\n" +
 	"\x05books\x18\x01 \x03(\v2\x0f.bookstore.BookR\x05books\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x14\n" +
-	"\x05query\x18\x03 \x01(\tR\x05query\"\x84\x01\n" +
-	"\x12FilterBooksRequest\x12\x1b\n" +
-	"\tmin_price\x18\x01 \x01(\x02R\bminPrice\x12\x1b\n" +
-	"\tmax_price\x18\x02 \x01(\x02R\bmaxPrice\x12\x19\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12*\n" +
+	"\x04hits\x18\x04 \x03(\v2\x16.bookservice.SearchHitR\x04hits\"|\n" +
+	"\tSearchHit\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\x12\x1c\n" +
+	"\trelevance\x18\x02 \x01(\x01R\trelevance\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x04 \x01(\x01R\n" +
+	"similarity\"\xe8\x02\n" +
+	"\x12FilterBooksRequest\x12\x19\n" +
 	"\bmin_year\x18\x03 \x01(\x05R\aminYear\x12\x19\n" +
-	"\bmax_year\x18\x04 \x01(\x05R\amaxYear\"R\n" +
+	"\bmax_year\x18\x04 \x01(\x05R\amaxYear\x127\n" +
+	"\n" +
+	"categories\x18\x05 \x03(\x0e2\x17.bookstore.BookCategoryR\n" +
+	"categories\x12-\n" +
+	"\tmin_price\x18\x06 \x01(\v2\x10.bookstore.MoneyR\bminPrice\x12-\n" +
+	"\tmax_price\x18\a \x01(\v2\x10.bookstore.MoneyR\bmaxPrice\x12\"\n" +
+	"\rin_stock_only\x18\b \x01(\bR\vinStockOnly\x12\x1b\n" +
+	"\tmin_stock\x18\t \x01(\x05R\bminStock\x12\x1b\n" +
+	"\tmax_stock\x18\n" +
+	" \x01(\x05R\bmaxStock\x12\x1b\n" +
+	"\tauthor_id\x18\v \x01(\x05R\bauthorIdJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03\"R\n" +
 	"\x13FilterBooksResponse\x12%\n" +
 	"\x05books\x18\x01 \x03(\v2\x0f.bookstore.BookR\x05books\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x05R\x05count\"\x11\n" +
-	"\x0fGetStatsRequest\"\xbf\x01\n" +
+	"\x0fGetStatsRequest\"\xd9\x01\n" +
 	"\x10GetStatsResponse\x12\x1f\n" +
 	"\vtotal_books\x18\x01 \x01(\x05R\n" +
-	"totalBooks\x12#\n" +
-	"\raverage_price\x18\x02 \x01(\x02R\faveragePrice\x12\x1f\n" +
+	"totalBooks\x12\x1f\n" +
 	"\vtotal_stock\x18\x03 \x01(\x05R\n" +
 	"totalStock\x12#\n" +
 	"\rearliest_year\x18\x04 \x01(\x05R\fearliestYear\x12\x1f\n" +
 	"\vlatest_year\x18\x05 \x01(\x05R\n" +
-	"latestYear\"6\n" +
+	"latestYear\x127\n" +
+	"\x0eaverage_prices\x18\x06 \x03(\v2\x10.bookstore.MoneyR\raveragePricesJ\x04\b\x02\x10\x03\"_\n" +
 	"\x17GetBooksByAuthorRequest\x12\x1b\n" +
-	"\tauthor_id\x18\x01 \x01(\x05R\bauthorId\"W\n" +
+	"\tauthor_id\x18\x01 \x01(\x05R\bauthorId\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"W\n" +
 	"\x18GetBooksByAuthorResponse\x12%\n" +
 	"\x05books\x18\x01 \x03(\v2\x0f.bookstore.BookR\x05books\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x05R\x05count2\xda\x05\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\x8d\x01\n" +
+	"\fWatchRequest\x128\n" +
+	"\x06action\x18\x01 \x01(\x0e2 .bookservice.WatchRequest.ActionR\x06action\x12\x19\n" +
+	"\bbook_ids\x18\x02 \x03(\x05R\abookIds\"(\n" +
+	"\x06Action\x12\r\n" +
+	"\tSUBSCRIBE\x10\x00\x12\x0f\n" +
+	"\vUNSUBSCRIBE\x10\x01\"\xb3\x02\n" +
+	"\tBookEvent\x12/\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1b.bookservice.BookEvent.TypeR\x04type\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\x05R\x06bookId\x12#\n" +
+	"\x04book\x18\x03 \x01(\v2\x0f.bookstore.BookR\x04book\x12\x1b\n" +
+	"\told_stock\x18\x05 \x01(\x05R\boldStock\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x12-\n" +
+	"\told_price\x18\a \x01(\v2\x10.bookstore.MoneyR\boldPrice\"G\n" +
+	"\x04Type\x12\f\n" +
+	"\bSNAPSHOT\x10\x00\x12\x11\n" +
+	"\rPRICE_CHANGED\x10\x01\x12\x11\n" +
+	"\rSTOCK_CHANGED\x10\x02\x12\v\n" +
+	"\aDELETED\x10\x03J\x04\b\x04\x10\x05\"\xa4\x02\n" +
+	"\x0eLifecycleEvent\x124\n" +
+	"\x04type\x18\x01 \x01(\x0e2 .bookservice.LifecycleEvent.TypeR\x04type\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\x05R\x06bookId\x12#\n" +
+	"\x04book\x18\x03 \x01(\v2\x0f.bookstore.BookR\x04book\x12\x1b\n" +
+	"\told_stock\x18\x04 \x01(\x05R\boldStock\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"c\n" +
+	"\x04Type\x12\x10\n" +
+	"\fBOOK_CREATED\x10\x00\x12\x10\n" +
+	"\fBOOK_UPDATED\x10\x01\x12\x10\n" +
+	"\fBOOK_DELETED\x10\x02\x12\x11\n" +
+	"\rSTOCK_CHANGED\x10\x03\x12\x12\n" +
+	"\x0eBOOK_UNDELETED\x10\x04\"P\n" +
+	"\x16SubscribeEventsRequest\x126\n" +
+	"\x05types\x18\x01 \x03(\x0e2 .bookservice.LifecycleEvent.TypeR\x05types\"4\n" +
+	"\x14WatchLowStockRequest\x12\x1c\n" +
+	"\tthreshold\x18\x01 \x01(\x05R\tthreshold\"\xa6\x01\n" +
+	"\rLowStockAlert\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\x12#\n" +
+	"\x04book\x18\x02 \x01(\v2\x0f.bookstore.BookR\x04book\x12\x1b\n" +
+	"\told_stock\x18\x03 \x01(\x05R\boldStock\x12\x1c\n" +
+	"\tthreshold\x18\x04 \x01(\x05R\tthreshold\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"!\n" +
+	"\vImportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xc3\x01\n" +
+	"\x0eImportProgress\x12%\n" +
+	"\x0erows_processed\x18\x01 \x01(\x05R\rrowsProcessed\x12#\n" +
+	"\rrows_imported\x18\x02 \x01(\x05R\frowsImported\x12\x1f\n" +
+	"\vrows_failed\x18\x03 \x01(\x05R\n" +
+	"rowsFailed\x120\n" +
+	"\x06errors\x18\x04 \x03(\v2\x18.bookservice.ImportErrorR\x06errors\x12\x12\n" +
+	"\x04done\x18\x05 \x01(\bR\x04done\";\n" +
+	"\vImportError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"h\n" +
+	"\rExportRequest\x129\n" +
+	"\x06format\x18\x01 \x01(\x0e2!.bookservice.ExportRequest.FormatR\x06format\"\x1c\n" +
+	"\x06Format\x12\t\n" +
+	"\x05JSONL\x10\x00\x12\a\n" +
+	"\x03CSV\x10\x01\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xef\t\n" +
 	"\vBookCatalog\x12D\n" +
 	"\aGetBook\x12\x1b.bookservice.GetBookRequest\x1a\x1c.bookservice.GetBookResponse\x12M\n" +
 	"\n" +
@@ -1125,8 +2467,16 @@ const file_proto_book_service_proto_rawDesc = "" +
 	"\n" +
 	"UpdateBook\x12\x1e.bookservice.UpdateBookRequest\x1a\x1f.bookservice.UpdateBookResponse\x12M\n" +
 	"\n" +
-	"DeleteBook\x12\x1e.bookservice.DeleteBookRequest\x1a\x1f.bookservice.DeleteBookResponse\x12J\n" +
-	"\tListBooks\x12\x1d.bookservice.ListBooksRequest\x1a\x1e.bookservice.ListBooksResponse\x12P\n" +
+	"DeleteBook\x12\x1e.bookservice.DeleteBookRequest\x1a\x1f.bookservice.DeleteBookResponse\x12S\n" +
+	"\fUndeleteBook\x12 .bookservice.UndeleteBookRequest\x1a!.bookservice.UndeleteBookResponse\x12J\n" +
+	"\tListBooks\x12\x1d.bookservice.ListBooksRequest\x1a\x1e.bookservice.ListBooksResponse\x12?\n" +
+	"\vStreamBooks\x12\x1d.bookservice.ListBooksRequest\x1a\x0f.bookstore.Book0\x01\x12C\n" +
+	"\n" +
+	"WatchBooks\x12\x19.bookservice.WatchRequest\x1a\x16.bookservice.BookEvent(\x010\x01\x12U\n" +
+	"\x0fSubscribeEvents\x12#.bookservice.SubscribeEventsRequest\x1a\x1b.bookservice.LifecycleEvent0\x01\x12P\n" +
+	"\rWatchLowStock\x12!.bookservice.WatchLowStockRequest\x1a\x1a.bookservice.LowStockAlert0\x01\x12H\n" +
+	"\vImportBooks\x12\x18.bookservice.ImportChunk\x1a\x1b.bookservice.ImportProgress(\x010\x01\x12E\n" +
+	"\vExportBooks\x12\x1a.bookservice.ExportRequest\x1a\x18.bookservice.ExportChunk0\x01\x12P\n" +
 	"\vSearchBooks\x12\x1f.bookservice.SearchBooksRequest\x1a .bookservice.SearchBooksResponse\x12P\n" +
 	"\vFilterBooks\x12\x1f.bookservice.FilterBooksRequest\x1a .bookservice.FilterBooksResponse\x12G\n" +
 	"\bGetStats\x12\x1c.bookservice.GetStatsRequest\x1a\x1d.bookservice.GetStatsResponse\x12_\n" +
@@ -1144,59 +2494,121 @@ func file_proto_book_service_proto_rawDescGZIP() []byte {
 	return file_proto_book_service_proto_rawDescData
 }
 
-var file_proto_book_service_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_proto_book_service_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_book_service_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_proto_book_service_proto_goTypes = []any{
-	(*GetBookRequest)(nil),           // 0: bookservice.GetBookRequest
-	(*GetBookResponse)(nil),          // 1: bookservice.GetBookResponse
-	(*CreateBookRequest)(nil),        // 2: bookservice.CreateBookRequest
-	(*CreateBookResponse)(nil),       // 3: bookservice.CreateBookResponse
-	(*UpdateBookRequest)(nil),        // 4: bookservice.UpdateBookRequest
-	(*UpdateBookResponse)(nil),       // 5: bookservice.UpdateBookResponse
-	(*DeleteBookRequest)(nil),        // 6: bookservice.DeleteBookRequest
-	(*DeleteBookResponse)(nil),       // 7: bookservice.DeleteBookResponse
-	(*ListBooksRequest)(nil),         // 8: bookservice.ListBooksRequest
-	(*ListBooksResponse)(nil),        // 9: bookservice.ListBooksResponse
-	(*SearchBooksRequest)(nil),       // 10: bookservice.SearchBooksRequest
-	(*SearchBooksResponse)(nil),      // 11: bookservice.SearchBooksResponse
-	(*FilterBooksRequest)(nil),       // 12: bookservice.FilterBooksRequest
-	(*FilterBooksResponse)(nil),      // 13: bookservice.FilterBooksResponse
-	(*GetStatsRequest)(nil),          // 14: bookservice.GetStatsRequest
-	(*GetStatsResponse)(nil),         // 15: bookservice.GetStatsResponse
-	(*GetBooksByAuthorRequest)(nil),  // 16: bookservice.GetBooksByAuthorRequest
-	(*GetBooksByAuthorResponse)(nil), // 17: bookservice.GetBooksByAuthorResponse
-	(*Book)(nil),                     // 18: bookstore.Book
+	(WatchRequest_Action)(0),         // 0: bookservice.WatchRequest.Action
+	(BookEvent_Type)(0),              // 1: bookservice.BookEvent.Type
+	(LifecycleEvent_Type)(0),         // 2: bookservice.LifecycleEvent.Type
+	(ExportRequest_Format)(0),        // 3: bookservice.ExportRequest.Format
+	(*GetBookRequest)(nil),           // 4: bookservice.GetBookRequest
+	(*GetBookResponse)(nil),          // 5: bookservice.GetBookResponse
+	(*CreateBookRequest)(nil),        // 6: bookservice.CreateBookRequest
+	(*CreateBookResponse)(nil),       // 7: bookservice.CreateBookResponse
+	(*UpdateBookRequest)(nil),        // 8: bookservice.UpdateBookRequest
+	(*UpdateBookResponse)(nil),       // 9: bookservice.UpdateBookResponse
+	(*DeleteBookRequest)(nil),        // 10: bookservice.DeleteBookRequest
+	(*DeleteBookResponse)(nil),       // 11: bookservice.DeleteBookResponse
+	(*UndeleteBookRequest)(nil),      // 12: bookservice.UndeleteBookRequest
+	(*UndeleteBookResponse)(nil),     // 13: bookservice.UndeleteBookResponse
+	(*ListBooksRequest)(nil),         // 14: bookservice.ListBooksRequest
+	(*ListBooksResponse)(nil),        // 15: bookservice.ListBooksResponse
+	(*SearchBooksRequest)(nil),       // 16: bookservice.SearchBooksRequest
+	(*SearchBooksResponse)(nil),      // 17: bookservice.SearchBooksResponse
+	(*SearchHit)(nil),                // 18: bookservice.SearchHit
+	(*FilterBooksRequest)(nil),       // 19: bookservice.FilterBooksRequest
+	(*FilterBooksResponse)(nil),      // 20: bookservice.FilterBooksResponse
+	(*GetStatsRequest)(nil),          // 21: bookservice.GetStatsRequest
+	(*GetStatsResponse)(nil),         // 22: bookservice.GetStatsResponse
+	(*GetBooksByAuthorRequest)(nil),  // 23: bookservice.GetBooksByAuthorRequest
+	(*GetBooksByAuthorResponse)(nil), // 24: bookservice.GetBooksByAuthorResponse
+	(*WatchRequest)(nil),             // 25: bookservice.WatchRequest
+	(*BookEvent)(nil),                // 26: bookservice.BookEvent
+	(*LifecycleEvent)(nil),           // 27: bookservice.LifecycleEvent
+	(*SubscribeEventsRequest)(nil),   // 28: bookservice.SubscribeEventsRequest
+	(*WatchLowStockRequest)(nil),     // 29: bookservice.WatchLowStockRequest
+	(*LowStockAlert)(nil),            // 30: bookservice.LowStockAlert
+	(*ImportChunk)(nil),              // 31: bookservice.ImportChunk
+	(*ImportProgress)(nil),           // 32: bookservice.ImportProgress
+	(*ImportError)(nil),              // 33: bookservice.ImportError
+	(*ExportRequest)(nil),            // 34: bookservice.ExportRequest
+	(*ExportChunk)(nil),              // 35: bookservice.ExportChunk
+	(*Book)(nil),                     // 36: bookstore.Book
+	(*BookRating)(nil),               // 37: reviewservice.BookRating
+	(BookCategory)(0),                // 38: bookstore.BookCategory
+	(*Money)(nil),                    // 39: bookstore.Money
+	(*fieldmaskpb.FieldMask)(nil),    // 40: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),    // 41: google.protobuf.Timestamp
 }
 var file_proto_book_service_proto_depIdxs = []int32{
-	18, // 0: bookservice.GetBookResponse.book:type_name -> bookstore.Book
-	18, // 1: bookservice.CreateBookResponse.book:type_name -> bookstore.Book
-	18, // 2: bookservice.UpdateBookResponse.book:type_name -> bookstore.Book
-	18, // 3: bookservice.ListBooksResponse.books:type_name -> bookstore.Book
-	18, // 4: bookservice.SearchBooksResponse.books:type_name -> bookstore.Book
-	18, // 5: bookservice.FilterBooksResponse.books:type_name -> bookstore.Book
-	18, // 6: bookservice.GetBooksByAuthorResponse.books:type_name -> bookstore.Book
-	0,  // 7: bookservice.BookCatalog.GetBook:input_type -> bookservice.GetBookRequest
-	2,  // 8: bookservice.BookCatalog.CreateBook:input_type -> bookservice.CreateBookRequest
-	4,  // 9: bookservice.BookCatalog.UpdateBook:input_type -> bookservice.UpdateBookRequest
-	6,  // 10: bookservice.BookCatalog.DeleteBook:input_type -> bookservice.DeleteBookRequest
-	8,  // 11: bookservice.BookCatalog.ListBooks:input_type -> bookservice.ListBooksRequest
-	10, // 12: bookservice.BookCatalog.SearchBooks:input_type -> bookservice.SearchBooksRequest
-	12, // 13: bookservice.BookCatalog.FilterBooks:input_type -> bookservice.FilterBooksRequest
-	14, // 14: bookservice.BookCatalog.GetStats:input_type -> bookservice.GetStatsRequest
-	16, // 15: bookservice.BookCatalog.GetBooksByAuthor:input_type -> bookservice.GetBooksByAuthorRequest
-	1,  // 16: bookservice.BookCatalog.GetBook:output_type -> bookservice.GetBookResponse
-	3,  // 17: bookservice.BookCatalog.CreateBook:output_type -> bookservice.CreateBookResponse
-	5,  // 18: bookservice.BookCatalog.UpdateBook:output_type -> bookservice.UpdateBookResponse
-	7,  // 19: bookservice.BookCatalog.DeleteBook:output_type -> bookservice.DeleteBookResponse
-	9,  // 20: bookservice.BookCatalog.ListBooks:output_type -> bookservice.ListBooksResponse
-	11, // 21: bookservice.BookCatalog.SearchBooks:output_type -> bookservice.SearchBooksResponse
-	13, // 22: bookservice.BookCatalog.FilterBooks:output_type -> bookservice.FilterBooksResponse
-	15, // 23: bookservice.BookCatalog.GetStats:output_type -> bookservice.GetStatsResponse
-	17, // 24: bookservice.BookCatalog.GetBooksByAuthor:output_type -> bookservice.GetBooksByAuthorResponse
-	16, // [16:25] is the sub-list for method output_type
-	7,  // [7:16] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	36, // 0: bookservice.GetBookResponse.book:type_name -> bookstore.Book
+	37, // 1: bookservice.GetBookResponse.rating:type_name -> reviewservice.BookRating
+	38, // 2: bookservice.CreateBookRequest.category:type_name -> bookstore.BookCategory
+	39, // 3: bookservice.CreateBookRequest.price:type_name -> bookstore.Money
+	36, // 4: bookservice.CreateBookResponse.book:type_name -> bookstore.Book
+	40, // 5: bookservice.UpdateBookRequest.update_mask:type_name -> google.protobuf.FieldMask
+	38, // 6: bookservice.UpdateBookRequest.category:type_name -> bookstore.BookCategory
+	39, // 7: bookservice.UpdateBookRequest.price:type_name -> bookstore.Money
+	36, // 8: bookservice.UpdateBookResponse.book:type_name -> bookstore.Book
+	36, // 9: bookservice.UndeleteBookResponse.book:type_name -> bookstore.Book
+	19, // 10: bookservice.ListBooksRequest.filter:type_name -> bookservice.FilterBooksRequest
+	41, // 11: bookservice.ListBooksRequest.updated_since:type_name -> google.protobuf.Timestamp
+	36, // 12: bookservice.ListBooksResponse.books:type_name -> bookstore.Book
+	36, // 13: bookservice.SearchBooksResponse.books:type_name -> bookstore.Book
+	18, // 14: bookservice.SearchBooksResponse.hits:type_name -> bookservice.SearchHit
+	38, // 15: bookservice.FilterBooksRequest.categories:type_name -> bookstore.BookCategory
+	39, // 16: bookservice.FilterBooksRequest.min_price:type_name -> bookstore.Money
+	39, // 17: bookservice.FilterBooksRequest.max_price:type_name -> bookstore.Money
+	36, // 18: bookservice.FilterBooksResponse.books:type_name -> bookstore.Book
+	39, // 19: bookservice.GetStatsResponse.average_prices:type_name -> bookstore.Money
+	36, // 20: bookservice.GetBooksByAuthorResponse.books:type_name -> bookstore.Book
+	0,  // 21: bookservice.WatchRequest.action:type_name -> bookservice.WatchRequest.Action
+	1,  // 22: bookservice.BookEvent.type:type_name -> bookservice.BookEvent.Type
+	36, // 23: bookservice.BookEvent.book:type_name -> bookstore.Book
+	39, // 24: bookservice.BookEvent.old_price:type_name -> bookstore.Money
+	2,  // 25: bookservice.LifecycleEvent.type:type_name -> bookservice.LifecycleEvent.Type
+	36, // 26: bookservice.LifecycleEvent.book:type_name -> bookstore.Book
+	2,  // 27: bookservice.SubscribeEventsRequest.types:type_name -> bookservice.LifecycleEvent.Type
+	36, // 28: bookservice.LowStockAlert.book:type_name -> bookstore.Book
+	33, // 29: bookservice.ImportProgress.errors:type_name -> bookservice.ImportError
+	3,  // 30: bookservice.ExportRequest.format:type_name -> bookservice.ExportRequest.Format
+	4,  // 31: bookservice.BookCatalog.GetBook:input_type -> bookservice.GetBookRequest
+	6,  // 32: bookservice.BookCatalog.CreateBook:input_type -> bookservice.CreateBookRequest
+	8,  // 33: bookservice.BookCatalog.UpdateBook:input_type -> bookservice.UpdateBookRequest
+	10, // 34: bookservice.BookCatalog.DeleteBook:input_type -> bookservice.DeleteBookRequest
+	12, // 35: bookservice.BookCatalog.UndeleteBook:input_type -> bookservice.UndeleteBookRequest
+	14, // 36: bookservice.BookCatalog.ListBooks:input_type -> bookservice.ListBooksRequest
+	14, // 37: bookservice.BookCatalog.StreamBooks:input_type -> bookservice.ListBooksRequest
+	25, // 38: bookservice.BookCatalog.WatchBooks:input_type -> bookservice.WatchRequest
+	28, // 39: bookservice.BookCatalog.SubscribeEvents:input_type -> bookservice.SubscribeEventsRequest
+	29, // 40: bookservice.BookCatalog.WatchLowStock:input_type -> bookservice.WatchLowStockRequest
+	31, // 41: bookservice.BookCatalog.ImportBooks:input_type -> bookservice.ImportChunk
+	34, // 42: bookservice.BookCatalog.ExportBooks:input_type -> bookservice.ExportRequest
+	16, // 43: bookservice.BookCatalog.SearchBooks:input_type -> bookservice.SearchBooksRequest
+	19, // 44: bookservice.BookCatalog.FilterBooks:input_type -> bookservice.FilterBooksRequest
+	21, // 45: bookservice.BookCatalog.GetStats:input_type -> bookservice.GetStatsRequest
+	23, // 46: bookservice.BookCatalog.GetBooksByAuthor:input_type -> bookservice.GetBooksByAuthorRequest
+	5,  // 47: bookservice.BookCatalog.GetBook:output_type -> bookservice.GetBookResponse
+	7,  // 48: bookservice.BookCatalog.CreateBook:output_type -> bookservice.CreateBookResponse
+	9,  // 49: bookservice.BookCatalog.UpdateBook:output_type -> bookservice.UpdateBookResponse
+	11, // 50: bookservice.BookCatalog.DeleteBook:output_type -> bookservice.DeleteBookResponse
+	13, // 51: bookservice.BookCatalog.UndeleteBook:output_type -> bookservice.UndeleteBookResponse
+	15, // 52: bookservice.BookCatalog.ListBooks:output_type -> bookservice.ListBooksResponse
+	36, // 53: bookservice.BookCatalog.StreamBooks:output_type -> bookstore.Book
+	26, // 54: bookservice.BookCatalog.WatchBooks:output_type -> bookservice.BookEvent
+	27, // 55: bookservice.BookCatalog.SubscribeEvents:output_type -> bookservice.LifecycleEvent
+	30, // 56: bookservice.BookCatalog.WatchLowStock:output_type -> bookservice.LowStockAlert
+	32, // 57: bookservice.BookCatalog.ImportBooks:output_type -> bookservice.ImportProgress
+	35, // 58: bookservice.BookCatalog.ExportBooks:output_type -> bookservice.ExportChunk
+	17, // 59: bookservice.BookCatalog.SearchBooks:output_type -> bookservice.SearchBooksResponse
+	20, // 60: bookservice.BookCatalog.FilterBooks:output_type -> bookservice.FilterBooksResponse
+	22, // 61: bookservice.BookCatalog.GetStats:output_type -> bookservice.GetStatsResponse
+	24, // 62: bookservice.BookCatalog.GetBooksByAuthor:output_type -> bookservice.GetBooksByAuthorResponse
+	47, // [47:63] is the sub-list for method output_type
+	31, // [31:47] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_proto_book_service_proto_init() }
@@ -1205,18 +2617,20 @@ func file_proto_book_service_proto_init() {
 		return
 	}
 	file_proto_book_proto_init()
+	file_proto_review_service_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_book_service_proto_rawDesc), len(file_proto_book_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   18,
+			NumEnums:      4,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_book_service_proto_goTypes,
 		DependencyIndexes: file_proto_book_service_proto_depIdxs,
+		EnumInfos:         file_proto_book_service_proto_enumTypes,
 		MessageInfos:      file_proto_book_service_proto_msgTypes,
 	}.Build()
 	File_proto_book_service_proto = out.File
@@ -23,7 +23,14 @@ const (
 	BookCatalog_CreateBook_FullMethodName       = "/bookservice.BookCatalog/CreateBook"
 	BookCatalog_UpdateBook_FullMethodName       = "/bookservice.BookCatalog/UpdateBook"
 	BookCatalog_DeleteBook_FullMethodName       = "/bookservice.BookCatalog/DeleteBook"
+	BookCatalog_UndeleteBook_FullMethodName     = "/bookservice.BookCatalog/UndeleteBook"
 	BookCatalog_ListBooks_FullMethodName        = "/bookservice.BookCatalog/ListBooks"
+	BookCatalog_StreamBooks_FullMethodName      = "/bookservice.BookCatalog/StreamBooks"
+	BookCatalog_WatchBooks_FullMethodName       = "/bookservice.BookCatalog/WatchBooks"
+	BookCatalog_SubscribeEvents_FullMethodName  = "/bookservice.BookCatalog/SubscribeEvents"
+	BookCatalog_WatchLowStock_FullMethodName    = "/bookservice.BookCatalog/WatchLowStock"
+	BookCatalog_ImportBooks_FullMethodName      = "/bookservice.BookCatalog/ImportBooks"
+	BookCatalog_ExportBooks_FullMethodName      = "/bookservice.BookCatalog/ExportBooks"
 	BookCatalog_SearchBooks_FullMethodName      = "/bookservice.BookCatalog/SearchBooks"
 	BookCatalog_FilterBooks_FullMethodName      = "/bookservice.BookCatalog/FilterBooks"
 	BookCatalog_GetStats_FullMethodName         = "/bookservice.BookCatalog/GetStats"
@@ -37,8 +44,34 @@ type BookCatalogClient interface {
 	GetBook(ctx context.Context, in *GetBookRequest, opts ...grpc.CallOption) (*GetBookResponse, error)
 	CreateBook(ctx context.Context, in *CreateBookRequest, opts ...grpc.CallOption) (*CreateBookResponse, error)
 	UpdateBook(ctx context.Context, in *UpdateBookRequest, opts ...grpc.CallOption) (*UpdateBookResponse, error)
+	// DeleteBook soft-deletes the book unless hard is set. A soft-deleted
+	// book reads as NotFound until UndeleteBook restores it.
 	DeleteBook(ctx context.Context, in *DeleteBookRequest, opts ...grpc.CallOption) (*DeleteBookResponse, error)
+	// UndeleteBook restores a soft-deleted book. Restoring a book that is not
+	// deleted returns it unchanged; a hard-deleted book is NotFound.
+	UndeleteBook(ctx context.Context, in *UndeleteBookRequest, opts ...grpc.CallOption) (*UndeleteBookResponse, error)
 	ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (*ListBooksResponse, error)
+	// StreamBooks sends books one message at a time, in id order. page and
+	// page_size work as in ListBooks; page_size 0 streams the whole catalog.
+	StreamBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error)
+	// WatchBooks pushes price and stock changes for the books the client has
+	// subscribed to over the same stream. A price and stock change in one
+	// update arrives as two events.
+	WatchBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WatchRequest, BookEvent], error)
+	// SubscribeEvents streams lifecycle events for every book, as they happen,
+	// until the client cancels. Events from before the call are not replayed.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LifecycleEvent], error)
+	// WatchLowStock sends an alert whenever an update takes a book's stock
+	// below the threshold, until the client cancels. A book already below it
+	// alerts again only after being restocked.
+	WatchLowStock(ctx context.Context, in *WatchLowStockRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LowStockAlert], error)
+	// ImportBooks reads a CSV file streamed in chunks, validates every row like
+	// CreateBook and inserts the valid ones in batches. Rejected rows are
+	// skipped and reported in the progress stream.
+	ImportBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportChunk, ImportProgress], error)
+	// ExportBooks streams the whole catalog, in id order, in the requested
+	// format.
+	ExportBooks(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
 	SearchBooks(ctx context.Context, in *SearchBooksRequest, opts ...grpc.CallOption) (*SearchBooksResponse, error)
 	FilterBooks(ctx context.Context, in *FilterBooksRequest, opts ...grpc.CallOption) (*FilterBooksResponse, error)
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
@@ -93,6 +126,16 @@ func (c *bookCatalogClient) DeleteBook(ctx context.Context, in *DeleteBookReques
 	return out, nil
 }
 
+func (c *bookCatalogClient) UndeleteBook(ctx context.Context, in *UndeleteBookRequest, opts ...grpc.CallOption) (*UndeleteBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UndeleteBookResponse)
+	err := c.cc.Invoke(ctx, BookCatalog_UndeleteBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *bookCatalogClient) ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (*ListBooksResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListBooksResponse)
@@ -103,6 +146,108 @@ func (c *bookCatalogClient) ListBooks(ctx context.Context, in *ListBooksRequest,
 	return out, nil
 }
 
+func (c *bookCatalogClient) StreamBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[0], BookCatalog_StreamBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListBooksRequest, Book]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_StreamBooksClient = grpc.ServerStreamingClient[Book]
+
+func (c *bookCatalogClient) WatchBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WatchRequest, BookEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[1], BookCatalog_WatchBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, BookEvent]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_WatchBooksClient = grpc.BidiStreamingClient[WatchRequest, BookEvent]
+
+func (c *bookCatalogClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LifecycleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[2], BookCatalog_SubscribeEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeEventsRequest, LifecycleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_SubscribeEventsClient = grpc.ServerStreamingClient[LifecycleEvent]
+
+func (c *bookCatalogClient) WatchLowStock(ctx context.Context, in *WatchLowStockRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LowStockAlert], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[3], BookCatalog_WatchLowStock_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLowStockRequest, LowStockAlert]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_WatchLowStockClient = grpc.ServerStreamingClient[LowStockAlert]
+
+func (c *bookCatalogClient) ImportBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportChunk, ImportProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[4], BookCatalog_ImportBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportChunk, ImportProgress]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_ImportBooksClient = grpc.BidiStreamingClient[ImportChunk, ImportProgress]
+
+func (c *bookCatalogClient) ExportBooks(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookCatalog_ServiceDesc.Streams[5], BookCatalog_ExportBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_ExportBooksClient = grpc.ServerStreamingClient[ExportChunk]
+
 func (c *bookCatalogClient) SearchBooks(ctx context.Context, in *SearchBooksRequest, opts ...grpc.CallOption) (*SearchBooksResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SearchBooksResponse)
@@ -150,8 +295,34 @@ type BookCatalogServer interface {
 	GetBook(context.Context, *GetBookRequest) (*GetBookResponse, error)
 	CreateBook(context.Context, *CreateBookRequest) (*CreateBookResponse, error)
 	UpdateBook(context.Context, *UpdateBookRequest) (*UpdateBookResponse, error)
+	// DeleteBook soft-deletes the book unless hard is set. A soft-deleted
+	// book reads as NotFound until UndeleteBook restores it.
 	DeleteBook(context.Context, *DeleteBookRequest) (*DeleteBookResponse, error)
+	// UndeleteBook restores a soft-deleted book. Restoring a book that is not
+	// deleted returns it unchanged; a hard-deleted book is NotFound.
+	UndeleteBook(context.Context, *UndeleteBookRequest) (*UndeleteBookResponse, error)
 	ListBooks(context.Context, *ListBooksRequest) (*ListBooksResponse, error)
+	// StreamBooks sends books one message at a time, in id order. page and
+	// page_size work as in ListBooks; page_size 0 streams the whole catalog.
+	StreamBooks(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error
+	// WatchBooks pushes price and stock changes for the books the client has
+	// subscribed to over the same stream. A price and stock change in one
+	// update arrives as two events.
+	WatchBooks(grpc.BidiStreamingServer[WatchRequest, BookEvent]) error
+	// SubscribeEvents streams lifecycle events for every book, as they happen,
+	// until the client cancels. Events from before the call are not replayed.
+	SubscribeEvents(*SubscribeEventsRequest, grpc.ServerStreamingServer[LifecycleEvent]) error
+	// WatchLowStock sends an alert whenever an update takes a book's stock
+	// below the threshold, until the client cancels. A book already below it
+	// alerts again only after being restocked.
+	WatchLowStock(*WatchLowStockRequest, grpc.ServerStreamingServer[LowStockAlert]) error
+	// ImportBooks reads a CSV file streamed in chunks, validates every row like
+	// CreateBook and inserts the valid ones in batches. Rejected rows are
+	// skipped and reported in the progress stream.
+	ImportBooks(grpc.BidiStreamingServer[ImportChunk, ImportProgress]) error
+	// ExportBooks streams the whole catalog, in id order, in the requested
+	// format.
+	ExportBooks(*ExportRequest, grpc.ServerStreamingServer[ExportChunk]) error
 	SearchBooks(context.Context, *SearchBooksRequest) (*SearchBooksResponse, error)
 	FilterBooks(context.Context, *FilterBooksRequest) (*FilterBooksResponse, error)
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
@@ -178,9 +349,30 @@ func (UnimplementedBookCatalogServer) UpdateBook(context.Context, *UpdateBookReq
 func (UnimplementedBookCatalogServer) DeleteBook(context.Context, *DeleteBookRequest) (*DeleteBookResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteBook not implemented")
 }
+func (UnimplementedBookCatalogServer) UndeleteBook(context.Context, *UndeleteBookRequest) (*UndeleteBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteBook not implemented")
+}
 func (UnimplementedBookCatalogServer) ListBooks(context.Context, *ListBooksRequest) (*ListBooksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListBooks not implemented")
 }
+func (UnimplementedBookCatalogServer) StreamBooks(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBooks not implemented")
+}
+func (UnimplementedBookCatalogServer) WatchBooks(grpc.BidiStreamingServer[WatchRequest, BookEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBooks not implemented")
+}
+func (UnimplementedBookCatalogServer) SubscribeEvents(*SubscribeEventsRequest, grpc.ServerStreamingServer[LifecycleEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (UnimplementedBookCatalogServer) WatchLowStock(*WatchLowStockRequest, grpc.ServerStreamingServer[LowStockAlert]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLowStock not implemented")
+}
+func (UnimplementedBookCatalogServer) ImportBooks(grpc.BidiStreamingServer[ImportChunk, ImportProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBooks not implemented")
+}
+func (UnimplementedBookCatalogServer) ExportBooks(*ExportRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportBooks not implemented")
+}
 func (UnimplementedBookCatalogServer) SearchBooks(context.Context, *SearchBooksRequest) (*SearchBooksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchBooks not implemented")
 }
@@ -286,6 +478,24 @@ func _BookCatalog_DeleteBook_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookCatalog_UndeleteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookCatalogServer).UndeleteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookCatalog_UndeleteBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookCatalogServer).UndeleteBook(ctx, req.(*UndeleteBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BookCatalog_ListBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListBooksRequest)
 	if err := dec(in); err != nil {
@@ -304,6 +514,64 @@ func _BookCatalog_ListBooks_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookCatalog_StreamBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookCatalogServer).StreamBooks(m, &grpc.GenericServerStream[ListBooksRequest, Book]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_StreamBooksServer = grpc.ServerStreamingServer[Book]
+
+func _BookCatalog_WatchBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookCatalogServer).WatchBooks(&grpc.GenericServerStream[WatchRequest, BookEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_WatchBooksServer = grpc.BidiStreamingServer[WatchRequest, BookEvent]
+
+func _BookCatalog_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookCatalogServer).SubscribeEvents(m, &grpc.GenericServerStream[SubscribeEventsRequest, LifecycleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_SubscribeEventsServer = grpc.ServerStreamingServer[LifecycleEvent]
+
+func _BookCatalog_WatchLowStock_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLowStockRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookCatalogServer).WatchLowStock(m, &grpc.GenericServerStream[WatchLowStockRequest, LowStockAlert]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_WatchLowStockServer = grpc.ServerStreamingServer[LowStockAlert]
+
+func _BookCatalog_ImportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookCatalogServer).ImportBooks(&grpc.GenericServerStream[ImportChunk, ImportProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_ImportBooksServer = grpc.BidiStreamingServer[ImportChunk, ImportProgress]
+
+func _BookCatalog_ExportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookCatalogServer).ExportBooks(m, &grpc.GenericServerStream[ExportRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookCatalog_ExportBooksServer = grpc.ServerStreamingServer[ExportChunk]
+
 func _BookCatalog_SearchBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchBooksRequest)
 	if err := dec(in); err != nil {
@@ -399,6 +667,10 @@ var BookCatalog_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBook",
 			Handler:    _BookCatalog_DeleteBook_Handler,
 		},
+		{
+			MethodName: "UndeleteBook",
+			Handler:    _BookCatalog_UndeleteBook_Handler,
+		},
 		{
 			MethodName: "ListBooks",
 			Handler:    _BookCatalog_ListBooks_Handler,
@@ -420,6 +692,39 @@ var BookCatalog_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BookCatalog_GetBooksByAuthor_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBooks",
+			Handler:       _BookCatalog_StreamBooks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBooks",
+			Handler:       _BookCatalog_WatchBooks_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _BookCatalog_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLowStock",
+			Handler:       _BookCatalog_WatchLowStock_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportBooks",
+			Handler:       _BookCatalog_ImportBooks_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportBooks",
+			Handler:       _BookCatalog_ExportBooks_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/book_service.proto",
 }
@@ -0,0 +1,625 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: proto/bookcatalog/v2/book_catalog.proto
+
+// bookcatalog.v2 is the second version of the BookCatalog API. It is served
+// next to bookservice.BookCatalog (v1) by the Task5 book-service, on the same
+// port and over the same database, so v1 clients keep working unchanged.
+//
+// Changes from v1:
+//   - ListBooks pages with an opaque page_token instead of page numbers.
+//   - Books carry create_time and update_time.
+//   - UpdateBook takes the Book itself plus a FieldMask, instead of
+//     repeating every Book field in the request.
+//   - Methods return the resource directly (Book, Empty) instead of
+//     wrapping it in a response message.
+
+package bookcatalogv2
+
+import (
+	proto "book-catalog-grpc/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Book struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Output only
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Isbn          string                 `protobuf:"bytes,4,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Stock         int32                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	PublishedYear int32                  `protobuf:"varint,7,opt,name=published_year,json=publishedYear,proto3" json:"published_year,omitempty"`
+	AuthorId      int32                  `protobuf:"varint,8,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // Foreign key to Author service
+	Category      proto.BookCategory     `protobuf:"varint,9,opt,name=category,proto3,enum=bookstore.BookCategory" json:"category,omitempty"`
+	// Output only. Unset for books stored before the service recorded times.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	Price      *proto.Money           `protobuf:"bytes,12,opt,name=price,proto3" json:"price,omitempty"`
+	// Changes with every update. UpdateBook must send the etag of the book as
+	// last read and fails with ABORTED if it has changed since.
+	Etag          string `protobuf:"bytes,13,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Book) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Book) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Book) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Book) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Book) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Book) GetPublishedYear() int32 {
+	if x != nil {
+		return x.PublishedYear
+	}
+	return 0
+}
+
+func (x *Book) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+func (x *Book) GetCategory() proto.BookCategory {
+	if x != nil {
+		return x.Category
+	}
+	return proto.BookCategory(0)
+}
+
+func (x *Book) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Book) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *Book) GetPrice() *proto.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Book) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type GetBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRequest) Reset() {
+	*x = GetBookRequest{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRequest) ProtoMessage() {}
+
+func (x *GetBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRequest.ProtoReflect.Descriptor instead.
+func (*GetBookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetBookRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most this many books are returned; 0 means 10.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response; empty for the first page.
+	// Pass the same order_by on every page.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Same syntax as v1, e.g. "price desc, title". Empty sorts by id.
+	OrderBy       string `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksRequest) ProtoMessage() {}
+
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListBooksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBooksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListBooksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Books []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalSize     int32  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksResponse) Reset() {
+	*x = ListBooksResponse{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksResponse) ProtoMessage() {}
+
+func (x *ListBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksResponse.ProtoReflect.Descriptor instead.
+func (*ListBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *ListBooksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListBooksResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type CreateBookRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id, create_time and update_time are ignored.
+	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	// Optional idempotency key, as in v1.
+	RequestId     string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBookRequest) Reset() {
+	*x = CreateBookRequest{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBookRequest) ProtoMessage() {}
+
+func (x *CreateBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBookRequest.ProtoReflect.Descriptor instead.
+func (*CreateBookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateBookRequest) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *CreateBookRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type UpdateBookRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// book.id names the book to update.
+	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	// Fields of book to write, e.g. paths: ["price", "stock"]. Empty writes
+	// every field.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBookRequest) Reset() {
+	*x = UpdateBookRequest{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBookRequest) ProtoMessage() {}
+
+func (x *UpdateBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBookRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateBookRequest) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBookRequest) Reset() {
+	*x = DeleteBookRequest{}
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBookRequest) ProtoMessage() {}
+
+func (x *DeleteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bookcatalog_v2_book_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteBookRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_proto_bookcatalog_v2_book_catalog_proto protoreflect.FileDescriptor
+
+const file_proto_bookcatalog_v2_book_catalog_proto_rawDesc = "" +
+	"\n" +
+	"'proto/bookcatalog/v2/book_catalog.proto\x12\x0ebookcatalog.v2\x1a\x10proto/book.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x03\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x12\n" +
+	"\x04isbn\x18\x04 \x01(\tR\x04isbn\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x05R\x05stock\x12%\n" +
+	"\x0epublished_year\x18\a \x01(\x05R\rpublishedYear\x12\x1b\n" +
+	"\tauthor_id\x18\b \x01(\x05R\bauthorId\x123\n" +
+	"\bcategory\x18\t \x01(\x0e2\x17.bookstore.BookCategoryR\bcategory\x12;\n" +
+	"\vcreate_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\x12&\n" +
+	"\x05price\x18\f \x01(\v2\x10.bookstore.MoneyR\x05price\x12\x12\n" +
+	"\x04etag\x18\r \x01(\tR\x04etagJ\x04\b\x05\x10\x06\" \n" +
+	"\x0eGetBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"i\n" +
+	"\x10ListBooksRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x03 \x01(\tR\aorderBy\"\x86\x01\n" +
+	"\x11ListBooksResponse\x12*\n" +
+	"\x05books\x18\x01 \x03(\v2\x14.bookcatalog.v2.BookR\x05books\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\\\n" +
+	"\x11CreateBookRequest\x12(\n" +
+	"\x04book\x18\x01 \x01(\v2\x14.bookcatalog.v2.BookR\x04book\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"z\n" +
+	"\x11UpdateBookRequest\x12(\n" +
+	"\x04book\x18\x01 \x01(\v2\x14.bookcatalog.v2.BookR\x04book\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"#\n" +
+	"\x11DeleteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id2\xf7\x02\n" +
+	"\vBookCatalog\x12?\n" +
+	"\aGetBook\x12\x1e.bookcatalog.v2.GetBookRequest\x1a\x14.bookcatalog.v2.Book\x12P\n" +
+	"\tListBooks\x12 .bookcatalog.v2.ListBooksRequest\x1a!.bookcatalog.v2.ListBooksResponse\x12E\n" +
+	"\n" +
+	"CreateBook\x12!.bookcatalog.v2.CreateBookRequest\x1a\x14.bookcatalog.v2.Book\x12E\n" +
+	"\n" +
+	"UpdateBook\x12!.bookcatalog.v2.UpdateBookRequest\x1a\x14.bookcatalog.v2.Book\x12G\n" +
+	"\n" +
+	"DeleteBook\x12!.bookcatalog.v2.DeleteBookRequest\x1a\x16.google.protobuf.EmptyB6Z4book-catalog-grpc/proto/bookcatalog/v2;bookcatalogv2b\x06proto3"
+
+var (
+	file_proto_bookcatalog_v2_book_catalog_proto_rawDescOnce sync.Once
+	file_proto_bookcatalog_v2_book_catalog_proto_rawDescData []byte
+)
+
+func file_proto_bookcatalog_v2_book_catalog_proto_rawDescGZIP() []byte {
+	file_proto_bookcatalog_v2_book_catalog_proto_rawDescOnce.Do(func() {
+		file_proto_bookcatalog_v2_book_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_bookcatalog_v2_book_catalog_proto_rawDesc), len(file_proto_bookcatalog_v2_book_catalog_proto_rawDesc)))
+	})
+	return file_proto_bookcatalog_v2_book_catalog_proto_rawDescData
+}
+
+var file_proto_bookcatalog_v2_book_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_bookcatalog_v2_book_catalog_proto_goTypes = []any{
+	(*Book)(nil),                  // 0: bookcatalog.v2.Book
+	(*GetBookRequest)(nil),        // 1: bookcatalog.v2.GetBookRequest
+	(*ListBooksRequest)(nil),      // 2: bookcatalog.v2.ListBooksRequest
+	(*ListBooksResponse)(nil),     // 3: bookcatalog.v2.ListBooksResponse
+	(*CreateBookRequest)(nil),     // 4: bookcatalog.v2.CreateBookRequest
+	(*UpdateBookRequest)(nil),     // 5: bookcatalog.v2.UpdateBookRequest
+	(*DeleteBookRequest)(nil),     // 6: bookcatalog.v2.DeleteBookRequest
+	(proto.BookCategory)(0),       // 7: bookstore.BookCategory
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*proto.Money)(nil),           // 9: bookstore.Money
+	(*fieldmaskpb.FieldMask)(nil), // 10: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),         // 11: google.protobuf.Empty
+}
+var file_proto_bookcatalog_v2_book_catalog_proto_depIdxs = []int32{
+	7,  // 0: bookcatalog.v2.Book.category:type_name -> bookstore.BookCategory
+	8,  // 1: bookcatalog.v2.Book.create_time:type_name -> google.protobuf.Timestamp
+	8,  // 2: bookcatalog.v2.Book.update_time:type_name -> google.protobuf.Timestamp
+	9,  // 3: bookcatalog.v2.Book.price:type_name -> bookstore.Money
+	0,  // 4: bookcatalog.v2.ListBooksResponse.books:type_name -> bookcatalog.v2.Book
+	0,  // 5: bookcatalog.v2.CreateBookRequest.book:type_name -> bookcatalog.v2.Book
+	0,  // 6: bookcatalog.v2.UpdateBookRequest.book:type_name -> bookcatalog.v2.Book
+	10, // 7: bookcatalog.v2.UpdateBookRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 8: bookcatalog.v2.BookCatalog.GetBook:input_type -> bookcatalog.v2.GetBookRequest
+	2,  // 9: bookcatalog.v2.BookCatalog.ListBooks:input_type -> bookcatalog.v2.ListBooksRequest
+	4,  // 10: bookcatalog.v2.BookCatalog.CreateBook:input_type -> bookcatalog.v2.CreateBookRequest
+	5,  // 11: bookcatalog.v2.BookCatalog.UpdateBook:input_type -> bookcatalog.v2.UpdateBookRequest
+	6,  // 12: bookcatalog.v2.BookCatalog.DeleteBook:input_type -> bookcatalog.v2.DeleteBookRequest
+	0,  // 13: bookcatalog.v2.BookCatalog.GetBook:output_type -> bookcatalog.v2.Book
+	3,  // 14: bookcatalog.v2.BookCatalog.ListBooks:output_type -> bookcatalog.v2.ListBooksResponse
+	0,  // 15: bookcatalog.v2.BookCatalog.CreateBook:output_type -> bookcatalog.v2.Book
+	0,  // 16: bookcatalog.v2.BookCatalog.UpdateBook:output_type -> bookcatalog.v2.Book
+	11, // 17: bookcatalog.v2.BookCatalog.DeleteBook:output_type -> google.protobuf.Empty
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_bookcatalog_v2_book_catalog_proto_init() }
+func file_proto_bookcatalog_v2_book_catalog_proto_init() {
+	if File_proto_bookcatalog_v2_book_catalog_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_bookcatalog_v2_book_catalog_proto_rawDesc), len(file_proto_bookcatalog_v2_book_catalog_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_bookcatalog_v2_book_catalog_proto_goTypes,
+		DependencyIndexes: file_proto_bookcatalog_v2_book_catalog_proto_depIdxs,
+		MessageInfos:      file_proto_bookcatalog_v2_book_catalog_proto_msgTypes,
+	}.Build()
+	File_proto_bookcatalog_v2_book_catalog_proto = out.File
+	file_proto_bookcatalog_v2_book_catalog_proto_goTypes = nil
+	file_proto_bookcatalog_v2_book_catalog_proto_depIdxs = nil
+}
@@ -0,0 +1,125 @@
+package bookcatalogv2
+
+// The compatibility shim between v1 (package proto) and v2. The server
+// implements BookCatalog once, in v1 terms; v2 requests are translated to
+// v1 ones here, and v1 books back to v2, so both versions share validation
+// and behaviour.
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	pb "book-catalog-grpc/proto"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// FromV1 converts a v1 book; its created_at and updated_at become
+// create_time and update_time.
+func FromV1(b *pb.Book) *Book {
+	return &Book{
+		Id:            b.Id,
+		Title:         b.Title,
+		Author:        b.Author,
+		Isbn:          b.Isbn,
+		Price:         b.Price,
+		Stock:         b.Stock,
+		PublishedYear: b.PublishedYear,
+		AuthorId:      b.AuthorId,
+		Category:      b.Category,
+		CreateTime:    b.CreatedAt,
+		UpdateTime:    b.UpdatedAt,
+		Etag:          b.Etag,
+	}
+}
+
+func (r *GetBookRequest) V1() *pb.GetBookRequest {
+	return &pb.GetBookRequest{Id: r.Id}
+}
+
+func (r *DeleteBookRequest) V1() *pb.DeleteBookRequest {
+	return &pb.DeleteBookRequest{Id: r.Id}
+}
+
+func (r *CreateBookRequest) V1() *pb.CreateBookRequest {
+	b := r.GetBook()
+	return &pb.CreateBookRequest{
+		Title:         b.GetTitle(),
+		Author:        b.GetAuthor(),
+		Isbn:          b.GetIsbn(),
+		Price:         b.GetPrice(),
+		Stock:         b.GetStock(),
+		PublishedYear: b.GetPublishedYear(),
+		AuthorId:      b.GetAuthorId(),
+		Category:      b.GetCategory(),
+		RequestId:     r.RequestId,
+	}
+}
+
+// V1 keeps the mask as is: v2 paths are the v1 ones, so paths to output
+// only fields such as "create_time" fail v1 validation as unknown.
+func (r *UpdateBookRequest) V1() *pb.UpdateBookRequest {
+	b := r.GetBook()
+	var mask *fieldmaskpb.FieldMask
+	if paths := r.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		mask = &fieldmaskpb.FieldMask{Paths: paths}
+	}
+	return &pb.UpdateBookRequest{
+		Id:            b.GetId(),
+		Title:         b.GetTitle(),
+		Author:        b.GetAuthor(),
+		Isbn:          b.GetIsbn(),
+		Price:         b.GetPrice(),
+		Stock:         b.GetStock(),
+		PublishedYear: b.GetPublishedYear(),
+		AuthorId:      b.GetAuthorId(),
+		Category:      b.GetCategory(),
+		UpdateMask:    mask,
+		Etag:          b.GetEtag(),
+	}
+}
+
+// V1 drops the page token; use Offset for where the page starts.
+func (r *ListBooksRequest) V1() *pb.ListBooksRequest {
+	return &pb.ListBooksRequest{PageSize: r.PageSize, OrderBy: r.OrderBy}
+}
+
+// Offset returns how many books the page token skips.
+func (r *ListBooksRequest) Offset() (int32, error) {
+	if r.PageToken == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(r.PageToken)
+	if err != nil {
+		return 0, fmt.Errorf("malformed page token")
+	}
+	offset, err := strconv.ParseInt(string(raw), 10, 32)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("malformed page token")
+	}
+	return int32(offset), nil
+}
+
+// PageToken returns the token for the page starting after offset books.
+// Tokens are opaque to clients, so their format may change.
+func PageToken(offset int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(int(offset))))
+}
+
+// Validate applies the v1 rules through V1.
+func (r *GetBookRequest) Validate() error    { return r.V1().Validate() }
+func (r *DeleteBookRequest) Validate() error { return r.V1().Validate() }
+func (r *CreateBookRequest) Validate() error { return r.V1().Validate() }
+func (r *UpdateBookRequest) Validate() error { return r.V1().Validate() }
+
+// Validate also rejects page tokens this server did not hand out.
+func (r *ListBooksRequest) Validate() error {
+	if err := r.V1().Validate(); err != nil {
+		return err
+	}
+	if _, err := r.Offset(); err != nil {
+		return fmt.Errorf("page_token: %v", err)
+	}
+	return nil
+}
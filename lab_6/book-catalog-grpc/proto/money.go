@@ -0,0 +1,153 @@
+package proto
+
+// Helpers for Money. Amounts are kept as whole units plus nanos, or as a
+// single count of nanos (what book-service stores), and never pass through
+// a float, except at the edges for servers that still store floats.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+// DefaultCurrency is the currency of prices stored before books had one,
+// and of CSV rows without a currency column.
+const DefaultCurrency = "USD"
+
+// currencies are the ISO 4217 codes the catalog accepts.
+var currencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "JPY": true, "CNY": true, "VND": true,
+	"AUD": true, "CAD": true, "CHF": true, "SGD": true, "KRW": true, "INR": true,
+}
+
+// ValidCurrency reports whether code is a currency the catalog accepts.
+func ValidCurrency(code string) bool {
+	return currencies[code]
+}
+
+// MoneyFromNanos returns nanos billionths of a unit of currency.
+func MoneyFromNanos(currency string, nanos int64) *Money {
+	return &Money{CurrencyCode: currency, Units: nanos / nanosPerUnit, Nanos: int32(nanos % nanosPerUnit)}
+}
+
+// TotalNanos returns m as a count of nanos. It overflows past about 9.2
+// billion units, far above any price Validate accepts.
+func (m *Money) TotalNanos() int64 {
+	return m.GetUnits()*nanosPerUnit + int64(m.GetNanos())
+}
+
+// MoneyFromFloat rounds f to the nearest cent. It is for the Task3/Task4
+// servers, which still store prices as floats.
+func MoneyFromFloat(currency string, f float64) *Money {
+	return MoneyFromNanos(currency, int64(math.Round(f*100))*(nanosPerUnit/100))
+}
+
+// Float returns m as a float, losing precision; only for display and for
+// servers that store floats.
+func (m *Money) Float() float64 {
+	return float64(m.GetUnits()) + float64(m.GetNanos())/nanosPerUnit
+}
+
+// ParseMoney parses a decimal amount such as "44.99" or "-0.5" exactly. At
+// most 9 decimals are allowed.
+func ParseMoney(currency, amount string) (*Money, error) {
+	s := strings.TrimSpace(amount)
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole+frac == "" || !digits(whole) || !digits(frac) || len(whole) > 12 || len(frac) > 9 {
+		return nil, fmt.Errorf("%q is not an amount", amount)
+	}
+	units, _ := strconv.ParseInt("0"+whole, 10, 64)
+	nanos, _ := strconv.ParseInt((frac + "000000000")[:9], 10, 64)
+	if neg {
+		units, nanos = -units, -nanos
+	}
+	return &Money{CurrencyCode: currency, Units: units, Nanos: int32(nanos)}, nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount of m with at least 2 decimals, e.g. "44.99"
+// or "0.125", as ParseMoney reads it back.
+func (m *Money) Decimal() string {
+	units, nanos := m.GetUnits(), int64(m.GetNanos())
+	sign := ""
+	if units < 0 || nanos < 0 {
+		sign, units, nanos = "-", -units, -nanos
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	return fmt.Sprintf("%s%d.%s", sign, units, frac)
+}
+
+// Format returns m for people, e.g. "44.99 USD".
+func (m *Money) Format() string {
+	if m == nil {
+		return "-"
+	}
+	return m.Decimal() + " " + m.CurrencyCode
+}
+
+// Times returns m × n, keeping units and nanos apart so that large stock
+// counts do not overflow.
+func (m *Money) Times(n int64) *Money {
+	nanos := int64(m.GetNanos()) * n
+	return normalize(m.GetCurrencyCode(), m.GetUnits()*n+nanos/nanosPerUnit, nanos%nanosPerUnit)
+}
+
+// Plus returns m + o. Both must be in the same currency.
+func (m *Money) Plus(o *Money) *Money {
+	return normalize(m.GetCurrencyCode(), m.GetUnits()+o.GetUnits(), int64(m.GetNanos())+int64(o.GetNanos()))
+}
+
+// normalize carries whole units out of nanos and gives both the same sign.
+func normalize(currency string, units, nanos int64) *Money {
+	units += nanos / nanosPerUnit
+	nanos %= nanosPerUnit
+	switch {
+	case units > 0 && nanos < 0:
+		units, nanos = units-1, nanos+nanosPerUnit
+	case units < 0 && nanos > 0:
+		units, nanos = units+1, nanos-nanosPerUnit
+	}
+	return &Money{CurrencyCode: currency, Units: units, Nanos: int32(nanos)}
+}
+
+// Totals sums amounts in several currencies, one Money per currency.
+type Totals map[string]*Money
+
+// Add adds m to the total of its currency.
+func (t Totals) Add(m *Money) {
+	if sum, ok := t[m.GetCurrencyCode()]; ok {
+		t[m.GetCurrencyCode()] = sum.Plus(m)
+	} else {
+		t[m.GetCurrencyCode()] = m
+	}
+}
+
+// Sorted returns the totals by currency code.
+func (t Totals) Sorted() []*Money {
+	codes := make([]string, 0, len(t))
+	for code := range t {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	out := make([]*Money, len(codes))
+	for i, code := range codes {
+		out[i] = t[code]
+	}
+	return out
+}
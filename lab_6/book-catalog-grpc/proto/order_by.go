@@ -0,0 +1,54 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortFields are the fields ListBooksRequest.order_by may sort on.
+var sortFields = map[string]bool{
+	"id":             true,
+	"title":          true,
+	"author":         true,
+	"isbn":           true,
+	"price":          true,
+	"stock":          true,
+	"published_year": true,
+}
+
+// SortKey is one key of ListBooksRequest.order_by.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOrderBy splits order_by such as "price desc, title" into its keys,
+// or reports why it cannot. "" has no keys. Validate rejects what it cannot
+// parse; servers map the keys to columns of their own.
+func ParseOrderBy(orderBy string) ([]SortKey, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+	var keys []SortKey
+	for _, key := range strings.Split(orderBy, ",") {
+		parts := strings.Fields(strings.ToLower(key))
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, fmt.Errorf("%q is not \"field [asc|desc]\"", strings.TrimSpace(key))
+		}
+		if !sortFields[parts[0]] {
+			return nil, fmt.Errorf("cannot sort by %q", parts[0])
+		}
+		k := SortKey{Field: parts[0]}
+		if len(parts) == 2 {
+			switch parts[1] {
+			case "asc":
+			case "desc":
+				k.Desc = true
+			default:
+				return nil, fmt.Errorf("direction must be asc or desc, got %q", parts[1])
+			}
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
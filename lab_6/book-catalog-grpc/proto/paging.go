@@ -0,0 +1,13 @@
+package proto
+
+// SetPageInfo fills total_pages, has_next and has_prev from total, page and
+// page_size, which must already be set, so every server pages alike. A page
+// past the end has no next page but does have a previous one.
+func (r *ListBooksResponse) SetPageInfo() *ListBooksResponse {
+	if r.PageSize > 0 {
+		r.TotalPages = (r.Total + r.PageSize - 1) / r.PageSize
+	}
+	r.HasNext = r.Page < r.TotalPages
+	r.HasPrev = r.Page > 1
+	return r
+}
@@ -0,0 +1,510 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: proto/review_service.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Review struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BookId        int32                  `protobuf:"varint,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Reviewer      string                 `protobuf:"bytes,3,opt,name=reviewer,proto3" json:"reviewer,omitempty"`
+	Rating        int32                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"` // 1 to 5 stars
+	Comment       string                 `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_proto_review_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Review) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Review) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *Review) GetReviewer() string {
+	if x != nil {
+		return x.Reviewer
+	}
+	return ""
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Review) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type AddReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Reviewer      string                 `protobuf:"bytes,2,opt,name=reviewer,proto3" json:"reviewer,omitempty"`
+	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`  // 1 to 5 stars
+	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"` // Optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddReviewRequest) Reset() {
+	*x = AddReviewRequest{}
+	mi := &file_proto_review_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReviewRequest) ProtoMessage() {}
+
+func (x *AddReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReviewRequest.ProtoReflect.Descriptor instead.
+func (*AddReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddReviewRequest) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *AddReviewRequest) GetReviewer() string {
+	if x != nil {
+		return x.Reviewer
+	}
+	return ""
+}
+
+func (x *AddReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *AddReviewRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type AddReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddReviewResponse) Reset() {
+	*x = AddReviewResponse{}
+	mi := &file_proto_review_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReviewResponse) ProtoMessage() {}
+
+func (x *AddReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReviewResponse.ProtoReflect.Descriptor instead.
+func (*AddReviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ListReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_proto_review_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListReviewsRequest) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+type GetBookRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRatingRequest) Reset() {
+	*x = GetBookRatingRequest{}
+	mi := &file_proto_review_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRatingRequest) ProtoMessage() {}
+
+func (x *GetBookRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRatingRequest.ProtoReflect.Descriptor instead.
+func (*GetBookRatingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBookRatingRequest) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+// BookRating summarises the reviews of one book. A book without reviews has
+// review_count 0 and average 0.
+type BookRating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        int32                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Average       float64                `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"`
+	ReviewCount   int32                  `protobuf:"varint,3,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookRating) Reset() {
+	*x = BookRating{}
+	mi := &file_proto_review_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookRating) ProtoMessage() {}
+
+func (x *BookRating) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookRating.ProtoReflect.Descriptor instead.
+func (*BookRating) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BookRating) GetBookId() int32 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *BookRating) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *BookRating) GetReviewCount() int32 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+type GetBookRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rating        *BookRating            `protobuf:"bytes,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRatingResponse) Reset() {
+	*x = GetBookRatingResponse{}
+	mi := &file_proto_review_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRatingResponse) ProtoMessage() {}
+
+func (x *GetBookRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_review_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRatingResponse.ProtoReflect.Descriptor instead.
+func (*GetBookRatingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_review_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBookRatingResponse) GetRating() *BookRating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+var File_proto_review_service_proto protoreflect.FileDescriptor
+
+const file_proto_review_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/review_service.proto\x12\rreviewservice\"\x9e\x01\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\x05R\x06bookId\x12\x1a\n" +
+	"\breviewer\x18\x03 \x01(\tR\breviewer\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x05R\x06rating\x12\x18\n" +
+	"\acomment\x18\x05 \x01(\tR\acomment\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"y\n" +
+	"\x10AddReviewRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\x12\x1a\n" +
+	"\breviewer\x18\x02 \x01(\tR\breviewer\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"B\n" +
+	"\x11AddReviewResponse\x12-\n" +
+	"\x06review\x18\x01 \x01(\v2\x15.reviewservice.ReviewR\x06review\"-\n" +
+	"\x12ListReviewsRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\"/\n" +
+	"\x14GetBookRatingRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\"b\n" +
+	"\n" +
+	"BookRating\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x05R\x06bookId\x12\x18\n" +
+	"\aaverage\x18\x02 \x01(\x01R\aaverage\x12!\n" +
+	"\freview_count\x18\x03 \x01(\x05R\vreviewCount\"J\n" +
+	"\x15GetBookRatingResponse\x121\n" +
+	"\x06rating\x18\x01 \x01(\v2\x19.reviewservice.BookRatingR\x06rating2\x86\x02\n" +
+	"\rReviewCatalog\x12N\n" +
+	"\tAddReview\x12\x1f.reviewservice.AddReviewRequest\x1a .reviewservice.AddReviewResponse\x12I\n" +
+	"\vListReviews\x12!.reviewservice.ListReviewsRequest\x1a\x15.reviewservice.Review0\x01\x12Z\n" +
+	"\rGetBookRating\x12#.reviewservice.GetBookRatingRequest\x1a$.reviewservice.GetBookRatingResponseB\x19Z\x17book-catalog-grpc/protob\x06proto3"
+
+var (
+	file_proto_review_service_proto_rawDescOnce sync.Once
+	file_proto_review_service_proto_rawDescData []byte
+)
+
+func file_proto_review_service_proto_rawDescGZIP() []byte {
+	file_proto_review_service_proto_rawDescOnce.Do(func() {
+		file_proto_review_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_review_service_proto_rawDesc), len(file_proto_review_service_proto_rawDesc)))
+	})
+	return file_proto_review_service_proto_rawDescData
+}
+
+var file_proto_review_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_review_service_proto_goTypes = []any{
+	(*Review)(nil),                // 0: reviewservice.Review
+	(*AddReviewRequest)(nil),      // 1: reviewservice.AddReviewRequest
+	(*AddReviewResponse)(nil),     // 2: reviewservice.AddReviewResponse
+	(*ListReviewsRequest)(nil),    // 3: reviewservice.ListReviewsRequest
+	(*GetBookRatingRequest)(nil),  // 4: reviewservice.GetBookRatingRequest
+	(*BookRating)(nil),            // 5: reviewservice.BookRating
+	(*GetBookRatingResponse)(nil), // 6: reviewservice.GetBookRatingResponse
+}
+var file_proto_review_service_proto_depIdxs = []int32{
+	0, // 0: reviewservice.AddReviewResponse.review:type_name -> reviewservice.Review
+	5, // 1: reviewservice.GetBookRatingResponse.rating:type_name -> reviewservice.BookRating
+	1, // 2: reviewservice.ReviewCatalog.AddReview:input_type -> reviewservice.AddReviewRequest
+	3, // 3: reviewservice.ReviewCatalog.ListReviews:input_type -> reviewservice.ListReviewsRequest
+	4, // 4: reviewservice.ReviewCatalog.GetBookRating:input_type -> reviewservice.GetBookRatingRequest
+	2, // 5: reviewservice.ReviewCatalog.AddReview:output_type -> reviewservice.AddReviewResponse
+	0, // 6: reviewservice.ReviewCatalog.ListReviews:output_type -> reviewservice.Review
+	6, // 7: reviewservice.ReviewCatalog.GetBookRating:output_type -> reviewservice.GetBookRatingResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_review_service_proto_init() }
+func file_proto_review_service_proto_init() {
+	if File_proto_review_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_review_service_proto_rawDesc), len(file_proto_review_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_review_service_proto_goTypes,
+		DependencyIndexes: file_proto_review_service_proto_depIdxs,
+		MessageInfos:      file_proto_review_service_proto_msgTypes,
+	}.Build()
+	File_proto_review_service_proto = out.File
+	file_proto_review_service_proto_goTypes = nil
+	file_proto_review_service_proto_depIdxs = nil
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: proto/review_service.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReviewCatalog_AddReview_FullMethodName     = "/reviewservice.ReviewCatalog/AddReview"
+	ReviewCatalog_ListReviews_FullMethodName   = "/reviewservice.ReviewCatalog/ListReviews"
+	ReviewCatalog_GetBookRating_FullMethodName = "/reviewservice.ReviewCatalog/GetBookRating"
+)
+
+// ReviewCatalogClient is the client API for ReviewCatalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReviewCatalog is served by book-service next to BookCatalog and keeps its
+// reviews in the same database, so every call on an unknown book fails with
+// NOT_FOUND.
+type ReviewCatalogClient interface {
+	AddReview(ctx context.Context, in *AddReviewRequest, opts ...grpc.CallOption) (*AddReviewResponse, error)
+	// ListReviews streams a book's reviews, oldest first.
+	ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Review], error)
+	GetBookRating(ctx context.Context, in *GetBookRatingRequest, opts ...grpc.CallOption) (*GetBookRatingResponse, error)
+}
+
+type reviewCatalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReviewCatalogClient(cc grpc.ClientConnInterface) ReviewCatalogClient {
+	return &reviewCatalogClient{cc}
+}
+
+func (c *reviewCatalogClient) AddReview(ctx context.Context, in *AddReviewRequest, opts ...grpc.CallOption) (*AddReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddReviewResponse)
+	err := c.cc.Invoke(ctx, ReviewCatalog_AddReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewCatalogClient) ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Review], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReviewCatalog_ServiceDesc.Streams[0], ReviewCatalog_ListReviews_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListReviewsRequest, Review]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReviewCatalog_ListReviewsClient = grpc.ServerStreamingClient[Review]
+
+func (c *reviewCatalogClient) GetBookRating(ctx context.Context, in *GetBookRatingRequest, opts ...grpc.CallOption) (*GetBookRatingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBookRatingResponse)
+	err := c.cc.Invoke(ctx, ReviewCatalog_GetBookRating_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReviewCatalogServer is the server API for ReviewCatalog service.
+// All implementations must embed UnimplementedReviewCatalogServer
+// for forward compatibility.
+//
+// ReviewCatalog is served by book-service next to BookCatalog and keeps its
+// reviews in the same database, so every call on an unknown book fails with
+// NOT_FOUND.
+type ReviewCatalogServer interface {
+	AddReview(context.Context, *AddReviewRequest) (*AddReviewResponse, error)
+	// ListReviews streams a book's reviews, oldest first.
+	ListReviews(*ListReviewsRequest, grpc.ServerStreamingServer[Review]) error
+	GetBookRating(context.Context, *GetBookRatingRequest) (*GetBookRatingResponse, error)
+	mustEmbedUnimplementedReviewCatalogServer()
+}
+
+// UnimplementedReviewCatalogServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReviewCatalogServer struct{}
+
+func (UnimplementedReviewCatalogServer) AddReview(context.Context, *AddReviewRequest) (*AddReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddReview not implemented")
+}
+func (UnimplementedReviewCatalogServer) ListReviews(*ListReviewsRequest, grpc.ServerStreamingServer[Review]) error {
+	return status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedReviewCatalogServer) GetBookRating(context.Context, *GetBookRatingRequest) (*GetBookRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBookRating not implemented")
+}
+func (UnimplementedReviewCatalogServer) mustEmbedUnimplementedReviewCatalogServer() {}
+func (UnimplementedReviewCatalogServer) testEmbeddedByValue()                       {}
+
+// UnsafeReviewCatalogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReviewCatalogServer will
+// result in compilation errors.
+type UnsafeReviewCatalogServer interface {
+	mustEmbedUnimplementedReviewCatalogServer()
+}
+
+func RegisterReviewCatalogServer(s grpc.ServiceRegistrar, srv ReviewCatalogServer) {
+	// If the following call pancis, it indicates UnimplementedReviewCatalogServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReviewCatalog_ServiceDesc, srv)
+}
+
+func _ReviewCatalog_AddReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewCatalogServer).AddReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewCatalog_AddReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewCatalogServer).AddReview(ctx, req.(*AddReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewCatalog_ListReviews_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListReviewsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReviewCatalogServer).ListReviews(m, &grpc.GenericServerStream[ListReviewsRequest, Review]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReviewCatalog_ListReviewsServer = grpc.ServerStreamingServer[Review]
+
+func _ReviewCatalog_GetBookRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBookRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewCatalogServer).GetBookRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewCatalog_GetBookRating_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewCatalogServer).GetBookRating(ctx, req.(*GetBookRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReviewCatalog_ServiceDesc is the grpc.ServiceDesc for ReviewCatalog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReviewCatalog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reviewservice.ReviewCatalog",
+	HandlerType: (*ReviewCatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddReview",
+			Handler:    _ReviewCatalog_AddReview_Handler,
+		},
+		{
+			MethodName: "GetBookRating",
+			Handler:    _ReviewCatalog_GetBookRating_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListReviews",
+			Handler:       _ReviewCatalog_ListReviews_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/review_service.proto",
+}
@@ -0,0 +1,37 @@
+package proto
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MatchExpr turns the query into an FTS5 MATCH expression on the fields
+// SearchBooksRequest.field names. Every word of the query must start a word
+// in those fields, so "prog lang" finds "The Go Programming Language".
+// Words are split on anything but letters and digits, as the FTS5 tokenizer
+// does, so FTS5 syntax in the query is never interpreted.
+//
+// It returns "" when the query has no words, and for field "isbn", which is
+// matched exactly rather than through the index.
+func (r *SearchBooksRequest) MatchExpr() string {
+	words := strings.FieldsFunc(r.Query, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `"*`
+	}
+	expr := strings.Join(terms, " ")
+
+	switch r.Field {
+	case "title", "author":
+		return r.Field + " : (" + expr + ")"
+	case "isbn":
+		return ""
+	default:
+		return expr
+	}
+}
@@ -0,0 +1,17 @@
+package proto
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UnixTime converts the Unix seconds a database keeps for a create or update
+// time. 0, which rows from before the times were kept hold, gives nil, so
+// the field is left unset.
+func UnixTime(sec int64) *timestamppb.Timestamp {
+	if sec == 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(sec, 0))
+}
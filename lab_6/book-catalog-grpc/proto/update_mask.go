@@ -0,0 +1,13 @@
+package proto
+
+// maskPaths are the update_mask paths UpdateBookRequest accepts.
+var maskPaths = map[string]bool{
+	"title":          true,
+	"author":         true,
+	"isbn":           true,
+	"price":          true,
+	"stock":          true,
+	"published_year": true,
+	"author_id":      true,
+	"category":       true,
+}
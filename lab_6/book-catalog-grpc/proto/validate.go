@@ -0,0 +1,457 @@
+package proto
+
+// Hand-written validation rules for the request messages. The servers check
+// them in interceptors.UnaryValidation / StreamValidation before a handler
+// runs, so handlers can assume well-formed input.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxTextLength = 255
+	maxPrice      = 10000
+	maxPageSize   = 1000
+	minBookYear   = 1450 // the printing press
+	minBirthYear  = 1800
+	maxBirthYear  = 2100
+	maxRating     = 5
+	maxComment    = 2000
+	maxRequestID  = 64
+)
+
+// ValidationError is what Validate returns: every broken rule of one
+// message, by field.
+type ValidationError struct {
+	Violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, fv := range e.Violations {
+		msgs[i] = fv.Field + ": " + fv.Description
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// BadRequest returns the violations as the google.rpc detail that
+// interceptors.UnaryValidation attaches to the InvalidArgument status.
+func (e *ValidationError) BadRequest() *errdetails.BadRequest {
+	return &errdetails.BadRequest{FieldViolations: e.Violations}
+}
+
+// violations collects every broken rule of one message.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+func (v *violations) requireText(field, value string) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		v.add(field, "is required")
+	case len(value) > maxTextLength:
+		v.add(field, "must be at most %d characters", maxTextLength)
+	}
+}
+
+func (v *violations) requirePositive(field string, value int32) {
+	if value <= 0 {
+		v.add(field, "must be positive")
+	}
+}
+
+// timestamp checks an optional Timestamp; nil is allowed.
+func (v *violations) timestamp(field string, t *timestamppb.Timestamp) {
+	if t == nil {
+		return
+	}
+	if err := t.CheckValid(); err != nil {
+		v.add(field, "is not a valid time")
+	}
+}
+
+// bookFields checks the attributes shared by CreateBook and UpdateBook. Only
+// the fields set reports as present are checked.
+func (v *violations) bookFields(set func(path string) bool, title, author, isbn string, price *Money, stock, year, authorID int32, category BookCategory) {
+	if set("title") {
+		v.requireText("title", title)
+	}
+	if set("author") {
+		v.requireText("author", author)
+	}
+	if set("isbn") && isbn != "" && !validISBN(isbn) {
+		v.add("isbn", "must be an ISBN-10 or ISBN-13, e.g. 978-0134190440")
+	}
+	if set("price") && v.money("price", price) {
+		if n := price.TotalNanos(); n < 0 || n > maxPrice*nanosPerUnit {
+			v.add("price", "must be between 0 and %d", maxPrice)
+		}
+	}
+	if set("stock") && stock < 0 {
+		v.add("stock", "cannot be negative")
+	}
+	// 0 means the year is unknown.
+	if maxYear := int32(time.Now().Year() + 1); set("published_year") && year != 0 && (year < minBookYear || year > maxYear) {
+		v.add("published_year", "must be between %d and %d", minBookYear, maxYear)
+	}
+	if set("author_id") && authorID < 0 {
+		v.add("author_id", "cannot be negative")
+	}
+	if set("category") && !validCategory(category) {
+		v.add("category", "unknown category %d", category)
+	}
+}
+
+// money checks that m is a well-formed amount in a supported currency and
+// reports whether it is.
+func (v *violations) money(field string, m *Money) bool {
+	switch {
+	case m == nil:
+		v.add(field, "is required")
+	case !ValidCurrency(m.CurrencyCode):
+		v.add(field+".currency_code", "unsupported currency %q", m.CurrencyCode)
+	case m.Nanos <= -nanosPerUnit || m.Nanos >= nanosPerUnit:
+		v.add(field+".nanos", "must be between -999999999 and 999999999")
+	case m.Units > 0 && m.Nanos < 0 || m.Units < 0 && m.Nanos > 0:
+		v.add(field, "units and nanos must have the same sign")
+	default:
+		return true
+	}
+	return false
+}
+
+func validCategory(c BookCategory) bool {
+	_, ok := BookCategory_name[int32(c)]
+	return ok
+}
+
+func allFields(string) bool { return true }
+
+// validISBN accepts ISBN-10 (last character may be X) and ISBN-13 starting
+// with 978 or 979, with optional hyphens or spaces. Check digits are not
+// verified, so catalog data entered by hand still passes.
+func validISBN(isbn string) bool {
+	s := strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	for i, r := range s {
+		if (r < '0' || r > '9') && !(len(s) == 10 && i == 9 && (r == 'X' || r == 'x')) {
+			return false
+		}
+	}
+	return len(s) == 10 || (len(s) == 13 && (strings.HasPrefix(s, "978") || strings.HasPrefix(s, "979")))
+}
+
+func (r *CreateBookRequest) Validate() error {
+	var v violations
+	v.bookFields(allFields, r.Title, r.Author, r.Isbn, r.Price, r.Stock, r.PublishedYear, r.AuthorId, r.Category)
+	if len(r.RequestId) > maxRequestID {
+		v.add("request_id", "must be at most %d characters", maxRequestID)
+	}
+	return v.err()
+}
+
+// Validate checks only the fields named in update_mask, if there is one.
+func (r *UpdateBookRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	set := allFields
+	if paths := r.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		masked := make(map[string]bool, len(paths))
+		for _, p := range paths {
+			if !maskPaths[p] {
+				v.add("update_mask", "unknown field %q", p)
+			}
+			masked[p] = true
+		}
+		set = func(path string) bool { return masked[path] }
+	}
+	v.bookFields(set, r.Title, r.Author, r.Isbn, r.Price, r.Stock, r.PublishedYear, r.AuthorId, r.Category)
+	return v.err()
+}
+
+func (r *GetBookRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	return v.err()
+}
+
+func (r *DeleteBookRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	return v.err()
+}
+
+func (r *UndeleteBookRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	return v.err()
+}
+
+// Validate allows page and page_size 0, which mean "use the default", and
+// an empty order_by.
+func (r *ListBooksRequest) Validate() error {
+	var v violations
+	if r.Page < 0 {
+		v.add("page", "cannot be negative")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPageSize {
+		v.add("page_size", "must be between 0 and %d", maxPageSize)
+	}
+	if _, err := ParseOrderBy(r.OrderBy); err != nil {
+		v.add("order_by", "%v", err)
+	}
+	if r.Filter != nil {
+		var fv violations
+		fv.filter(r.Filter)
+		for _, f := range fv {
+			f.Field = "filter." + f.Field
+			v = append(v, f)
+		}
+	}
+	v.timestamp("updated_since", r.UpdatedSince)
+	return v.err()
+}
+
+// Validate allows an empty field, which searches all fields.
+func (r *SearchBooksRequest) Validate() error {
+	var v violations
+	v.requireText("query", r.Query)
+	switch r.Field {
+	case "title", "author", "isbn", "all", "":
+	default:
+		v.add("field", "must be title, author, isbn, or all")
+	}
+	if r.Fuzzy && r.Field == "isbn" {
+		v.add("fuzzy", "is not supported for field isbn")
+	}
+	return v.err()
+}
+
+// Validate treats 0 as "no bound" for each end of the year and stock
+// ranges, and an unset price as no bound on price.
+func (r *FilterBooksRequest) Validate() error {
+	var v violations
+	v.filter(r)
+	return v.err()
+}
+
+// filter checks a FilterBooksRequest, on its own or as ListBooksRequest.filter.
+func (v *violations) filter(r *FilterBooksRequest) {
+	validMin := r.MinPrice == nil || v.money("min_price", r.MinPrice)
+	validMax := r.MaxPrice == nil || v.money("max_price", r.MaxPrice)
+	if r.MinPrice.TotalNanos() < 0 || r.MaxPrice.TotalNanos() < 0 {
+		v.add("price", "cannot be negative")
+	}
+	if r.MinPrice != nil && r.MaxPrice != nil && validMin && validMax {
+		switch {
+		case r.MinPrice.CurrencyCode != r.MaxPrice.CurrencyCode:
+			v.add("max_price.currency_code", "must be the currency of min_price")
+		case r.MinPrice.TotalNanos() > r.MaxPrice.TotalNanos():
+			v.add("min_price", "cannot be greater than max_price")
+		}
+	}
+	if r.MinYear < 0 || r.MaxYear < 0 {
+		v.add("year", "cannot be negative")
+	}
+	if r.MinYear > 0 && r.MaxYear > 0 && r.MinYear > r.MaxYear {
+		v.add("min_year", "cannot be greater than max_year")
+	}
+	if r.MinStock < 0 || r.MaxStock < 0 {
+		v.add("stock", "cannot be negative")
+	}
+	if r.MinStock > 0 && r.MaxStock > 0 && r.MinStock > r.MaxStock {
+		v.add("min_stock", "cannot be greater than max_stock")
+	}
+	if r.AuthorId < 0 {
+		v.add("author_id", "cannot be negative")
+	}
+	for _, c := range r.Categories {
+		if !validCategory(c) {
+			v.add("categories", "unknown category %d", c)
+			break
+		}
+	}
+}
+
+func (r *GetBooksByAuthorRequest) Validate() error {
+	var v violations
+	v.requirePositive("author_id", r.AuthorId)
+	return v.err()
+}
+
+func (r *WatchRequest) Validate() error {
+	var v violations
+	if len(r.BookIds) == 0 {
+		v.add("book_ids", "is required")
+	}
+	for _, id := range r.BookIds {
+		if id <= 0 {
+			v.add("book_ids", "must be positive, got %d", id)
+			break
+		}
+	}
+	return v.err()
+}
+
+func (r *WatchLowStockRequest) Validate() error {
+	var v violations
+	v.requirePositive("threshold", r.Threshold)
+	return v.err()
+}
+
+func (r *ExportRequest) Validate() error {
+	var v violations
+	if _, ok := ExportRequest_Format_name[int32(r.Format)]; !ok {
+		v.add("format", "must be JSONL or CSV")
+	}
+	return v.err()
+}
+
+func (r *AddReviewRequest) Validate() error {
+	var v violations
+	v.requirePositive("book_id", r.BookId)
+	v.requireText("reviewer", r.Reviewer)
+	if r.Rating < 1 || r.Rating > maxRating {
+		v.add("rating", "must be between 1 and %d", maxRating)
+	}
+	if len(r.Comment) > maxComment {
+		v.add("comment", "must be at most %d characters", maxComment)
+	}
+	return v.err()
+}
+
+func (r *ListReviewsRequest) Validate() error {
+	var v violations
+	v.requirePositive("book_id", r.BookId)
+	return v.err()
+}
+
+func (r *GetBookRatingRequest) Validate() error {
+	var v violations
+	v.requirePositive("book_id", r.BookId)
+	return v.err()
+}
+
+func (r *GetAuthorRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	return v.err()
+}
+
+// authorFields checks the attributes shared by CreateAuthor and UpdateAuthor.
+func (v *violations) authorFields(name string, birthYear int32) {
+	v.requireText("name", name)
+	if birthYear < minBirthYear || birthYear > maxBirthYear {
+		v.add("birth_year", "must be between %d and %d", minBirthYear, maxBirthYear)
+	}
+}
+
+func (r *CreateAuthorRequest) Validate() error {
+	var v violations
+	v.authorFields(r.Name, r.BirthYear)
+	return v.err()
+}
+
+// Validate checks the books as CreateBook would, minus the author fields
+// that author-service fills in, so a bad book fails before anything is
+// created.
+func (r *CreateAuthorWithBooksRequest) Validate() error {
+	var v violations
+	if r.Author == nil {
+		v.add("author", "is required")
+	} else {
+		v.authorFields(r.Author.Name, r.Author.BirthYear)
+	}
+	if len(r.Books) == 0 {
+		v.add("books", "is required")
+	}
+	notAuthor := func(path string) bool { return path != "author" && path != "author_id" }
+	for i, b := range r.Books {
+		var bv violations
+		bv.bookFields(notAuthor, b.Title, "", b.Isbn, b.Price, b.Stock, b.PublishedYear, 0, BookCategory_UNKNOWN)
+		for _, fv := range bv {
+			fv.Field = fmt.Sprintf("books[%d].%s", i, fv.Field)
+			v = append(v, fv)
+		}
+	}
+	return v.err()
+}
+
+func (r *UpdateAuthorRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	v.authorFields(r.Name, r.BirthYear)
+	return v.err()
+}
+
+func (r *DeleteAuthorRequest) Validate() error {
+	var v violations
+	v.requirePositive("id", r.Id)
+	return v.err()
+}
+
+func (r *ListAuthorsRequest) Validate() error {
+	var v violations
+	if r.Page < 0 {
+		v.add("page", "cannot be negative")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPageSize {
+		v.add("page_size", "must be between 0 and %d", maxPageSize)
+	}
+	v.timestamp("updated_since", r.UpdatedSince)
+	return v.err()
+}
+
+// Validate treats 0 as "no bound" for each end of the birth-year range.
+func (r *SearchAuthorsRequest) Validate() error {
+	var v violations
+	if len(r.Name) > maxTextLength {
+		v.add("name", "must be at most %d characters", maxTextLength)
+	}
+	if len(r.Country) > maxTextLength {
+		v.add("country", "must be at most %d characters", maxTextLength)
+	}
+	if r.MinBirthYear < 0 || r.MaxBirthYear < 0 {
+		v.add("birth_year", "cannot be negative")
+	}
+	if r.MinBirthYear > 0 && r.MaxBirthYear > 0 && r.MinBirthYear > r.MaxBirthYear {
+		v.add("min_birth_year", "cannot be greater than max_birth_year")
+	}
+	if r.Page < 0 {
+		v.add("page", "cannot be negative")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPageSize {
+		v.add("page_size", "must be between 0 and %d", maxPageSize)
+	}
+	return v.err()
+}
+
+func (r *AuthorStatsRequest) Validate() error {
+	var v violations
+	if r.Page < 0 {
+		v.add("page", "cannot be negative")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPageSize {
+		v.add("page_size", "must be between 0 and %d", maxPageSize)
+	}
+	return v.err()
+}
+
+func (r *GetAuthorBooksRequest) Validate() error {
+	var v violations
+	v.requirePositive("author_id", r.AuthorId)
+	return v.err()
+}
@@ -0,0 +1,30 @@
+// Package reflectionconfig registers the gRPC server reflection service on
+// the lab's servers, so grpcurl and evans can list and call the services
+// without the .proto files. It is off unless turned on with a flag that
+// defaults to an environment variable:
+//
+//	-reflection GRPC_REFLECTION register the reflection service (default false)
+//
+// Call flag.Parse before Register.
+package reflectionconfig
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var enabled = flag.Bool("reflection", os.Getenv("GRPC_REFLECTION") == "true", "register the gRPC server reflection service")
+
+// Register adds the reflection service to s when -reflection is set. Call it
+// after the lab's own services are registered.
+func Register(s *grpc.Server) {
+	if !*enabled {
+		return
+	}
+	reflection.Register(s)
+	log.Println("🔍 Server reflection enabled")
+}
@@ -0,0 +1,108 @@
+// Package requestid tags every RPC with an "x-request-id" so one logical
+// request can be followed through the logs of every service it touches.
+// Servers take the id from the incoming metadata, or make one up, and send
+// it back in the response headers; clients inside a server forward it on
+// their outgoing calls.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header is the metadata key carrying the id.
+const Header = "x-request-id"
+
+// maxLength bounds ids taken from clients, so a caller cannot flood the
+// logs through them.
+const maxLength = 64
+
+type idKey struct{}
+
+// FromContext returns the request id of ctx, or "" outside an RPC.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// Printf logs like log.Printf, prefixed with the request id of ctx if it
+// has one.
+func Printf(ctx context.Context, format string, args ...any) {
+	if id := FromContext(ctx); id != "" {
+		format = "req=" + id + " | " + format
+	}
+	log.Printf(format, args...)
+}
+
+// incoming returns the id the client sent, or a new one if it sent none or
+// one that is not fit for a log line.
+func incoming(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(Header); len(ids) > 0 && valid(ids[0]) {
+			return ids[0]
+		}
+	}
+	return newID()
+}
+
+// valid accepts ids of letters, digits, '-', '_' and '.', such as UUIDs.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// UnaryServerInterceptor puts the request id in the handler's context and
+// the response headers. Install it before interceptors.UnaryLogging.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := incoming(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(Header, id))
+		return handler(context.WithValue(ctx, idKey{}, id), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incoming(ss.Context())
+		ss.SetHeader(metadata.Pairs(Header, id))
+		return handler(srv, &idStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), idKey{}, id)})
+	}
+}
+
+// idStream hands the context carrying the id to stream handlers.
+type idStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *idStream) Context() context.Context { return s.ctx }
+
+// UnaryClientInterceptor forwards the request id of ctx, if any, so a call
+// made while serving an RPC is logged under the same id.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, Header, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
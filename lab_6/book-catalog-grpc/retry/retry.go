@@ -0,0 +1,82 @@
+// Package retry gives the lab clients a default service config that retries
+// RPCs failing with UNAVAILABLE, so a service restarted mid-demo costs a few
+// seconds instead of a failed script.
+//
+// gRPC-Go does not implement hedgingPolicy, so reads are retried the same
+// way rather than hedged.
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// serviceConfig retries each RPC up to 5 times (the gRPC maximum) after
+// roughly 0.5s, 1s, 2s and 4s with jitter. Only UNAVAILABLE is retried: it
+// almost always means the call never reached a handler, so writes are not
+// applied twice.
+//
+// Retries only cover calls that reached a connection. While the server is
+// down an RPC fails before that unless it waits for the channel to be ready,
+// so waitForReady is set for the demo clients.
+const serviceConfig = `{
+  "methodConfig": [{
+    "name": [
+      {"service": "bookservice.BookCatalog"},
+      {"service": "bookcatalog.v2.BookCatalog"},
+      {"service": "authorservice.AuthorCatalog"}
+    ],
+    "waitForReady": %t,
+    "retryPolicy": {
+      "maxAttempts": 5,
+      "initialBackoff": "0.5s",
+      "maxBackoff": "4s",
+      "backoffMultiplier": 2,
+      "retryableStatusCodes": ["UNAVAILABLE"]
+    }
+  }],
+  "retryThrottling": {
+    "maxTokens": 10,
+    "tokenRatio": 0.1
+  }
+}`
+
+// DialOption installs the retry policy as the channel's default service
+// config; a config from the name resolver still wins. RPCs wait, up to
+// their deadline, for an unreachable server to come back.
+//
+// A channel has only one default service config, so other top-level fields,
+// such as the load balancing config from package endpoints, are passed in
+// extra and merged.
+func DialOption(extra ...map[string]any) grpc.DialOption {
+	return grpc.WithDefaultServiceConfig(merge(fmt.Sprintf(serviceConfig, true), extra))
+}
+
+// FailFastDialOption is DialOption without the waiting, for callers that
+// would rather degrade at once when the server is down, like author-service
+// calling book-service.
+func FailFastDialOption(extra ...map[string]any) grpc.DialOption {
+	return grpc.WithDefaultServiceConfig(merge(fmt.Sprintf(serviceConfig, false), extra))
+}
+
+func merge(base string, extra []map[string]any) string {
+	if len(extra) == 0 {
+		return base
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(base), &cfg); err != nil {
+		panic("retry: bad service config: " + err.Error())
+	}
+	for _, fields := range extra {
+		for k, v := range fields {
+			cfg[k] = v
+		}
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		panic("retry: bad service config: " + err.Error())
+	}
+	return string(out)
+}
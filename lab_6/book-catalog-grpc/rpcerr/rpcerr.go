@@ -0,0 +1,45 @@
+// Package rpcerr prints the errors of the lab servers for the demo clients,
+// including the google.rpc details they attach to a status: BadRequest on
+// validation failures, ErrorInfo and RetryInfo on database errors,
+// PreconditionFailure when a write refers to something that does not exist.
+package rpcerr
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Describe returns the status message of err, with a BadRequest listed one
+// field per line instead, and the ErrorInfo reason, RetryInfo delay and
+// PreconditionFailure violations, if any, appended in parentheses.
+func Describe(err error) string {
+	st := status.Convert(err)
+	msg := st.Message()
+	var notes []string
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			var b strings.Builder
+			b.WriteString("invalid request:")
+			for _, fv := range d.FieldViolations {
+				fmt.Fprintf(&b, "\n    - %s: %s", fv.Field, fv.Description)
+			}
+			msg = b.String()
+		case *errdetails.ErrorInfo:
+			notes = append(notes, "reason "+d.Reason)
+		case *errdetails.RetryInfo:
+			notes = append(notes, "retry after "+d.RetryDelay.AsDuration().String())
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.Violations {
+				notes = append(notes, "precondition "+v.Type+" failed for "+v.Subject)
+			}
+		}
+	}
+	if len(notes) > 0 {
+		msg += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return msg
+}
@@ -2,12 +2,18 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"math"
 	"net"
 
+	"book-catalog-grpc/interceptors"
+	"book-catalog-grpc/keepaliveconfig"
 	pb "book-catalog-grpc/proto"
+	"book-catalog-grpc/reflectionconfig"
+	"book-catalog-grpc/requestid"
+	"book-catalog-grpc/tlsconfig"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -20,7 +26,6 @@ type server struct {
 }
 
 func (s *server) Calculate(ctx context.Context, req *pb.CalculateRequest) (*pb.CalculateResponse, error) {
-	log.Printf("Calculate: %.2f %s %.2f", req.A, req.Operation, req.B)
 	var result float32
 
 	switch req.Operation {
@@ -46,7 +51,6 @@ func (s *server) Calculate(ctx context.Context, req *pb.CalculateRequest) (*pb.C
 }
 
 func (s *server) SquareRoot(ctx context.Context, req *pb.SquareRootRequest) (*pb.SquareRootResponse, error) {
-	log.Printf("SquareRoot: %.2f", req.Number)
 	if req.Number < 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "cannot calculate square root of negative number: %.2f", req.Number)
 	}
@@ -59,7 +63,6 @@ func (s *server) SquareRoot(ctx context.Context, req *pb.SquareRootRequest) (*pb
 }
 
 func (s *server) GetHistory(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
-	log.Println("GetHistory called")
 	return &pb.HistoryResponse{
 		Calculations: s.history,
 		Count:        int32(len(s.history)),
@@ -67,13 +70,23 @@ func (s *server) GetHistory(ctx context.Context, req *pb.HistoryRequest) (*pb.Hi
 }
 
 func main() {
+	flag.Parse()
+
 	lis, err := net.Listen("tcp", "0.0.0.0:50051")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	creds, err := tlsconfig.ServerCredentials()
+	if err != nil {
+		log.Fatalf("failed to load TLS credentials: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), keepaliveconfig.ServerParams(), keepaliveconfig.EnforcementPolicy(),
+		grpc.ChainUnaryInterceptor(interceptors.UnaryMetrics(), requestid.UnaryServerInterceptor(), interceptors.UnaryLogging(), interceptors.UnaryRecovery()),
+		grpc.ChainStreamInterceptor(interceptors.StreamMetrics(), requestid.StreamServerInterceptor(), interceptors.StreamLogging(), interceptors.StreamRecovery()))
+	interceptors.ServeMetrics()
 	pb.RegisterCalculatorServer(grpcServer, &server{history: []string{}})
+	reflectionconfig.Register(grpcServer)
 
 	log.Println("🚀 Calculator gRPC server listening on :50051")
 	if err := grpcServer.Serve(lis); err != nil {
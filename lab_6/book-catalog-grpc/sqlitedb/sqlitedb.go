@@ -0,0 +1,71 @@
+// Package sqlitedb opens the lab's SQLite databases set up for a gRPC
+// server, where many RPCs hit the same file at once:
+//
+//   - WAL journal, so reads no longer block on a write and vice versa;
+//   - busy_timeout, so a connection waits for the write lock instead of
+//     failing at once with "database is locked";
+//   - transactions begin IMMEDIATE, taking the write lock up front, since a
+//     read-then-write transaction that has to upgrade its lock fails with
+//     SQLITE_BUSY regardless of the timeout;
+//   - a small connection pool: SQLite allows one writer at a time, so more
+//     connections only add lock contention.
+package sqlitedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	busyTimeout  = 5 * time.Second
+	maxOpenConns = 4
+	maxIdleConns = 4
+)
+
+// Open opens, creating it if needed, the database file at path.
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{
+		"_pragma": {
+			"journal_mode(WAL)",
+			fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+			"synchronous(NORMAL)", // safe with WAL, and far fewer fsyncs
+		},
+		"_txlock": {"immediate"},
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+
+	// sql.Open connects lazily; connect now so a bad path fails here.
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// IsBusy reports whether err is SQLite giving up on a lock another
+// connection held past busy_timeout. Nothing was written, so the call is
+// safe to retry once the other writer is done.
+func IsBusy(err error) bool {
+	var e *sqlite.Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	// Extended codes such as SQLITE_BUSY_SNAPSHOT keep the primary code in
+	// the low byte.
+	switch e.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
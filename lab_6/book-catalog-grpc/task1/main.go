@@ -15,7 +15,7 @@ func main() {
 		Title:         "The Go Programming Language",
 		Author:        "Alan Donovan",
 		Isbn:          "978-0134190440",
-		Price:         39.99,
+		Price:         &pb.Money{CurrencyCode: "USD", Units: 39, Nanos: 990000000},
 		Stock:         15,
 		PublishedYear: 2015,
 	}
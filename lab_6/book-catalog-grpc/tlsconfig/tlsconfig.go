@@ -0,0 +1,111 @@
+// Package tlsconfig builds the transport credentials shared by the lab's
+// gRPC servers and clients from command-line flags, each of which defaults
+// to an environment variable:
+//
+//	-tls-cert        TLS_CERT_FILE        this process's certificate (PEM)
+//	-tls-key         TLS_KEY_FILE         its private key (PEM)
+//	-tls-ca          TLS_CA_FILE          CA that signed the other side's certificate
+//	-tls-client-auth TLS_CLIENT_AUTH=true servers require a client certificate (mTLS)
+//	-tls-server-name TLS_SERVER_NAME      name clients expect in the server certificate
+//	-tls             TLS_ENABLED=true     clients use TLS with the system roots
+//
+// A server uses TLS when it has a certificate and key; a client uses TLS when
+// -tls or -tls-ca is given, and presents its own certificate when it has one.
+// Without any of these both sides fall back to plaintext, as before.
+//
+// Call flag.Parse before ServerCredentials or ClientCredentials.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	certFile   = flag.String("tls-cert", os.Getenv("TLS_CERT_FILE"), "certificate file (PEM)")
+	keyFile    = flag.String("tls-key", os.Getenv("TLS_KEY_FILE"), "private key file (PEM)")
+	caFile     = flag.String("tls-ca", os.Getenv("TLS_CA_FILE"), "CA certificate used to verify the peer (PEM)")
+	clientAuth = flag.Bool("tls-client-auth", os.Getenv("TLS_CLIENT_AUTH") == "true", "require and verify client certificates (server)")
+	serverName = flag.String("tls-server-name", os.Getenv("TLS_SERVER_NAME"), "expected server name in the server certificate (client)")
+	enabled    = flag.Bool("tls", os.Getenv("TLS_ENABLED") == "true", "dial with TLS even without -tls-ca (client)")
+)
+
+// ServerCredentials returns the credentials to pass to grpc.Creds.
+func ServerCredentials() (credentials.TransportCredentials, error) {
+	if *certFile == "" && *keyFile == "" {
+		if *clientAuth {
+			return nil, fmt.Errorf("-tls-client-auth needs -tls-cert and -tls-key")
+		}
+		log.Println("⚠️  TLS disabled: serving plaintext")
+		return insecure.NewCredentials(), nil
+	}
+	cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if *clientAuth {
+		if *caFile == "" {
+			return nil, fmt.Errorf("-tls-client-auth needs -tls-ca to verify client certificates")
+		}
+		pool, err := loadCA(*caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		log.Println("🔒 mTLS enabled: client certificates required")
+	} else {
+		log.Println("🔒 TLS enabled")
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// ClientCredentials returns the credentials to pass to
+// grpc.WithTransportCredentials.
+func ClientCredentials() (credentials.TransportCredentials, error) {
+	if !*enabled && *caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	cfg := &tls.Config{
+		ServerName: *serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if *caFile != "" {
+		pool, err := loadCA(*caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if *certFile != "" || *keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+func loadCA(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
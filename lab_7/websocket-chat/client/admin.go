@@ -7,23 +7,51 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"time"
 )
 
 func main() {
-	if len(os.Args) < 3 {
+	if len(os.Args) < 2 {
 		fmt.Println("Usage: go run admin.go <cmd> [args]")
 		fmt.Println(`Commands:
 	broadcast "message"
 	room <room> "message"
 	user <username> "message"
-	announce "title" "message"`)
+	announce "title" "message"
+	rooms
+	create-room <name> ["topic"] [max members] [password]
+	delete-room <name>`)
 		return
 	}
 	cmd := os.Args[1]
 	host := "http://localhost:8080"
 
+	// room management
+	switch cmd {
+	case "rooms":
+		call(http.MethodGet, host+"/api/rooms", nil)
+		return
+	case "create-room":
+		if len(os.Args) < 3 { log.Fatal("room name required") }
+		room := map[string]interface{}{"name": os.Args[2]}
+		if len(os.Args) > 3 { room["topic"] = os.Args[3] }
+		if len(os.Args) > 4 {
+			max, err := strconv.Atoi(os.Args[4])
+			if err != nil { log.Fatal("max members must be a number") }
+			room["max_members"] = max
+		}
+		if len(os.Args) > 5 { room["password"] = os.Args[5] }
+		call(http.MethodPost, host+"/api/rooms", room)
+		return
+	case "delete-room":
+		if len(os.Args) < 3 { log.Fatal("room name required") }
+		call(http.MethodDelete, host+"/api/rooms/"+url.PathEscape(os.Args[2]), nil)
+		return
+	}
+
 	n := make(map[string]interface{})
 	n["id"] = ""
 	n["timestamp"] = time.Now()
@@ -65,3 +93,20 @@ func main() {
 	json.NewDecoder(resp.Body).Decode(&out)
 	fmt.Println("server response:", out)
 }
+
+// call sends a request to the room API and prints the response.
+func call(method, endpoint string, body interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, err := http.NewRequest(method, endpoint, &buf)
+	if err != nil { log.Fatal(err) }
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil { log.Fatal(err) }
+	defer resp.Body.Close()
+	var out interface{}
+	json.NewDecoder(resp.Body).Decode(&out)
+	fmt.Println("server response:", resp.Status, out)
+}
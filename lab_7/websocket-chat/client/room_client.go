@@ -11,28 +11,47 @@ import (
     "net/url"
     "os"
     "strings"
+    "sync"
 
 	
     "github.com/gorilla/websocket"
 )
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: go run room_client.go <username> <room>")
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run room_client.go <username> [room] [password]")
+		fmt.Println("More rooms: /join <room> [password], /leave [room]; chat goes to the room joined last")
 		return
 	}
 	username := os.Args[1]
-	room := os.Args[2]
+	query := url.Values{"username": {username}}
+	if len(os.Args) > 2 {
+		query.Set("room", os.Args[2])
+	}
+	if len(os.Args) > 3 {
+		query.Set("password", os.Args[3])
+	}
 
-	u := url.URL{Scheme: "ws", Host: "localhost:8080", Path: "/ws", RawQuery: "username=" + username + "&room=" + room}
+	u := url.URL{Scheme: "ws", Host: "localhost:8080", Path: "/ws", RawQuery: query.Encode()}
 	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 	if err != nil {
 		log.Fatal("dial:", err)
 	}
 	defer c.Close()
 
+	// the reader goroutine sends receipts while main sends input
+	var writeMu sync.Mutex
+	send := func(v interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		c.WriteJSON(v)
+	}
+
 	// read incoming
 	go func() {
+		// lastSeq is the highest seq seen so far in each room; a jump past
+		// lastSeq+1 means messages were dropped, so ask for them again
+		lastSeq := make(map[string]float64)
 		for {
 			var msg map[string]interface{}
 			if err := c.ReadJSON(&msg); err != nil {
@@ -40,8 +59,37 @@ func main() {
 				return
 			}
 			// pretty print
-			if t, ok := msg["type"].(string); ok && (t == "chat" || t == "join" || t == "leave" || t == "system" || t == "user_list" || t == "stats") {
-				fmt.Printf("[%s] %s: %s\n", msg["time"], msg["username"], msg["text"])
+			t, _ := msg["type"].(string)
+			id, _ := msg["id"].(float64)
+			room, _ := msg["room"].(string)
+			if t == "join" && msg["username"] == username {
+				// a new membership counts from its join
+				delete(lastSeq, room)
+			}
+			if seq, ok := msg["seq"].(float64); ok {
+				last := lastSeq[room]
+				if last > 0 && seq > last+1 {
+					fmt.Printf("(missed messages %.0f-%.0f of #%s, asking for them again)\n", last+1, seq-1, room)
+					send(map[string]interface{}{"type": "resend", "room": room, "seq": last + 1, "until": seq - 1})
+				}
+				if seq > last {
+					lastSeq[room] = seq
+				}
+			}
+			tag := ""
+			if room != "" {
+				tag = "#" + room + " "
+			}
+			if t == "chat" && id > 0 && msg["username"] != username {
+				send(map[string]interface{}{"type": "ack", "id": id})
+			}
+			if t == "chat" || t == "join" || t == "leave" || t == "system" || t == "user_list" || t == "stats" || t == "presence" {
+				fmt.Printf("[%s] %s%s: %s\n", msg["time"], tag, msg["username"], msg["text"])
+				if t == "chat" && id > 0 && msg["username"] != username {
+					send(map[string]interface{}{"type": "read", "id": id})
+				}
+			} else if t == "delivered" || t == "read" {
+				fmt.Printf("[%s] %smessage #%.0f %s by %s\n", msg["time"], tag, id, t, msg["username"])
 			} else {
 				b, _ := jsonMarshal(msg)
 				fmt.Println(string(b))
@@ -58,10 +106,10 @@ func main() {
 		}
 		// special commands start with '/'
 		if strings.HasPrefix(text, "/") {
-			c.WriteJSON(map[string]string{"type": "command", "text": text})
+			send(map[string]string{"type": "command", "text": text})
 			continue
 		}
-		c.WriteJSON(map[string]string{"type": "chat", "text": text})
+		send(map[string]string{"type": "chat", "text": text})
 	}
 }
 
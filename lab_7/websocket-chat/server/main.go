@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,11 +13,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // --- Message & Notification types ---
 type Message struct {
-	Type     string `json:"type"` // "join","leave","chat","system"
+	ID       int64  `json:"id,omitempty"`    // set by the hub on chat messages
+	Seq      int64  `json:"seq,omitempty"`   // set on everything broadcast to a room
+	Until    int64  `json:"until,omitempty"` // last seq of a resend request
+	Type     string `json:"type"`            // "join","leave","chat","system"
 	Room     string `json:"room"`
 	Username string `json:"username"`
 	Text     string `json:"text"`
@@ -29,8 +34,23 @@ const (
 	MsgUserList = "user_list"
 	MsgStats    = "stats"
 	MsgCommand  = "command"
+	MsgPresence = "presence"
+
+	// Receipts: a client sends ack when a chat message arrives and read once
+	// it is shown; the sender gets delivered and read back.
+	MsgAck       = "ack"
+	MsgRead      = "read"
+	MsgDelivered = "delivered"
+
+	// A client that sees a gap in seq asks for the missing messages with
+	// resend, from Seq to Until; the hub replays those it still has.
+	MsgResend = "resend"
 )
 
+// recentMessages is how many chat messages the hub keeps for receipts and
+// redelivery.
+const recentMessages = 200
+
 type Notification struct {
 	ID        string    `json:"id"`
 	Type      string    `json:"type"` // info, warning, error, success
@@ -40,13 +60,87 @@ type Notification struct {
 	Target    string    `json:"target"` // all, room:name, user:username
 }
 
+// --- Presence ---
+const (
+	StatusOnline  = "online"
+	StatusIdle    = "idle"
+	StatusOffline = "offline"
+)
+
+// idleAfter is how long a user may send nothing before they show as idle.
+const idleAfter = 5 * time.Minute
+
+type Presence struct {
+	Username   string    `json:"username"`
+	Status     string    `json:"status"` // online, idle, offline
+	LastActive time.Time `json:"last_active"`
+	LastSeen   time.Time `json:"last_seen,omitzero"` // set on disconnect
+	conns      int       // open connections of the user, across rooms
+}
+
 // --- Client ---
 type Client struct {
 	conn     *websocket.Conn
 	send     chan Message
 	username string
-	room     string
 	hub      *Hub
+
+	// rooms are the rooms the client is in; current is the one messages and
+	// commands without a room go to, the room joined last.
+	mu      sync.RWMutex
+	rooms   map[string]bool
+	current string
+}
+
+func (c *Client) inRoom(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rooms[name]
+}
+
+// roomNames lists the rooms of the client, sorted.
+func (c *Client) roomNames() []string {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.rooms))
+	for name := range c.rooms {
+		names = append(names, name)
+	}
+	c.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// target is the room a message from the client is for: the room it names,
+// or the current room.
+func (c *Client) target(room string) string {
+	if room != "" {
+		return room
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+// dropRoom takes name out of the client's rooms, reporting whether it was
+// in it. The current room moves to another of them.
+func (c *Client) dropRoom(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	in := c.rooms[name]
+	delete(c.rooms, name)
+	if c.current == name {
+		c.current = ""
+		for other := range c.rooms {
+			c.current = other
+			break
+		}
+	}
+	return in
+}
+
+// reply sends the client a message of its own, in the current room.
+func (c *Client) reply(msgType, text string) {
+	c.send <- Message{Type: msgType, Room: c.target(""), Username: "SYSTEM", Text: text, Time: time.Now().Format(time.RFC3339)}
 }
 
 // --- Room & Hub ---
@@ -54,40 +148,98 @@ type Room struct {
 	Name    string
 	Clients map[*Client]bool
 	mu      sync.RWMutex
+
+	// Rooms made with POST /api/rooms have a topic, may limit their members
+	// (0 for no limit) or need a password, and stay when they empty. Rooms
+	// made by joining them have none of that.
+	Topic        string
+	MaxMembers   int
+	passwordHash []byte
+	created      bool
+
+	// deleted is set by deleteRoom, which runs outside the hub loop; a join
+	// that fetched r just before must not admit anyone into it.
+	deleted bool
+
+	// seq numbers the messages broadcast to the room; recent keeps the last
+	// of them for resend requests.
+	seq    int64
+	recent []Message
 }
 
 type Hub struct {
 	rooms      map[string]*Room
-	register   chan *Client
+	register   chan membership
 	unregister chan *Client
+	join       chan membership
+	leave      chan membership
 	broadcast  chan Message
 	mu         sync.RWMutex
 
 	notifMu sync.RWMutex
 	history []Notification
+
+	presenceMu sync.RWMutex
+	presence   map[string]*Presence
+
+	// recent holds the last chat messages, oldest first; acked the ID each
+	// user last acked in each room, kept across reconnects.
+	sentMu sync.RWMutex
+	nextID int64
+	recent []Message
+	acked  map[ackKey]int64
+}
+
+type ackKey struct {
+	username, room string
+}
+
+// membership is a client joining or leaving a room, with the room's
+// password on joining. On register, room is the one given when dialling, if
+// any.
+type membership struct {
+	client   *Client
+	room     string
+	password string
 }
 
 // --- New Hub ---
 func NewHub() *Hub {
 	return &Hub{
 		rooms:      make(map[string]*Room),
-		register:   make(chan *Client),
+		register:   make(chan membership),
 		unregister: make(chan *Client),
+		join:       make(chan membership),
+		leave:      make(chan membership),
 		broadcast:  make(chan Message, 256),
 		history:    make([]Notification, 0, 50),
+		presence:   make(map[string]*Presence),
+		recent:     make([]Message, 0, recentMessages),
+		acked:      make(map[ackKey]int64),
 	}
 }
 
 // --- Hub run loop ---
 func (h *Hub) run() {
+	idleCheck := time.NewTicker(30 * time.Second)
+	defer idleCheck.Stop()
 	for {
 		select {
-		case c := <-h.register:
-			h.addClientToRoom(c)
+		case m := <-h.register:
+			h.connect(m.client, m.room, m.password)
 		case c := <-h.unregister:
-			h.removeClientFromRoom(c)
+			h.disconnect(c)
+		case m := <-h.join:
+			h.addClientToRoom(m.client, m.room, m.password)
+		case m := <-h.leave:
+			if h.removeClientFromRoom(m.client, m.room) {
+				m.client.reply(MsgSystem, fmt.Sprintf("You left '%s'", m.room))
+			}
 		case msg := <-h.broadcast:
+			msg = h.remember(msg)
 			h.broadcastToRoom(msg.Room, msg)
+		case <-idleCheck.C:
+			h.markIdle()
 		}
 	}
 }
@@ -104,13 +256,69 @@ func (h *Hub) getOrCreateRoom(name string) *Room {
 	return r
 }
 
+// --- Connect & disconnect ---
+func (h *Hub) connect(client *Client, room, password string) {
+	h.sendHistory(client, func(target string) bool {
+		return target == "all" || target == "user:"+client.username
+	})
+	h.setOnline(client.username, room)
+	if room != "" {
+		h.addClientToRoom(client, room, password)
+	}
+}
+
+func (h *Hub) disconnect(client *Client) {
+	rooms := client.roomNames()
+	for _, name := range rooms {
+		h.removeClientFromRoom(client, name)
+	}
+	h.setOffline(client.username, rooms)
+}
+
+// sendHistory sends client the past notifications whose target matches:
+// those for everyone and for the user on connect, those for a room on
+// joining it.
+func (h *Hub) sendHistory(client *Client, match func(target string) bool) {
+	h.notifMu.RLock()
+	history := make([]Notification, len(h.history))
+	copy(history, h.history)
+	h.notifMu.RUnlock()
+
+	for _, n := range history {
+		if match(n.Target) {
+			client.reply(MsgSystem, fmt.Sprintf("[NOTIF] %s: %s", n.Title, n.Message))
+		}
+	}
+}
+
 // --- Add client to room ---
-func (h *Hub) addClientToRoom(client *Client) {
-	r := h.getOrCreateRoom(client.room)
+func (h *Hub) addClientToRoom(client *Client, name, password string) {
+	client.mu.Lock()
+	already := client.rooms[name]
+	if already {
+		client.current = name
+	}
+	client.mu.Unlock()
+	if already {
+		client.reply(MsgSystem, fmt.Sprintf("Now talking in '%s'", name))
+		return
+	}
+
+	r := h.getOrCreateRoom(name)
 	r.mu.Lock()
+	if err := r.admit(password); err != nil {
+		r.mu.Unlock()
+		client.reply(MsgSystem, fmt.Sprintf("Cannot join '%s': %v", name, err))
+		return
+	}
 	r.Clients[client] = true
 	r.mu.Unlock()
 
+	client.mu.Lock()
+	client.rooms[name] = true
+	client.current = name
+	client.mu.Unlock()
+
 	// join notification
 	join := Message{
 		Type:     "join",
@@ -121,37 +329,16 @@ func (h *Hub) addClientToRoom(client *Client) {
 	}
 	h.broadcastToRoom(r.Name, join)
 
-	// send filtered notification history
-	h.notifMu.RLock()
-	history := make([]Notification, len(h.history))
-	copy(history, h.history)
-	h.notifMu.RUnlock()
+	// send the room's notification history
+	h.sendHistory(client, func(target string) bool { return target == "room:"+name })
 
-	for _, n := range history {
-		send := false
-		switch {
-		case n.Target == "all":
-			send = true
-		case strings.HasPrefix(n.Target, "room:"):
-			roomName := strings.TrimPrefix(n.Target, "room:")
-			if roomName == client.room {
-				send = true
-			}
-		case strings.HasPrefix(n.Target, "user:"):
-			user := strings.TrimPrefix(n.Target, "user:")
-			if user == client.username {
-				send = true
-			}
-		}
-		if send {
-			nj := Message{
-				Type:     MsgSystem,
-				Room:     client.room,
-				Username: "SYSTEM",
-				Text:     fmt.Sprintf("[NOTIF] %s: %s", n.Title, n.Message),
-				Time:     n.Timestamp.Format(time.RFC3339),
-			}
-			client.send <- nj
+	// resend what a returning user missed, without blocking the hub on a
+	// full send buffer
+	for _, m := range h.unacked(client.username, name) {
+		select {
+		case client.send <- m:
+		default:
+			log.Printf("send buffer of %s full, dropped replay of message %d in '%s'", client.username, m.ID, name)
 		}
 	}
 
@@ -160,12 +347,18 @@ func (h *Hub) addClientToRoom(client *Client) {
 }
 
 // --- Remove client ---
-func (h *Hub) removeClientFromRoom(client *Client) {
+// removeClientFromRoom reports whether the client was in the room.
+func (h *Hub) removeClientFromRoom(client *Client, name string) bool {
+	if !client.dropRoom(name) {
+		client.reply(MsgSystem, fmt.Sprintf("You are not in '%s'", name))
+		return false
+	}
+
 	h.mu.RLock()
-	r, ok := h.rooms[client.room]
+	r, ok := h.rooms[name]
 	h.mu.RUnlock()
 	if !ok {
-		return
+		return true
 	}
 
 	r.mu.Lock()
@@ -173,6 +366,7 @@ func (h *Hub) removeClientFromRoom(client *Client) {
 		delete(r.Clients, client)
 	}
 	remaining := len(r.Clients)
+	keep := r.created
 	r.mu.Unlock()
 
 	// leave notification
@@ -187,11 +381,97 @@ func (h *Hub) removeClientFromRoom(client *Client) {
 
 	h.sendUserListToRoom(r.Name)
 
-	if remaining == 0 {
+	if remaining == 0 && !keep {
 		h.mu.Lock()
-		delete(h.rooms, r.Name)
+		// unless it was filled or recreated meanwhile
+		if h.rooms[r.Name] == r {
+			r.mu.RLock()
+			if len(r.Clients) == 0 {
+				delete(h.rooms, r.Name)
+			}
+			r.mu.RUnlock()
+		}
 		h.mu.Unlock()
 	}
+	return true
+}
+
+// --- Room management ---
+// admit checks that a client with password may join r. The caller holds r.mu.
+func (r *Room) admit(password string) error {
+	if r.deleted {
+		return fmt.Errorf("room was deleted")
+	}
+	if r.passwordHash != nil && bcrypt.CompareHashAndPassword(r.passwordHash, []byte(password)) != nil {
+		return fmt.Errorf("wrong password")
+	}
+	if r.MaxMembers > 0 && len(r.Clients) >= r.MaxMembers {
+		return fmt.Errorf("room is full (max %d)", r.MaxMembers)
+	}
+	return nil
+}
+
+// RoomInfo describes a room for the REST API.
+type RoomInfo struct {
+	Name        string `json:"name"`
+	Topic       string `json:"topic"`
+	MaxMembers  int    `json:"max_members"`
+	Members     int    `json:"members"`
+	HasPassword bool   `json:"has_password"`
+}
+
+func (r *Room) info() RoomInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return RoomInfo{Name: r.Name, Topic: r.Topic, MaxMembers: r.MaxMembers, Members: len(r.Clients), HasPassword: r.passwordHash != nil}
+}
+
+// createRoom adds r, failing if a room of that name exists.
+func (h *Hub) createRoom(r *Room) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.rooms[r.Name]; ok {
+		return fmt.Errorf("room '%s' already exists", r.Name)
+	}
+	h.rooms[r.Name] = r
+	return nil
+}
+
+// deleteRoom removes the room, taking its members out of it, and reports
+// whether it existed.
+func (h *Hub) deleteRoom(name string) bool {
+	h.mu.Lock()
+	r, ok := h.rooms[name]
+	delete(h.rooms, name)
+	h.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	r.mu.Lock()
+	r.deleted = true
+	members := r.Clients
+	r.Clients = make(map[*Client]bool)
+	r.mu.Unlock()
+
+	for c := range members {
+		if c.dropRoom(name) {
+			c.reply(MsgSystem, fmt.Sprintf("Room '%s' was deleted", name))
+		}
+	}
+	return true
+}
+
+// roomList describes every room, sorted by name.
+func (h *Hub) roomList() []RoomInfo {
+	h.mu.RLock()
+	list := make([]RoomInfo, 0, len(h.rooms))
+	for _, r := range h.rooms {
+		list = append(list, r.info())
+	}
+	h.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
 }
 
 // --- Broadcast to room ---
@@ -203,14 +483,57 @@ func (h *Hub) broadcastToRoom(roomName string, msg Message) {
 		return
 	}
 
-	r.mu.RLock()
+	r.mu.Lock()
+	r.seq++
+	msg.Seq = r.seq
+	r.recent = append(r.recent, msg)
+	if len(r.recent) > recentMessages {
+		r.recent = r.recent[len(r.recent)-recentMessages:]
+	}
 	for c := range r.Clients {
 		select {
 		case c.send <- msg:
 		default:
+			// the client sees the gap in seq and asks for a resend
+			log.Printf("send buffer of %s full, dropped message %d of '%s'", c.username, msg.Seq, roomName)
+		}
+	}
+	r.mu.Unlock()
+}
+
+// --- Resend ---
+// resend replays the messages from..until of room to the client, and tells
+// the client which of them are no longer kept.
+func (h *Hub) resend(client *Client, room string, from, until int64) {
+	if !client.inRoom(room) {
+		return
+	}
+	h.mu.RLock()
+	r, ok := h.rooms[room]
+	h.mu.RUnlock()
+	if !ok || from <= 0 || until < from {
+		return
+	}
+
+	r.mu.RLock()
+	replay := make([]Message, 0)
+	for _, m := range r.recent {
+		if m.Seq >= from && m.Seq <= until {
+			replay = append(replay, m)
 		}
 	}
 	r.mu.RUnlock()
+
+	if len(replay) == 0 || replay[0].Seq > from {
+		lost := until
+		if len(replay) > 0 {
+			lost = replay[0].Seq - 1
+		}
+		client.send <- Message{Type: MsgSystem, Room: room, Username: "SYSTEM", Text: fmt.Sprintf("Messages %d-%d are no longer available", from, lost), Time: time.Now().Format(time.RFC3339)}
+	}
+	for _, m := range replay {
+		client.send <- m
+	}
 }
 
 // --- Send user list ---
@@ -231,18 +554,275 @@ func (h *Hub) sendUserListToRoom(roomName string) {
 
 	text := fmt.Sprintf("Users in '%s' (%d):\n", roomName, len(users))
 	for _, u := range users {
-		text += "- " + u + "\n"
+		text += "- " + u + " (" + h.describePresence(u) + ")\n"
 	}
 
 	msg := Message{Type: MsgUserList, Room: roomName, Username: "SYSTEM", Text: text, Time: time.Now().Format(time.RFC3339)}
 	h.broadcastToRoom(roomName, msg)
 }
 
+// --- Presence tracking ---
+// setOnline records a new connection of username, which is about to join
+// room, if not "".
+func (h *Hub) setOnline(username, room string) {
+	h.presenceMu.Lock()
+	p, ok := h.presence[username]
+	if !ok {
+		p = &Presence{Username: username}
+		h.presence[username] = p
+	}
+	p.conns++
+	p.LastActive = time.Now()
+	changed := p.Status != StatusOnline
+	p.Status = StatusOnline
+	h.presenceMu.Unlock()
+
+	if changed {
+		h.broadcastPresence(username, StatusOnline, room)
+	}
+}
+
+// setOffline records that a connection of username, which was in rooms,
+// closed. The user goes offline with their last connection.
+func (h *Hub) setOffline(username string, rooms []string) {
+	h.presenceMu.Lock()
+	p, ok := h.presence[username]
+	if !ok {
+		h.presenceMu.Unlock()
+		return
+	}
+	p.conns--
+	offline := p.conns <= 0
+	if offline {
+		p.conns = 0
+		p.Status = StatusOffline
+		p.LastSeen = time.Now()
+	}
+	h.presenceMu.Unlock()
+
+	if offline {
+		h.broadcastPresence(username, StatusOffline, rooms...)
+	}
+}
+
+// touch records activity of username, bringing them back from idle.
+func (h *Hub) touch(username string) {
+	h.presenceMu.Lock()
+	p, ok := h.presence[username]
+	if !ok {
+		h.presenceMu.Unlock()
+		return
+	}
+	p.LastActive = time.Now()
+	back := p.Status == StatusIdle
+	if back {
+		p.Status = StatusOnline
+	}
+	h.presenceMu.Unlock()
+
+	if back {
+		h.broadcastPresence(username, StatusOnline)
+	}
+}
+
+// markIdle marks the online users who sent nothing for idleAfter as idle.
+func (h *Hub) markIdle() {
+	now := time.Now()
+	idle := make([]string, 0)
+	h.presenceMu.Lock()
+	for name, p := range h.presence {
+		if p.Status == StatusOnline && now.Sub(p.LastActive) >= idleAfter {
+			p.Status = StatusIdle
+			idle = append(idle, name)
+		}
+	}
+	h.presenceMu.Unlock()
+
+	for _, name := range idle {
+		h.broadcastPresence(name, StatusIdle)
+	}
+}
+
+// broadcastPresence tells the rooms username is in, and the extra ones, that
+// their status changed.
+func (h *Hub) broadcastPresence(username, status string, extra ...string) {
+	rooms := make(map[string]bool)
+	for _, name := range extra {
+		if name != "" {
+			rooms[name] = true
+		}
+	}
+	h.mu.RLock()
+	for name, room := range h.rooms {
+		room.mu.RLock()
+		for c := range room.Clients {
+			if c.username == username {
+				rooms[name] = true
+				break
+			}
+		}
+		room.mu.RUnlock()
+	}
+	h.mu.RUnlock()
+
+	for name := range rooms {
+		msg := Message{Type: MsgPresence, Room: name, Username: username, Text: fmt.Sprintf("%s is %s", username, status), Time: time.Now().Format(time.RFC3339)}
+		h.broadcastToRoom(name, msg)
+	}
+}
+
+// describePresence is the status of username for the user list, e.g.
+// "idle, last active 15:04:05".
+func (h *Hub) describePresence(username string) string {
+	h.presenceMu.RLock()
+	defer h.presenceMu.RUnlock()
+	p, ok := h.presence[username]
+	if !ok {
+		return StatusOffline
+	}
+	switch p.Status {
+	case StatusIdle:
+		return fmt.Sprintf("idle, last active %s", p.LastActive.Format(time.TimeOnly))
+	case StatusOffline:
+		return fmt.Sprintf("offline, last seen %s", p.LastSeen.Format(time.TimeOnly))
+	}
+	return p.Status
+}
+
+// presenceList is a copy of every user's presence, sorted by username.
+func (h *Hub) presenceList() []Presence {
+	h.presenceMu.RLock()
+	list := make([]Presence, 0, len(h.presence))
+	for _, p := range h.presence {
+		list = append(list, *p)
+	}
+	h.presenceMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Username < list[j].Username })
+	return list
+}
+
+// --- Delivery receipts ---
+// remember gives msg the next message ID and keeps it in recent.
+func (h *Hub) remember(msg Message) Message {
+	h.sentMu.Lock()
+	defer h.sentMu.Unlock()
+	h.nextID++
+	msg.ID = h.nextID
+	h.recent = append(h.recent, msg)
+	if len(h.recent) > recentMessages {
+		h.recent = h.recent[len(h.recent)-recentMessages:]
+	}
+	return msg
+}
+
+// findRecent returns the recent message with the given ID.
+func (h *Hub) findRecent(id int64) (Message, bool) {
+	h.sentMu.RLock()
+	defer h.sentMu.RUnlock()
+	for _, m := range h.recent {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return Message{}, false
+}
+
+// unacked returns the recent messages of room after the last one username
+// acked there, or none if they never acked in it. The user's own messages
+// are left out.
+func (h *Hub) unacked(username, room string) []Message {
+	h.sentMu.RLock()
+	defer h.sentMu.RUnlock()
+	last, ok := h.acked[ackKey{username, room}]
+	if !ok {
+		return nil
+	}
+	missed := make([]Message, 0)
+	for _, m := range h.recent {
+		if m.Room == room && m.ID > last && m.Username != username {
+			missed = append(missed, m)
+		}
+	}
+	return missed
+}
+
+// receipt handles an ack or read of message id from client: it records the
+// ack and tells the sender of the message, with kind delivered or read.
+func (h *Hub) receipt(client *Client, id int64, kind string) {
+	m, ok := h.findRecent(id)
+	if !ok || !client.inRoom(m.Room) {
+		return
+	}
+
+	key := ackKey{client.username, m.Room}
+	h.sentMu.Lock()
+	if id > h.acked[key] {
+		h.acked[key] = id
+	}
+	h.sentMu.Unlock()
+
+	if m.Username == client.username {
+		return
+	}
+	h.sendToUser(m.Username, m.Room, Message{ID: id, Type: kind, Room: m.Room, Username: client.username, Text: kind, Time: time.Now().Format(time.RFC3339)})
+}
+
+// sendToUser sends msg to the clients of username in roomName.
+func (h *Hub) sendToUser(username, roomName string, msg Message) {
+	h.mu.RLock()
+	r, ok := h.rooms[roomName]
+	h.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	r.mu.RLock()
+	for c := range r.Clients {
+		if c.username == username {
+			select {
+			case c.send <- msg:
+			default:
+			}
+		}
+	}
+	r.mu.RUnlock()
+}
+
 // --- Handle commands ---
 func (h *Hub) handleCommand(client *Client, cmd string) {
-	switch cmd {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		fields = []string{""}
+	}
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+	switch fields[0] {
+	case "/join":
+		if arg == "" {
+			client.reply(MsgSystem, "Usage: /join <room> [password]")
+			return
+		}
+		password := ""
+		if len(fields) > 2 {
+			password = fields[2]
+		}
+		h.join <- membership{client, arg, password}
+	case "/leave":
+		room := client.target(arg)
+		if room == "" {
+			client.reply(MsgSystem, "You are not in any room")
+			return
+		}
+		h.leave <- membership{client: client, room: room}
 	case "/users":
-		h.sendUserListToRoom(client.room)
+		room := client.target(arg)
+		if room == "" {
+			client.reply(MsgSystem, "Join a room first with /join <room>")
+			return
+		}
+		h.sendUserListToRoom(room)
 	case "/stats":
 		h.mu.RLock()
 		roomDetails := make(map[string]int)
@@ -259,21 +839,29 @@ func (h *Hub) handleCommand(client *Client, cmd string) {
 		totalRooms := len(roomDetails)
 		stats := map[string]interface{}{"total_users": totalUsers, "total_rooms": totalRooms, "room_details": roomDetails}
 		b, _ := json.MarshalIndent(stats, "", "  ")
-		client.send <- Message{Type: MsgStats, Room: client.room, Username: "SYSTEM", Text: string(b), Time: time.Now().Format(time.RFC3339)}
+		client.reply(MsgStats, string(b))
 	case "/rooms":
-		h.mu.RLock()
-		names := make([]string, 0, len(h.rooms))
-		for name := range h.rooms {
-			names = append(names, name)
-		}
-		h.mu.RUnlock()
 		text := "Rooms:\n"
-		for _, n := range names {
-			text += "- " + n + "\n"
+		for _, r := range h.roomList() {
+			text += fmt.Sprintf("- %s (%d", r.Name, r.Members)
+			if r.MaxMembers > 0 {
+				text += fmt.Sprintf("/%d", r.MaxMembers)
+			}
+			text += ")"
+			if r.HasPassword {
+				text += " [password]"
+			}
+			if client.inRoom(r.Name) {
+				text += " (joined)"
+			}
+			if r.Topic != "" {
+				text += ": " + r.Topic
+			}
+			text += "\n"
 		}
-		client.send <- Message{Type: MsgSystem, Room: client.room, Username: "SYSTEM", Text: text, Time: time.Now().Format(time.RFC3339)}
+		client.reply(MsgSystem, text)
 	default:
-		client.send <- Message{Type: MsgSystem, Room: client.room, Username: "SYSTEM", Text: "Unknown command", Time: time.Now().Format(time.RFC3339)}
+		client.reply(MsgSystem, "Unknown command")
 	}
 }
 
@@ -306,11 +894,14 @@ func (h *Hub) routeNotification(n Notification) {
 		h.broadcastToRoom(room, system)
 	case strings.HasPrefix(n.Target, "user:"):
 		user := strings.TrimPrefix(n.Target, "user:")
+		// a client in several rooms gets it once, in the first of them
+		sent := make(map[*Client]bool)
 		h.mu.RLock()
 		for _, room := range h.rooms {
 			room.mu.RLock()
 			for c := range room.Clients {
-				if c.username == user {
+				if c.username == user && !sent[c] {
+					sent[c] = true
 					c.send <- Message{Type: MsgSystem, Room: room.Name, Username: "ADMIN", Text: fmt.Sprintf("%s: %s", n.Title, n.Message), Time: n.Timestamp.Format(time.RFC3339)}
 				}
 			}
@@ -324,10 +915,11 @@ func (h *Hub) routeNotification(n Notification) {
 var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
 
 func serveWs(hub *Hub, c *gin.Context) {
+	// room is optional: more rooms are joined with /join
 	username := c.Query("username")
 	room := c.Query("room")
-	if username == "" || room == "" {
-		c.String(400, "username and room query params required")
+	if username == "" {
+		c.String(400, "username query param required")
 		return
 	}
 
@@ -337,8 +929,8 @@ func serveWs(hub *Hub, c *gin.Context) {
 		return
 	}
 
-	client := &Client{conn: ws, send: make(chan Message, 256), username: username, room: room, hub: hub}
-	hub.register <- client
+	client := &Client{conn: ws, send: make(chan Message, 256), username: username, hub: hub, rooms: make(map[string]bool)}
+	hub.register <- membership{client, room, c.Query("password")}
 
 	go client.writePump()
 	client.readPump()
@@ -355,13 +947,34 @@ func (c *Client) readPump() {
 			log.Println("read error:", err)
 			break
 		}
+		switch msg.Type {
+		case MsgAck:
+			c.hub.receipt(c, msg.ID, MsgDelivered)
+			continue
+		case MsgRead:
+			c.hub.receipt(c, msg.ID, MsgRead)
+			continue
+		case MsgResend:
+			c.hub.resend(c, c.target(msg.Room), msg.Seq, msg.Until)
+			continue
+		}
+		c.hub.touch(c.username)
 		if msg.Type == MsgCommand {
 			c.hub.handleCommand(c, msg.Text)
 			continue
 		}
+		msg.Room = c.target(msg.Room)
+		if !c.inRoom(msg.Room) {
+			if msg.Room == "" {
+				c.reply(MsgSystem, "Join a room first with /join <room>")
+			} else {
+				c.reply(MsgSystem, fmt.Sprintf("You are not in '%s'", msg.Room))
+			}
+			continue
+		}
 		msg.Username = c.username
-		msg.Room = c.room
 		msg.Time = time.Now().Format(time.RFC3339)
+		msg.Until = 0
 		c.hub.broadcast <- msg
 	}
 }
@@ -414,6 +1027,70 @@ func getStats(h *Hub) gin.HandlerFunc {
 	}
 }
 
+// CreateRoomRequest is the body of POST /api/rooms.
+type CreateRoomRequest struct {
+	Name       string `json:"name"`
+	Topic      string `json:"topic"`
+	MaxMembers int    `json:"max_members"`
+	Password   string `json:"password"`
+}
+
+func createRoom(h *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateRoomRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" || strings.ContainsAny(req.Name, " \t") {
+			c.JSON(400, gin.H{"error": "name is required and may not contain spaces"})
+			return
+		}
+		if req.MaxMembers < 0 {
+			c.JSON(400, gin.H{"error": "max_members may not be negative"})
+			return
+		}
+		r := &Room{Name: req.Name, Clients: make(map[*Client]bool), Topic: req.Topic, MaxMembers: req.MaxMembers, created: true}
+		if req.Password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			r.passwordHash = hash
+		}
+		if err := h.createRoom(r); err != nil {
+			c.JSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(201, r.info())
+	}
+}
+
+func listRooms(h *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(200, h.roomList())
+	}
+}
+
+func deleteRoom(h *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if !h.deleteRoom(name) {
+			c.JSON(404, gin.H{"error": fmt.Sprintf("room '%s' not found", name)})
+			return
+		}
+		c.JSON(200, gin.H{"status": "deleted", "name": name})
+	}
+}
+
+func getPresence(h *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(200, h.presenceList())
+	}
+}
+
 // --- StatsMessage for API ---
 type StatsMessage struct {
 	TotalUsers  int            `json:"total_users"`
@@ -430,6 +1107,10 @@ func main() {
 	r.GET("/ws", func(c *gin.Context) { serveWs(hub, c) })
 	r.POST("/api/notify", handleNotification(hub))
 	r.GET("/api/stats", getStats(hub))
+	r.GET("/api/presence", getPresence(hub))
+	r.POST("/api/rooms", createRoom(hub))
+	r.GET("/api/rooms", listRooms(hub))
+	r.DELETE("/api/rooms/:name", deleteRoom(hub))
 
 	log.Println("Server running on :8080")
 	r.Run(":8080")